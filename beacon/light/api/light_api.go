@@ -391,7 +391,33 @@ func (api *BeaconLightApi) GetCheckpointData(checkpointHash common.Hash) (*types
 }
 
 func (api *BeaconLightApi) GetBeaconBlock(blockRoot common.Hash) (*types.BeaconBlock, error) {
-	resp, err := api.httpGet(fmt.Sprintf("/eth/v2/beacon/blocks/0x%x", blockRoot), nil)
+	block, err := api.getBeaconBlock(fmt.Sprintf("0x%x", blockRoot))
+	if err != nil {
+		return nil, err
+	}
+	computedRoot := block.Root()
+	if computedRoot != blockRoot {
+		return nil, fmt.Errorf("Beacon block root hash mismatch (expected: %x, got: %x)", blockRoot, computedRoot)
+	}
+	return block, nil
+}
+
+// GetBeaconBlockBySlot fetches the beacon block proposed in the given slot.
+// Note that the block is not validated against any known root.
+func (api *BeaconLightApi) GetBeaconBlockBySlot(slot uint64) (*types.BeaconBlock, error) {
+	block, err := api.getBeaconBlock(strconv.FormatUint(slot, 10))
+	if err != nil {
+		return nil, err
+	}
+	if block.Slot() != slot {
+		return nil, fmt.Errorf("Beacon block slot mismatch (expected: %d, got: %d)", slot, block.Slot())
+	}
+	return block, nil
+}
+
+// getBeaconBlock fetches the beacon block with the given block identifier.
+func (api *BeaconLightApi) getBeaconBlock(blockId string) (*types.BeaconBlock, error) {
+	resp, err := api.httpGet("/eth/v2/beacon/blocks/"+blockId, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -405,15 +431,24 @@ func (api *BeaconLightApi) GetBeaconBlock(blockRoot common.Hash) (*types.BeaconB
 	if err := json.Unmarshal(resp, &beaconBlockMessage); err != nil {
 		return nil, fmt.Errorf("invalid block json data: %v", err)
 	}
-	block, err := types.BlockFromJSON(beaconBlockMessage.Version, beaconBlockMessage.Data.Message)
+	return types.BlockFromJSON(beaconBlockMessage.Version, beaconBlockMessage.Data.Message)
+}
+
+// GetGenesisTime fetches the unix timestamp of the first slot of the chain.
+func (api *BeaconLightApi) GetGenesisTime() (uint64, error) {
+	resp, err := api.httpGet("/eth/v1/beacon/genesis", nil)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	computedRoot := block.Root()
-	if computedRoot != blockRoot {
-		return nil, fmt.Errorf("Beacon block root hash mismatch (expected: %x, got: %x)", blockRoot, computedRoot)
+	var data struct {
+		Data struct {
+			GenesisTime common.Decimal `json:"genesis_time"`
+		} `json:"data"`
 	}
-	return block, nil
+	if err := json.Unmarshal(resp, &data); err != nil {
+		return 0, err
+	}
+	return uint64(data.Data.GenesisTime), nil
 }
 
 func decodeHeadEvent(enc []byte) (uint64, common.Hash, error) {
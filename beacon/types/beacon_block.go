@@ -20,7 +20,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/bits"
 
+	"github.com/ethereum/go-ethereum/beacon/merkle"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	zrntcommon "github.com/protolambda/zrnt/eth2/beacon/common"
@@ -29,6 +31,7 @@ import (
 	"github.com/protolambda/ztyp/tree"
 
 	// beacon forks
+	"github.com/protolambda/zrnt/eth2/beacon/bellatrix"
 	"github.com/protolambda/zrnt/eth2/beacon/capella"
 	"github.com/protolambda/zrnt/eth2/beacon/deneb"
 	"github.com/protolambda/zrnt/eth2/beacon/electra"
@@ -48,6 +51,8 @@ type BeaconBlock struct {
 func BlockFromJSON(forkName string, data []byte) (*BeaconBlock, error) {
 	var obj blockObject
 	switch forkName {
+	case "bellatrix":
+		obj = new(bellatrix.BeaconBlock)
 	case "capella":
 		obj = new(capella.BeaconBlock)
 	case "deneb":
@@ -66,6 +71,8 @@ func BlockFromJSON(forkName string, data []byte) (*BeaconBlock, error) {
 // NewBeaconBlock wraps a ZRNT block.
 func NewBeaconBlock(obj blockObject) *BeaconBlock {
 	switch obj := obj.(type) {
+	case *bellatrix.BeaconBlock:
+		return &BeaconBlock{obj}
 	case *capella.BeaconBlock:
 		return &BeaconBlock{obj}
 	case *deneb.BeaconBlock:
@@ -80,6 +87,8 @@ func NewBeaconBlock(obj blockObject) *BeaconBlock {
 // Slot returns the slot number of the block.
 func (b *BeaconBlock) Slot() uint64 {
 	switch obj := b.blockObj.(type) {
+	case *bellatrix.BeaconBlock:
+		return uint64(obj.Slot)
 	case *capella.BeaconBlock:
 		return uint64(obj.Slot)
 	case *deneb.BeaconBlock:
@@ -94,6 +103,8 @@ func (b *BeaconBlock) Slot() uint64 {
 // ExecutionPayload parses and returns the execution payload of the block.
 func (b *BeaconBlock) ExecutionPayload() (*types.Block, error) {
 	switch obj := b.blockObj.(type) {
+	case *bellatrix.BeaconBlock:
+		return convertPayload(&obj.Body.ExecutionPayload, &obj.ParentRoot, nil)
 	case *capella.BeaconBlock:
 		return convertPayload(&obj.Body.ExecutionPayload, &obj.ParentRoot, nil)
 	case *deneb.BeaconBlock:
@@ -109,6 +120,8 @@ func (b *BeaconBlock) ExecutionPayload() (*types.Block, error) {
 // Header returns the block's header data.
 func (b *BeaconBlock) Header() Header {
 	switch obj := b.blockObj.(type) {
+	case *bellatrix.BeaconBlock:
+		return headerFromZRNT(obj.Header(configs.Mainnet))
 	case *capella.BeaconBlock:
 		return headerFromZRNT(obj.Header(configs.Mainnet))
 	case *deneb.BeaconBlock:
@@ -125,10 +138,127 @@ func (b *BeaconBlock) Root() common.Hash {
 	return common.Hash(b.blockObj.HashTreeRoot(configs.Mainnet, tree.GetHashFn()))
 }
 
+// ExecutionBlockHash returns the hash of the execution block included in the
+// beacon block.
+func (b *BeaconBlock) ExecutionBlockHash() common.Hash {
+	switch obj := b.blockObj.(type) {
+	case *bellatrix.BeaconBlock:
+		return common.Hash(obj.Body.ExecutionPayload.BlockHash)
+	case *capella.BeaconBlock:
+		return common.Hash(obj.Body.ExecutionPayload.BlockHash)
+	case *deneb.BeaconBlock:
+		return common.Hash(obj.Body.ExecutionPayload.BlockHash)
+	case *electra.BeaconBlock:
+		return common.Hash(obj.Body.ExecutionPayload.BlockHash)
+	default:
+		panic(fmt.Errorf("unsupported block type %T", b.blockObj))
+	}
+}
+
+// ExecutionBlockHashProof returns the generalized index of the execution block
+// hash in the beacon block and the merkle branch proving it against the block
+// root. The index differs between forks, as the execution payload container
+// outgrew 16 fields in deneb.
+func (b *BeaconBlock) ExecutionBlockHashProof() (uint64, merkle.Values) {
+	var (
+		spec          = configs.Mainnet
+		body, payload []tree.HTR
+	)
+	switch obj := b.blockObj.(type) {
+	case *bellatrix.BeaconBlock:
+		bd, p := &obj.Body, &obj.Body.ExecutionPayload
+		body = []tree.HTR{bd.RandaoReveal, &bd.Eth1Data, bd.Graffiti, spec.Wrap(&bd.ProposerSlashings),
+			spec.Wrap(&bd.AttesterSlashings), spec.Wrap(&bd.Attestations), spec.Wrap(&bd.Deposits),
+			spec.Wrap(&bd.VoluntaryExits), spec.Wrap(&bd.SyncAggregate), spec.Wrap(p)}
+		payload = []tree.HTR{&p.ParentHash, &p.FeeRecipient, &p.StateRoot, &p.ReceiptsRoot, &p.LogsBloom,
+			&p.PrevRandao, &p.BlockNumber, &p.GasLimit, &p.GasUsed, &p.Timestamp, &p.ExtraData,
+			&p.BaseFeePerGas, &p.BlockHash, spec.Wrap(&p.Transactions)}
+	case *capella.BeaconBlock:
+		bd, p := &obj.Body, &obj.Body.ExecutionPayload
+		body = []tree.HTR{bd.RandaoReveal, &bd.Eth1Data, bd.Graffiti, spec.Wrap(&bd.ProposerSlashings),
+			spec.Wrap(&bd.AttesterSlashings), spec.Wrap(&bd.Attestations), spec.Wrap(&bd.Deposits),
+			spec.Wrap(&bd.VoluntaryExits), spec.Wrap(&bd.SyncAggregate), spec.Wrap(p),
+			spec.Wrap(&bd.BLSToExecutionChanges)}
+		payload = []tree.HTR{&p.ParentHash, &p.FeeRecipient, &p.StateRoot, &p.ReceiptsRoot, &p.LogsBloom,
+			&p.PrevRandao, &p.BlockNumber, &p.GasLimit, &p.GasUsed, &p.Timestamp, &p.ExtraData,
+			&p.BaseFeePerGas, &p.BlockHash, spec.Wrap(&p.Transactions), spec.Wrap(&p.Withdrawals)}
+	case *deneb.BeaconBlock:
+		bd, p := &obj.Body, &obj.Body.ExecutionPayload
+		body = []tree.HTR{bd.RandaoReveal, &bd.Eth1Data, bd.Graffiti, spec.Wrap(&bd.ProposerSlashings),
+			spec.Wrap(&bd.AttesterSlashings), spec.Wrap(&bd.Attestations), spec.Wrap(&bd.Deposits),
+			spec.Wrap(&bd.VoluntaryExits), spec.Wrap(&bd.SyncAggregate), spec.Wrap(p),
+			spec.Wrap(&bd.BLSToExecutionChanges), spec.Wrap(&bd.BlobKZGCommitments)}
+		payload = denebPayloadFields(spec, p)
+	case *electra.BeaconBlock:
+		bd, p := &obj.Body, &obj.Body.ExecutionPayload
+		body = []tree.HTR{bd.RandaoReveal, &bd.Eth1Data, bd.Graffiti, spec.Wrap(&bd.ProposerSlashings),
+			spec.Wrap(&bd.AttesterSlashings), spec.Wrap(&bd.Attestations), spec.Wrap(&bd.Deposits),
+			spec.Wrap(&bd.VoluntaryExits), spec.Wrap(&bd.SyncAggregate), spec.Wrap(p),
+			spec.Wrap(&bd.BLSToExecutionChanges), spec.Wrap(&bd.BlobKZGCommitments),
+			spec.Wrap(&bd.ExecutionRequests)}
+		payload = denebPayloadFields(spec, p)
+	default:
+		panic(fmt.Errorf("unsupported block type %T", b.blockObj))
+	}
+	h := b.blockObj.Header(spec)
+	block := []tree.HTR{h.Slot, h.ProposerIndex, h.ParentRoot, h.StateRoot, h.BodyRoot}
+
+	// Walk down block -> body -> execution_payload -> block_hash, collecting
+	// the branches bottom-up.
+	var (
+		index  = uint64(1)
+		branch merkle.Values
+	)
+	for _, level := range []struct {
+		fields []tree.HTR
+		field  int
+	}{
+		{block, 4},    // body
+		{body, 9},     // execution_payload
+		{payload, 12}, // block_hash
+	} {
+		levelBranch, depth := containerBranch(level.fields, level.field)
+		index = index<<depth | uint64(level.field)
+		branch = append(levelBranch, branch...)
+	}
+	return index, branch
+}
+
+// denebPayloadFields returns the fields of a deneb execution payload, which is
+// also used by electra.
+func denebPayloadFields(spec *zrntcommon.Spec, p *deneb.ExecutionPayload) []tree.HTR {
+	return []tree.HTR{&p.ParentHash, &p.FeeRecipient, &p.StateRoot, &p.ReceiptsRoot, &p.LogsBloom,
+		&p.PrevRandao, &p.BlockNumber, &p.GasLimit, &p.GasUsed, &p.Timestamp, &p.ExtraData,
+		&p.BaseFeePerGas, &p.BlockHash, spec.Wrap(&p.Transactions), spec.Wrap(&p.Withdrawals),
+		&p.BlobGasUsed, &p.ExcessBlobGas}
+}
+
+// containerBranch returns the merkle branch of a field of an SSZ container,
+// ordered from the leaf upwards, and the depth of the container tree.
+func containerBranch(fields []tree.HTR, field int) (merkle.Values, uint64) {
+	var (
+		hFn    = tree.GetHashFn()
+		depth  = bits.Len(uint(len(fields) - 1))
+		layer  = make([]tree.Root, 1<<depth)
+		branch = make(merkle.Values, 0, depth)
+	)
+	for i, f := range fields {
+		layer[i] = f.HashTreeRoot(hFn)
+	}
+	for len(layer) > 1 {
+		branch = append(branch, merkle.Value(layer[field^1]))
+		for i := 0; i < len(layer)/2; i++ {
+			layer[i] = hFn(layer[2*i], layer[2*i+1])
+		}
+		layer, field = layer[:len(layer)/2], field/2
+	}
+	return branch, uint64(depth)
+}
+
 // ExecutionRequestsList returns the execution layer requests of the block.
 func (b *BeaconBlock) ExecutionRequestsList() [][]byte {
 	switch obj := b.blockObj.(type) {
-	case *capella.BeaconBlock, *deneb.BeaconBlock:
+	case *bellatrix.BeaconBlock, *capella.BeaconBlock, *deneb.BeaconBlock:
 		return nil
 	case *electra.BeaconBlock:
 		r := obj.Body.ExecutionRequests
@@ -21,7 +21,9 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/beacon/merkle"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/protolambda/zrnt/eth2/beacon/bellatrix"
 )
 
 func TestBlockFromJSON(t *testing.T) {
@@ -31,6 +33,7 @@ func TestBlockFromJSON(t *testing.T) {
 		wantSlot        uint64
 		wantBlockNumber uint64
 		wantBlockHash   common.Hash
+		wantHashIndex   uint64
 	}
 	tests := []blocktest{
 		{
@@ -39,6 +42,7 @@ func TestBlockFromJSON(t *testing.T) {
 			wantSlot:        151850,
 			wantBlockNumber: 141654,
 			wantBlockHash:   common.HexToHash("0xf6730485a38be5ada3e110990a2c7adaabd2e8d4a49782134f1a8bfbc246a5d7"),
+			wantHashIndex:   6444,
 		},
 		{
 			file:            "block_electra_deposits.json",
@@ -46,6 +50,7 @@ func TestBlockFromJSON(t *testing.T) {
 			wantSlot:        151016,
 			wantBlockNumber: 140858,
 			wantBlockHash:   common.HexToHash("0x1f2637170986346c7993d5adbadbebbf4c9ed89c6a4d2dff653db99c8c168076"),
+			wantHashIndex:   6444,
 		},
 		{
 			file:            "block_electra_consolidations.json",
@@ -53,6 +58,7 @@ func TestBlockFromJSON(t *testing.T) {
 			wantSlot:        151717,
 			wantBlockNumber: 141529,
 			wantBlockHash:   common.HexToHash("0xc8807f7a1f96b0a073ff27065776dd21eff6b7e64079c60bffd33f690efbb330"),
+			wantHashIndex:   6444,
 		},
 		{
 			file:            "block_deneb.json",
@@ -60,6 +66,7 @@ func TestBlockFromJSON(t *testing.T) {
 			wantSlot:        8631513,
 			wantBlockNumber: 19431837,
 			wantBlockHash:   common.HexToHash("0x4cf7d9108fc01b50023ab7cab9b372a96068fddcadec551630393b65acb1f34c"),
+			wantHashIndex:   6444,
 		},
 		{
 			file:            "block_capella.json",
@@ -67,6 +74,7 @@ func TestBlockFromJSON(t *testing.T) {
 			wantSlot:        7378495,
 			wantBlockNumber: 18189758,
 			wantBlockHash:   common.HexToHash("0x802acf5c350f4252e31d83c431fcb259470250fa0edf49e8391cfee014239820"),
+			wantHashIndex:   3228,
 		},
 	}
 
@@ -93,6 +101,36 @@ func TestBlockFromJSON(t *testing.T) {
 			if execBlock.Hash() != test.wantBlockHash {
 				t.Errorf("wrong block hash: %v", execBlock.Hash())
 			}
+			if hash := beaconBlock.ExecutionBlockHash(); hash != test.wantBlockHash {
+				t.Errorf("wrong execution block hash: %v", hash)
+			}
+			index, branch := beaconBlock.ExecutionBlockHashProof()
+			if index != test.wantHashIndex {
+				t.Errorf("wrong block hash index: have %d, want %d", index, test.wantHashIndex)
+			}
+			if err := merkle.VerifyProof(beaconBlock.Root(), index, branch, merkle.Value(test.wantBlockHash)); err != nil {
+				t.Errorf("invalid block hash proof: %v", err)
+			}
 		})
 	}
 }
+
+func TestBellatrixBlockHashProof(t *testing.T) {
+	obj := new(bellatrix.BeaconBlock)
+	obj.Slot = 4700013
+	obj.Body.ExecutionPayload.BlockNumber = 15537394
+	obj.Body.ExecutionPayload.BlockHash[0] = 0xaa
+	block := NewBeaconBlock(obj)
+
+	index, branch := block.ExecutionBlockHashProof()
+	if index != 3228 {
+		t.Fatalf("wrong block hash index: have %d, want 3228", index)
+	}
+	hash := block.ExecutionBlockHash()
+	if hash != (common.Hash{0xaa}) {
+		t.Fatalf("wrong execution block hash: %v", hash)
+	}
+	if err := merkle.VerifyProof(block.Root(), index, branch, merkle.Value(hash)); err != nil {
+		t.Fatalf("invalid block hash proof: %v", err)
+	}
+}
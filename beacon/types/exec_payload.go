@@ -24,13 +24,14 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/holiman/uint256"
+	"github.com/protolambda/zrnt/eth2/beacon/bellatrix"
 	"github.com/protolambda/zrnt/eth2/beacon/capella"
 	zrntcommon "github.com/protolambda/zrnt/eth2/beacon/common"
 	"github.com/protolambda/zrnt/eth2/beacon/deneb"
 )
 
 type payloadType interface {
-	*capella.ExecutionPayload | *deneb.ExecutionPayload
+	*bellatrix.ExecutionPayload | *capella.ExecutionPayload | *deneb.ExecutionPayload
 }
 
 // convertPayload converts a beacon chain execution payload to types.Block.
@@ -43,6 +44,13 @@ func convertPayload[T payloadType](payload T, parentRoot *zrntcommon.Root, reque
 		err          error
 	)
 	switch p := any(payload).(type) {
+	case *bellatrix.ExecutionPayload:
+		convertBellatrixHeader(p, &header)
+		transactions, err = convertTransactions(p.Transactions, &header)
+		if err != nil {
+			return nil, err
+		}
+		expectedHash = p.BlockHash
 	case *capella.ExecutionPayload:
 		convertCapellaHeader(p, &header)
 		transactions, err = convertTransactions(p.Transactions, &header)
@@ -73,6 +81,25 @@ func convertPayload[T payloadType](payload T, parentRoot *zrntcommon.Root, reque
 	return block, nil
 }
 
+func convertBellatrixHeader(payload *bellatrix.ExecutionPayload, h *types.Header) {
+	// note: h.TxHash is set in convertTransactions
+	h.ParentHash = common.Hash(payload.ParentHash)
+	h.UncleHash = types.EmptyUncleHash
+	h.Coinbase = common.Address(payload.FeeRecipient)
+	h.Root = common.Hash(payload.StateRoot)
+	h.ReceiptHash = common.Hash(payload.ReceiptsRoot)
+	h.Bloom = types.Bloom(payload.LogsBloom)
+	h.Difficulty = common.Big0
+	h.Number = new(big.Int).SetUint64(uint64(payload.BlockNumber))
+	h.GasLimit = uint64(payload.GasLimit)
+	h.GasUsed = uint64(payload.GasUsed)
+	h.Time = uint64(payload.Timestamp)
+	h.Extra = []byte(payload.ExtraData)
+	h.MixDigest = common.Hash(payload.PrevRandao)
+	h.Nonce = types.BlockNonce{}
+	h.BaseFee = (*uint256.Int)(&payload.BaseFeePerGas).ToBig()
+}
+
 func convertCapellaHeader(payload *capella.ExecutionPayload, h *types.Header) {
 	// note: h.TxHash is set in convertTransactions
 	h.ParentHash = common.Hash(payload.ParentHash)
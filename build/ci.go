@@ -403,6 +403,7 @@ func doCheckBadDeps() {
 		// Rawdb tends to be a dumping ground for db utils, sometimes leaking the db itself
 		{"github.com/ethereum/go-ethereum/core/rawdb", "github.com/ethereum/go-ethereum/ethdb/leveldb"},
 		{"github.com/ethereum/go-ethereum/core/rawdb", "github.com/ethereum/go-ethereum/ethdb/pebbledb"},
+		{"github.com/ethereum/go-ethereum/core/rawdb", "github.com/ethereum/go-ethereum/ethdb/bbolt"},
 	}
 	tc := new(build.GoToolchain)
 
@@ -25,6 +25,8 @@ import (
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/forkid"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
@@ -50,6 +52,20 @@ func (s *Suite) dial() (*Conn, error) {
 	return s.dialAs(key)
 }
 
+// dialVersion attempts to dial the given node and perform a handshake, only
+// advertising the given eth protocol version.
+func (s *Suite) dialVersion(version uint) (*Conn, error) {
+	conn, err := s.dial()
+	if err != nil {
+		return nil, err
+	}
+	conn.caps = []p2p.Cap{
+		{Name: "eth", Version: version},
+	}
+	conn.ourHighestProtoVersion = version
+	return conn, nil
+}
+
 // dialAs attempts to dial a given node and perform a handshake using the given
 // private key.
 func (s *Suite) dialAs(key *ecdsa.PrivateKey) (*Conn, error) {
@@ -66,10 +82,10 @@ func (s *Suite) dialAs(key *ecdsa.PrivateKey) (*Conn, error) {
 		return nil, err
 	}
 	conn.caps = []p2p.Cap{
-		{Name: "eth", Version: 67},
 		{Name: "eth", Version: 68},
+		{Name: "eth", Version: 69},
 	}
-	conn.ourHighestProtoVersion = 68
+	conn.ourHighestProtoVersion = 69
 	return &conn, nil
 }
 
@@ -156,7 +172,11 @@ func (c *Conn) ReadEth() (any, error) {
 		var msg any
 		switch int(code) {
 		case eth.StatusMsg:
-			msg = new(eth.StatusPacket)
+			if c.negotiatedProtoVersion < eth.ETH69 {
+				msg = new(eth.StatusPacket68)
+			} else {
+				msg = new(eth.StatusPacket69)
+			}
 		case eth.GetBlockHeadersMsg:
 			msg = new(eth.GetBlockHeadersPacket)
 		case eth.BlockHeadersMsg:
@@ -177,6 +197,16 @@ func (c *Conn) ReadEth() (any, error) {
 			msg = new(eth.GetPooledTransactionsPacket)
 		case eth.PooledTransactionsMsg:
 			msg = new(eth.PooledTransactionsPacket)
+		case eth.GetReceiptsMsg:
+			msg = new(eth.GetReceiptsPacket)
+		case eth.ReceiptsMsg:
+			if c.negotiatedProtoVersion < eth.ETH69 {
+				msg = new(eth.ReceiptsPacket)
+			} else {
+				msg = new(eth.Receipts69Packet)
+			}
+		case eth.BlockRangeUpdateMsg:
+			msg = new(eth.BlockRangeUpdatePacket)
 		default:
 			panic(fmt.Sprintf("unhandled eth msg code %d", code))
 		}
@@ -230,8 +260,9 @@ func (c *Conn) ReadSnap() (any, error) {
 }
 
 // peer performs both the protocol handshake and the status message
-// exchange with the node in order to peer with it.
-func (c *Conn) peer(chain *Chain, status *eth.StatusPacket) error {
+// exchange with the node in order to peer with it. The status message, if
+// given, must match the negotiated protocol version.
+func (c *Conn) peer(chain *Chain, status any) error {
 	if err := c.handshake(); err != nil {
 		return fmt.Errorf("handshake failed: %v", err)
 	}
@@ -304,7 +335,7 @@ func (c *Conn) negotiateEthProtocol(caps []p2p.Cap) {
 }
 
 // statusExchange performs a `Status` message exchange with the given node.
-func (c *Conn) statusExchange(chain *Chain, status *eth.StatusPacket) error {
+func (c *Conn) statusExchange(chain *Chain, status any) error {
 loop:
 	for {
 		code, data, err := c.Read()
@@ -313,19 +344,8 @@ loop:
 		}
 		switch code {
 		case eth.StatusMsg + protoOffset(ethProto):
-			msg := new(eth.StatusPacket)
-			if err := rlp.DecodeBytes(data, &msg); err != nil {
-				return fmt.Errorf("error decoding status packet: %w", err)
-			}
-			if have, want := msg.Head, chain.blocks[chain.Len()-1].Hash(); have != want {
-				return fmt.Errorf("wrong head block in status, want:  %#x (block %d) have %#x",
-					want, chain.blocks[chain.Len()-1].NumberU64(), have)
-			}
-			if have, want := msg.ForkID, chain.ForkID(); !reflect.DeepEqual(have, want) {
-				return fmt.Errorf("wrong fork ID in status: have %v, want %v", have, want)
-			}
-			if have, want := msg.ProtocolVersion, c.ourHighestProtoVersion; have != uint32(want) {
-				return fmt.Errorf("wrong protocol version: have %v, want %v", have, want)
+			if err := c.checkStatus(chain, data); err != nil {
+				return err
 			}
 			break loop
 		case discMsg:
@@ -348,13 +368,26 @@ loop:
 	}
 	if status == nil {
 		// default status message
-		status = &eth.StatusPacket{
-			ProtocolVersion: uint32(c.negotiatedProtoVersion),
-			NetworkID:       chain.config.ChainID.Uint64(),
-			TD:              chain.TD(),
-			Head:            chain.blocks[chain.Len()-1].Hash(),
-			Genesis:         chain.blocks[0].Hash(),
-			ForkID:          chain.ForkID(),
+		head := chain.blocks[chain.Len()-1]
+		if c.negotiatedProtoVersion < eth.ETH69 {
+			status = &eth.StatusPacket68{
+				ProtocolVersion: uint32(c.negotiatedProtoVersion),
+				NetworkID:       chain.config.ChainID.Uint64(),
+				TD:              chain.TD(),
+				Head:            head.Hash(),
+				Genesis:         chain.blocks[0].Hash(),
+				ForkID:          chain.ForkID(),
+			}
+		} else {
+			status = &eth.StatusPacket69{
+				ProtocolVersion: uint32(c.negotiatedProtoVersion),
+				NetworkID:       chain.config.ChainID.Uint64(),
+				Genesis:         chain.blocks[0].Hash(),
+				ForkID:          chain.ForkID(),
+				EarliestBlock:   0,
+				LatestBlock:     head.NumberU64(),
+				LatestBlockHash: head.Hash(),
+			}
 		}
 	}
 	if err := c.Write(ethProto, eth.StatusMsg, status); err != nil {
@@ -362,3 +395,44 @@ loop:
 	}
 	return nil
 }
+
+// checkStatus decodes the status message received from the node according to
+// the negotiated protocol version and checks it against the given chain.
+func (c *Conn) checkStatus(chain *Chain, data []byte) error {
+	var (
+		head            = chain.blocks[chain.Len()-1]
+		headHash        common.Hash
+		forkID          forkid.ID
+		protocolVersion uint32
+	)
+	if c.negotiatedProtoVersion < eth.ETH69 {
+		msg := new(eth.StatusPacket68)
+		if err := rlp.DecodeBytes(data, &msg); err != nil {
+			return fmt.Errorf("error decoding status packet: %w", err)
+		}
+		headHash, forkID, protocolVersion = msg.Head, msg.ForkID, msg.ProtocolVersion
+	} else {
+		msg := new(eth.StatusPacket69)
+		if err := rlp.DecodeBytes(data, &msg); err != nil {
+			return fmt.Errorf("error decoding status packet: %w", err)
+		}
+		if have, want := msg.LatestBlock, head.NumberU64(); have != want {
+			return fmt.Errorf("wrong head number in status: have %d, want %d", have, want)
+		}
+		if msg.EarliestBlock > msg.LatestBlock {
+			return fmt.Errorf("invalid block range in status: earliest %d > latest %d", msg.EarliestBlock, msg.LatestBlock)
+		}
+		headHash, forkID, protocolVersion = msg.LatestBlockHash, msg.ForkID, msg.ProtocolVersion
+	}
+	if have, want := headHash, head.Hash(); have != want {
+		return fmt.Errorf("wrong head block in status, want:  %#x (block %d) have %#x",
+			want, head.NumberU64(), have)
+	}
+	if have, want := forkID, chain.ForkID(); !reflect.DeepEqual(have, want) {
+		return fmt.Errorf("wrong fork ID in status: have %v, want %v", have, want)
+	}
+	if have, want := protocolVersion, c.ourHighestProtoVersion; have != uint32(want) {
+		return fmt.Errorf("wrong protocol version: have %v, want %v", have, want)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/rand"
 	"fmt"
+	"net"
 	"reflect"
 	"sync"
 	"time"
@@ -33,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/internal/utesting"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/trie"
 	"github.com/holiman/uint256"
 )
 
@@ -68,6 +70,7 @@ func (s *Suite) EthTests() []utesting.Test {
 	return []utesting.Test{
 		// status
 		{Name: "Status", Fn: s.TestStatus},
+		{Name: "Status68", Fn: s.TestStatus68},
 		// get block headers
 		{Name: "GetBlockHeaders", Fn: s.TestGetBlockHeaders},
 		{Name: "SimultaneousRequests", Fn: s.TestSimultaneousRequests},
@@ -75,6 +78,11 @@ func (s *Suite) EthTests() []utesting.Test {
 		{Name: "ZeroRequestID", Fn: s.TestZeroRequestID},
 		// get block bodies
 		{Name: "GetBlockBodies", Fn: s.TestGetBlockBodies},
+		// get receipts
+		{Name: "GetBlockReceipts", Fn: s.TestGetBlockReceipts},
+		{Name: "GetBlockReceipts68", Fn: s.TestGetBlockReceipts68},
+		// block range updates
+		{Name: "BlockRangeUpdateInvalid", Fn: s.TestBlockRangeUpdateInvalid},
 		// // malicious handshakes + status
 		{Name: "MaliciousHandshake", Fn: s.TestMaliciousHandshake},
 		// test transactions
@@ -111,6 +119,19 @@ func (s *Suite) TestStatus(t *utesting.T) {
 	}
 }
 
+func (s *Suite) TestStatus68(t *utesting.T) {
+	t.Log(`This test performs an eth protocol handshake, only advertising eth/68.`)
+
+	conn, err := s.dialVersion(eth.ETH68)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.peer(s.chain, nil); err != nil {
+		t.Fatalf("peering failed: %v", err)
+	}
+}
+
 // headersMatch returns whether the received headers match the given request
 func headersMatch(expected []*types.Header, headers []*types.Header) bool {
 	return reflect.DeepEqual(expected, headers)
@@ -375,6 +396,127 @@ func (s *Suite) TestGetBlockBodies(t *utesting.T) {
 	}
 }
 
+func (s *Suite) TestGetBlockReceipts(t *utesting.T) {
+	t.Log(`This test sends GetReceipts requests to the node for known blocks in the test chain
+and verifies that the returned receipts match the receipt roots of the block headers.`)
+
+	s.testGetBlockReceipts(t, eth.ETH69)
+}
+
+func (s *Suite) TestGetBlockReceipts68(t *utesting.T) {
+	t.Log(`This test sends GetReceipts requests to the node over eth/68, where receipts
+include the bloom filter, and verifies them against the receipt roots of the block headers.`)
+
+	s.testGetBlockReceipts(t, eth.ETH68)
+}
+
+func (s *Suite) testGetBlockReceipts(t *utesting.T, version uint) {
+	conn, err := s.dialVersion(version)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.peer(s.chain, nil); err != nil {
+		t.Fatalf("peering failed: %v", err)
+	}
+	// Create receipts request for a handful of blocks.
+	var blocks []*types.Block
+	for i := 1; i < s.chain.Len(); i += s.chain.Len() / 8 {
+		blocks = append(blocks, s.chain.blocks[i])
+	}
+	req := &eth.GetReceiptsPacket{RequestId: 66}
+	for _, block := range blocks {
+		req.GetReceiptsRequest = append(req.GetReceiptsRequest, block.Hash())
+	}
+	if err := conn.Write(ethProto, eth.GetReceiptsMsg, req); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	// Wait for response.
+	var (
+		requestId uint64
+		lists     []types.Receipts
+	)
+	if version < eth.ETH69 {
+		resp := new(eth.ReceiptsPacket)
+		if err := conn.ReadMsg(ethProto, eth.ReceiptsMsg, &resp); err != nil {
+			t.Fatalf("error reading receipts msg: %v", err)
+		}
+		requestId = resp.RequestId
+		for _, list := range resp.ReceiptsResponse {
+			lists = append(lists, list)
+		}
+	} else {
+		resp := new(eth.Receipts69Packet)
+		if err := conn.ReadMsg(ethProto, eth.ReceiptsMsg, &resp); err != nil {
+			t.Fatalf("error reading receipts msg: %v", err)
+		}
+		requestId = resp.RequestId
+		for i, list := range resp.Receipts69Response {
+			receipts := make(types.Receipts, len(list))
+			for j, r := range list {
+				receipt, err := r.Receipt()
+				if err != nil {
+					t.Fatalf("invalid receipt %d in block %d: %v", j, blocks[i].NumberU64(), err)
+				}
+				receipts[j] = receipt
+			}
+			lists = append(lists, receipts)
+		}
+	}
+	if got, want := requestId, req.RequestId; got != want {
+		t.Fatalf("unexpected request id in response: got %d, want %d", got, want)
+	}
+	if len(lists) != len(blocks) {
+		t.Fatalf("wrong receipts in response: expected %d lists, got %d", len(blocks), len(lists))
+	}
+	for i, receipts := range lists {
+		if have, want := types.DeriveSha(receipts, trie.NewStackTrie(nil)), blocks[i].ReceiptHash(); have != want {
+			t.Fatalf("receipt root mismatch for block %d: have %x, want %x", blocks[i].NumberU64(), have, want)
+		}
+	}
+}
+
+func (s *Suite) TestBlockRangeUpdateInvalid(t *utesting.T) {
+	t.Log(`This test sends an invalid BlockRangeUpdate message and expects to be disconnected.`)
+
+	conn, err := s.dial()
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.peer(s.chain, nil); err != nil {
+		t.Fatalf("peering failed: %v", err)
+	}
+	head := s.chain.Head()
+	update := &eth.BlockRangeUpdatePacket{
+		EarliestBlock:   head.NumberU64() + 10,
+		LatestBlock:     head.NumberU64(),
+		LatestBlockHash: head.Hash(),
+	}
+	if err := conn.Write(ethProto, eth.BlockRangeUpdateMsg, update); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	// Check that the peer disconnected.
+	for {
+		code, _, err := conn.Read()
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				t.Fatalf("peer was not disconnected after invalid block range")
+			}
+			// Client may have disconnected without sending disconnect msg.
+			return
+		}
+		switch code {
+		case discMsg:
+			return
+		case pingMsg:
+			conn.Write(baseProto, pongMsg, []byte{})
+		default:
+			// Ignore any other traffic (e.g. transaction announcements).
+		}
+	}
+}
+
 // randBuf makes a random buffer size kilobytes large.
 func randBuf(size int) []byte {
 	buf := make([]byte, size*1024)
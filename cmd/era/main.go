@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/erae"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/params"
@@ -42,12 +43,12 @@ var app = flags.NewApp("go-ethereum era tool")
 var (
 	dirFlag = &cli.StringFlag{
 		Name:  "dir",
-		Usage: "directory storing all relevant era1 files",
+		Usage: "directory storing all relevant era1 and erae files",
 		Value: "eras",
 	}
 	networkFlag = &cli.StringFlag{
 		Name:  "network",
-		Usage: "network name associated with era1 files",
+		Usage: "network name associated with era1 and erae files",
 		Value: "mainnet",
 	}
 	eraSizeFlag = &cli.IntFlag{
@@ -80,7 +81,7 @@ var (
 	verifyCommand = &cli.Command{
 		Name:      "verify",
 		ArgsUsage: "<expected>",
-		Usage:     "verifies each era1 against expected accumulator root",
+		Usage:     "verifies each era1 and erae against expected accumulator root",
 		Action:    verify,
 	}
 )
@@ -105,21 +106,15 @@ func main() {
 	}
 }
 
-// block prints the specified block from an era1 store.
+// block prints the specified block from an era1 or erae store.
 func block(ctx *cli.Context) error {
 	num, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
 	if err != nil {
 		return fmt.Errorf("invalid block number: %w", err)
 	}
-	e, err := open(ctx, num/uint64(ctx.Int(eraSizeFlag.Name)))
+	block, err := readBlock(ctx, num)
 	if err != nil {
-		return fmt.Errorf("error opening era1: %w", err)
-	}
-	defer e.Close()
-	// Read block with number.
-	block, err := e.GetBlockByNumber(num)
-	if err != nil {
-		return fmt.Errorf("error reading block %d: %w", num, err)
+		return err
 	}
 	// Convert block to JSON and print.
 	val := ethapi.RPCMarshalBlock(block, ctx.Bool(txsFlag.Name), ctx.Bool(txsFlag.Name), params.MainnetChainConfig)
@@ -131,7 +126,30 @@ func block(ctx *cli.Context) error {
 	return nil
 }
 
-// info prints some high-level information about the era1 file.
+// readBlock reads the block with the given number, from the era1 file of its
+// epoch if it's a pre-merge block, or the erae file otherwise.
+func readBlock(ctx *cli.Context, num uint64) (*types.Block, error) {
+	epoch := num / uint64(ctx.Int(eraSizeFlag.Name))
+	if e, err := open(ctx, epoch); err == nil {
+		defer e.Close()
+		if block, err := e.GetBlockByNumber(num); err == nil {
+			return block, nil
+		}
+	}
+	e, err := openMerged(ctx, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error opening era: %w", err)
+	}
+	defer e.Close()
+	block, err := e.GetBlockByNumber(num)
+	if err != nil {
+		return nil, fmt.Errorf("error reading block %d: %w", num, err)
+	}
+	return block, nil
+}
+
+// info prints some high-level information about the era1 and erae files of
+// an epoch.
 func info(ctx *cli.Context) error {
 	epoch, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
 	if err != nil {
@@ -139,7 +157,8 @@ func info(ctx *cli.Context) error {
 	}
 	e, err := open(ctx, epoch)
 	if err != nil {
-		return err
+		// Post-merge epochs are only stored in erae files.
+		return mergedInfo(ctx, epoch, err)
 	}
 	defer e.Close()
 	acc, err := e.Accumulator()
@@ -160,6 +179,38 @@ func info(ctx *cli.Context) error {
 	}
 	b, _ := json.MarshalIndent(info, "", "  ")
 	fmt.Println(string(b))
+
+	// The transition epoch is split across an era1 and an erae file.
+	if _, err := findMerged(ctx, epoch); err == nil {
+		return mergedInfo(ctx, epoch, nil)
+	}
+	return nil
+}
+
+// mergedInfo prints some high-level information about the erae file of an
+// epoch. If no such file exists, fallback is returned if set.
+func mergedInfo(ctx *cli.Context, epoch uint64, fallback error) error {
+	e, err := openMerged(ctx, epoch)
+	if err != nil {
+		if fallback != nil {
+			return fallback
+		}
+		return err
+	}
+	defer e.Close()
+	acc, err := e.Accumulator()
+	if err != nil {
+		return fmt.Errorf("error reading accumulator: %w", err)
+	}
+	info := struct {
+		Accumulator common.Hash `json:"accumulator"`
+		StartBlock  uint64      `json:"startBlock"`
+		Count       uint64      `json:"count"`
+	}{
+		acc, e.Start(), e.Count(),
+	}
+	b, _ := json.MarshalIndent(info, "", "  ")
+	fmt.Println(string(b))
 	return nil
 }
 
@@ -179,8 +230,37 @@ func open(ctx *cli.Context, epoch uint64) (*era.Era, error) {
 	return era.Open(filepath.Join(dir, entries[epoch]))
 }
 
-// verify checks each era1 file in a directory to ensure it is well-formed and
-// that the accumulator matches the expected value.
+// findMerged returns the name of the erae file at a certain epoch.
+func findMerged(ctx *cli.Context, epoch uint64) (string, error) {
+	var (
+		dir     = ctx.String(dirFlag.Name)
+		network = ctx.String(networkFlag.Name)
+	)
+	entries, err := erae.ReadDir(dir, network)
+	if err != nil {
+		return "", fmt.Errorf("error reading era dir: %w", err)
+	}
+	prefix := fmt.Sprintf("%s-%05d-", network, epoch)
+	for _, name := range entries {
+		if strings.HasPrefix(name, prefix) {
+			return filepath.Join(dir, name), nil
+		}
+	}
+	return "", fmt.Errorf("no erae file for epoch %d", epoch)
+}
+
+// openMerged opens an erae file at a certain epoch.
+func openMerged(ctx *cli.Context, epoch uint64) (*erae.Era, error) {
+	name, err := findMerged(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	return erae.Open(name)
+}
+
+// verify checks each era1 and erae file in a directory to ensure it is
+// well-formed and that the accumulator matches the expected value. The roots of
+// the erae files are expected to follow the ones of the era1 files.
 func verify(ctx *cli.Context) error {
 	if ctx.Args().Len() != 1 {
 		return errors.New("missing accumulators file")
@@ -202,15 +282,21 @@ func verify(ctx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("error reading %s: %w", dir, err)
 	}
-
-	if len(entries) != len(roots) {
-		return errors.New("number of era1 files should match the number of accumulator hashes")
+	merged, err := erae.ReadDir(dir, network)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", dir, err)
+	}
+	if len(entries)+len(merged) != len(roots) {
+		return errors.New("number of era1 and erae files should match the number of accumulator hashes")
 	}
 
 	// Verify each epoch matches the expected root.
 	for i, want := range roots {
 		// Wrap in function so defers don't stack.
 		err := func() error {
+			if i >= len(entries) {
+				return verifyMerged(filepath.Join(dir, merged[i-len(entries)]), want)
+			}
 			name := entries[i]
 			e, err := era.Open(filepath.Join(dir, name))
 			if err != nil {
@@ -227,21 +313,42 @@ func verify(ctx *cli.Context) error {
 			if err := checkAccumulator(e); err != nil {
 				return fmt.Errorf("error verify era1 file %s: %w", name, err)
 			}
-			// Give the user some feedback that something is happening.
-			if time.Since(reported) >= 8*time.Second {
-				fmt.Printf("Verifying Era1 files \t\t verified=%d,\t elapsed=%s\n", i, common.PrettyDuration(time.Since(start)))
-				reported = time.Now()
-			}
 			return nil
 		}()
 		if err != nil {
 			return err
 		}
+		// Give the user some feedback that something is happening.
+		if time.Since(reported) >= 8*time.Second {
+			fmt.Printf("Verifying Era files \t\t verified=%d,\t elapsed=%s\n", i, common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
 	}
 
 	return nil
 }
 
+// verifyMerged checks an erae file is well-formed and that its accumulator
+// matches the expected value.
+func verifyMerged(name string, want common.Hash) error {
+	e, err := erae.Open(name)
+	if err != nil {
+		return fmt.Errorf("error opening erae file %s: %w", name, err)
+	}
+	defer e.Close()
+	// Read accumulator and check against expected.
+	if got, err := e.Accumulator(); err != nil {
+		return fmt.Errorf("error retrieving accumulator for %s: %w", name, err)
+	} else if got != want {
+		return fmt.Errorf("invalid root %s: got %s, want %s", name, got, want)
+	}
+	// Recompute accumulator.
+	if err := checkMergedAccumulator(e); err != nil {
+		return fmt.Errorf("error verify erae file %s: %w", name, err)
+	}
+	return nil
+}
+
 // checkAccumulator verifies the accumulator matches the data in the Era.
 func checkAccumulator(e *era.Era) error {
 	var (
@@ -305,6 +412,94 @@ func checkAccumulator(e *era.Era) error {
 	return nil
 }
 
+// checkMergedAccumulator verifies the accumulator and beacon proofs match the
+// data in the EraE.
+func checkMergedAccumulator(e *erae.Era) error {
+	var (
+		err    error
+		want   common.Hash
+		prev   *types.Block
+		proof  *erae.BlockProof
+		hashes = make([]common.Hash, 0)
+		roots  = make([]common.Hash, 0)
+	)
+	if want, err = e.Accumulator(); err != nil {
+		return fmt.Errorf("error reading accumulator: %w", err)
+	}
+	it, err := erae.NewIterator(e)
+	if err != nil {
+		return fmt.Errorf("error making era iterator: %w", err)
+	}
+	// To fully verify an erae the following attributes must be checked:
+	//   1) the block index is constructed correctly
+	//   2) the tx root matches the value in the block
+	//   3) the receipts root matches the value in the block
+	//   4) the blocks are post-merge and form a chain
+	//   5) the beacon roots match the ones committed to by the next blocks, and
+	//      the branches prove the block hashes against all known beacon roots
+	//   6) the accumulator is correct by recomputing it locally, which verifies
+	//      the blocks are all correct (via hash)
+	//
+	// The attributes 1) to 5) are checked for each block. 6) requires
+	// accumulation across the entire set and is verified at the end.
+	for it.Next() {
+		// 1) next() walks the block index, so we're able to implicitly verify it.
+		if it.Error() != nil {
+			return fmt.Errorf("error reading block %d: %w", it.Number(), it.Error())
+		}
+		block, receipts, err := it.BlockAndReceipts()
+		if err != nil {
+			return fmt.Errorf("error reading block %d: %w", it.Number(), err)
+		}
+		// 2) recompute tx root and verify against header.
+		tr := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil))
+		if tr != block.TxHash() {
+			return fmt.Errorf("tx root in block %d mismatch: want %s, got %s", block.NumberU64(), block.TxHash(), tr)
+		}
+		// 3) recompute receipt root and check value against block.
+		rr := types.DeriveSha(receipts, trie.NewStackTrie(nil))
+		if rr != block.ReceiptHash() {
+			return fmt.Errorf("receipt root in block %d mismatch: want %s, got %s", block.NumberU64(), block.ReceiptHash(), rr)
+		}
+		// 4) check the block is post-merge and extends the previous one.
+		if block.Difficulty().Sign() != 0 {
+			return fmt.Errorf("block %d is not a post-merge block", block.NumberU64())
+		}
+		if prev != nil && block.ParentHash() != prev.Hash() {
+			return fmt.Errorf("block %d parent hash mismatch: want %s, got %s", block.NumberU64(), prev.Hash(), block.ParentHash())
+		}
+		// 5) check the previous beacon root against the one committed to by
+		// this block, and the branch of this block against its beacon root.
+		// Blocks without a known beacon root carry no branch.
+		if proof != nil && proof.BeaconRoot != (common.Hash{}) && block.BeaconRoot() != nil && *block.BeaconRoot() != proof.BeaconRoot {
+			return fmt.Errorf("beacon root of block %d mismatch: want %s, got %s", prev.NumberU64(), *block.BeaconRoot(), proof.BeaconRoot)
+		}
+		if proof, err = it.Proof(); err != nil {
+			return fmt.Errorf("error reading proof %d: %w", it.Number(), err)
+		}
+		if proof.BeaconRoot != (common.Hash{}) {
+			if err := proof.Verify(block.Hash()); err != nil {
+				return fmt.Errorf("block %d: %w", block.NumberU64(), err)
+			}
+		}
+		hashes = append(hashes, block.Hash())
+		roots = append(roots, proof.BeaconRoot)
+		prev = block
+	}
+	if it.Error() != nil {
+		return fmt.Errorf("error reading block %d: %w", it.Number(), it.Error())
+	}
+	// 6) Verify accumulator.
+	got, err := erae.ComputeAccumulator(hashes, roots)
+	if err != nil {
+		return fmt.Errorf("error computing accumulator: %w", err)
+	}
+	if got != want {
+		return fmt.Errorf("expected accumulator root does not match calculated: got %s, want %s", got, want)
+	}
+	return nil
+}
+
 // readHashes reads a file of newline-delimited hashes.
 func readHashes(f string) ([]common.Hash, error) {
 	b, err := os.ReadFile(f)
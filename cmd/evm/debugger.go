@@ -0,0 +1,622 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/holiman/uint256"
+)
+
+// errDebuggerReplay is thrown from within the tracing hooks to abort the current
+// execution when the user steps backwards. It never leaves the debugger.
+var errDebuggerReplay = errors.New("replay execution")
+
+// debugMode determines at which opcode the debugger pauses next.
+type debugMode int
+
+const (
+	modeStep     debugMode = iota // pause after a number of opcodes
+	modeContinue                  // pause at the next breakpoint
+	modeNext                      // pause at the next opcode of the current or a parent frame
+	modeOut                       // pause at the next opcode of a parent frame
+	modeDetach                    // never pause again
+)
+
+// breakpoint pauses the execution at a program counter, an opcode, or when
+// entering a call depth. Program counter breakpoints may be restricted to the
+// code executing at a single address.
+type breakpoint struct {
+	id    int
+	pc    *uint64
+	addr  *common.Address
+	op    *vm.OpCode
+	depth *int
+}
+
+func (b *breakpoint) String() string {
+	switch {
+	case b.pc != nil && b.addr != nil:
+		return fmt.Sprintf("#%d: pc %d at %v", b.id, *b.pc, *b.addr)
+	case b.pc != nil:
+		return fmt.Sprintf("#%d: pc %d", b.id, *b.pc)
+	case b.op != nil:
+		return fmt.Sprintf("#%d: op %v", b.id, *b.op)
+	default:
+		return fmt.Sprintf("#%d: depth %d", b.id, *b.depth)
+	}
+}
+
+// debugStep is the execution context the debugger is paused at.
+type debugStep struct {
+	pc    uint64
+	op    vm.OpCode
+	gas   uint64
+	cost  uint64
+	depth int
+	err   error
+	scope tracing.OpContext
+	rData []byte
+}
+
+// debugger is an interactive step debugger, pausing the execution in the opcode
+// hook and reading commands from its input until execution is resumed.
+//
+// Since executions are deterministic, stepping backwards is implemented by
+// aborting the current run and replaying it from the start up to the wanted
+// step.
+type debugger struct {
+	in  *bufio.Scanner
+	out io.Writer
+
+	breaks []*breakpoint
+	nextID int
+
+	// Settings of the current run, reset before each replay
+	statedb   tracing.StateDB
+	step      int                                         // Number of opcodes seen in this run
+	lastDepth int                                         // Call depth of the previous opcode
+	slots     map[common.Address]map[common.Hash]struct{} // Storage slots accessed so far
+	tslots    map[common.Address]map[common.Hash]struct{} // Transient storage slots accessed so far
+
+	// Settings persisting across replays
+	mode   debugMode
+	count  int // Remaining opcodes to step over in modeStep
+	frame  int // Call depth modeNext and modeOut are relative to
+	target int // Step to pause at while replaying, zero if not replaying
+}
+
+// newDebugger creates a debugger reading commands from in, and writing its
+// output to out. It starts paused at the first opcode.
+func newDebugger(in io.Reader, out io.Writer) *debugger {
+	return &debugger{
+		in:    bufio.NewScanner(in),
+		out:   out,
+		mode:  modeStep,
+		count: 1,
+	}
+}
+
+// Hooks returns the tracing hooks driving the debugger.
+func (d *debugger) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnTxStart: d.onTxStart,
+		OnOpcode:  d.onOpcode,
+		OnFault:   d.onFault,
+	}
+}
+
+// wrap runs the given execution under the debugger, replaying it whenever the
+// user steps backwards.
+func (d *debugger) wrap(exec func() ([]byte, uint64, error)) func() ([]byte, uint64, error) {
+	return func() (output []byte, gasUsed uint64, err error) {
+		for {
+			var replay bool
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						if r != errDebuggerReplay {
+							panic(r)
+						}
+						replay = true
+					}
+				}()
+				d.reset()
+				output, gasUsed, err = exec()
+			}()
+			if replay {
+				continue
+			}
+			if d.mode == modeDetach || !d.finish(output, err) {
+				return output, gasUsed, err
+			}
+		}
+	}
+}
+
+// reset clears the state collected during a previous run.
+func (d *debugger) reset() {
+	d.statedb = nil
+	d.step = 0
+	d.lastDepth = 0
+	d.slots = make(map[common.Address]map[common.Hash]struct{})
+	d.tslots = make(map[common.Address]map[common.Hash]struct{})
+}
+
+func (d *debugger) onTxStart(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	d.statedb = env.StateDB
+}
+
+func (d *debugger) onOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	d.step++
+	d.track(vm.OpCode(op), scope)
+
+	entered := depth != d.lastDepth
+	d.lastDepth = depth
+
+	if d.target != 0 {
+		if d.step < d.target {
+			return
+		}
+		d.target = 0
+	} else if !d.shouldPause(pc, vm.OpCode(op), scope.Address(), depth, entered) {
+		return
+	}
+	d.pause(&debugStep{
+		pc:    pc,
+		op:    vm.OpCode(op),
+		gas:   gas,
+		cost:  cost,
+		depth: depth,
+		err:   err,
+		scope: scope,
+		rData: rData,
+	})
+}
+
+func (d *debugger) onFault(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, depth int, err error) {
+	if d.mode != modeDetach && d.target == 0 {
+		fmt.Fprintf(d.out, "fault at depth %d pc %d (%v): %v\n", depth, pc, vm.OpCode(op), err)
+	}
+}
+
+// track records the storage slots accessed by the given opcode, so they can be
+// listed when inspecting the storage.
+func (d *debugger) track(op vm.OpCode, scope tracing.OpContext) {
+	var slots map[common.Address]map[common.Hash]struct{}
+	switch op {
+	case vm.SLOAD, vm.SSTORE:
+		slots = d.slots
+	case vm.TLOAD, vm.TSTORE:
+		slots = d.tslots
+	default:
+		return
+	}
+	stack := scope.StackData()
+	if len(stack) == 0 {
+		return
+	}
+	addr := scope.Address()
+	if slots[addr] == nil {
+		slots[addr] = make(map[common.Hash]struct{})
+	}
+	slots[addr][stack[len(stack)-1].Bytes32()] = struct{}{}
+}
+
+// shouldPause reports whether the execution should be paused at the given
+// opcode, according to the current mode and breakpoints.
+func (d *debugger) shouldPause(pc uint64, op vm.OpCode, addr common.Address, depth int, entered bool) bool {
+	switch d.mode {
+	case modeDetach:
+		return false
+	case modeStep:
+		d.count--
+		return d.count <= 0
+	case modeNext:
+		if depth <= d.frame {
+			return true
+		}
+	case modeOut:
+		if depth < d.frame {
+			return true
+		}
+	}
+	for _, b := range d.breaks {
+		switch {
+		case b.pc != nil && *b.pc == pc && (b.addr == nil || *b.addr == addr):
+			return true
+		case b.op != nil && *b.op == op:
+			return true
+		case b.depth != nil && *b.depth == depth && entered:
+			return true
+		}
+	}
+	return false
+}
+
+// pause prints the current position and processes commands until the
+// execution is resumed.
+func (d *debugger) pause(s *debugStep) {
+	d.printStep(s)
+	for {
+		args, ok := d.readCommand()
+		if !ok {
+			d.mode = modeDetach
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		switch args[0] {
+		case "s", "step":
+			n, err := d.parseCount(args)
+			if err != nil {
+				fmt.Fprintln(d.out, err)
+				continue
+			}
+			d.mode, d.count = modeStep, n
+			return
+		case "n", "next":
+			d.mode, d.frame = modeNext, s.depth
+			return
+		case "o", "out":
+			d.mode, d.frame = modeOut, s.depth
+			return
+		case "c", "continue":
+			d.mode = modeContinue
+			return
+		case "q", "quit":
+			d.mode = modeDetach
+			return
+		case "b", "back":
+			n, err := d.parseCount(args)
+			if err != nil {
+				fmt.Fprintln(d.out, err)
+				continue
+			}
+			if d.step == 1 {
+				fmt.Fprintln(d.out, "already at the first step")
+				continue
+			}
+			d.target = max(d.step-n, 1)
+			d.mode, d.count = modeStep, 1
+			panic(errDebuggerReplay)
+		case "stack":
+			d.printStack(s.scope.StackData())
+		case "mem", "memory":
+			d.printMemory(s.scope.MemoryData())
+		case "st", "storage":
+			d.printStorage(args, s.scope.Address(), d.slots, d.statedb.GetState)
+		case "ts", "tstorage":
+			d.printStorage(args, s.scope.Address(), d.tslots, d.statedb.GetTransientState)
+		case "rd", "returndata":
+			fmt.Fprintf(d.out, "%#x\n", s.rData)
+		case "code":
+			d.printCode(s.scope.ContractCode(), s.pc)
+		case "info":
+			d.printStep(s)
+			fmt.Fprintf(d.out, "address %v caller %v value %v input %#x\n", s.scope.Address(), s.scope.Caller(), s.scope.CallValue(), s.scope.CallInput())
+		default:
+			if !d.command(args) {
+				fmt.Fprintf(d.out, "unknown command %q, type 'help' for a list of commands\n", args[0])
+			}
+		}
+	}
+}
+
+// finish is called when the execution ends. It reports the result and lets the
+// user step back into the execution, in which case it returns true.
+func (d *debugger) finish(output []byte, err error) bool {
+	fmt.Fprintf(d.out, "execution finished after %d steps, output %#x", d.step, output)
+	if err != nil {
+		fmt.Fprintf(d.out, ", error: %v", err)
+	}
+	fmt.Fprintln(d.out)
+
+	for {
+		args, ok := d.readCommand()
+		if !ok || len(args) == 0 {
+			return false
+		}
+		switch args[0] {
+		case "b", "back":
+			n, err := d.parseCount(args)
+			if err != nil {
+				fmt.Fprintln(d.out, err)
+				continue
+			}
+			if d.step == 0 {
+				fmt.Fprintln(d.out, "no steps executed")
+				continue
+			}
+			d.target = max(d.step+1-n, 1)
+			d.mode, d.count = modeStep, 1
+			return true
+		case "s", "step", "n", "next", "o", "out", "c", "continue", "q", "quit":
+			return false
+		default:
+			if !d.command(args) {
+				fmt.Fprintln(d.out, "execution finished, use 'back' to step backwards or 'quit' to exit")
+			}
+		}
+	}
+}
+
+// command handles the commands which are independent of the execution
+// position, returning false for unknown commands.
+func (d *debugger) command(args []string) bool {
+	switch args[0] {
+	case "break":
+		b, err := d.parseBreakpoint(args[1:])
+		if err != nil {
+			fmt.Fprintln(d.out, err)
+			return true
+		}
+		d.breaks = append(d.breaks, b)
+		fmt.Fprintf(d.out, "breakpoint %v\n", b)
+	case "breaks":
+		for _, b := range d.breaks {
+			fmt.Fprintln(d.out, b)
+		}
+	case "delete":
+		if len(args) != 2 {
+			fmt.Fprintln(d.out, "usage: delete <id>")
+			return true
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(args[1], "#"))
+		if err != nil {
+			fmt.Fprintf(d.out, "invalid breakpoint id %q\n", args[1])
+			return true
+		}
+		n := len(d.breaks)
+		d.breaks = slices.DeleteFunc(d.breaks, func(b *breakpoint) bool { return b.id == id })
+		if len(d.breaks) == n {
+			fmt.Fprintf(d.out, "no breakpoint #%d\n", id)
+		}
+	case "h", "help":
+		fmt.Fprint(d.out, debuggerHelp)
+	default:
+		return false
+	}
+	return true
+}
+
+const debuggerHelp = `Execution:
+  s, step [n]          execute the next n opcodes (default 1)
+  n, next              step over calls made by the current opcode
+  o, out               run until the current call frame returns
+  c, continue          run until the next breakpoint
+  b, back [n]          step back n opcodes (default 1) by replaying the execution
+  q, quit              run to completion without pausing
+Inspection:
+  info                 show the current position and call context
+  stack                show the stack, top first
+  mem, memory          show the memory
+  st, storage [slot]   show the accessed storage slots, or the given one
+  ts, tstorage [slot]  show the accessed transient storage slots, or the given one
+  rd, returndata       show the return data of the last call
+  code                 show the disassembled code around the current position
+Breakpoints:
+  break pc <pc> [addr] pause at the given program counter, optionally only in
+                       the code executing at the given address
+  break op <opcode>    pause at the given opcode, e.g. SSTORE
+  break depth <depth>  pause when entering the given call depth
+  breaks               list the breakpoints
+  delete <id>          delete a breakpoint
+`
+
+// readCommand prompts for the next command, returning false if the input is
+// exhausted.
+func (d *debugger) readCommand() ([]string, bool) {
+	fmt.Fprint(d.out, "debug> ")
+	if !d.in.Scan() {
+		fmt.Fprintln(d.out)
+		return nil, false
+	}
+	return strings.Fields(d.in.Text()), true
+}
+
+// parseCount parses the optional repetition count of a command.
+func (d *debugger) parseCount(args []string) (int, error) {
+	if len(args) < 2 {
+		return 1, nil
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid count %q", args[1])
+	}
+	return n, nil
+}
+
+// parseBreakpoint parses the arguments of the break command.
+func (d *debugger) parseBreakpoint(args []string) (*breakpoint, error) {
+	if len(args) != 2 && (len(args) != 3 || args[0] != "pc") {
+		return nil, errors.New("usage: break pc <pc> [address] | op <opcode> | depth <depth>")
+	}
+	b := &breakpoint{id: d.nextID + 1}
+	switch args[0] {
+	case "pc":
+		pc, err := strconv.ParseUint(args[1], 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pc %q", args[1])
+		}
+		b.pc = &pc
+		if len(args) == 3 {
+			if !common.IsHexAddress(args[2]) {
+				return nil, fmt.Errorf("invalid address %q", args[2])
+			}
+			addr := common.HexToAddress(args[2])
+			b.addr = &addr
+		}
+	case "op":
+		name := strings.ToUpper(args[1])
+		op := vm.StringToOp(name)
+		if op.String() != name {
+			return nil, fmt.Errorf("unknown opcode %q", args[1])
+		}
+		b.op = &op
+	case "depth":
+		depth, err := strconv.Atoi(args[1])
+		if err != nil || depth < 1 {
+			return nil, fmt.Errorf("invalid depth %q", args[1])
+		}
+		b.depth = &depth
+	default:
+		return nil, fmt.Errorf("unknown breakpoint kind %q", args[0])
+	}
+	d.nextID++
+	return b, nil
+}
+
+func (d *debugger) printStep(s *debugStep) {
+	fmt.Fprintf(d.out, "[step %d] depth %d pc %d %v gas %d cost %d", d.step, s.depth, s.pc, s.op, s.gas, s.cost)
+	if s.err != nil {
+		fmt.Fprintf(d.out, " error: %v", s.err)
+	}
+	fmt.Fprintln(d.out)
+}
+
+func (d *debugger) printStack(stack []uint256.Int) {
+	if len(stack) == 0 {
+		fmt.Fprintln(d.out, "empty stack")
+		return
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		fmt.Fprintf(d.out, "%4d: %#x\n", len(stack)-1-i, &stack[i])
+	}
+}
+
+func (d *debugger) printMemory(mem []byte) {
+	if len(mem) == 0 {
+		fmt.Fprintln(d.out, "empty memory")
+		return
+	}
+	for i := 0; i < len(mem); i += 32 {
+		fmt.Fprintf(d.out, "%#06x: %x\n", i, mem[i:min(i+32, len(mem))])
+	}
+}
+
+func (d *debugger) printStorage(args []string, addr common.Address, accessed map[common.Address]map[common.Hash]struct{}, get func(common.Address, common.Hash) common.Hash) {
+	if d.statedb == nil {
+		fmt.Fprintln(d.out, "state not available")
+		return
+	}
+	if len(args) > 1 {
+		slot, err := uint256.FromHex(args[1])
+		if err != nil {
+			if slot, err = uint256.FromDecimal(args[1]); err != nil {
+				fmt.Fprintf(d.out, "invalid slot %q\n", args[1])
+				return
+			}
+		}
+		key := common.Hash(slot.Bytes32())
+		fmt.Fprintf(d.out, "%v: %v\n", key, get(addr, key))
+		return
+	}
+	keys := make([]common.Hash, 0, len(accessed[addr]))
+	for key := range accessed[addr] {
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		fmt.Fprintln(d.out, "no slots accessed")
+		return
+	}
+	slices.SortFunc(keys, common.Hash.Cmp)
+	for _, key := range keys {
+		fmt.Fprintf(d.out, "%v: %v\n", key, get(addr, key))
+	}
+}
+
+// printCode prints the disassembled code around the given program counter. EOF
+// containers are listed by code section, as their program counters are
+// relative to the start of a section.
+func (d *debugger) printCode(code []byte, pc uint64) {
+	if len(code) > 2 && code[0] == 0xef && code[1] == 0x00 {
+		var c vm.Container
+		if err := c.UnmarshalBinary(code, false); err == nil {
+			for i, section := range c.CodeSections() {
+				fmt.Fprintf(d.out, "code section %d:\n", i)
+				d.printInstructions(disassemble(section, true), nil)
+			}
+			return
+		}
+	}
+	d.printInstructions(disassemble(code, false), &pc)
+}
+
+// printInstructions prints the instructions around the given program counter,
+// or all of them if it's nil.
+func (d *debugger) printInstructions(ins []instruction, pc *uint64) {
+	start, end := 0, len(ins)
+	if pc != nil {
+		idx := slices.IndexFunc(ins, func(in instruction) bool { return in.pc >= *pc })
+		if idx < 0 {
+			idx = len(ins)
+		}
+		start, end = max(idx-5, 0), min(idx+6, len(ins))
+	}
+	for _, in := range ins[start:end] {
+		marker := "  "
+		if pc != nil && in.pc == *pc {
+			marker = "=>"
+		}
+		if len(in.arg) > 0 {
+			fmt.Fprintf(d.out, "%s %5d: %v %#x\n", marker, in.pc, in.op, in.arg)
+		} else {
+			fmt.Fprintf(d.out, "%s %5d: %v\n", marker, in.pc, in.op)
+		}
+	}
+}
+
+// instruction is a disassembled opcode along with its immediate argument.
+type instruction struct {
+	pc  uint64
+	op  vm.OpCode
+	arg []byte
+}
+
+// disassemble splits the given code into instructions. EOF code sections have
+// immediate arguments for more opcodes than legacy code.
+func disassemble(code []byte, eof bool) []instruction {
+	var ins []instruction
+	for pc := 0; pc < len(code); {
+		op := vm.OpCode(code[pc])
+		size := 0
+		switch {
+		case eof && op == vm.RJUMPV && pc+1 < len(code):
+			size = 1 + 2*(int(code[pc+1])+1)
+		case eof:
+			size = vm.Immediates(op)
+		case op.IsPush():
+			size = int(op - vm.PUSH0)
+		}
+		end := min(pc+1+size, len(code))
+		ins = append(ins, instruction{pc: uint64(pc), op: op, arg: code[pc+1 : end]})
+		pc = end
+	}
+	return ins
+}
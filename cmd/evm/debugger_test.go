@@ -0,0 +1,135 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/program"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+)
+
+// runDebugger executes the caller contract, which calls into the callee, under
+// a debugger driven by the given commands.
+func runDebugger(t *testing.T, commands ...string) (string, []byte) {
+	t.Helper()
+
+	var (
+		caller = common.HexToAddress("0xc0")
+		callee = common.HexToAddress("0xc1")
+		out    = new(bytes.Buffer)
+		dbg    = newDebugger(strings.NewReader(strings.Join(commands, "\n")), out)
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.SetCode(caller, program.New().Call(nil, callee, 0, 0, 0, 0, 0).Op(vm.STOP).Bytes())
+	statedb.SetCode(callee, program.New().Sstore(1, 2).Tstore(3, 4).Op(vm.STOP).Bytes())
+
+	cfg := &runtime.Config{State: statedb, EVMConfig: vm.Config{Tracer: dbg.Hooks()}}
+	exec := dbg.wrap(func() ([]byte, uint64, error) {
+		cfg.State = statedb.Copy()
+		output, gas, err := runtime.Call(caller, nil, cfg)
+		return output, gas, err
+	})
+	if _, _, err := exec(); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	return out.String(), statedb.GetCode(caller)
+}
+
+func TestDebuggerStepping(t *testing.T) {
+	output, code := runDebugger(t,
+		"break op CALL",
+		"continue", // stop at the call
+		"next",     // step over it
+		"back",     // step back into the callee
+		"storage",
+		"tstorage",
+		"out",
+		"quit",
+	)
+	var (
+		stop    = len(code) - 1
+		expects = []string{
+			"[step 1] depth 1 pc 0",
+			"[step 8] depth 1 pc " + fmt.Sprint(stop-1) + " CALL",
+			"[step 16] depth 1 pc " + fmt.Sprint(stop) + " STOP",
+			"[step 15] depth 2 pc 10 STOP",
+			"0x0000000000000000000000000000000000000000000000000000000000000001: 0x0000000000000000000000000000000000000000000000000000000000000002",
+			"0x0000000000000000000000000000000000000000000000000000000000000003: 0x0000000000000000000000000000000000000000000000000000000000000004",
+			"[step 16] depth 1 pc " + fmt.Sprint(stop) + " STOP",
+		}
+	)
+	for _, want := range expects {
+		idx := strings.Index(output, want)
+		if idx < 0 {
+			t.Fatalf("missing %q in debugger output:\n%s", want, output)
+		}
+		output = output[idx+len(want):]
+	}
+}
+
+func TestDebuggerBreakpoints(t *testing.T) {
+	output, _ := runDebugger(t,
+		"break depth 2",
+		"break pc 5 0x00000000000000000000000000000000000000c1",
+		"continue", // first opcode of the callee
+		"continue", // pc 5 in the callee
+		"back 100", // replays to the first step
+		"delete 1",
+		"continue", // pc 5 in the callee
+		"continue", // execution finishes
+		"back",
+		"quit",
+	)
+	expects := []string{
+		"[step 1] depth 1 pc 0",
+		"breakpoint #1: depth 2",
+		"breakpoint #2: pc 5 at 0x00000000000000000000000000000000000000C1",
+		"[step 9] depth 2 pc 0",
+		"[step 12] depth 2 pc 5",
+		"[step 1] depth 1 pc 0",
+		"[step 12] depth 2 pc 5",
+		"execution finished after 16 steps",
+		"[step 16] depth 1",
+	}
+	for _, want := range expects {
+		idx := strings.Index(output, want)
+		if idx < 0 {
+			t.Fatalf("missing %q in debugger output:\n%s", want, output)
+		}
+		output = output[idx+len(want):]
+	}
+}
+
+func TestDisassembleEOF(t *testing.T) {
+	// RJUMPV with two targets, followed by STOP
+	ins := disassemble(common.FromHex("e2010000000000"), true)
+	if len(ins) != 2 || ins[0].op != vm.RJUMPV || len(ins[0].arg) != 5 || ins[1].pc != 6 || ins[1].op != vm.STOP {
+		t.Fatalf("wrong EOF disassembly: %+v", ins)
+	}
+	// The same bytes as legacy code have no immediates
+	if ins := disassemble(common.FromHex("e2010000000000"), false); len(ins) != 7 {
+		t.Fatalf("wrong legacy disassembly: %+v", ins)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/eth/tracers/sourcemap"
 	"github.com/ethereum/go-ethereum/internal/debug"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/urfave/cli/v2"
@@ -74,6 +75,10 @@ var (
 		Name:  "statdump",
 		Usage: "displays stack and heap memory information",
 	}
+	DebuggerFlag = &cli.BoolFlag{
+		Name:  "debugger",
+		Usage: "step through the execution interactively",
+	}
 
 	// Tracing flags.
 	TraceFlag = &cli.BoolFlag{
@@ -113,6 +118,16 @@ var (
 		Usage:    "enable return data output",
 		Category: traceCategory,
 	}
+	TraceArtifactsFlag = &cli.StringFlag{
+		Name:     "trace.artifacts",
+		Usage:    "File containing the solc standard-json output of the executed contracts, used to annotate struct traces with Solidity sources",
+		Category: traceCategory,
+	}
+	TraceSourcesFlag = &cli.StringFlag{
+		Name:     "trace.sources",
+		Usage:    "File containing the solc standard-json input of the contracts given by --trace.artifacts, used to report source lines",
+		Category: traceCategory,
+	}
 
 	// Deprecated flags.
 	DebugFlag = &cli.BoolFlag{
@@ -193,6 +208,8 @@ var traceFlags = []cli.Flag{
 	TraceDisableMemoryFlag,
 	TraceDisableStorageFlag,
 	TraceDisableReturnDataFlag,
+	TraceArtifactsFlag,
+	TraceSourcesFlag,
 
 	// deprecated
 	DebugFlag,
@@ -238,6 +255,18 @@ func tracerFromFlags(ctx *cli.Context) *tracing.Hooks {
 		DisableStorage:   ctx.Bool(TraceDisableStorageFlag.Name),
 		EnableReturnData: !ctx.Bool(TraceDisableReturnDataFlag.Name),
 	}
+	if ctx.IsSet(TraceArtifactsFlag.Name) {
+		if ctx.Bool(MachineFlag.Name) || (ctx.Bool(TraceFlag.Name) && ctx.String(TraceFormatFlag.Name) != "struct") {
+			fmt.Fprintf(os.Stderr, "--%s requires the struct trace format\n", TraceArtifactsFlag.Name)
+			os.Exit(1)
+		}
+		sources, err := loadArtifacts(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load compiler artifacts: %v\n", err)
+			os.Exit(1)
+		}
+		config.Sources = sources
+	}
 	switch {
 	case ctx.Bool(TraceFlag.Name):
 		switch format := ctx.String(TraceFormatFlag.Name); format {
@@ -262,6 +291,21 @@ func tracerFromFlags(ctx *cli.Context) *tracing.Hooks {
 	}
 }
 
+// loadArtifacts reads the compiler artifacts given by the trace flags.
+func loadArtifacts(ctx *cli.Context) (*sourcemap.Artifacts, error) {
+	output, err := os.ReadFile(ctx.String(TraceArtifactsFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	var input []byte
+	if ctx.IsSet(TraceSourcesFlag.Name) {
+		if input, err = os.ReadFile(ctx.String(TraceSourcesFlag.Name)); err != nil {
+			return nil, err
+		}
+	}
+	return sourcemap.Parse(input, output)
+}
+
 // collectFiles walks the given path. If the path is a directory, it will
 // return a list of all accumulates all files with json extension.
 // Otherwise (if path points to a file), it will return the path.
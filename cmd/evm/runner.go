@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -39,6 +40,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/triedb"
@@ -66,6 +68,9 @@ var runCommand = &cli.Command{
 		ValueFlag,
 		StatDumpFlag,
 		DumpFlag,
+		DebuggerFlag,
+		TracerFlag,
+		TracerConfigFlag,
 	}, traceFlags),
 }
 
@@ -123,6 +128,16 @@ var (
 		Value:    new(big.Int),
 		Category: flags.VMCategory,
 	}
+	TracerFlag = &cli.StringFlag{
+		Name:     "trace.tracer",
+		Usage:    "Native or js tracer to run the execution with (e.g. callTracer or gasProfileTracer), the result is printed to stdout",
+		Category: traceCategory,
+	}
+	TracerConfigFlag = &cli.StringFlag{
+		Name:     "trace.jsonconfig",
+		Usage:    "Configuration of the tracer specified by --trace.tracer, in JSON format",
+		Category: traceCategory,
+	}
 )
 
 // readGenesis will read the given JSON format genesis file and return
@@ -208,6 +223,12 @@ func runCmd(ctx *cli.Context) error {
 		blobBaseFee = new(big.Int) // TODO (MariusVanDerWijden) implement blob fee in state tests
 	)
 	tracer = tracerFromFlags(ctx)
+	if ctx.Bool(DebuggerFlag.Name) && (tracer != nil || ctx.IsSet(TracerFlag.Name) || ctx.Bool(BenchFlag.Name)) {
+		return errors.New("the debugger can't be combined with tracing or benchmarking")
+	}
+	if tracer != nil && ctx.IsSet(TracerFlag.Name) {
+		return fmt.Errorf("--%s can't be combined with opcode tracing", TracerFlag.Name)
+	}
 	initialGas := ctx.Uint64(GasFlag.Name)
 	genesisConfig := new(core.Genesis)
 	genesisConfig.GasLimit = initialGas
@@ -293,6 +314,23 @@ func runCmd(ctx *cli.Context) error {
 	} else {
 		runtimeConfig.ChainConfig = params.AllEthashProtocolChanges
 	}
+	// Set up the custom tracer, which needs the chain config
+	var customTracer *tracers.Tracer
+	if name := ctx.String(TracerFlag.Name); name != "" {
+		var (
+			config = json.RawMessage(ctx.String(TracerConfigFlag.Name))
+			err    error
+		)
+		if len(config) == 0 {
+			config = nil
+		}
+		customTracer, err = tracers.DefaultDirectory.New(name, new(tracers.Context), config, runtimeConfig.ChainConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create tracer %q: %v", name, err)
+		}
+		tracer = customTracer.Hooks
+		runtimeConfig.EVMConfig.Tracer = tracer
+	}
 
 	var hexInput []byte
 	if inputFileFlag := ctx.String(InputFileFlag.Name); inputFileFlag != "" {
@@ -332,6 +370,11 @@ func runCmd(ctx *cli.Context) error {
 		}
 	}
 
+	if ctx.Bool(DebuggerFlag.Name) {
+		debugger := newDebugger(os.Stdin, os.Stdout)
+		runtimeConfig.EVMConfig.Tracer = debugger.Hooks()
+		execFunc = debugger.wrap(execFunc)
+	}
 	bench := ctx.Bool(BenchFlag.Name)
 	output, stats, err := timedExec(bench, execFunc)
 
@@ -363,6 +406,13 @@ allocations:     %d
 allocated bytes: %d
 `, stats.GasUsed, stats.Time, stats.Allocs, stats.BytesAllocated)
 	}
+	if customTracer != nil {
+		result, err := customTracer.GetResult()
+		if err != nil {
+			return fmt.Errorf("failed to retrieve trace result: %v", err)
+		}
+		fmt.Println(string(result))
+	}
 	if tracer == nil {
 		fmt.Printf("%#x\n", output)
 		if err != nil {
@@ -39,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/debug"
 	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/erae"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
@@ -145,7 +146,8 @@ be gzipped.`,
 		Flags:     slices.Concat([]cli.Flag{utils.TxLookupLimitFlag, utils.TransactionHistoryFlag}, utils.DatabaseFlags, utils.NetworkFlags),
 		Description: `
 The import-history command will import blocks and their corresponding receipts
-from Era archives.
+from Era archives. Pre-merge history is read from Era1 files, and post-merge
+history from EraE files.
 `,
 	}
 	exportHistoryCommand = &cli.Command{
@@ -153,10 +155,14 @@ from Era archives.
 		Name:      "export-history",
 		Usage:     "Export blockchain history to Era archives",
 		ArgsUsage: "<dir> <first> <last>",
-		Flags:     utils.DatabaseFlags,
+		Flags:     slices.Concat([]cli.Flag{utils.BeaconApiFlag, utils.BeaconApiHeaderFlag}, utils.DatabaseFlags),
 		Description: `
 The export-history command will export blocks and their corresponding receipts
-into Era archives. Eras are typically packaged in steps of 8192 blocks.
+into Era archives. Eras are typically packaged in steps of 8192 blocks. Pre-merge
+blocks are exported into Era1 files, and post-merge blocks into EraE files.
+The EraE files tie each block to the beacon block which included it by a merkle
+proof, built from the beacon blocks served by the --beacon.api endpoint. Without
+it, the proofs are omitted.
 `,
 	}
 	importPreimagesCommand = &cli.Command{
@@ -448,12 +454,16 @@ func importHistory(ctx *cli.Context) error {
 			if err != nil {
 				return fmt.Errorf("error reading %s: %w", dir, err)
 			}
-			if len(entries) > 0 {
+			merged, err := erae.ReadDir(dir, n)
+			if err != nil {
+				return fmt.Errorf("error reading %s: %w", dir, err)
+			}
+			if len(entries) > 0 || len(merged) > 0 {
 				networks = append(networks, n)
 			}
 		}
 		if len(networks) == 0 {
-			return fmt.Errorf("no era1 files found in %s", dir)
+			return fmt.Errorf("no era1 or erae files found in %s", dir)
 		}
 		if len(networks) > 1 {
 			return errors.New("multiple networks found, use a network flag to specify desired network")
@@ -495,7 +505,8 @@ func exportHistory(ctx *cli.Context) error {
 	if head := chain.CurrentSnapBlock(); uint64(last) > head.Number.Uint64() {
 		utils.Fatalf("Export error: block number %d larger than head block %d\n", uint64(last), head.Number.Uint64())
 	}
-	err := utils.ExportHistory(chain, dir, uint64(first), uint64(last), uint64(era.MaxEra1Size))
+	beacon := utils.MakeBeaconBlockSource(ctx)
+	err := utils.ExportHistory(chain, dir, uint64(first), uint64(last), uint64(era.MaxEra1Size), beacon)
 	if err != nil {
 		utils.Fatalf("Export error: %v\n", err)
 	}
@@ -195,6 +195,12 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 	// Start metrics export if enabled
 	utils.SetupMetrics(&cfg.Metrics)
 
+	// In stateless mode, no chain is maintained locally, only the remote head
+	// is verified.
+	if ctx.Bool(utils.StatelessFlag.Name) {
+		utils.RegisterStatelessVerifier(ctx, stack, &cfg.Eth)
+		return stack
+	}
 	backend, eth := utils.RegisterEthService(stack, &cfg.Eth)
 
 	// Create gauge with geth system and build information
@@ -222,6 +228,10 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 	if cfg.Ethstats.URL != "" {
 		utils.RegisterEthStatsService(stack, backend, cfg.Ethstats.URL)
 	}
+	// Expose the chain database to remote clients if requested
+	if ctx.Bool(utils.RemoteDBServeFlag.Name) {
+		utils.RegisterRemoteDBService(stack, eth.ChainDb())
+	}
 	// Configure full-sync tester service if requested
 	if ctx.IsSet(utils.SyncTargetFlag.Name) {
 		hex := hexutil.MustDecode(ctx.String(utils.SyncTargetFlag.Name))
@@ -185,7 +185,7 @@ func TestCustomBackend(t *testing.T) {
 		},
 		{ // Reject invalid backend choice
 			initArgs:   []string{"--db.engine", "mssql"},
-			initExpect: `Fatal: Invalid choice for db.engine 'mssql', allowed 'leveldb' or 'pebble'`,
+			initExpect: `Fatal: Invalid choice for db.engine 'mssql', allowed 'leveldb', 'pebble' or 'bbolt'`,
 			// Since the init fails, this will return the (default) mainnet genesis
 			// block nonce
 			execExpect: `0x0000000000000042`,
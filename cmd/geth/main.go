@@ -57,6 +57,7 @@ var (
 		utils.PasswordFileFlag,
 		utils.BootnodesFlag,
 		utils.MinFreeDiskSpaceFlag,
+		utils.EraFlag,
 		utils.KeyStoreDirFlag,
 		utils.ExternalSignerFlag,
 		utils.NoUSBFlag, // deprecated
@@ -69,6 +70,9 @@ var (
 		utils.TxPoolNoLocalsFlag,
 		utils.TxPoolJournalFlag,
 		utils.TxPoolRejournalFlag,
+		utils.TxPoolSnapshotFlag,
+		utils.TxPoolSnapshotIntervalFlag,
+		utils.TxPoolSnapshotSizeFlag,
 		utils.TxPoolPriceLimitFlag,
 		utils.TxPoolPriceBumpFlag,
 		utils.TxPoolAccountSlotsFlag,
@@ -81,6 +85,8 @@ var (
 		utils.BlobPoolPriceBumpFlag,
 		utils.SyncModeFlag,
 		utils.SyncTargetFlag,
+		utils.StatelessFlag,
+		utils.StatelessEndpointFlag,
 		utils.ExitWhenSyncedFlag,
 		utils.GCModeFlag,
 		utils.SnapshotFlag,
@@ -168,6 +174,7 @@ var (
 		utils.AuthPortFlag,
 		utils.AuthVirtualHostsFlag,
 		utils.JWTSecretFlag,
+		utils.RemoteDBServeFlag,
 		utils.HTTPVirtualHostsFlag,
 		utils.GraphQLEnabledFlag,
 		utils.GraphQLCORSDomainFlag,
@@ -189,6 +196,16 @@ var (
 		utils.AllowUnprotectedTxs,
 		utils.BatchRequestLimit,
 		utils.BatchResponseMaxSize,
+		utils.RPCRateLimitFlag,
+		utils.RPCRateLimitCostFlag,
+		utils.RPCRateLimitHeaderFlag,
+		utils.RPCRateLimitClientsFlag,
+		utils.RPCAccessFileFlag,
+		utils.RPCAccessLogFlag,
+		utils.RPCAccessLogMaxSizeFlag,
+		utils.RPCAccessLogMaxBackupsFlag,
+		utils.RPCAccessLogMaxAgeFlag,
+		utils.RPCAccessLogCompressFlag,
 	}
 
 	metricsFlags = []cli.Flag{
@@ -247,6 +264,8 @@ func init() {
 		snapshotCommand,
 		// See verkle.go
 		verkleCommand,
+		// See witnesscmd.go
+		witnessCommand,
 	}
 	if logTestCommand != nil {
 		app.Commands = append(app.Commands, logTestCommand)
@@ -0,0 +1,237 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	witnessCompressionFlag = &cli.StringFlag{
+		Name:  "compression",
+		Usage: "Compression of the exported compact witnesses (none, snappy, zstd)",
+		Value: "snappy",
+	}
+	witnessOutputFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "File to export the compact witness stream into",
+	}
+
+	witnessCommand = &cli.Command{
+		Name:  "witness",
+		Usage: "A set of commands operating on execution witnesses",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "stats",
+				Usage:     "Generate the witnesses of a block range and report their sizes",
+				ArgsUsage: "<first> <last>",
+				Action:    witnessStats,
+				Flags: slices.Concat([]cli.Flag{
+					witnessCompressionFlag,
+					witnessOutputFlag,
+				}, utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth witness stats <first> <last>
+This command re-executes the blocks in the given range on top of their locally
+available parent states, and reports the size distribution of their witnesses
+in the RLP encoding and in the compact encoding with each compression. The raw
+size is the total size of the witness contents, without any encoding overhead.
+
+The compact encoding deduplicates items across the witnesses of the range, so
+the reported sizes depend on the range processed. If --output is given, the
+compact witness stream is exported into the file, using the compression chosen
+by --compression.
+`,
+			},
+		},
+	}
+)
+
+// witnessEncoding tracks the per-block sizes of the witnesses in an encoding.
+type witnessEncoding struct {
+	name    string
+	encoder *stateless.WitnessEncoder // Nil for non-compact encodings
+	sizes   []int
+}
+
+func witnessStats(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return errors.New("need <first> and <last> block numbers as arguments")
+	}
+	first, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid first block number: %v", err)
+	}
+	last, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid last block number: %v", err)
+	}
+	if first == 0 || first > last {
+		return fmt.Errorf("invalid block range %d..%d", first, last)
+	}
+	export, err := stateless.ParseCompression(ctx.String(witnessCompressionFlag.Name))
+	if err != nil {
+		return err
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack, true)
+	defer db.Close()
+
+	// Set up the exported file, if requested
+	var output *bufio.Writer
+	if path := ctx.String(witnessOutputFlag.Name); path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		output = bufio.NewWriter(file)
+	}
+	// Set up the measured encodings, skipping compressions not supported by
+	// the current build
+	var (
+		raw       = &witnessEncoding{name: "raw"}
+		plain     = &witnessEncoding{name: "rlp"}
+		encodings = []*witnessEncoding{raw, plain}
+	)
+	for _, compression := range []stateless.Compression{stateless.NoCompression, stateless.SnappyCompression, stateless.ZstdCompression} {
+		var w io.Writer = io.Discard
+		if output != nil && compression == export {
+			w = output
+		}
+		encoder, err := stateless.NewWitnessEncoder(w, compression)
+		if err != nil {
+			if compression == export && output != nil {
+				return err
+			}
+			log.Warn("Skipping unsupported witness compression", "compression", compression, "err", err)
+			continue
+		}
+		encodings = append(encodings, &witnessEncoding{
+			name:    "compact/" + compression.String(),
+			encoder: encoder,
+		})
+	}
+	// Generate the witnesses of the requested range and measure them
+	var (
+		start  = time.Now()
+		logged = time.Now()
+		stats  stateless.Stats
+	)
+	for number := first; number <= last; number++ {
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			return fmt.Errorf("block %d not found", number)
+		}
+		witness, err := chain.GenerateWitness(block)
+		if err != nil {
+			return fmt.Errorf("failed to generate witness of block %d: %v", number, err)
+		}
+		blockStats := witness.Stats()
+		stats.Headers += blockStats.Headers
+		stats.Codes += blockStats.Codes
+		stats.Nodes += blockStats.Nodes
+		raw.sizes = append(raw.sizes, blockStats.Size())
+
+		blob, err := rlp.EncodeToBytes(witness)
+		if err != nil {
+			return err
+		}
+		plain.sizes = append(plain.sizes, len(blob))
+
+		for _, encoding := range encodings {
+			if encoding.encoder == nil {
+				continue
+			}
+			size, err := encoding.encoder.Encode(witness)
+			if err != nil {
+				return fmt.Errorf("failed to encode witness of block %d: %v", number, err)
+			}
+			encoding.sizes = append(encoding.sizes, size)
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Generating witnesses", "number", number, "remaining", last-number, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if output != nil {
+		if err := output.Flush(); err != nil {
+			return err
+		}
+	}
+	blocks := int(last - first + 1)
+	log.Info("Generated witnesses", "blocks", blocks, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	// Report the size distributions of the encodings
+	fmt.Printf("Blocks: %d..%d, average items per witness: %d headers, %d codes, %d nodes\n\n",
+		first, last, stats.Headers/blocks, stats.Codes/blocks, stats.Nodes/blocks)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Encoding", "Total", "Min", "Median", "P90", "P99", "Max", "Ratio"})
+	rlpTotal := total(plain.sizes)
+	for _, encoding := range encodings {
+		sizes := slices.Clone(encoding.sizes)
+		slices.Sort(sizes)
+		sum := total(sizes)
+
+		table.Append([]string{
+			encoding.name,
+			common.StorageSize(sum).String(),
+			common.StorageSize(sizes[0]).String(),
+			common.StorageSize(percentile(sizes, 50)).String(),
+			common.StorageSize(percentile(sizes, 90)).String(),
+			common.StorageSize(percentile(sizes, 99)).String(),
+			common.StorageSize(sizes[len(sizes)-1]).String(),
+			fmt.Sprintf("%.3f", float64(sum)/float64(rlpTotal)),
+		})
+	}
+	table.Render()
+	return nil
+}
+
+// total returns the sum of the sizes.
+func total(sizes []int) int {
+	var sum int
+	for _, size := range sizes {
+		sum += size
+	}
+	return sum
+}
+
+// percentile returns the nearest-rank percentile of the sorted sizes.
+func percentile(sizes []int, p int) int {
+	rank := (len(sizes)*p + 99) / 100
+	return sizes[max(rank, 1)-1]
+}
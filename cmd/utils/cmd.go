@@ -34,6 +34,7 @@ import (
 	"syscall"
 	"time"
 
+	btypes "github.com/ethereum/go-ethereum/beacon/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -44,6 +45,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/debug"
 	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/erae"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
@@ -53,6 +55,7 @@ import (
 
 const (
 	importBatchSize = 2500
+	beaconSlotTime  = 12 // seconds per beacon chain slot
 )
 
 // ErrImportInterrupted is returned when the user interrupts the import process.
@@ -256,6 +259,13 @@ func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 	if err != nil {
 		return fmt.Errorf("error reading %s: %w", dir, err)
 	}
+	// Post-merge history is stored in EraE files, following the Era1 ones.
+	merged, err := erae.ReadDir(dir, network)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", dir, err)
+	}
+	entries = append(entries, merged...)
+
 	checksums, err := readList(filepath.Join(dir, "checksums.txt"))
 	if err != nil {
 		return fmt.Errorf("unable to read checksums.txt: %w", err)
@@ -288,14 +298,10 @@ func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 			h.Reset()
 			buf.Reset()
 
-			// Import all block data from Era1.
-			e, err := era.From(f)
-			if err != nil {
-				return fmt.Errorf("error opening era: %w", err)
-			}
-			it, err := era.NewIterator(e)
+			// Import all block data from the archive.
+			it, err := newHistoryIterator(f, filepath.Ext(filename) == ".erae")
 			if err != nil {
-				return fmt.Errorf("error making era reader: %w", err)
+				return err
 			}
 			for it.Next() {
 				block, err := it.Block()
@@ -321,7 +327,7 @@ func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 					reported = time.Now()
 				}
 			}
-			return nil
+			return it.Error()
 		}()
 		if err != nil {
 			return err
@@ -331,6 +337,40 @@ func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 	return nil
 }
 
+// historyIterator is the common interface of the Era1 and EraE iterators.
+type historyIterator interface {
+	Next() bool
+	Number() uint64
+	Error() error
+	Block() (*types.Block, error)
+	Receipts() (types.Receipts, error)
+}
+
+// newHistoryIterator opens an Era1 or EraE archive and returns an iterator over
+// its blocks.
+func newHistoryIterator(f era.ReadAtSeekCloser, merged bool) (historyIterator, error) {
+	if merged {
+		e, err := erae.From(f)
+		if err != nil {
+			return nil, fmt.Errorf("error opening era: %w", err)
+		}
+		it, err := erae.NewIterator(e)
+		if err != nil {
+			return nil, fmt.Errorf("error making era reader: %w", err)
+		}
+		return it, nil
+	}
+	e, err := era.From(f)
+	if err != nil {
+		return nil, fmt.Errorf("error opening era: %w", err)
+	}
+	it, err := era.NewIterator(e)
+	if err != nil {
+		return nil, fmt.Errorf("error making era reader: %w", err)
+	}
+	return it, nil
+}
+
 func missingBlocks(chain *core.BlockChain, blocks []*types.Block) []*types.Block {
 	head := chain.CurrentBlock()
 	for i, block := range blocks {
@@ -401,9 +441,21 @@ func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, las
 }
 
 // ExportHistory exports blockchain history into the specified directory,
-// following the Era format.
-func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) error {
+// following the Era format. Pre-merge blocks are exported into Era1 files, and
+// post-merge blocks into EraE files, both numbered by the same epochs. The
+// beacon proofs of the post-merge blocks are built from the blocks of the given
+// beacon source, and omitted if it is nil.
+func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64, beacon BeaconBlockSource) error {
 	log.Info("Exporting blockchain history", "dir", dir)
+	var genesisTime uint64
+	if beacon != nil {
+		var err error
+		if genesisTime, err = beacon.GetGenesisTime(); err != nil {
+			return fmt.Errorf("failed to retrieve beacon genesis time: %w", err)
+		}
+	} else {
+		log.Warn("No beacon API configured, exporting EraE files without beacon proofs")
+	}
 	if head := bc.CurrentBlock().Number.Uint64(); head < last {
 		log.Warn("Last block beyond head, setting last = head", "head", head, "last", last)
 		last = head
@@ -416,11 +468,10 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 		return fmt.Errorf("error creating output directory: %w", err)
 	}
 	var (
-		start     = time.Now()
-		reported  = time.Now()
-		h         = sha256.New()
-		buf       = bytes.NewBuffer(nil)
-		checksums []string
+		start          = time.Now()
+		reported       = time.Now()
+		checksums      []string
+		mergedChecksum []string
 	)
 	td := new(big.Int)
 	for i := uint64(0); i < first; i++ {
@@ -428,14 +479,21 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 	}
 	for i := first; i <= last; i += step {
 		err := func() error {
-			filename := filepath.Join(dir, era.Filename(network, int(i/step), common.Hash{}))
-			f, err := os.Create(filename)
-			if err != nil {
-				return fmt.Errorf("could not create era file: %w", err)
-			}
-			defer f.Close()
-
-			w := era.NewBuilder(f)
+			var (
+				epoch      = int(i / step)
+				era1File   *os.File
+				eraeFile   *os.File
+				era1Writer *era.Builder
+				eraeWriter *erae.Builder
+			)
+			defer func() {
+				if era1File != nil {
+					era1File.Close()
+				}
+				if eraeFile != nil {
+					eraeFile.Close()
+				}
+			}()
 			for j := uint64(0); j < step && j <= last-i; j++ {
 				var (
 					n     = i + j
@@ -448,28 +506,57 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 				if receipts == nil {
 					return fmt.Errorf("export failed on #%d: receipts not found", n)
 				}
-				td.Add(td, block.Difficulty())
-				if err := w.Add(block, receipts, new(big.Int).Set(td)); err != nil {
+				if block.Difficulty().Sign() != 0 {
+					if era1Writer == nil {
+						f, err := os.Create(filepath.Join(dir, era.Filename(network, epoch, common.Hash{})))
+						if err != nil {
+							return fmt.Errorf("could not create era file: %w", err)
+						}
+						era1File, era1Writer = f, era.NewBuilder(f)
+					}
+					td.Add(td, block.Difficulty())
+					if err := era1Writer.Add(block, receipts, new(big.Int).Set(td)); err != nil {
+						return err
+					}
+					continue
+				}
+				if eraeWriter == nil {
+					f, err := os.Create(filepath.Join(dir, erae.Filename(network, epoch, common.Hash{})))
+					if err != nil {
+						return fmt.Errorf("could not create era file: %w", err)
+					}
+					eraeFile, eraeWriter = f, erae.NewBuilder(f)
+				}
+				proof, err := beaconProof(bc, beacon, genesisTime, block)
+				if err != nil {
+					return fmt.Errorf("export failed on #%d: %w", n, err)
+				}
+				if err := eraeWriter.Add(block, receipts, proof); err != nil {
 					return err
 				}
 			}
-			root, err := w.Finalize()
-			if err != nil {
-				return fmt.Errorf("export failed to finalize %d: %w", step/i, err)
-			}
-			// Set correct filename with root.
-			os.Rename(filename, filepath.Join(dir, era.Filename(network, int(i/step), root)))
-
-			// Compute checksum of entire Era1.
-			if _, err := f.Seek(0, io.SeekStart); err != nil {
-				return err
+			if era1Writer != nil {
+				root, err := era1Writer.Finalize()
+				if err != nil {
+					return fmt.Errorf("export failed to finalize %d: %w", epoch, err)
+				}
+				checksum, err := finalizeHistoryFile(era1File, filepath.Join(dir, era.Filename(network, epoch, root)))
+				if err != nil {
+					return err
+				}
+				checksums = append(checksums, checksum)
 			}
-			if _, err := io.Copy(h, f); err != nil {
-				return fmt.Errorf("unable to calculate checksum: %w", err)
+			if eraeWriter != nil {
+				root, err := eraeWriter.Finalize()
+				if err != nil {
+					return fmt.Errorf("export failed to finalize %d: %w", epoch, err)
+				}
+				checksum, err := finalizeHistoryFile(eraeFile, filepath.Join(dir, erae.Filename(network, epoch, root)))
+				if err != nil {
+					return err
+				}
+				mergedChecksum = append(mergedChecksum, checksum)
 			}
-			checksums = append(checksums, common.BytesToHash(h.Sum(buf.Bytes()[:])).Hex())
-			h.Reset()
-			buf.Reset()
 			return nil
 		}()
 		if err != nil {
@@ -480,7 +567,8 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 			reported = time.Now()
 		}
 	}
-
+	// The checksums of the EraE files are listed after the Era1 ones.
+	checksums = append(checksums, mergedChecksum...)
 	os.WriteFile(filepath.Join(dir, "checksums.txt"), []byte(strings.Join(checksums, "\n")), os.ModePerm)
 
 	log.Info("Exported blockchain to", "dir", dir)
@@ -488,6 +576,71 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 	return nil
 }
 
+// BeaconBlockSource retrieves the beacon blocks which included post-merge
+// execution blocks. It is implemented by the beacon light client API.
+type BeaconBlockSource interface {
+	GetGenesisTime() (uint64, error)
+	GetBeaconBlock(blockRoot common.Hash) (*btypes.BeaconBlock, error)
+	GetBeaconBlockBySlot(slot uint64) (*btypes.BeaconBlock, error)
+}
+
+// beaconProof returns the proof tying the given post-merge block to the beacon
+// block which included it. The beacon block is looked up by the root committed
+// to by the next block if available, and by the slot of the block otherwise.
+// No proof is created without a beacon block source.
+func beaconProof(bc *core.BlockChain, beacon BeaconBlockSource, genesisTime uint64, block *types.Block) (*erae.BlockProof, error) {
+	if beacon == nil {
+		return nil, nil
+	}
+	var (
+		beaconBlock *btypes.BeaconBlock
+		beaconRoot  *common.Hash
+		err         error
+	)
+	if next := bc.GetHeaderByNumber(block.NumberU64() + 1); next != nil && next.ParentBeaconRoot != nil {
+		beaconRoot = next.ParentBeaconRoot
+		beaconBlock, err = beacon.GetBeaconBlock(*beaconRoot)
+	} else {
+		if block.Time() < genesisTime {
+			return nil, fmt.Errorf("block timestamp %d before beacon genesis %d", block.Time(), genesisTime)
+		}
+		beaconBlock, err = beacon.GetBeaconBlockBySlot((block.Time() - genesisTime) / beaconSlotTime)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve beacon block: %w", err)
+	}
+	if hash := beaconBlock.ExecutionBlockHash(); hash != block.Hash() {
+		return nil, fmt.Errorf("beacon block %d includes execution block %s", beaconBlock.Slot(), hash)
+	}
+	root := beaconBlock.Root()
+	if beaconRoot != nil && root != *beaconRoot {
+		return nil, fmt.Errorf("beacon root mismatch: want %s, got %s", *beaconRoot, root)
+	}
+	_, branch := beaconBlock.ExecutionBlockHashProof()
+	proof := &erae.BlockProof{BeaconRoot: root, Branch: make([]common.Hash, len(branch))}
+	for i, node := range branch {
+		proof.Branch[i] = common.Hash(node)
+	}
+	return proof, nil
+}
+
+// finalizeHistoryFile renames a finalized era archive to its root-qualified name
+// and returns its checksum.
+func finalizeHistoryFile(f *os.File, filename string) (string, error) {
+	// Set correct filename with root.
+	os.Rename(f.Name(), filename)
+
+	// Compute checksum of entire archive.
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("unable to calculate checksum: %w", err)
+	}
+	return common.BytesToHash(h.Sum(nil)).Hex(), nil
+}
+
 // ImportPreimages imports a batch of exported hash preimages into the database.
 // It's a part of the deprecated functionality, should be removed in the future.
 func ImportPreimages(db ethdb.Database, fn string) error {
@@ -37,6 +37,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/beacon/light/api"
 	bparams "github.com/ethereum/go-ethereum/beacon/params"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/fdlimit"
@@ -54,6 +55,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/verifier"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/ethdb/remotedb"
 	"github.com/ethereum/go-ethereum/ethstats"
@@ -100,9 +102,15 @@ var (
 		Usage:    "URL for remote database",
 		Category: flags.LoggingCategory,
 	}
+	RemoteDBJWTSecretFlag = &cli.StringFlag{
+		Name:      "remotedb.jwtsecret",
+		Usage:     "Path to the JWT secret of the remote database's authenticated RPC endpoint",
+		TakesFile: true,
+		Category:  flags.APICategory,
+	}
 	DBEngineFlag = &cli.StringFlag{
 		Name:     "db.engine",
-		Usage:    "Backing database implementation to use ('pebble' or 'leveldb')",
+		Usage:    "Backing database implementation to use ('pebble', 'leveldb' or 'bbolt')",
 		Value:    node.DefaultConfig.DBEngine,
 		Category: flags.EthCategory,
 	}
@@ -111,6 +119,11 @@ var (
 		Usage:    "Root directory for ancient data (default = inside chaindata)",
 		Category: flags.EthCategory,
 	}
+	EraFlag = &flags.DirectoryFlag{
+		Name:     "datadir.era",
+		Usage:    "Directory of era1 files serving the pruned chain history",
+		Category: flags.EthCategory,
+	}
 	MinFreeDiskSpaceFlag = &flags.DirectoryFlag{
 		Name:     "datadir.minfreedisk",
 		Usage:    "Minimum free disk space in MB, once reached triggers auto shut down (default = --cache.gc converted to MB, 0 = disabled)",
@@ -254,9 +267,19 @@ var (
 		Value:    ethconfig.Defaults.SyncMode.String(),
 		Category: flags.StateCategory,
 	}
+	StatelessFlag = &cli.BoolFlag{
+		Name:     "stateless",
+		Usage:    "Keep no state, verify each new head block against the execution witness fetched from --stateless.endpoint",
+		Category: flags.StateCategory,
+	}
+	StatelessEndpointFlag = &cli.StringFlag{
+		Name:     "stateless.endpoint",
+		Usage:    "RPC endpoint of a node serving blocks and execution witnesses (debug_executionWitness) in stateless mode",
+		Category: flags.StateCategory,
+	}
 	GCModeFlag = &cli.StringFlag{
 		Name:     "gcmode",
-		Usage:    `Blockchain garbage collection mode, only relevant in state.scheme=hash ("full", "archive")`,
+		Usage:    `Blockchain garbage collection mode ("full", "archive")`,
 		Value:    "full",
 		Category: flags.StateCategory,
 	}
@@ -380,6 +403,24 @@ var (
 		Value:    ethconfig.Defaults.TxPool.Rejournal,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolSnapshotFlag = &cli.StringFlag{
+		Name:     "txpool.snapshot",
+		Usage:    "Disk snapshot of all pooled non-blob transactions to survive node restarts (disabled if empty)",
+		Value:    ethconfig.Defaults.TxPool.Snapshot,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolSnapshotIntervalFlag = &cli.DurationFlag{
+		Name:     "txpool.snapshotinterval",
+		Usage:    "Time interval to regenerate the transaction pool snapshot",
+		Value:    ethconfig.Defaults.TxPool.SnapshotInterval,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolSnapshotSizeFlag = &cli.Uint64Flag{
+		Name:     "txpool.snapshotsize",
+		Usage:    "Maximum size in bytes of the transactions persisted in the pool snapshot",
+		Value:    ethconfig.Defaults.TxPool.SnapshotSize,
+		Category: flags.TxPoolCategory,
+	}
 	TxPoolPriceLimitFlag = &cli.Uint64Flag{
 		Name:     "txpool.pricelimit",
 		Usage:    "Minimum gas price tip to enforce for acceptance into the pool",
@@ -603,6 +644,11 @@ var (
 		Usage:    "Path to a JWT secret to use for authenticated RPC endpoints",
 		Category: flags.APICategory,
 	}
+	RemoteDBServeFlag = &cli.BoolFlag{
+		Name:     "remotedb.serve",
+		Usage:    "Expose read-write access to the chain database over the authenticated RPC endpoints ('db' namespace, writes to live chain and state data are refused)",
+		Category: flags.APICategory,
+	}
 
 	// Logging and debug settings
 	EthStatsURLFlag = &cli.StringFlag{
@@ -755,6 +801,59 @@ var (
 		Value:    node.DefaultConfig.BatchResponseMaxSize,
 		Category: flags.APICategory,
 	}
+	RPCRateLimitFlag = &cli.StringFlag{
+		Name:     "rpc.ratelimit",
+		Usage:    "Per-client rate limits of the HTTP and WebSocket method calls, as comma separated selector=rate/burst items (e.g. '*=100/200,eth_getLogs=5/10,debug=1/2')",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitCostFlag = &cli.StringFlag{
+		Name:     "rpc.ratelimit.cost",
+		Usage:    "Tokens taken by the rate limited method calls, as comma separated selector=cost items (e.g. 'eth_getLogs=10,debug_traceTransaction=50')",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitHeaderFlag = &cli.StringFlag{
+		Name:     "rpc.ratelimit.header",
+		Usage:    "HTTP header identifying the rate limited clients, e.g. an API key header set by an authenticating proxy",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitClientsFlag = &cli.StringFlag{
+		Name:     "rpc.ratelimit.clients",
+		Usage:    "Comma separated values of the rate limit client header accepted as client identities, others are limited by IP address",
+		Category: flags.APICategory,
+	}
+	RPCAccessFileFlag = &flags.DirectoryFlag{
+		Name:     "rpc.access",
+		Usage:    "TOML file with the methods callable by the HTTP and WebSocket clients, and the API keys and JWT secrets of the tenants (reloaded when changed)",
+		Category: flags.APICategory,
+	}
+	RPCAccessLogFlag = &flags.DirectoryFlag{
+		Name:     "rpc.accesslog",
+		Usage:    "File to write a JSON line for each method call served over HTTP and WebSocket",
+		Category: flags.APICategory,
+	}
+	RPCAccessLogMaxSizeFlag = &cli.IntFlag{
+		Name:     "rpc.accesslog.maxsize",
+		Usage:    "Maximum size in MBs of a single access log file",
+		Value:    100,
+		Category: flags.APICategory,
+	}
+	RPCAccessLogMaxBackupsFlag = &cli.IntFlag{
+		Name:     "rpc.accesslog.maxbackups",
+		Usage:    "Maximum number of access log files to retain",
+		Value:    10,
+		Category: flags.APICategory,
+	}
+	RPCAccessLogMaxAgeFlag = &cli.IntFlag{
+		Name:     "rpc.accesslog.maxage",
+		Usage:    "Maximum number of days to retain an access log file",
+		Value:    30,
+		Category: flags.APICategory,
+	}
+	RPCAccessLogCompressFlag = &cli.BoolFlag{
+		Name:     "rpc.accesslog.compress",
+		Usage:    "Compress the rotated access log files",
+		Category: flags.APICategory,
+	}
 
 	// Network Settings
 	MaxPeersFlag = &cli.IntFlag{
@@ -978,6 +1077,7 @@ var (
 		DataDirFlag,
 		AncientFlag,
 		RemoteDBFlag,
+		RemoteDBJWTSecretFlag,
 		DBEngineFlag,
 		StateSchemeFlag,
 		HttpHeaderFlag,
@@ -1192,6 +1292,87 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(BatchResponseMaxSize.Name) {
 		cfg.BatchResponseMaxSize = ctx.Int(BatchResponseMaxSize.Name)
 	}
+	setRPCRateLimits(ctx, cfg)
+	if ctx.IsSet(RPCAccessFileFlag.Name) {
+		cfg.RPCAccessFile = ctx.String(RPCAccessFileFlag.Name)
+	}
+	setRPCAccessLog(ctx, cfg)
+}
+
+// setRPCAccessLog configures the RPC access log from the command line flags.
+func setRPCAccessLog(ctx *cli.Context, cfg *node.Config) {
+	if !ctx.IsSet(RPCAccessLogFlag.Name) {
+		for _, flag := range []string{RPCAccessLogMaxSizeFlag.Name, RPCAccessLogMaxBackupsFlag.Name, RPCAccessLogMaxAgeFlag.Name, RPCAccessLogCompressFlag.Name} {
+			if ctx.IsSet(flag) {
+				Fatalf("Option %q requires %q", flag, RPCAccessLogFlag.Name)
+			}
+		}
+		return
+	}
+	cfg.RPCAccessLog = &node.RPCAccessLogConfig{
+		File:       ctx.String(RPCAccessLogFlag.Name),
+		MaxSize:    ctx.Int(RPCAccessLogMaxSizeFlag.Name),
+		MaxBackups: ctx.Int(RPCAccessLogMaxBackupsFlag.Name),
+		MaxAge:     ctx.Int(RPCAccessLogMaxAgeFlag.Name),
+		Compress:   ctx.Bool(RPCAccessLogCompressFlag.Name),
+	}
+}
+
+// setRPCRateLimits creates the RPC rate limits from the command line flags.
+func setRPCRateLimits(ctx *cli.Context, cfg *node.Config) {
+	if !ctx.IsSet(RPCRateLimitFlag.Name) {
+		for _, flag := range []string{RPCRateLimitCostFlag.Name, RPCRateLimitHeaderFlag.Name, RPCRateLimitClientsFlag.Name} {
+			if ctx.IsSet(flag) {
+				Fatalf("Option %q requires %q", flag, RPCRateLimitFlag.Name)
+			}
+		}
+		return
+	}
+	if ctx.IsSet(RPCRateLimitHeaderFlag.Name) != ctx.IsSet(RPCRateLimitClientsFlag.Name) {
+		Fatalf("Options %q and %q must be set together", RPCRateLimitHeaderFlag.Name, RPCRateLimitClientsFlag.Name)
+	}
+	limits := &rpc.RateLimitConfig{
+		Limits:       make(map[string]rpc.RateLimit),
+		Costs:        make(map[string]int),
+		ClientHeader: ctx.String(RPCRateLimitHeaderFlag.Name),
+		Clients:      SplitAndTrim(ctx.String(RPCRateLimitClientsFlag.Name)),
+	}
+	for selector, value := range splitRateLimitItems(RPCRateLimitFlag.Name, ctx.String(RPCRateLimitFlag.Name)) {
+		rateStr, burstStr, ok := strings.Cut(value, "/")
+		if !ok {
+			Fatalf("Option %q: invalid limit %q for %s, want rate/burst", RPCRateLimitFlag.Name, value, selector)
+		}
+		rate, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil {
+			Fatalf("Option %q: invalid rate for %s: %v", RPCRateLimitFlag.Name, selector, err)
+		}
+		burst, err := strconv.Atoi(burstStr)
+		if err != nil {
+			Fatalf("Option %q: invalid burst for %s: %v", RPCRateLimitFlag.Name, selector, err)
+		}
+		limits.Limits[selector] = rpc.RateLimit{Rate: rate, Burst: burst}
+	}
+	for selector, value := range splitRateLimitItems(RPCRateLimitCostFlag.Name, ctx.String(RPCRateLimitCostFlag.Name)) {
+		cost, err := strconv.Atoi(value)
+		if err != nil {
+			Fatalf("Option %q: invalid cost for %s: %v", RPCRateLimitCostFlag.Name, selector, err)
+		}
+		limits.Costs[selector] = cost
+	}
+	cfg.RPCRateLimits = limits
+}
+
+// splitRateLimitItems splits a comma separated list of selector=value items.
+func splitRateLimitItems(flag string, list string) map[string]string {
+	items := make(map[string]string)
+	for _, item := range SplitAndTrim(list) {
+		selector, value, ok := strings.Cut(item, "=")
+		if !ok || selector == "" {
+			Fatalf("Option %q: invalid item %q, want selector=value", flag, item)
+		}
+		items[selector] = value
+	}
+	return items
 }
 
 // setGraphQL creates the GraphQL listener interface string from the set
@@ -1399,8 +1580,8 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	}
 	if ctx.IsSet(DBEngineFlag.Name) {
 		dbEngine := ctx.String(DBEngineFlag.Name)
-		if dbEngine != "leveldb" && dbEngine != "pebble" {
-			Fatalf("Invalid choice for db.engine '%s', allowed 'leveldb' or 'pebble'", dbEngine)
+		if dbEngine != "leveldb" && dbEngine != "pebble" && dbEngine != "bbolt" {
+			Fatalf("Invalid choice for db.engine '%s', allowed 'leveldb', 'pebble' or 'bbolt'", dbEngine)
 		}
 		log.Info(fmt.Sprintf("Using %s as db engine", dbEngine))
 		cfg.DBEngine = dbEngine
@@ -1484,6 +1665,15 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.Duration(TxPoolRejournalFlag.Name)
 	}
+	if ctx.IsSet(TxPoolSnapshotFlag.Name) {
+		cfg.Snapshot = ctx.String(TxPoolSnapshotFlag.Name)
+	}
+	if ctx.IsSet(TxPoolSnapshotIntervalFlag.Name) {
+		cfg.SnapshotInterval = ctx.Duration(TxPoolSnapshotIntervalFlag.Name)
+	}
+	if ctx.IsSet(TxPoolSnapshotSizeFlag.Name) {
+		cfg.SnapshotSize = ctx.Uint64(TxPoolSnapshotSizeFlag.Name)
+	}
 	if ctx.IsSet(TxPoolPriceLimitFlag.Name) {
 		cfg.PriceLimit = ctx.Uint64(TxPoolPriceLimitFlag.Name)
 	}
@@ -1630,6 +1820,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(AncientFlag.Name) {
 		cfg.DatabaseFreezer = ctx.String(AncientFlag.Name)
 	}
+	if ctx.IsSet(EraFlag.Name) {
+		cfg.DatabaseEra = ctx.String(EraFlag.Name)
+	}
 
 	if gcmode := ctx.String(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
@@ -1670,7 +1863,14 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 			log.Warn("Disabled transaction unindexing for archive node")
 		}
 
-		if cfg.StateScheme != rawdb.HashScheme {
+		// The path-based archive node serves historical state from the indexed
+		// state histories, which are all retained unless explicitly limited.
+		if cfg.StateScheme == rawdb.PathScheme {
+			if !ctx.IsSet(StateHistoryFlag.Name) && cfg.StateHistory != 0 {
+				cfg.StateHistory = 0
+				log.Warn("Disabled state history pruning for archive node")
+			}
+		} else if cfg.StateScheme != rawdb.HashScheme {
 			cfg.StateScheme = rawdb.HashScheme
 			log.Warn("Forcing hash state-scheme for archive mode")
 		}
@@ -1946,17 +2146,33 @@ func MakeBeaconLightConfig(ctx *cli.Context) bparams.ClientConfig {
 	if config.Apis == nil {
 		Fatalf("Beacon node light client API URL not specified")
 	}
-	config.CustomHeader = make(map[string]string)
+	config.CustomHeader = makeBeaconApiHeaders(ctx)
+	config.Threshold = ctx.Int(BeaconThresholdFlag.Name)
+	config.NoFilter = ctx.Bool(BeaconNoFilterFlag.Name)
+	return config
+}
+
+// MakeBeaconBlockSource creates a beacon block source from the first configured
+// beacon API endpoint, or returns nil if there is none.
+func MakeBeaconBlockSource(ctx *cli.Context) BeaconBlockSource {
+	apis := ctx.StringSlice(BeaconApiFlag.Name)
+	if len(apis) == 0 {
+		return nil
+	}
+	return api.NewBeaconLightApi(apis[0], makeBeaconApiHeaders(ctx))
+}
+
+// makeBeaconApiHeaders parses the custom HTTP headers of the beacon API.
+func makeBeaconApiHeaders(ctx *cli.Context) map[string]string {
+	headers := make(map[string]string)
 	for _, s := range ctx.StringSlice(BeaconApiHeaderFlag.Name) {
 		kv := strings.Split(s, ":")
 		if len(kv) != 2 {
 			Fatalf("Invalid custom API header entry: %s", s)
 		}
-		config.CustomHeader[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
 	}
-	config.Threshold = ctx.Int(BeaconThresholdFlag.Name)
-	config.NoFilter = ctx.Bool(BeaconNoFilterFlag.Name)
-	return config
+	return headers
 }
 
 // SetDNSDiscoveryDefaults configures DNS discovery with the given URL if
@@ -2010,12 +2226,56 @@ func RegisterFilterAPI(stack *node.Node, backend ethapi.Backend, ethcfg *ethconf
 	return filterSystem
 }
 
+// RegisterRemoteDBService exposes the chain database over the authenticated RPC
+// endpoints of the node.
+func RegisterRemoteDBService(stack *node.Node, db ethdb.Database) {
+	stack.RegisterAPIs([]rpc.API{{
+		Namespace:     remotedb.Namespace,
+		Service:       remotedb.NewServer(db, rawdb.IsLiveKey),
+		Authenticated: true,
+	}})
+	log.Warn("Exposed chain database over authenticated RPC", "namespace", remotedb.Namespace)
+}
+
 // RegisterFullSyncTester adds the full-sync tester service into node.
 func RegisterFullSyncTester(stack *node.Node, eth *eth.Ethereum, target common.Hash) {
 	catalyst.RegisterFullSyncTester(stack, eth, target)
 	log.Info("Registered full-sync tester", "hash", target)
 }
 
+// RegisterStatelessVerifier adds the stateless block verifier service into node.
+// Only the networks with a known genesis are supported, as there is no local
+// database to read the chain configuration from.
+func RegisterStatelessVerifier(ctx *cli.Context, stack *node.Node, ethcfg *ethconfig.Config) {
+	endpoint := ctx.String(StatelessEndpointFlag.Name)
+	if endpoint == "" {
+		Fatalf("--%s requires --%s", StatelessFlag.Name, StatelessEndpointFlag.Name)
+	}
+	config, err := statelessChainConfig(ethcfg)
+	if err != nil {
+		Fatalf("Failed to register the stateless verifier: %v", err)
+	}
+	if _, err := verifier.New(stack, config, verifier.Config{Endpoint: endpoint}); err != nil {
+		Fatalf("Failed to register the stateless verifier: %v", err)
+	}
+	log.Info("Registered stateless verifier", "endpoint", endpoint)
+}
+
+// statelessChainConfig returns the chain config of the network selected by the
+// network flags, as resolved by SetEthConfig. The stateless verifier has no
+// database to load the genesis of other networks from.
+func statelessChainConfig(ethcfg *ethconfig.Config) (*params.ChainConfig, error) {
+	switch {
+	case ethcfg.Genesis != nil:
+		return ethcfg.Genesis.Config, nil
+	case ethcfg.NetworkId == 0 || ethcfg.NetworkId == params.MainnetChainConfig.ChainID.Uint64():
+		// No network selected, run on mainnet like the full node does.
+		return params.MainnetChainConfig, nil
+	default:
+		return nil, fmt.Errorf("unknown network %d, select a network with its flag", ethcfg.NetworkId)
+	}
+}
+
 // SetupMetrics configures the metrics system.
 func SetupMetrics(cfg *metrics.Config) {
 	if !cfg.Enabled {
@@ -2094,7 +2354,8 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node, readonly bool) ethdb.
 	switch {
 	case ctx.IsSet(RemoteDBFlag.Name):
 		log.Info("Using remote db", "url", ctx.String(RemoteDBFlag.Name), "headers", len(ctx.StringSlice(HttpHeaderFlag.Name)))
-		client, err := DialRPCWithHeaders(ctx.String(RemoteDBFlag.Name), ctx.StringSlice(HttpHeaderFlag.Name))
+		var client *rpc.Client
+		client, err = dialRemoteDB(ctx)
 		if err != nil {
 			break
 		}
@@ -2108,6 +2369,26 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node, readonly bool) ethdb.
 	return chainDb
 }
 
+// dialRemoteDB connects to the remote database endpoint, authenticating with the
+// configured JWT secret if any.
+func dialRemoteDB(ctx *cli.Context) (*rpc.Client, error) {
+	if !ctx.IsSet(RemoteDBJWTSecretFlag.Name) {
+		return DialRPCWithHeaders(ctx.String(RemoteDBFlag.Name), ctx.StringSlice(HttpHeaderFlag.Name))
+	}
+	// Don't use node.ObtainJWTSecret, a missing secret must not be generated
+	data, err := os.ReadFile(ctx.String(RemoteDBJWTSecretFlag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load remote database JWT secret: %v", err)
+	}
+	secret := common.FromHex(strings.TrimSpace(string(data)))
+	if len(secret) != 32 {
+		return nil, fmt.Errorf("invalid remote database JWT secret length %d", len(secret))
+	}
+	var jwtSecret [32]byte
+	copy(jwtSecret[:], secret)
+	return DialRPCWithHeaders(ctx.String(RemoteDBFlag.Name), ctx.StringSlice(HttpHeaderFlag.Name), rpc.WithHTTPAuth(node.NewJWTAuth(jwtSecret)))
+}
+
 // tryMakeReadOnlyDatabase try to open the chain database in read-only mode,
 // or fallback to write mode if the database is not initialized.
 func tryMakeReadOnlyDatabase(ctx *cli.Context, stack *node.Node) ethdb.Database {
@@ -2130,7 +2411,7 @@ func IsNetworkPreset(ctx *cli.Context) bool {
 	return false
 }
 
-func DialRPCWithHeaders(endpoint string, headers []string) (*rpc.Client, error) {
+func DialRPCWithHeaders(endpoint string, headers []string, opts ...rpc.ClientOption) (*rpc.Client, error) {
 	if endpoint == "" {
 		return nil, errors.New("endpoint must be specified")
 	}
@@ -2139,7 +2420,6 @@ func DialRPCWithHeaders(endpoint string, headers []string) (*rpc.Client, error)
 		// these prefixes.
 		endpoint = endpoint[4:]
 	}
-	var opts []rpc.ClientOption
 	if len(headers) > 0 {
 		customHeaders := make(http.Header)
 		for _, h := range headers {
@@ -2202,6 +2482,7 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readonly bool) (*core.BlockCh
 		Preimages:           ctx.Bool(CachePreimagesFlag.Name),
 		StateScheme:         scheme,
 		StateHistory:        ctx.Uint64(StateHistoryFlag.Name),
+		StateIndexing:       ctx.String(GCModeFlag.Name) == "archive",
 	}
 	if cache.TrieDirtyDisabled && !cache.Preimages {
 		cache.Preimages = true
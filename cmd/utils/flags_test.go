@@ -20,6 +20,10 @@ package utils
 import (
 	"reflect"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 func Test_SplitTagsFlag(t *testing.T) {
@@ -64,3 +68,32 @@ func Test_SplitTagsFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestStatelessChainConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		cfg  ethconfig.Config
+		want *params.ChainConfig
+	}{
+		{cfg: ethconfig.Config{}, want: params.MainnetChainConfig},
+		{cfg: ethconfig.Config{NetworkId: 1}, want: params.MainnetChainConfig},
+		{cfg: ethconfig.Config{NetworkId: 11155111, Genesis: core.DefaultSepoliaGenesisBlock()}, want: params.SepoliaChainConfig},
+		{cfg: ethconfig.Config{NetworkId: 560048, Genesis: core.DefaultHoodiGenesisBlock()}, want: params.HoodiChainConfig},
+		{cfg: ethconfig.Config{NetworkId: 12345}},
+	}
+	for i, test := range tests {
+		config, err := statelessChainConfig(&test.cfg)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("test %d: expected error for unknown network", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		} else if config != test.want {
+			t.Errorf("test %d: wrong chain config: have chain %v, want %v", i, config.ChainID, test.want.ChainID)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package utils
 import (
 	"bytes"
 	"crypto/sha256"
+	"errors"
 	"io"
 	"math/big"
 	"os"
@@ -26,7 +27,9 @@ import (
 	"strings"
 	"testing"
 
+	btypes "github.com/ethereum/go-ethereum/beacon/types"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -34,9 +37,12 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/erae"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/triedb"
+	zrntcommon "github.com/protolambda/zrnt/eth2/beacon/common"
+	"github.com/protolambda/zrnt/eth2/beacon/deneb"
 )
 
 var (
@@ -90,7 +96,7 @@ func TestHistoryImportAndExport(t *testing.T) {
 	dir := t.TempDir()
 
 	// Export history to temp directory.
-	if err := ExportHistory(chain, dir, 0, count, step); err != nil {
+	if err := ExportHistory(chain, dir, 0, count, step, nil); err != nil {
 		t.Fatalf("error exporting history: %v", err)
 	}
 
@@ -178,3 +184,183 @@ func TestHistoryImportAndExport(t *testing.T) {
 		t.Fatalf("imported chain does not match expected, have (%d, %s) want (%d, %s)", have.Number, have.Hash(), want.Number, want.Hash())
 	}
 }
+
+func TestMergedHistoryImportAndExport(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		genesis = &core.Genesis{
+			Config:     params.MergedTestChainConfig,
+			Alloc:      types.GenesisAlloc{address: {Balance: big.NewInt(1000000000000000000)}},
+			Difficulty: big.NewInt(131072),
+		}
+		signer = types.LatestSigner(genesis.Config)
+		engine = beacon.New(ethash.NewFaker())
+		source = newTestBeaconSource()
+	)
+
+	// Generate a chain with a pre-merge genesis, followed by post-merge blocks.
+	// Every block commits to a beacon block including its parent.
+	db, blocks, _ := core.GenerateChainWithGenesis(genesis, engine, int(count), func(i int, g *core.BlockGen) {
+		parent := g.PrevBlock(i - 1)
+		g.SetParentBeaconRoot(source.add(parent.Time()/beaconSlotTime, parent.Hash()))
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   genesis.Config.ChainID,
+			Nonce:     uint64(i),
+			GasTipCap: common.Big0,
+			GasFeeCap: g.PrevBlock(-1).BaseFee(),
+			Gas:       50000,
+			To:        &common.Address{0xaa},
+			Value:     big.NewInt(int64(i)),
+		})
+		if err != nil {
+			t.Fatalf("error creating tx: %v", err)
+		}
+		g.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(db, nil, genesis, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("unable to initialize chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("error inserting chain: %v", err)
+	}
+	// The beacon block of the head is only found by its slot.
+	head := blocks[len(blocks)-1]
+	source.add(head.Time()/beaconSlotTime, head.Hash())
+
+	// Export history, the genesis goes into an Era1 file and the rest into EraE files.
+	dir := t.TempDir()
+	if err := ExportHistory(chain, dir, 0, count, step, source); err != nil {
+		t.Fatalf("error exporting history: %v", err)
+	}
+	entries, _ := era.ReadDir(dir, "mainnet")
+	if len(entries) != 1 {
+		t.Fatalf("wrong number of era1 files: have %d, want 1", len(entries))
+	}
+	merged, _ := erae.ReadDir(dir, "mainnet")
+	if want := int(count/step) + 1; len(merged) != want {
+		t.Fatalf("wrong number of erae files: have %d, want %d", len(merged), want)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "checksums.txt"))
+	if err != nil {
+		t.Fatalf("failed to read checksums: %v", err)
+	}
+	if checksums := strings.Split(string(b), "\n"); len(checksums) != len(entries)+len(merged) {
+		t.Fatalf("wrong number of checksums: have %d, want %d", len(checksums), len(entries)+len(merged))
+	}
+
+	// Verify each EraE.
+	next := uint64(1)
+	for _, filename := range merged {
+		func() {
+			e, err := erae.Open(filepath.Join(dir, filename))
+			if err != nil {
+				t.Fatalf("error opening era: %v", err)
+			}
+			defer e.Close()
+			it, err := erae.NewIterator(e)
+			if err != nil {
+				t.Fatalf("error making era reader: %v", err)
+			}
+			for ; it.Next(); next++ {
+				if it.Error() != nil {
+					t.Fatalf("error reading block entry %d: %v", next, it.Error())
+				}
+				block, receipts, err := it.BlockAndReceipts()
+				if err != nil {
+					t.Fatalf("error reading block entry %d: %v", next, err)
+				}
+				want := chain.GetBlockByNumber(next)
+				if want.Hash() != block.Hash() {
+					t.Fatalf("block hash mismatch %d: want %s, got %s", next, want.Hash().Hex(), block.Hash().Hex())
+				}
+				if got := types.DeriveSha(receipts, trie.NewStackTrie(nil)); got != want.ReceiptHash() {
+					t.Fatalf("receipt root %d mismatch: want %s, got %s", next, want.ReceiptHash(), got)
+				}
+				// The beacon root is committed to by the next block, and found
+				// by the slot for the head.
+				proof, err := it.Proof()
+				if err != nil {
+					t.Fatalf("error reading proof %d: %v", next, err)
+				}
+				if root := source.roots[block.Hash()]; proof.BeaconRoot != root {
+					t.Fatalf("beacon root %d mismatch: want %s, got %s", next, root, proof.BeaconRoot)
+				}
+				if err := proof.Verify(block.Hash()); err != nil {
+					t.Fatalf("invalid proof %d: %v", next, err)
+				}
+			}
+		}()
+	}
+	if next != count+1 {
+		t.Fatalf("wrong number of exported blocks: have %d, want %d", next-1, count)
+	}
+
+	// Now import the archives.
+	db2, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	if err != nil {
+		panic(err)
+	}
+	t.Cleanup(func() {
+		db2.Close()
+	})
+	genesis.MustCommit(db2, triedb.NewDatabase(db2, triedb.HashDefaults))
+	imported, err := core.NewBlockChain(db2, nil, genesis, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("unable to initialize chain: %v", err)
+	}
+	if err := ImportHistory(imported, dir, "mainnet"); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	if have, want := imported.CurrentSnapBlock(), chain.CurrentHeader(); have.Hash() != want.Hash() {
+		t.Fatalf("imported chain does not match expected, have (%d, %s) want (%d, %s)", have.Number, have.Hash(), want.Number, want.Hash())
+	}
+}
+
+// testBeaconSource serves synthetic beacon blocks including the blocks of a
+// test chain.
+type testBeaconSource struct {
+	blocks map[common.Hash]*btypes.BeaconBlock // beacon blocks by root
+	slots  map[uint64]*btypes.BeaconBlock      // beacon blocks by slot
+	roots  map[common.Hash]common.Hash         // beacon roots by execution block hash
+}
+
+func newTestBeaconSource() *testBeaconSource {
+	return &testBeaconSource{
+		blocks: make(map[common.Hash]*btypes.BeaconBlock),
+		slots:  make(map[uint64]*btypes.BeaconBlock),
+		roots:  make(map[common.Hash]common.Hash),
+	}
+}
+
+// add creates a beacon block including the given execution block hash and
+// returns its root.
+func (s *testBeaconSource) add(slot uint64, hash common.Hash) common.Hash {
+	obj := new(deneb.BeaconBlock)
+	obj.Slot = zrntcommon.Slot(slot)
+	obj.Body.ExecutionPayload.BlockHash = zrntcommon.Hash32(hash)
+
+	block := btypes.NewBeaconBlock(obj)
+	root := block.Root()
+	s.blocks[root], s.slots[slot], s.roots[hash] = block, block, root
+	return root
+}
+
+func (s *testBeaconSource) GetGenesisTime() (uint64, error) {
+	return 0, nil
+}
+
+func (s *testBeaconSource) GetBeaconBlock(root common.Hash) (*btypes.BeaconBlock, error) {
+	if block, ok := s.blocks[root]; ok {
+		return block, nil
+	}
+	return nil, errors.New("unknown beacon block")
+}
+
+func (s *testBeaconSource) GetBeaconBlockBySlot(slot uint64) (*btypes.BeaconBlock, error) {
+	if block, ok := s.slots[slot]; ok {
+		return block, nil
+	}
+	return nil, errors.New("unknown beacon block")
+}
@@ -95,6 +95,13 @@ var (
 	blockPrefetchExecuteTimer   = metrics.NewRegisteredTimer("chain/prefetch/executes", nil)
 	blockPrefetchInterruptMeter = metrics.NewRegisteredMeter("chain/prefetch/interrupts", nil)
 
+	witnessSizeHist      = metrics.NewRegisteredHistogram("chain/witness/size", nil, metrics.NewExpDecaySample(1028, 0.015))
+	witnessHeadersHist   = metrics.NewRegisteredHistogram("chain/witness/headers", nil, metrics.NewExpDecaySample(1028, 0.015))
+	witnessCodesHist     = metrics.NewRegisteredHistogram("chain/witness/codes", nil, metrics.NewExpDecaySample(1028, 0.015))
+	witnessCodeBytesHist = metrics.NewRegisteredHistogram("chain/witness/codes/size", nil, metrics.NewExpDecaySample(1028, 0.015))
+	witnessNodesHist     = metrics.NewRegisteredHistogram("chain/witness/nodes", nil, metrics.NewExpDecaySample(1028, 0.015))
+	witnessNodeBytesHist = metrics.NewRegisteredHistogram("chain/witness/nodes/size", nil, metrics.NewExpDecaySample(1028, 0.015))
+
 	errInsertionInterrupted = errors.New("insertion is interrupted")
 	errChainStopped         = errors.New("blockchain is stopped")
 	errInvalidOldChain      = errors.New("invalid old chain")
@@ -157,6 +164,7 @@ type CacheConfig struct {
 	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory
 	Preimages           bool          // Whether to store preimage of trie key to the disk
 	StateHistory        uint64        // Number of blocks from head whose state histories are reserved.
+	StateIndexing       bool          // Whether to index state histories for serving historical state (path scheme only)
 	StateScheme         string        // Scheme used to store ethereum states and merkle tree nodes on top
 
 	SnapshotNoBuild bool // Whether the background generation is allowed
@@ -180,9 +188,10 @@ func (c *CacheConfig) triedbConfig(isVerkle bool) *triedb.Config {
 	}
 	if c.StateScheme == rawdb.PathScheme {
 		config.PathDB = &pathdb.Config{
-			StateHistory:    c.StateHistory,
-			CleanCacheSize:  c.TrieCleanLimit * 1024 * 1024,
-			WriteBufferSize: c.TrieDirtyLimit * 1024 * 1024,
+			StateHistory:        c.StateHistory,
+			EnableStateIndexing: c.StateIndexing,
+			CleanCacheSize:      c.TrieCleanLimit * 1024 * 1024,
+			WriteBufferSize:     c.TrieDirtyLimit * 1024 * 1024,
 		}
 	}
 	return config
@@ -1880,6 +1889,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool, makeWitness
 		if err != nil {
 			return nil, it.index, err
 		}
+		if witness != nil && metrics.Enabled() {
+			reportWitness(witness)
+		}
 		// Report the import stats before returning the various results
 		stats.processed++
 		stats.usedGas += res.usedGas
@@ -1932,6 +1944,18 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool, makeWitness
 	return witness, it.index, err
 }
 
+// reportWitness updates the per-block witness size metrics.
+func reportWitness(witness *stateless.Witness) {
+	stats := witness.Stats()
+
+	witnessSizeHist.Update(int64(stats.Size()))
+	witnessHeadersHist.Update(int64(stats.Headers))
+	witnessCodesHist.Update(int64(stats.Codes))
+	witnessCodeBytesHist.Update(int64(stats.CodeBytes))
+	witnessNodesHist.Update(int64(stats.Nodes))
+	witnessNodeBytesHist.Update(int64(stats.NodeBytes))
+}
+
 // blockProcessingResult is a summary of block processing
 // used for updating the stats.
 type blockProcessingResult struct {
@@ -4390,3 +4390,69 @@ func testInsertChainWithCutoff(t *testing.T, cutoff uint64, ancientLimit uint64,
 		}
 	}
 }
+
+// Tests that the path-based archive node serves the historical states which
+// are already flushed out of the live state database.
+func TestPathArchiveHistoricState(t *testing.T) {
+	var (
+		key, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address   = crypto.PubkeyToAddress(key.PublicKey)
+		recipient = common.Address{0xaa}
+		funds     = big.NewInt(1000000000000000000)
+		gspec     = &Genesis{Config: params.TestChainConfig, Alloc: types.GenesisAlloc{address: {Balance: funds}}}
+		signer    = types.LatestSigner(gspec.Config)
+		balances  []*uint256.Int
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 2*state.TriesInMemory, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), recipient, big.NewInt(1000), params.TxGas, block.header.BaseFee, nil), signer, key)
+		if err != nil {
+			panic(err)
+		}
+		block.AddTx(tx)
+		balances = append(balances, block.GetBalance(recipient))
+	})
+	db, _ := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	defer db.Close()
+
+	config := DefaultCacheConfigWithScheme(rawdb.PathScheme)
+	config.StateHistory = 0
+	config.StateIndexing = true
+	chain, err := NewBlockChain(db, config, gspec, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("Failed to insert block %d: %v", n, err)
+	}
+	// The states of the blocks below the disk layer are only available as
+	// the historical states.
+	for i := 0; i < state.TriesInMemory-1; i++ {
+		root := blocks[i].Root()
+		if _, err := chain.StateAt(root); err == nil {
+			t.Fatalf("Unexpected live state for block %d", i+1)
+		}
+		// The state histories are indexed in the background, retry for a while.
+		var (
+			statedb *state.StateDB
+			timeout = time.Now().Add(10 * time.Second)
+		)
+		for {
+			statedb, err = chain.HistoricState(root)
+			if err == nil || time.Now().After(timeout) {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if err != nil {
+			t.Fatalf("Failed to open historic state for block %d: %v", i+1, err)
+		}
+		if balance := statedb.GetBalance(recipient); !balance.Eq(balances[i]) {
+			t.Fatalf("Unexpected balance at block %d, want %v, got %v", i+1, balances[i], balance)
+		}
+		if nonce := statedb.GetNonce(address); nonce != uint64(i+1) {
+			t.Fatalf("Unexpected nonce at block %d, want %d, got %d", i+1, i+1, nonce)
+		}
+	}
+}
@@ -499,3 +499,79 @@ func DeleteBloomBitsDb(db ethdb.KeyValueStore, hashScheme bool, stopCallback fun
 	}
 	return deletePrefixRange(db, bloomBitsMetaPrefix, hashScheme, stopCallback)
 }
+
+// ReadCallTraces retrieves the encoded call frames of the specified block.
+func ReadCallTraces(db ethdb.KeyValueReader, number uint64, hash common.Hash) []byte {
+	data, _ := db.Get(callTraceBlockKey(number, hash))
+	return data
+}
+
+// WriteCallTraces stores the encoded call frames of the specified block.
+func WriteCallTraces(db ethdb.KeyValueWriter, number uint64, hash common.Hash, frames []byte) {
+	if err := db.Put(callTraceBlockKey(number, hash), frames); err != nil {
+		log.Crit("Failed to store call traces", "err", err)
+	}
+}
+
+// ReadCallTraceHashes retrieves the hashes of all the blocks with the given
+// number whose call frames are stored.
+func ReadCallTraceHashes(db ethdb.Iteratee, number uint64) []common.Hash {
+	prefix := append(append([]byte{}, callTraceBlockPrefix...), encodeBlockNumber(number)...)
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	var hashes []common.Hash
+	for it.Next() {
+		if key := it.Key(); len(key) == len(prefix)+common.HashLength {
+			hashes = append(hashes, common.BytesToHash(key[len(prefix):]))
+		}
+	}
+	return hashes
+}
+
+// DeleteCallTraces removes the encoded call frames of the specified block.
+func DeleteCallTraces(db ethdb.KeyValueWriter, number uint64, hash common.Hash) {
+	if err := db.Delete(callTraceBlockKey(number, hash)); err != nil {
+		log.Crit("Failed to delete call traces", "err", err)
+	}
+}
+
+// WriteCallTraceAddress stores an entry marking that the given address is
+// involved in the call frames of the specified block.
+func WriteCallTraceAddress(db ethdb.KeyValueWriter, address common.Address, number uint64, hash common.Hash) {
+	if err := db.Put(callTraceAddressKey(address, number, hash), []byte{}); err != nil {
+		log.Crit("Failed to store call trace address index", "err", err)
+	}
+}
+
+// DeleteCallTraceAddress removes the entry marking that the given address is
+// involved in the call frames of the specified block.
+func DeleteCallTraceAddress(db ethdb.KeyValueWriter, address common.Address, number uint64, hash common.Hash) {
+	if err := db.Delete(callTraceAddressKey(address, number, hash)); err != nil {
+		log.Crit("Failed to delete call trace address index", "err", err)
+	}
+}
+
+// IterateCallTraceAddress iterates over the blocks within the range [from, to]
+// in which the given address is involved in the call frames, in ascending order.
+// The iteration is terminated if the callback returns false. Note, the blocks
+// are not necessarily canonical.
+func IterateCallTraceAddress(db ethdb.Iteratee, address common.Address, from, to uint64, fn func(number uint64, hash common.Hash) bool) {
+	prefix := append(append([]byte{}, callTraceAddressPrefix...), address.Bytes()...)
+	it := db.NewIterator(prefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+8+common.HashLength {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key[len(prefix):])
+		if number > to {
+			return
+		}
+		if !fn(number, common.BytesToHash(key[len(prefix)+8:])) {
+			return
+		}
+	}
+}
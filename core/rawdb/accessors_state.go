@@ -17,6 +17,7 @@
 package rawdb
 
 import (
+	"bytes"
 	"encoding/binary"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -268,3 +269,137 @@ func WriteStateHistory(db ethdb.AncientWriter, id uint64, meta []byte, accountIn
 		return nil
 	})
 }
+
+// ReadStateHistoryIndexHead retrieves the id of the latest state history which
+// has been indexed. Nil is returned if the index has not been initialized yet.
+func ReadStateHistoryIndexHead(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(stateHistoryIndexHeadKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteStateHistoryIndexHead stores the id of the latest indexed state history.
+func WriteStateHistoryIndexHead(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(stateHistoryIndexHeadKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the state history index head", "err", err)
+	}
+}
+
+// DeleteStateHistoryIndexHead removes the state history index head marker.
+func DeleteStateHistoryIndexHead(db ethdb.KeyValueWriter) {
+	if err := db.Delete(stateHistoryIndexHeadKey); err != nil {
+		log.Crit("Failed to delete the state history index head", "err", err)
+	}
+}
+
+// WriteAccountHistoryIndex records that the specified account was modified in
+// the state history with the given id.
+func WriteAccountHistoryIndex(db ethdb.KeyValueWriter, accountHash common.Hash, id uint64) {
+	if err := db.Put(accountHistoryIndexKey(accountHash, id), []byte{}); err != nil {
+		log.Crit("Failed to store account history index", "err", err)
+	}
+}
+
+// DeleteAccountHistoryIndex removes the specified account history index entry.
+func DeleteAccountHistoryIndex(db ethdb.KeyValueWriter, accountHash common.Hash, id uint64) {
+	if err := db.Delete(accountHistoryIndexKey(accountHash, id)); err != nil {
+		log.Crit("Failed to delete account history index", "err", err)
+	}
+}
+
+// WriteStorageHistoryIndex records that the specified storage slot was modified
+// in the state history with the given id.
+func WriteStorageHistoryIndex(db ethdb.KeyValueWriter, accountHash common.Hash, storageHash common.Hash, id uint64) {
+	if err := db.Put(storageHistoryIndexKey(accountHash, storageHash, id), []byte{}); err != nil {
+		log.Crit("Failed to store storage history index", "err", err)
+	}
+}
+
+// DeleteStorageHistoryIndex removes the specified storage history index entry.
+func DeleteStorageHistoryIndex(db ethdb.KeyValueWriter, accountHash common.Hash, storageHash common.Hash, id uint64) {
+	if err := db.Delete(storageHistoryIndexKey(accountHash, storageHash, id)); err != nil {
+		log.Crit("Failed to delete storage history index", "err", err)
+	}
+}
+
+// ReadAccountHistoryIndex returns the id of the first state history in range
+// [start, limit] in which the specified account was modified. False is returned
+// if the account was not touched within the range.
+func ReadAccountHistoryIndex(db ethdb.Iteratee, accountHash common.Hash, start uint64, limit uint64) (uint64, bool) {
+	prefix := accountHistoryIndexKey(accountHash, 0)
+	prefix = prefix[:len(prefix)-8]
+	return seekHistoryIndex(db, prefix, start, limit)
+}
+
+// ReadStorageHistoryIndex returns the id of the first state history in range
+// [start, limit] in which the specified storage slot was modified. False is
+// returned if the slot was not touched within the range.
+func ReadStorageHistoryIndex(db ethdb.Iteratee, accountHash common.Hash, storageHash common.Hash, start uint64, limit uint64) (uint64, bool) {
+	prefix := storageHistoryIndexKey(accountHash, storageHash, 0)
+	prefix = prefix[:len(prefix)-8]
+	return seekHistoryIndex(db, prefix, start, limit)
+}
+
+// seekHistoryIndex locates the first history index entry under the given prefix
+// whose id falls within the range [start, limit].
+func seekHistoryIndex(db ethdb.Iteratee, prefix []byte, start uint64, limit uint64) (uint64, bool) {
+	if start > limit {
+		return 0, false
+	}
+	it := db.NewIterator(prefix, encodeBlockNumber(start))
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(prefix)+8 {
+			continue
+		}
+		id := binary.BigEndian.Uint64(it.Key()[len(prefix):])
+		if id > limit {
+			return 0, false
+		}
+		return id, true
+	}
+	return 0, false
+}
+
+// DeleteStateHistoryIndex removes the entire state history index along with
+// the index head marker.
+func DeleteStateHistoryIndex(db ethdb.KeyValueStore) error {
+	for _, prefix := range [][]byte{StateHistoryAccountIndexPrefix, StateHistoryStorageIndexPrefix} {
+		var (
+			keyLen = len(prefix) + common.HashLength + 8
+			batch  = db.NewBatch()
+			it     = db.NewIterator(prefix, nil)
+		)
+		if bytes.Equal(prefix, StateHistoryStorageIndexPrefix) {
+			keyLen += common.HashLength
+		}
+		for it.Next() {
+			// Skip the unrelated entries, e.g. legacy hash-scheme trie
+			// nodes which happen to share the same prefix.
+			if len(it.Key()) != keyLen {
+				continue
+			}
+			if err := batch.Delete(it.Key()); err != nil {
+				it.Release()
+				return err
+			}
+			if batch.ValueSize() >= ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					it.Release()
+					return err
+				}
+				batch.Reset()
+			}
+		}
+		it.Release()
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	DeleteStateHistoryIndexHead(db)
+	return nil
+}
@@ -17,6 +17,7 @@
 package rawdb
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 
@@ -90,6 +91,9 @@ func inspectFreezers(db ethdb.Database) ([]freezerInfo, error) {
 
 		case MerkleStateFreezerName, VerkleStateFreezerName:
 			datadir, err := db.AncientDatadir()
+			if errors.Is(err, errors.ErrUnsupported) {
+				continue // state freezers are not accessible, e.g. in remote databases
+			}
 			if err != nil {
 				return nil, err
 			}
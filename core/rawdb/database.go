@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"os"
 	"path/filepath"
@@ -163,6 +164,114 @@ func NewDatabase(db ethdb.KeyValueStore) ethdb.Database {
 	return &nofreezedb{KeyValueStore: db}
 }
 
+// historydb is a database wrapper serving the chain segments missing from the
+// ancient store, e.g. pruned history, from a read-only history backend.
+type historydb struct {
+	ethdb.Database
+	history ethdb.AncientReader
+}
+
+// NewDatabaseWithHistory wraps a database, serving the ancient chain data it
+// doesn't contain from the given history backend. The history backend is only
+// read from, and closed along with the database if it implements io.Closer.
+func NewDatabaseWithHistory(db ethdb.Database, history ethdb.AncientReader) ethdb.Database {
+	return &historydb{Database: db, history: history}
+}
+
+// HasAncient returns an indicator whether the specified data exists in either
+// the ancient store or the history backend.
+func (db *historydb) HasAncient(kind string, number uint64) (bool, error) {
+	return (&historyReader{db.Database, db.history}).HasAncient(kind, number)
+}
+
+// Ancient retrieves an ancient binary blob, falling back to the history backend
+// if it's missing from the ancient store.
+func (db *historydb) Ancient(kind string, number uint64) ([]byte, error) {
+	return (&historyReader{db.Database, db.history}).Ancient(kind, number)
+}
+
+// AncientRange retrieves multiple items in sequence, reading the items below
+// the tail of the ancient store from the history backend.
+func (db *historydb) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	return (&historyReader{db.Database, db.history}).AncientRange(kind, start, count, maxBytes)
+}
+
+// ReadAncients runs the given read operation on the ancient store, falling back
+// to the history backend for missing items.
+func (db *historydb) ReadAncients(fn func(ethdb.AncientReaderOp) error) error {
+	return db.Database.ReadAncients(func(op ethdb.AncientReaderOp) error {
+		return fn(&historyReader{op, db.history})
+	})
+}
+
+// Close closes the wrapped database and the history backend.
+func (db *historydb) Close() error {
+	err := db.Database.Close()
+	if closer, ok := db.history.(io.Closer); ok {
+		if cerr := closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// historyReader is an ancient reader falling back to the history backend for
+// the items missing from the ancient store.
+type historyReader struct {
+	ethdb.AncientReaderOp
+	history ethdb.AncientReader
+}
+
+func (r *historyReader) HasAncient(kind string, number uint64) (bool, error) {
+	if has, err := r.AncientReaderOp.HasAncient(kind, number); err == nil && has {
+		return true, nil
+	}
+	return r.history.HasAncient(kind, number)
+}
+
+func (r *historyReader) Ancient(kind string, number uint64) ([]byte, error) {
+	data, err := r.AncientReaderOp.Ancient(kind, number)
+	if err == nil && len(data) > 0 {
+		return data, nil
+	}
+	if hdata, herr := r.history.Ancient(kind, number); herr == nil {
+		return hdata, nil
+	}
+	return data, err
+}
+
+func (r *historyReader) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	tail, _ := r.AncientReaderOp.Tail()
+	if start >= tail {
+		return r.AncientReaderOp.AncientRange(kind, start, count, maxBytes)
+	}
+	// Read the pruned items from the history backend, and the remainder from
+	// the ancient store.
+	n := min(count, tail-start)
+	items, err := r.history.AncientRange(kind, start, n, maxBytes)
+	if err != nil || uint64(len(items)) < n || n == count {
+		return items, err
+	}
+	var size uint64
+	for _, item := range items {
+		size += uint64(len(item))
+	}
+	if maxBytes != 0 {
+		if size >= maxBytes {
+			return items, nil
+		}
+		maxBytes -= size
+	}
+	rest, err := r.AncientReaderOp.AncientRange(kind, tail, count-n, maxBytes)
+	if err != nil {
+		return items, nil
+	}
+	if maxBytes != 0 && len(rest) == 1 && uint64(len(rest[0])) > maxBytes {
+		return items, nil // the ancient store returns at least one item
+	}
+	return append(items, rest...), nil
+}
+
 // resolveChainFreezerDir is a helper function which resolves the absolute path
 // of chain freezer by considering backward compatibility.
 func resolveChainFreezerDir(ancient string) string {
@@ -305,12 +414,17 @@ func NewMemoryDatabase() ethdb.Database {
 const (
 	DBPebble  = "pebble"
 	DBLeveldb = "leveldb"
+	DBBbolt   = "bbolt"
 )
 
 // PreexistingDatabase checks the given data directory whether a database is already
 // instantiated at that location, and if so, returns the type of database (or the
 // empty string).
 func PreexistingDatabase(path string) string {
+	// The bbolt database is a single file, see bbolt.FileName
+	if _, err := os.Stat(filepath.Join(path, "bbolt.db")); err == nil {
+		return DBBbolt
+	}
 	if _, err := os.Stat(filepath.Join(path, "CURRENT")); err != nil {
 		return "" // No pre-existing db
 	}
@@ -373,6 +487,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		hashNumPairings    stat
 		legacyTries        stat
 		stateLookups       stat
+		stateIndexes       stat
+		callTraces         stat
 		accountTries       stat
 		storageTries       stat
 		codes              stat
@@ -427,6 +543,14 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			legacyTries.Add(size)
 		case bytes.HasPrefix(key, stateIDPrefix) && len(key) == len(stateIDPrefix)+common.HashLength:
 			stateLookups.Add(size)
+		case bytes.HasPrefix(key, StateHistoryAccountIndexPrefix) && len(key) == len(StateHistoryAccountIndexPrefix)+common.HashLength+8:
+			stateIndexes.Add(size)
+		case bytes.HasPrefix(key, StateHistoryStorageIndexPrefix) && len(key) == len(StateHistoryStorageIndexPrefix)+2*common.HashLength+8:
+			stateIndexes.Add(size)
+		case bytes.HasPrefix(key, callTraceBlockPrefix) && len(key) == len(callTraceBlockPrefix)+8+common.HashLength:
+			callTraces.Add(size)
+		case bytes.HasPrefix(key, callTraceAddressPrefix) && len(key) == len(callTraceAddressPrefix)+common.AddressLength+8+common.HashLength:
+			callTraces.Add(size)
 		case IsAccountTrieNode(key):
 			accountTries.Add(size)
 		case IsStorageTrieNode(key):
@@ -517,6 +641,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Contract codes", codes.Size(), codes.Count()},
 		{"Key-Value store", "Hash trie nodes", legacyTries.Size(), legacyTries.Count()},
 		{"Key-Value store", "Path trie state lookups", stateLookups.Size(), stateLookups.Count()},
+		{"Key-Value store", "Path state history index", stateIndexes.Size(), stateIndexes.Count()},
+		{"Key-Value store", "Call trace index", callTraces.Size(), callTraces.Count()},
 		{"Key-Value store", "Path trie account nodes", accountTries.Size(), accountTries.Count()},
 		{"Key-Value store", "Path trie storage nodes", storageTries.Size(), storageTries.Count()},
 		{"Key-Value store", "Verkle trie nodes", verkleTries.Size(), verkleTries.Count()},
@@ -566,7 +692,7 @@ var knownMetadataKeys = [][]byte{
 	snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
 	uncleanShutdownKey, badBlockKey, transitionStatusKey, skeletonSyncStatusKey,
 	persistentStateIDKey, trieJournalKey, snapshotSyncStatusKey, snapSyncStatusFlagKey,
-	filterMapsRangeKey,
+	filterMapsRangeKey, stateHistoryIndexHeadKey,
 }
 
 // printChainMetadata prints out chain metadata to stderr.
@@ -0,0 +1,316 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package eradb implements a read-only ancient store backed by a directory of
+// Era1 files, serving the chain history pruned from the freezer.
+package eradb
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// openFileLimit is the maximum number of era files kept open at once.
+const openFileLimit = 64
+
+var (
+	errClosed       = errors.New("era store closed")
+	errOutOfBounds  = errors.New("out of bounds")
+	errUnknownTable = errors.New("unknown table")
+	errNotSupported = errors.New("this operation is not supported")
+)
+
+// Store is a read-only ancient store serving the chain tables of the freezer
+// from Era1 files. It implements ethdb.AncientReader.
+type Store struct {
+	dir   string
+	files []string // Era1 file names, indexed by epoch
+	step  uint64   // Number of blocks per era file
+	items uint64   // Number of blocks stored in the era files
+
+	mu     sync.Mutex // Protects the open files, not held while reading them
+	open   lru.BasicLRU[uint64, *eraFile]
+	closed bool
+}
+
+// eraFile is an open era file, which is closed once it's evicted from the
+// cache and no longer read.
+type eraFile struct {
+	*era.Era
+	refs    int  // Number of ongoing reads
+	evicted bool // Whether the file was evicted from the cache
+}
+
+// New opens the Era1 files of the given network in dir. The epochs of the
+// files must be contiguous, starting from genesis.
+func New(dir string, network string) (*Store, error) {
+	files, err := era.ReadDir(dir, network)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{
+		dir:   dir,
+		files: files,
+		step:  uint64(era.MaxEra1Size),
+		open:  lru.NewBasicLRU[uint64, *eraFile](openFileLimit),
+	}
+	if len(files) > 1 {
+		first, err := s.acquire(0)
+		if err != nil {
+			return nil, err
+		}
+		s.step = first.Count()
+		s.release(first)
+	}
+	if len(files) > 0 {
+		last, err := s.acquire(uint64(len(files) - 1))
+		if err != nil {
+			return nil, err
+		}
+		s.items = last.Start() + last.Count()
+		s.release(last)
+	}
+	log.Info("Opened era history store", "dir", dir, "files", len(files), "blocks", s.items)
+	return s, nil
+}
+
+// Close closes all open era files.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	for _, epoch := range s.open.Keys() {
+		f, _ := s.open.Peek(epoch)
+		s.evict(f)
+	}
+	s.open.Purge()
+	return nil
+}
+
+// CheckChain verifies the era files belong to the chain stored in db, by
+// comparing the hash of the last archived block with the canonical one. The
+// check is skipped if the chain is not yet known up to that block.
+func (s *Store) CheckChain(db ethdb.Reader) error {
+	if s.items == 0 {
+		return nil
+	}
+	want := rawdb.ReadCanonicalHash(db, s.items-1)
+	if want == (common.Hash{}) {
+		return nil
+	}
+	hash, err := s.Ancient(rawdb.ChainFreezerHashTable, s.items-1)
+	if err != nil {
+		return err
+	}
+	if want != common.BytesToHash(hash) {
+		return fmt.Errorf("era block %d hash mismatch: have %x, want %x", s.items-1, hash, want)
+	}
+	return nil
+}
+
+// HasAncient returns an indicator whether the specified data exists in the
+// era files.
+func (s *Store) HasAncient(kind string, number uint64) (bool, error) {
+	if !isChainTable(kind) {
+		return false, errUnknownTable
+	}
+	return number < s.items, nil
+}
+
+// Ancient retrieves the specified item, encoded as in the chain freezer.
+func (s *Store) Ancient(kind string, number uint64) ([]byte, error) {
+	if !isChainTable(kind) {
+		return nil, errUnknownTable
+	}
+	if number >= s.items {
+		return nil, errOutOfBounds
+	}
+	e, err := s.acquire(number / s.step)
+	if err != nil {
+		return nil, err
+	}
+	defer s.release(e)
+
+	switch kind {
+	case rawdb.ChainFreezerHeaderTable:
+		header, err := e.GetHeaderByNumber(number)
+		if err != nil {
+			return nil, err
+		}
+		return rlp.EncodeToBytes(header)
+
+	case rawdb.ChainFreezerHashTable:
+		header, err := e.GetHeaderByNumber(number)
+		if err != nil {
+			return nil, err
+		}
+		return header.Hash().Bytes(), nil
+
+	case rawdb.ChainFreezerBodiesTable:
+		block, err := e.GetBlockByNumber(number)
+		if err != nil {
+			return nil, err
+		}
+		return rlp.EncodeToBytes(block.Body())
+
+	default:
+		receipts, err := e.GetReceiptsByNumber(number)
+		if err != nil {
+			return nil, err
+		}
+		// The freezer stores receipts in their storage encoding.
+		stored := make([]*types.ReceiptForStorage, len(receipts))
+		for i, receipt := range receipts {
+			stored[i] = (*types.ReceiptForStorage)(receipt)
+		}
+		return rlp.EncodeToBytes(stored)
+	}
+}
+
+// AncientRange retrieves multiple items in sequence, starting from the index
+// 'start'. It returns at most 'count' items, and if maxBytes is specified, at
+// least one item but otherwise as many as fit into maxBytes.
+func (s *Store) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	if start >= s.items {
+		return nil, errOutOfBounds
+	}
+	var (
+		items [][]byte
+		size  uint64
+	)
+	for number := start; number < start+count && number < s.items; number++ {
+		item, err := s.Ancient(kind, number)
+		if err != nil {
+			return nil, err
+		}
+		if maxBytes != 0 && len(items) > 0 && size+uint64(len(item)) > maxBytes {
+			break
+		}
+		items = append(items, item)
+		size += uint64(len(item))
+	}
+	return items, nil
+}
+
+// Ancients returns the number of blocks stored in the era files.
+func (s *Store) Ancients() (uint64, error) {
+	return s.items, nil
+}
+
+// Tail returns the number of the first stored item, which is always genesis.
+func (s *Store) Tail() (uint64, error) {
+	return 0, nil
+}
+
+// AncientSize is not supported, as the era files are compressed archives of
+// all the chain tables at once.
+func (s *Store) AncientSize(kind string) (uint64, error) {
+	return 0, errNotSupported
+}
+
+// ReadAncients runs the given read operation. The era files being immutable,
+// no locking is needed.
+func (s *Store) ReadAncients(fn func(ethdb.AncientReaderOp) error) error {
+	return fn(s)
+}
+
+// acquire returns the era file of the given epoch, opening it if needed. The
+// file must be released once it's no longer read.
+func (s *Store) acquire(epoch uint64) (*eraFile, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, errClosed
+	}
+	if f, ok := s.open.Get(epoch); ok {
+		f.refs++
+		s.mu.Unlock()
+		return f, nil
+	}
+	s.mu.Unlock()
+
+	// Open the file without holding the lock, another reader might open it
+	// concurrently, in which case the first one opened is kept.
+	e, err := era.Open(filepath.Join(s.dir, s.files[epoch]))
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		e.Close()
+		return nil, errClosed
+	}
+	if f, ok := s.open.Get(epoch); ok {
+		e.Close()
+		f.refs++
+		return f, nil
+	}
+	if s.open.Len() >= openFileLimit {
+		if _, old, ok := s.open.RemoveOldest(); ok {
+			s.evict(old)
+		}
+	}
+	f := &eraFile{Era: e, refs: 1}
+	s.open.Add(epoch, f)
+	return f, nil
+}
+
+// release marks a read of the given era file finished, closing the file if it
+// was evicted in the meantime.
+func (s *Store) release(f *eraFile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f.refs--
+	if f.refs == 0 && f.evicted {
+		f.Close()
+	}
+}
+
+// evict marks the era file evicted from the cache, closing it unless it's still
+// being read. The caller must hold the lock.
+func (s *Store) evict(f *eraFile) {
+	f.evicted = true
+	if f.refs == 0 {
+		f.Close()
+	}
+}
+
+// isChainTable reports whether the given table is served by the era files.
+func isChainTable(kind string) bool {
+	switch kind {
+	case rawdb.ChainFreezerHeaderTable, rawdb.ChainFreezerHashTable, rawdb.ChainFreezerBodiesTable, rawdb.ChainFreezerReceiptTable:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eradb
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	testBlocks = 20 // Number of blocks in the test chain
+	testStep   = 8  // Number of blocks per era file
+	testPruned = 16 // Number of blocks pruned from the freezer
+)
+
+// newTestChain generates a chain, exports it into era files and imports it
+// into a freezer database, with its history pruned.
+func newTestChain(t *testing.T) (string, []*types.Block, ethdb.Database) {
+	var (
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		genesis = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(genesis.Config)
+	)
+	_, blocks, receipts := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), testBlocks, func(i int, g *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(g.TxNonce(address), common.Address{0xaa}, big.NewInt(1), params.TxGas, g.BaseFee(), nil), signer, key)
+		g.AddTx(tx)
+	})
+	blocks = append([]*types.Block{genesis.ToBlock()}, blocks...)
+	receipts = append([]types.Receipts{nil}, receipts...)
+
+	// Export the chain into era files.
+	var (
+		dir = t.TempDir()
+		td  = new(big.Int)
+	)
+	for epoch := 0; epoch*testStep < len(blocks); epoch++ {
+		f, err := os.Create(filepath.Join(dir, era.Filename("mainnet", epoch, common.Hash{})))
+		if err != nil {
+			t.Fatalf("failed to create era file: %v", err)
+		}
+		builder := era.NewBuilder(f)
+		for n := epoch * testStep; n < (epoch+1)*testStep && n < len(blocks); n++ {
+			td.Add(td, blocks[n].Difficulty())
+			if err := builder.Add(blocks[n], receipts[n], new(big.Int).Set(td)); err != nil {
+				t.Fatalf("failed to add block %d: %v", n, err)
+			}
+		}
+		if _, err := builder.Finalize(); err != nil {
+			t.Fatalf("failed to finalize era file: %v", err)
+		}
+		f.Close()
+	}
+	// Import the chain into the freezer and prune its history.
+	db, err := rawdb.NewDatabaseWithFreezer(memorydb.New(), "", "", false)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	chain, err := core.NewBlockChain(db, nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	t.Cleanup(chain.Stop)
+	if _, err := chain.InsertReceiptChain(blocks[1:], receipts[1:], uint64(len(blocks))); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	if _, err := db.TruncateTail(testPruned); err != nil {
+		t.Fatalf("failed to prune history: %v", err)
+	}
+	return dir, blocks, db
+}
+
+func TestHistoryFallback(t *testing.T) {
+	dir, blocks, db := newTestChain(t)
+
+	store, err := New(dir, "mainnet")
+	if err != nil {
+		t.Fatalf("failed to open era store: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.CheckChain(db); err != nil {
+		t.Fatalf("era chain mismatch: %v", err)
+	}
+	if items, _ := store.Ancients(); items != uint64(len(blocks)) {
+		t.Fatalf("wrong number of items: have %d, want %d", items, len(blocks))
+	}
+	if body := rawdb.ReadBody(db, blocks[1].Hash(), 1); body != nil {
+		t.Fatal("pruned body still available")
+	}
+	history := rawdb.NewDatabaseWithHistory(db, store)
+	for _, block := range blocks {
+		var (
+			hash   = block.Hash()
+			number = block.NumberU64()
+		)
+		if have := rawdb.ReadCanonicalHash(history, number); have != hash {
+			t.Fatalf("block %d: hash mismatch: have %x, want %x", number, have, hash)
+		}
+		have := rawdb.ReadBlock(history, hash, number)
+		if have == nil {
+			t.Fatalf("block %d: missing", number)
+		}
+		if types.DeriveSha(have.Transactions(), trie.NewStackTrie(nil)) != block.TxHash() {
+			t.Fatalf("block %d: body mismatch", number)
+		}
+		receipts := rawdb.ReadRawReceipts(history, hash, number)
+		if number > 0 && receipts == nil {
+			t.Fatalf("block %d: missing receipts", number)
+		}
+		if types.DeriveSha(receipts, trie.NewStackTrie(nil)) != block.ReceiptHash() {
+			t.Fatalf("block %d: receipts mismatch", number)
+		}
+	}
+	// Ranges spanning the pruned and the frozen items are stitched together.
+	items, err := history.AncientRange(rawdb.ChainFreezerBodiesTable, testPruned-4, 8, 0)
+	if err != nil {
+		t.Fatalf("failed to read range: %v", err)
+	}
+	if len(items) != 8 {
+		t.Fatalf("wrong range length: have %d, want 8", len(items))
+	}
+	for i, item := range items {
+		want, _ := history.Ancient(rawdb.ChainFreezerBodiesTable, uint64(testPruned-4+i))
+		if !bytes.Equal(item, want) {
+			t.Fatalf("range item %d mismatch", i)
+		}
+	}
+}
+
+func TestCheckChainMismatch(t *testing.T) {
+	dir, _, _ := newTestChain(t)
+	_, _, other := newTestChain(t)
+
+	store, err := New(dir, "mainnet")
+	if err != nil {
+		t.Fatalf("failed to open era store: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.CheckChain(other); err == nil {
+		t.Fatal("expected chain mismatch")
+	}
+}
+
+func TestConcurrentReads(t *testing.T) {
+	dir, blocks, _ := newTestChain(t)
+
+	store, err := New(dir, "mainnet")
+	if err != nil {
+		t.Fatalf("failed to open era store: %v", err)
+	}
+	defer store.Close()
+
+	// Keep a single file open, so that files are evicted while being read.
+	store.open = lru.NewBasicLRU[uint64, *eraFile](1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				number := uint64((i + j) % len(blocks))
+				hash, err := store.Ancient(rawdb.ChainFreezerHashTable, number)
+				if err != nil {
+					t.Errorf("block %d: failed to read hash: %v", number, err)
+					return
+				}
+				if common.BytesToHash(hash) != blocks[number].Hash() {
+					t.Errorf("block %d: hash mismatch", number)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -96,6 +96,9 @@ var (
 	// snapSyncStatusFlagKey flags that status of snap sync.
 	snapSyncStatusFlagKey = []byte("SnapSyncStatus")
 
+	// stateHistoryIndexHeadKey tracks the id of the latest indexed state history.
+	stateHistoryIndexHeadKey = []byte("LastStateHistoryIndex")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td (deprecated)
@@ -117,6 +120,14 @@ var (
 	TrieNodeStoragePrefix = []byte("O") // TrieNodeStoragePrefix + accountHash + hexPath -> trie node
 	stateIDPrefix         = []byte("L") // stateIDPrefix + state root -> state id
 
+	// State history index of path-based storage scheme.
+	StateHistoryAccountIndexPrefix = []byte("ma") // StateHistoryAccountIndexPrefix + account hash + id (uint64 big endian) -> nil
+	StateHistoryStorageIndexPrefix = []byte("ms") // StateHistoryStorageIndexPrefix + account hash + storage hash + id (uint64 big endian) -> nil
+
+	// Call trace index, maintained by the call index live tracer
+	callTraceBlockPrefix   = []byte("Tb") // callTraceBlockPrefix + num (uint64 big endian) + hash -> call frames of the block
+	callTraceAddressPrefix = []byte("Ta") // callTraceAddressPrefix + address + num (uint64 big endian) + hash -> nil
+
 	// VerklePrefix is the database prefix for Verkle trie data, which includes:
 	// (a) Trie nodes
 	// (b) In-memory trie node journal
@@ -257,6 +268,40 @@ func stateIDKey(root common.Hash) []byte {
 	return append(stateIDPrefix, root.Bytes()...)
 }
 
+// accountHistoryIndexKey = StateHistoryAccountIndexPrefix + account hash + id (uint64 big endian)
+func accountHistoryIndexKey(accountHash common.Hash, id uint64) []byte {
+	buf := make([]byte, len(StateHistoryAccountIndexPrefix)+common.HashLength+8)
+	n := copy(buf, StateHistoryAccountIndexPrefix)
+	n += copy(buf[n:], accountHash.Bytes())
+	binary.BigEndian.PutUint64(buf[n:], id)
+	return buf
+}
+
+// storageHistoryIndexKey = StateHistoryStorageIndexPrefix + account hash + storage hash + id (uint64 big endian)
+func storageHistoryIndexKey(accountHash common.Hash, storageHash common.Hash, id uint64) []byte {
+	buf := make([]byte, len(StateHistoryStorageIndexPrefix)+2*common.HashLength+8)
+	n := copy(buf, StateHistoryStorageIndexPrefix)
+	n += copy(buf[n:], accountHash.Bytes())
+	n += copy(buf[n:], storageHash.Bytes())
+	binary.BigEndian.PutUint64(buf[n:], id)
+	return buf
+}
+
+// callTraceBlockKey = callTraceBlockPrefix + num (uint64 big endian) + hash
+func callTraceBlockKey(number uint64, hash common.Hash) []byte {
+	return append(append(callTraceBlockPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// callTraceAddressKey = callTraceAddressPrefix + address + num (uint64 big endian) + hash
+func callTraceAddressKey(address common.Address, number uint64, hash common.Hash) []byte {
+	buf := make([]byte, len(callTraceAddressPrefix)+common.AddressLength+8+common.HashLength)
+	n := copy(buf, callTraceAddressPrefix)
+	n += copy(buf[n:], address.Bytes())
+	binary.BigEndian.PutUint64(buf[n:], number)
+	copy(buf[n+8:], hash.Bytes())
+	return buf
+}
+
 // accountTrieNodeKey = TrieNodeAccountPrefix + nodePath.
 func accountTrieNodeKey(path []byte) []byte {
 	return append(TrieNodeAccountPrefix, path...)
@@ -332,6 +377,60 @@ func IsStorageTrieNode(key []byte) bool {
 	return ok
 }
 
+// liveMetadataKeys are the metadata entries tracking the chain and state data
+// of a running node.
+var liveMetadataKeys = [][]byte{
+	headHeaderKey, headBlockKey, headFastBlockKey, headFinalizedBlockKey, persistentStateIDKey,
+	lastPivotKey, SnapshotRootKey, snapshotJournalKey, snapshotGeneratorKey, snapshotRecoveryKey,
+	snapshotSyncStatusKey, skeletonSyncStatusKey, trieJournalKey, txIndexTailKey, snapSyncStatusFlagKey,
+	stateHistoryIndexHeadKey, filterMapsRangeKey,
+}
+
+// IsLiveKey reports whether a provided database key holds chain, state or index
+// data which a running node actively uses and caches in memory. Modifying such
+// entries behind the node's back leaves it inconsistent. Any key of hash length
+// is considered a trie node of the hash-based state scheme.
+func IsLiveKey(key []byte) bool {
+	for _, meta := range liveMetadataKeys {
+		if bytes.Equal(key, meta) {
+			return true
+		}
+	}
+	switch {
+	case len(key) == common.HashLength:
+		return true
+	case bytes.HasPrefix(key, headerPrefix) && len(key) >= len(headerPrefix)+8:
+		return true
+	case bytes.HasPrefix(key, headerNumberPrefix) && len(key) == len(headerNumberPrefix)+common.HashLength:
+		return true
+	case bytes.HasPrefix(key, blockBodyPrefix) && len(key) == len(blockBodyPrefix)+8+common.HashLength:
+		return true
+	case bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == len(blockReceiptsPrefix)+8+common.HashLength:
+		return true
+	case bytes.HasPrefix(key, txLookupPrefix) && len(key) == len(txLookupPrefix)+common.HashLength:
+		return true
+	case bytes.HasPrefix(key, SnapshotAccountPrefix) && len(key) == len(SnapshotAccountPrefix)+common.HashLength:
+		return true
+	case bytes.HasPrefix(key, SnapshotStoragePrefix) && len(key) == len(SnapshotStoragePrefix)+2*common.HashLength:
+		return true
+	case bytes.HasPrefix(key, CodePrefix) && len(key) == len(CodePrefix)+common.HashLength:
+		return true
+	case bytes.HasPrefix(key, skeletonHeaderPrefix) && len(key) == len(skeletonHeaderPrefix)+8:
+		return true
+	case bytes.HasPrefix(key, stateIDPrefix) && len(key) == len(stateIDPrefix)+common.HashLength:
+		return true
+	case IsAccountTrieNode(key) || IsStorageTrieNode(key):
+		return true
+	case bytes.HasPrefix(key, StateHistoryAccountIndexPrefix) || bytes.HasPrefix(key, StateHistoryStorageIndexPrefix):
+		return true
+	case bytes.HasPrefix(key, callTraceBlockPrefix) || bytes.HasPrefix(key, callTraceAddressPrefix):
+		return true
+	case bytes.HasPrefix(key, VerklePrefix) || bytes.HasPrefix(key, []byte(filterMapsPrefix)):
+		return true
+	}
+	return false
+}
+
 // filterMapRowKey = filterMapRowPrefix + mapRowIndex (uint64 big endian)
 func filterMapRowKey(mapRowIndex uint64, base bool) []byte {
 	extLen := 8
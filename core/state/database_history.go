@@ -0,0 +1,205 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/trie/utils"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/database"
+)
+
+// errHistoricTrieReadOnly is returned if a mutation is attempted on the trie
+// opened on top of a historical state.
+var errHistoricTrieReadOnly = errors.New("historic trie is read-only")
+
+// HistoricDB is an implementation of Database interface, providing access to
+// historical states that are no longer available in the trie database. The
+// states are resolved from the indexed state histories, which is only supported
+// by the path-based trie database with state indexing enabled.
+//
+// The states opened by the HistoricDB are meant for reading only, the tries are
+// not available and all the trie mutations are rejected.
+type HistoricDB struct {
+	disk          ethdb.KeyValueStore
+	triedb        *triedb.Database
+	codeCache     *lru.SizeConstrainedCache[common.Hash, []byte]
+	codeSizeCache *lru.Cache[common.Hash, int]
+	pointCache    *utils.PointCache
+}
+
+// NewHistoricDatabase creates a historic state database with the provided
+// data sources.
+func NewHistoricDatabase(disk ethdb.KeyValueStore, triedb *triedb.Database) *HistoricDB {
+	return &HistoricDB{
+		disk:          disk,
+		triedb:        triedb,
+		codeCache:     lru.NewSizeConstrainedCache[common.Hash, []byte](codeCacheSize),
+		codeSizeCache: lru.NewCache[common.Hash, int](codeSizeCacheSize),
+		pointCache:    utils.NewPointCache(pointCacheSize),
+	}
+}
+
+// Reader implements Database interface, returning a reader of the specified
+// historical state.
+func (db *HistoricDB) Reader(stateRoot common.Hash) (Reader, error) {
+	hr, err := db.triedb.HistoricReader(stateRoot)
+	if err != nil {
+		return nil, err
+	}
+	return newReader(newCachingCodeReader(db.disk, db.codeCache, db.codeSizeCache), newFlatReader(hr)), nil
+}
+
+// OpenTrie implements Database interface, returning a read-only trie of the
+// specified historical state.
+func (db *HistoricDB) OpenTrie(root common.Hash) (Trie, error) {
+	hr, err := db.triedb.HistoricReader(root)
+	if err != nil {
+		return nil, err
+	}
+	return &historicTrie{root: root, reader: hr, flat: newFlatReader(hr)}, nil
+}
+
+// OpenStorageTrie implements Database interface, returning a read-only storage
+// trie of the specified account.
+func (db *HistoricDB) OpenStorageTrie(stateRoot common.Hash, address common.Address, root common.Hash, self Trie) (Trie, error) {
+	hr, err := db.triedb.HistoricReader(stateRoot)
+	if err != nil {
+		return nil, err
+	}
+	return &historicTrie{root: root, reader: hr, flat: newFlatReader(hr)}, nil
+}
+
+// PointCache returns the cache of evaluated curve points.
+func (db *HistoricDB) PointCache() *utils.PointCache {
+	return db.pointCache
+}
+
+// TrieDB returns the underlying trie database for managing trie nodes.
+func (db *HistoricDB) TrieDB() *triedb.Database {
+	return db.triedb
+}
+
+// Snapshot returns the underlying state snapshot, which is not available for
+// historical states.
+func (db *HistoricDB) Snapshot() *snapshot.Tree {
+	return nil
+}
+
+// historicTrie is a read-only Trie implementation backed by a historical state
+// reader. Only the account and storage retrievals are supported.
+type historicTrie struct {
+	root   common.Hash
+	reader database.StateReader
+	flat   *flatReader
+}
+
+// GetKey implements Trie, the preimages are not tracked.
+func (t *historicTrie) GetKey(key []byte) []byte {
+	return nil
+}
+
+// GetAccount implements Trie, retrieving the account with the given address.
+func (t *historicTrie) GetAccount(address common.Address) (*types.StateAccount, error) {
+	return t.flat.Account(address)
+}
+
+// GetStorage implements Trie, retrieving the storage slot with the given key.
+func (t *historicTrie) GetStorage(addr common.Address, key []byte) ([]byte, error) {
+	value, err := t.flat.Storage(addr, common.BytesToHash(key))
+	if err != nil {
+		return nil, err
+	}
+	if value == (common.Hash{}) {
+		return nil, nil
+	}
+	return common.TrimLeftZeroes(value.Bytes()), nil
+}
+
+// UpdateAccount implements Trie, which is not supported.
+func (t *historicTrie) UpdateAccount(address common.Address, account *types.StateAccount, codeLen int) error {
+	return errHistoricTrieReadOnly
+}
+
+// UpdateStorage implements Trie, which is not supported.
+func (t *historicTrie) UpdateStorage(addr common.Address, key, value []byte) error {
+	return errHistoricTrieReadOnly
+}
+
+// DeleteAccount implements Trie, which is not supported.
+func (t *historicTrie) DeleteAccount(address common.Address) error {
+	return errHistoricTrieReadOnly
+}
+
+// DeleteStorage implements Trie, which is not supported.
+func (t *historicTrie) DeleteStorage(addr common.Address, key []byte) error {
+	return errHistoricTrieReadOnly
+}
+
+// UpdateContractCode implements Trie, which is not supported.
+func (t *historicTrie) UpdateContractCode(address common.Address, codeHash common.Hash, code []byte) error {
+	return errHistoricTrieReadOnly
+}
+
+// Hash implements Trie, returning the root hash of the historical state. As
+// the trie can't be mutated, the hash always stays the same.
+func (t *historicTrie) Hash() common.Hash {
+	return t.root
+}
+
+// Commit implements Trie. Nothing will be committed as the trie can't be
+// mutated.
+func (t *historicTrie) Commit(collectLeaf bool) (common.Hash, *trienode.NodeSet) {
+	return t.root, nil
+}
+
+// Witness implements Trie, the witness is not tracked.
+func (t *historicTrie) Witness() map[string]struct{} {
+	return nil
+}
+
+// NodeIterator implements Trie, which is not supported.
+func (t *historicTrie) NodeIterator(startKey []byte) (trie.NodeIterator, error) {
+	return nil, errors.New("node iteration is not supported by historic trie")
+}
+
+// Prove implements Trie, which is not supported.
+func (t *historicTrie) Prove(key []byte, proofDb ethdb.KeyValueWriter) error {
+	return errors.New("proof generation is not supported by historic trie")
+}
+
+// IsVerkle implements Trie, historical states are only available for merkle tries.
+func (t *historicTrie) IsVerkle() bool {
+	return false
+}
+
+// copy returns a deep-copied historic trie.
+func (t *historicTrie) copy() *historicTrie {
+	return &historicTrie{
+		root:   t.root,
+		reader: t.reader,
+		flat:   newFlatReader(t.reader),
+	}
+}
@@ -17,6 +17,9 @@
 package core
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
@@ -78,3 +81,37 @@ func ExecuteStateless(config *params.ChainConfig, vmconfig vm.Config, block *typ
 	stateRoot := db.IntermediateRoot(config.IsEIP158(block.Number()))
 	return stateRoot, receiptRoot, nil
 }
+
+// GenerateWitness re-executes a block on top of its parent state and returns the
+// witness required to execute it statelessly. The chain is not modified.
+func (bc *BlockChain) GenerateWitness(block *types.Block) (*stateless.Witness, error) {
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not executable")
+	}
+	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %x not found", block.ParentHash())
+	}
+	statedb, err := bc.StateAt(parent.Root)
+	if err != nil {
+		return nil, err
+	}
+	witness, err := stateless.NewWitness(block.Header(), bc)
+	if err != nil {
+		return nil, err
+	}
+	statedb.StartPrefetcher("witness", witness)
+	defer statedb.StopPrefetcher()
+
+	// The block was already imported, don't report it to the live tracer again.
+	res, err := bc.processor.Process(block, statedb, vm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	// Validating the state also hashes the tries, pulling the nodes needed
+	// for the post-state root into the witness.
+	if err := bc.validator.ValidateState(block, statedb, res, false); err != nil {
+		return nil, err
+	}
+	return witness, nil
+}
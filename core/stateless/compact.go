@@ -0,0 +1,442 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package stateless
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// The compact witness encoding is a stream of witnesses, deduplicating headers,
+// bytecodes and trie nodes across all the witnesses of the stream:
+//
+//	stream  = magic || version || compression || uvarint(limit) || record*
+//	record  = uvarint(len(payload)) || payload
+//	payload = compress(headers || codes || nodes)
+//	list    = uvarint(count) || item*
+//	item    = uvarint(0) || uvarint(len(data)) || data
+//	        | uvarint(index + 1)
+//
+// Each list item either carries new data, which is appended to the table of
+// its kind (headers are RLP encoded), or references an item of an earlier
+// witness by its index within that table. Codes and nodes are sorted to make
+// the encoding deterministic. Once the tables hold at least limit items when
+// a record starts, they are all cleared, bounding the memory used on both the
+// encoding and decoding side.
+const (
+	compactVersion    = 1       // Version of the compact witness encoding
+	compactDedupLimit = 1 << 20 // Number of items deduplicated against before a reset
+
+	// maxCompactDedupLimit is the maximum deduplication limit accepted by the
+	// decoder, bounding the size of its tables regardless of the stream header.
+	maxCompactDedupLimit = 1 << 24
+
+	// maxCompactRecordSize is the maximum size of a single record, compressed
+	// or not, accepted by the decoder.
+	maxCompactRecordSize = 512 * 1024 * 1024
+)
+
+var compactMagic = []byte("gwit")
+
+var (
+	errNotCompactWitness = errors.New("not a compact witness stream")
+	errRecordTooLarge    = errors.New("witness record too large")
+	errInvalidReference  = errors.New("invalid witness item reference")
+)
+
+// Compression is the compression algorithm applied to the records of a compact
+// witness stream.
+type Compression byte
+
+const (
+	NoCompression Compression = iota
+	SnappyCompression
+	ZstdCompression
+)
+
+// String implements fmt.Stringer.
+func (c Compression) String() string {
+	switch c {
+	case NoCompression:
+		return "none"
+	case SnappyCompression:
+		return "snappy"
+	case ZstdCompression:
+		return "zstd"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(c))
+	}
+}
+
+// ParseCompression converts a compression name into the algorithm.
+func ParseCompression(name string) (Compression, error) {
+	switch name {
+	case "", "none":
+		return NoCompression, nil
+	case "snappy":
+		return SnappyCompression, nil
+	case "zstd":
+		return ZstdCompression, nil
+	default:
+		return 0, fmt.Errorf("unknown witness compression %q", name)
+	}
+}
+
+// WitnessEncoder writes a stream of witnesses in the compact encoding.
+type WitnessEncoder struct {
+	w           io.Writer
+	compression Compression
+	limit       uint64
+
+	headers map[common.Hash]uint64 // Indices of the headers already encoded
+	codes   map[string]uint64      // Indices of the bytecodes already encoded
+	nodes   map[string]uint64      // Indices of the trie nodes already encoded
+
+	payload []byte // Scratch buffer for the uncompressed payload
+	record  []byte // Scratch buffer for the compressed record
+}
+
+// NewWitnessEncoder creates an encoder writing compact witnesses into w, with
+// the records compressed using the given algorithm. The stream header is
+// written right away.
+func NewWitnessEncoder(w io.Writer, compression Compression) (*WitnessEncoder, error) {
+	if err := checkCompression(compression); err != nil {
+		return nil, err
+	}
+	header := append(slices.Clone(compactMagic), compactVersion, byte(compression))
+	header = binary.AppendUvarint(header, compactDedupLimit)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	e := &WitnessEncoder{
+		w:           w,
+		compression: compression,
+		limit:       compactDedupLimit,
+	}
+	e.reset()
+	return e, nil
+}
+
+// reset clears the deduplication tables.
+func (e *WitnessEncoder) reset() {
+	e.headers = make(map[common.Hash]uint64)
+	e.codes = make(map[string]uint64)
+	e.nodes = make(map[string]uint64)
+}
+
+// Encode appends a witness to the stream, returning the number of bytes it
+// took up, including the length prefix.
+func (e *WitnessEncoder) Encode(w *Witness) (int, error) {
+	if uint64(len(e.headers)+len(e.codes)+len(e.nodes)) >= e.limit {
+		e.reset()
+	}
+	// Encode the witness into the uncompressed payload
+	payload := binary.AppendUvarint(e.payload[:0], uint64(len(w.Headers)))
+	for _, header := range w.Headers {
+		hash := header.Hash()
+		if index, ok := e.headers[hash]; ok {
+			payload = binary.AppendUvarint(payload, index+1)
+			continue
+		}
+		blob, err := rlp.EncodeToBytes(header)
+		if err != nil {
+			return 0, err
+		}
+		payload = appendCompactItem(payload, blob)
+		e.headers[hash] = uint64(len(e.headers))
+	}
+	payload = appendCompactSet(payload, w.Codes, e.codes)
+	payload = appendCompactSet(payload, w.State, e.nodes)
+	e.payload = payload
+
+	// Compress the payload and write it out with a length prefix
+	data, err := compress(e.compression, e.record, payload)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) > maxCompactRecordSize {
+		return 0, errRecordTooLarge
+	}
+	e.record = data
+
+	prefix := binary.AppendUvarint(nil, uint64(len(data)))
+	if _, err := e.w.Write(prefix); err != nil {
+		return 0, err
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return 0, err
+	}
+	return len(prefix) + len(data), nil
+}
+
+// appendCompactItem appends a new item to the payload.
+func appendCompactItem(payload []byte, data []byte) []byte {
+	payload = binary.AppendUvarint(payload, 0)
+	payload = binary.AppendUvarint(payload, uint64(len(data)))
+	return append(payload, data...)
+}
+
+// appendCompactSet appends a set of blobs to the payload in sorted order,
+// referencing the ones already present in the table and adding the rest.
+func appendCompactSet(payload []byte, set map[string]struct{}, table map[string]uint64) []byte {
+	payload = binary.AppendUvarint(payload, uint64(len(set)))
+	for _, blob := range slices.Sorted(maps.Keys(set)) {
+		if index, ok := table[blob]; ok {
+			payload = binary.AppendUvarint(payload, index+1)
+			continue
+		}
+		payload = binary.AppendUvarint(payload, 0)
+		payload = binary.AppendUvarint(payload, uint64(len(blob)))
+		payload = append(payload, blob...)
+		table[blob] = uint64(len(table))
+	}
+	return payload
+}
+
+// WitnessDecoder reads witnesses one by one from a compact witness stream.
+type WitnessDecoder struct {
+	r           *bufio.Reader
+	compression Compression
+	limit       uint64
+
+	headers []*types.Header // Headers decoded so far, by index
+	codes   []string        // Bytecodes decoded so far, by index
+	nodes   []string        // Trie nodes decoded so far, by index
+
+	record  []byte // Scratch buffer for the compressed record
+	payload []byte // Scratch buffer for the uncompressed payload
+}
+
+// NewWitnessDecoder creates a decoder reading compact witnesses from r, and
+// parses the stream header.
+func NewWitnessDecoder(r io.Reader) (*WitnessDecoder, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(compactMagic)+2)
+	if _, err := io.ReadFull(br, header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, errNotCompactWitness
+		}
+		return nil, err
+	}
+	if !bytes.Equal(header[:len(compactMagic)], compactMagic) {
+		return nil, errNotCompactWitness
+	}
+	if version := header[len(compactMagic)]; version != compactVersion {
+		return nil, fmt.Errorf("unsupported compact witness version %d", version)
+	}
+	compression := Compression(header[len(compactMagic)+1])
+	if err := checkCompression(compression); err != nil {
+		return nil, err
+	}
+	limit, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compact witness header: %w", err)
+	}
+	if limit > maxCompactDedupLimit {
+		return nil, fmt.Errorf("compact witness dedup limit too large (%d, max %d)", limit, maxCompactDedupLimit)
+	}
+	return &WitnessDecoder{
+		r:           br,
+		compression: compression,
+		limit:       limit,
+	}, nil
+}
+
+// Compression returns the compression algorithm used by the stream.
+func (d *WitnessDecoder) Compression() Compression {
+	return d.compression
+}
+
+// Decode reads the next witness from the stream. It returns io.EOF once the
+// stream is exhausted.
+func (d *WitnessDecoder) Decode() (*Witness, error) {
+	size, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return nil, err // io.EOF if the stream ended cleanly
+	}
+	if size > maxCompactRecordSize {
+		return nil, errRecordTooLarge
+	}
+	if uint64(cap(d.record)) < size {
+		d.record = make([]byte, size)
+	}
+	d.record = d.record[:size]
+	if _, err := io.ReadFull(d.r, d.record); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	payload, err := decompress(d.compression, d.payload, d.record, maxCompactRecordSize)
+	if err != nil {
+		return nil, err
+	}
+	d.payload = payload
+
+	if uint64(len(d.headers)+len(d.codes)+len(d.nodes)) >= d.limit {
+		d.headers, d.codes, d.nodes = nil, nil, nil
+	}
+	return d.decodePayload(bytes.NewReader(payload))
+}
+
+// decodePayload parses an uncompressed record into a witness, resolving the
+// references to earlier items and registering the new ones.
+func (d *WitnessDecoder) decodePayload(r *bytes.Reader) (*Witness, error) {
+	// Decode the headers in their original order
+	count, err := readCompactCount(r)
+	if err != nil {
+		return nil, err
+	}
+	headers := make([]*types.Header, 0, count)
+	for i := uint64(0); i < count; i++ {
+		index, blob, err := readCompactItem(r, uint64(len(d.headers)))
+		if err != nil {
+			return nil, err
+		}
+		if blob == nil {
+			headers = append(headers, d.headers[index])
+			continue
+		}
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(blob, header); err != nil {
+			return nil, fmt.Errorf("invalid witness header: %w", err)
+		}
+		d.headers = append(d.headers, header)
+		headers = append(headers, header)
+	}
+	// Decode the bytecode and trie node sets
+	codes, err := readCompactSet(r, &d.codes)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := readCompactSet(r, &d.nodes)
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes in witness record", r.Len())
+	}
+	return &Witness{
+		Headers: headers,
+		Codes:   codes,
+		State:   nodes,
+	}, nil
+}
+
+// readCompactCount reads the number of items in a list, sanity checking it
+// against the remaining payload size.
+func readCompactCount(r *bytes.Reader) (uint64, error) {
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, fmt.Errorf("invalid witness record: %w", err)
+	}
+	if count > uint64(r.Len()) {
+		return 0, fmt.Errorf("witness item count %d exceeds record size", count)
+	}
+	return count, nil
+}
+
+// readCompactItem reads a list item. New items are returned as a data blob,
+// references as an index into the table of the given size, with a nil blob.
+func readCompactItem(r *bytes.Reader, tableSize uint64) (uint64, []byte, error) {
+	ref, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid witness item: %w", err)
+	}
+	if ref != 0 {
+		if ref > tableSize {
+			return 0, nil, errInvalidReference
+		}
+		return ref - 1, nil, nil
+	}
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid witness item: %w", err)
+	}
+	if size > uint64(r.Len()) {
+		return 0, nil, fmt.Errorf("witness item size %d exceeds record size", size)
+	}
+	blob := make([]byte, size)
+	r.Read(blob)
+	return 0, blob, nil
+}
+
+// readCompactSet reads a set of blobs, resolving references against the table
+// and appending the new items to it.
+func readCompactSet(r *bytes.Reader, table *[]string) (map[string]struct{}, error) {
+	count, err := readCompactCount(r)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]struct{}, count)
+	for i := uint64(0); i < count; i++ {
+		index, blob, err := readCompactItem(r, uint64(len(*table)))
+		if err != nil {
+			return nil, err
+		}
+		if blob == nil {
+			set[(*table)[index]] = struct{}{}
+			continue
+		}
+		*table = append(*table, string(blob))
+		set[string(blob)] = struct{}{}
+	}
+	return set, nil
+}
+
+// EncodeCompact serializes a single witness as a compact witness stream.
+func (w *Witness) EncodeCompact(compression Compression) ([]byte, error) {
+	var buf bytes.Buffer
+	enc, err := NewWitnessEncoder(&buf, compression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := enc.Encode(w); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeCompact parses a compact witness stream containing a single witness.
+func DecodeCompact(blob []byte) (*Witness, error) {
+	dec, err := NewWitnessDecoder(bytes.NewReader(blob))
+	if err != nil {
+		return nil, err
+	}
+	w, err := dec.Decode()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		return nil, errors.New("trailing data after witness")
+	}
+	return w, nil
+}
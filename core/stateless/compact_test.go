@@ -0,0 +1,197 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package stateless
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"maps"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// makeTestWitness creates a witness of the given block, sharing some of its
+// headers and nodes with the neighbouring blocks.
+func makeTestWitness(number int64) *Witness {
+	w := &Witness{
+		Codes: map[string]struct{}{"shared code": {}},
+		State: map[string]struct{}{"shared node": {}},
+	}
+	for n := number - 1; n >= 0 && n >= number-3; n-- {
+		w.Headers = append(w.Headers, &types.Header{
+			Number:     big.NewInt(n),
+			Difficulty: common.Big1,
+			Root:       common.Hash{byte(n)},
+		})
+	}
+	for i := 0; i < 16; i++ {
+		w.State[string(bytes.Repeat([]byte{byte(number), byte(i)}, 32))] = struct{}{}
+	}
+	w.Codes[string([]byte{0x60, byte(number)})] = struct{}{}
+	return w
+}
+
+func checkWitnessEqual(t *testing.T, have, want *Witness) {
+	t.Helper()
+
+	if len(have.Headers) != len(want.Headers) {
+		t.Fatalf("header count mismatch: have %d, want %d", len(have.Headers), len(want.Headers))
+	}
+	for i := range want.Headers {
+		if have.Headers[i].Hash() != want.Headers[i].Hash() {
+			t.Fatalf("header %d mismatch: have %x, want %x", i, have.Headers[i].Hash(), want.Headers[i].Hash())
+		}
+	}
+	if !maps.Equal(have.Codes, want.Codes) {
+		t.Fatalf("codes mismatch: have %v, want %v", have.Codes, want.Codes)
+	}
+	if !maps.Equal(have.State, want.State) {
+		t.Fatalf("state mismatch: have %d nodes, want %d", len(have.State), len(want.State))
+	}
+}
+
+func TestCompactWitnessStream(t *testing.T) {
+	for _, compression := range []Compression{NoCompression, SnappyCompression, ZstdCompression} {
+		t.Run(compression.String(), func(t *testing.T) {
+			var (
+				buf       bytes.Buffer
+				witnesses []*Witness
+				sizes     []int
+			)
+			enc, err := NewWitnessEncoder(&buf, compression)
+			if err != nil {
+				t.Fatalf("failed to create encoder: %v", err)
+			}
+			for n := int64(1); n <= 8; n++ {
+				w := makeTestWitness(n)
+				size, err := enc.Encode(w)
+				if err != nil {
+					t.Fatalf("failed to encode witness %d: %v", n, err)
+				}
+				witnesses = append(witnesses, w)
+				sizes = append(sizes, size)
+			}
+			// Witnesses sharing items with earlier ones must be smaller than
+			// the same witnesses encoded on their own
+			if compression == NoCompression {
+				var header bytes.Buffer
+				NewWitnessEncoder(&header, compression)
+
+				for i, w := range witnesses[1:] {
+					single, _ := w.EncodeCompact(compression)
+					if standalone := len(single) - header.Len(); sizes[i+1] >= standalone {
+						t.Errorf("witness %d not deduplicated: %d >= %d", i+1, sizes[i+1], standalone)
+					}
+				}
+			}
+			dec, err := NewWitnessDecoder(&buf)
+			if err != nil {
+				t.Fatalf("failed to create decoder: %v", err)
+			}
+			if dec.Compression() != compression {
+				t.Fatalf("compression mismatch: have %v, want %v", dec.Compression(), compression)
+			}
+			for i, want := range witnesses {
+				have, err := dec.Decode()
+				if err != nil {
+					t.Fatalf("failed to decode witness %d: %v", i, err)
+				}
+				checkWitnessEqual(t, have, want)
+			}
+			if _, err := dec.Decode(); err != io.EOF {
+				t.Fatalf("expected end of stream, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCompactWitnessDeterministic(t *testing.T) {
+	w := makeTestWitness(5)
+	first, err := w.EncodeCompact(NoCompression)
+	if err != nil {
+		t.Fatalf("failed to encode witness: %v", err)
+	}
+	for i := 0; i < 8; i++ {
+		blob, _ := w.Copy().EncodeCompact(NoCompression)
+		if !bytes.Equal(blob, first) {
+			t.Fatalf("non-deterministic encoding")
+		}
+	}
+	have, err := DecodeCompact(first)
+	if err != nil {
+		t.Fatalf("failed to decode witness: %v", err)
+	}
+	checkWitnessEqual(t, have, w)
+}
+
+func TestCompactWitnessCorrupt(t *testing.T) {
+	blob, err := makeTestWitness(3).EncodeCompact(SnappyCompression)
+	if err != nil {
+		t.Fatalf("failed to encode witness: %v", err)
+	}
+	if _, err := DecodeCompact(blob[1:]); err != errNotCompactWitness {
+		t.Errorf("missing magic accepted: %v", err)
+	}
+	for size := len(compactMagic) + 3; size < len(blob); size++ {
+		if _, err := DecodeCompact(blob[:size]); err == nil {
+			t.Errorf("truncated stream of %d bytes accepted", size)
+		}
+	}
+	// Reference to an item never transmitted
+	stream := append(bytes.Clone(compactMagic), compactVersion, byte(NoCompression), 0x01)
+	stream = append(stream, 0x02, 0x01, 0x01) // record of one header reference
+	if _, err := DecodeCompact(stream); err != errInvalidReference {
+		t.Errorf("dangling reference accepted: %v", err)
+	}
+	// Dedup limit beyond what the decoder is willing to track
+	stream = append(bytes.Clone(compactMagic), compactVersion, byte(NoCompression))
+	stream = binary.AppendUvarint(stream, maxCompactDedupLimit+1)
+	if _, err := NewWitnessDecoder(bytes.NewReader(stream)); err == nil {
+		t.Errorf("oversized dedup limit accepted")
+	}
+}
+
+func TestCompactWitnessDedupReset(t *testing.T) {
+	var buf bytes.Buffer
+	enc, _ := NewWitnessEncoder(&buf, NoCompression)
+	enc.limit = 20 // reset after every second witness
+
+	var witnesses []*Witness
+	for n := int64(1); n <= 6; n++ {
+		witnesses = append(witnesses, makeTestWitness(n))
+		if _, err := enc.Encode(witnesses[len(witnesses)-1]); err != nil {
+			t.Fatalf("failed to encode witness %d: %v", n, err)
+		}
+	}
+	dec, err := NewWitnessDecoder(&buf)
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+	dec.limit = enc.limit
+
+	for i, want := range witnesses {
+		have, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("failed to decode witness %d: %v", i, err)
+		}
+		checkWitnessEqual(t, have, want)
+	}
+}
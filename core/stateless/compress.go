@@ -0,0 +1,84 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package stateless
+
+import (
+	"fmt"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// The zstd encoder and decoder are safe for concurrent use through EncodeAll and
+// DecodeAll, so a single instance of each is shared by all the streams.
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxCompactRecordSize))
+)
+
+// checkCompression returns an error if the compression algorithm is unknown.
+func checkCompression(compression Compression) error {
+	switch compression {
+	case NoCompression, SnappyCompression, ZstdCompression:
+		return nil
+	default:
+		return fmt.Errorf("unknown witness compression %v", compression)
+	}
+}
+
+// compress compresses a record payload, reusing dst if large enough.
+func compress(compression Compression, dst, src []byte) ([]byte, error) {
+	switch compression {
+	case NoCompression:
+		return src, nil
+	case SnappyCompression:
+		return snappy.Encode(dst[:cap(dst)], src), nil
+	case ZstdCompression:
+		return zstdEncoder.EncodeAll(src, dst[:0]), nil
+	default:
+		return nil, fmt.Errorf("unknown witness compression %v", compression)
+	}
+}
+
+// decompress decompresses a record payload, reusing dst if large enough and
+// refusing to inflate it beyond the given limit.
+func decompress(compression Compression, dst, src []byte, limit int) ([]byte, error) {
+	switch compression {
+	case NoCompression:
+		return src, nil
+	case SnappyCompression:
+		size, err := snappy.DecodedLen(src)
+		if err != nil {
+			return nil, err
+		}
+		if size > limit {
+			return nil, errRecordTooLarge
+		}
+		return snappy.Decode(dst[:cap(dst)], src)
+	case ZstdCompression:
+		data, err := zstdDecoder.DecodeAll(src, dst[:0])
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > limit {
+			return nil, errRecordTooLarge
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unknown witness compression %v", compression)
+	}
+}
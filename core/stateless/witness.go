@@ -24,6 +24,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 // HeaderReader is an interface to pull in headers in place of block hashes for
@@ -120,3 +121,42 @@ func (w *Witness) Copy() *Witness {
 func (w *Witness) Root() common.Hash {
 	return w.Headers[0].Root
 }
+
+// Stats contains the item counts and byte sizes of the contents of a witness.
+type Stats struct {
+	Headers     int // Number of headers
+	HeaderBytes int // Total size of the RLP encoded headers
+	Codes       int // Number of bytecodes
+	CodeBytes   int // Total size of the bytecodes
+	Nodes       int // Number of trie nodes
+	NodeBytes   int // Total size of the trie nodes
+}
+
+// Size returns the total byte size of the witness contents, excluding any
+// encoding overhead.
+func (s Stats) Size() int {
+	return s.HeaderBytes + s.CodeBytes + s.NodeBytes
+}
+
+// Stats gathers the item counts and byte sizes of the witness contents.
+func (w *Witness) Stats() Stats {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	stats := Stats{
+		Headers: len(w.Headers),
+		Codes:   len(w.Codes),
+		Nodes:   len(w.State),
+	}
+	for _, header := range w.Headers {
+		blob, _ := rlp.EncodeToBytes(header)
+		stats.HeaderBytes += len(blob)
+	}
+	for code := range w.Codes {
+		stats.CodeBytes += len(code)
+	}
+	for node := range w.State {
+		stats.NodeBytes += len(node)
+	}
+	return stats
+}
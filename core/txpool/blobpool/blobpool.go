@@ -320,12 +320,18 @@ type BlobPool struct {
 
 	discoverFeed event.Feed // Event feed to send out new tx events on pool discovery (reorg excluded)
 	insertFeed   event.Feed // Event feed to send out new tx events on pool inclusion (reorg included)
+	dropFeed     event.Feed // Event feed to send out removed tx events
+
+	drops []txpool.TxDrop // Transactions removed since the last drop event was sent
 
 	// txValidationFn defaults to txpool.ValidateTransaction, but can be
 	// overridden for testing purposes.
 	txValidationFn txpool.ValidationFunction
 
 	lock sync.RWMutex // Mutex protecting the pool during reorg handling
+
+	quit chan struct{}  // Channel to signal termination to background tasks
+	wg   sync.WaitGroup // Tracks the background sidecar conversion
 }
 
 // New creates a new blob transaction pool to gather, sort and filter inbound
@@ -344,6 +350,7 @@ func New(config Config, chain BlockChain, hasPendingAuth func(common.Address) bo
 		index:          make(map[common.Address][]*blobTxMeta),
 		spent:          make(map[common.Address]*uint256.Int),
 		txValidationFn: txpool.ValidateTransaction,
+		quit:           make(chan struct{}),
 	}
 }
 
@@ -444,11 +451,15 @@ func (p *BlobPool) Init(gasTip uint64, head *types.Header, reserver txpool.Reser
 	// Update the metrics and return the constructed pool
 	datacapGauge.Update(int64(p.config.Datacap))
 	p.updateStorageMetrics()
+	p.sendDrops(p.takeDrops())
 	return nil
 }
 
 // Close closes down the underlying persistent store.
 func (p *BlobPool) Close() error {
+	close(p.quit)
+	p.wg.Wait()
+
 	var errs []error
 	if p.limbo != nil { // Close might be invoked due to error in constructor, before p,limbo is set
 		if err := p.limbo.Close(); err != nil {
@@ -545,6 +556,11 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 
 			p.stored -= uint64(txs[i].storageSize)
 			p.lookup.untrack(txs[i])
+			if gapped {
+				p.recordDrop(txs[i].hash, txpool.DropInvalid)
+			} else {
+				p.recordDrop(txs[i].hash, txpool.DropIncluded)
+			}
 
 			// Included transactions blobs need to be moved to the limbo
 			if filled && inclusions != nil {
@@ -586,6 +602,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[0].costCap)
 			p.stored -= uint64(txs[0].storageSize)
 			p.lookup.untrack(txs[0])
+			p.recordDrop(txs[0].hash, txpool.DropIncluded)
 
 			// Included transactions blobs need to be moved to the limbo
 			if inclusions != nil {
@@ -642,6 +659,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[i].costCap)
 			p.stored -= uint64(txs[i].storageSize)
 			p.lookup.untrack(txs[i])
+			p.recordDrop(txs[i].hash, txpool.DropInvalid)
 
 			if err := p.store.Delete(id); err != nil {
 				log.Error("Failed to delete blob transaction", "from", addr, "id", id, "err", err)
@@ -664,6 +682,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[j].costCap)
 			p.stored -= uint64(txs[j].storageSize)
 			p.lookup.untrack(txs[j])
+			p.recordDrop(txs[j].hash, txpool.DropInvalid)
 		}
 		txs = txs[:i]
 
@@ -702,6 +721,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], last.costCap)
 			p.stored -= uint64(last.storageSize)
 			p.lookup.untrack(last)
+			p.recordDrop(last.hash, txpool.DropUnpayable)
 		}
 		if len(txs) == 0 {
 			delete(p.index, addr)
@@ -742,6 +762,7 @@ func (p *BlobPool) recheck(addr common.Address, inclusions map[common.Hash]uint6
 			p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], last.costCap)
 			p.stored -= uint64(last.storageSize)
 			p.lookup.untrack(last)
+			p.recordDrop(last.hash, txpool.DropAccountLimit)
 		}
 		p.index[addr] = txs
 
@@ -796,7 +817,7 @@ func (p *BlobPool) Reset(oldHead, newHead *types.Header) {
 	waitStart := time.Now()
 	p.lock.Lock()
 	resetwaitHist.Update(time.Since(waitStart).Nanoseconds())
-	defer p.lock.Unlock()
+	defer p.unlockAndSendDrops()
 
 	defer func(start time.Time) {
 		resettimeHist.Update(time.Since(start).Nanoseconds())
@@ -807,6 +828,21 @@ func (p *BlobPool) Reset(oldHead, newHead *types.Header) {
 		log.Error("Failed to reset blobpool state", "err", err)
 		return
 	}
+	// If the Osaka fork just activated, the pooled transactions need their
+	// sidecars converted to cell proofs. It's slow, do it in the background.
+	if oldHead != nil {
+		config := p.chain.Config()
+		if !config.IsOsaka(oldHead.Number, oldHead.Time) && config.IsOsaka(newHead.Number, newHead.Time) {
+			var hashes []common.Hash
+			for _, txs := range p.index {
+				for _, tx := range txs {
+					hashes = append(hashes, tx.hash)
+				}
+			}
+			p.wg.Add(1)
+			go p.convertLegacySidecars(hashes)
+		}
+	}
 	p.head = newHead
 	p.state = statedb
 
@@ -1028,7 +1064,7 @@ func (p *BlobPool) reinject(addr common.Address, txhash common.Hash) error {
 // to be kept in sync with the main transaction pool's gas requirements.
 func (p *BlobPool) SetGasTip(tip *big.Int) {
 	p.lock.Lock()
-	defer p.lock.Unlock()
+	defer p.unlockAndSendDrops()
 
 	// Store the new minimum gas tip
 	old := p.gasTip
@@ -1047,6 +1083,7 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 					p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], txs[i].costCap)
 					p.stored -= uint64(tx.storageSize)
 					p.lookup.untrack(tx)
+					p.recordDrop(tx.hash, txpool.DropUnderpriced)
 					txs[i] = nil
 
 					// Drop everything afterwards, no gaps allowed
@@ -1057,6 +1094,7 @@ func (p *BlobPool) SetGasTip(tip *big.Int) {
 						p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], tx.costCap)
 						p.stored -= uint64(tx.storageSize)
 						p.lookup.untrack(tx)
+						p.recordDrop(tx.hash, txpool.DropInvalid)
 						txs[i+1+j] = nil
 					}
 					// Clear out the dropped transactions from the index
@@ -1142,6 +1180,15 @@ func (p *BlobPool) validateTx(tx *types.Transaction) error {
 	if err := p.ValidateTxBasics(tx); err != nil {
 		return err
 	}
+	return p.validateTxState(tx)
+}
+
+// validateTxState checks whether a transaction adheres to the stateful pool
+// filters (nonce, balance, replacement pricing). Contrary to the basic checks,
+// it does not touch the blob sidecar, and is thus cheap to run.
+//
+// Note, this method assumes the pool lock is held.
+func (p *BlobPool) validateTxState(tx *types.Transaction) error {
 	// Ensure the transaction adheres to the stateful pool filters (nonce, balance)
 	stateOpts := &txpool.ValidationOptionsWithState{
 		State: p.state,
@@ -1295,14 +1342,16 @@ func (p *BlobPool) GetMetadata(hash common.Hash) *txpool.TxMetadata {
 	}
 }
 
-// GetBlobs returns a number of blobs are proofs for the given versioned hashes.
-// This is a utility method for the engine API, enabling consensus clients to
-// retrieve blobs from the pools directly instead of the network.
-func (p *BlobPool) GetBlobs(vhashes []common.Hash) ([]*kzg4844.Blob, []*kzg4844.Proof) {
+// GetBlobs returns a number of blobs and proofs for the given versioned hashes,
+// the proofs being of the requested sidecar version. Blobs only available with
+// proofs of another version are not returned. This is a utility method for the
+// engine API, enabling consensus clients to retrieve blobs from the pools
+// directly instead of the network.
+func (p *BlobPool) GetBlobs(vhashes []common.Hash, version byte) ([]*kzg4844.Blob, [][]kzg4844.Proof) {
 	// Create a map of the blob hash to indices for faster fills
 	var (
 		blobs  = make([]*kzg4844.Blob, len(vhashes))
-		proofs = make([]*kzg4844.Proof, len(vhashes))
+		proofs = make([][]kzg4844.Proof, len(vhashes))
 	)
 	index := make(map[common.Hash]int)
 	for i, vhash := range vhashes {
@@ -1337,10 +1386,22 @@ func (p *BlobPool) GetBlobs(vhashes []common.Hash) ([]*kzg4844.Blob, []*kzg4844.
 		}
 		// Fill anything requested, not just the current versioned hash
 		sidecar := item.BlobTxSidecar()
+		if sidecar.Version != version {
+			continue
+		}
 		for j, blobhash := range item.BlobHashes() {
 			if idx, ok := index[blobhash]; ok {
+				if version == types.BlobSidecarVersion0 {
+					proofs[idx] = []kzg4844.Proof{sidecar.Proofs[j]}
+				} else {
+					cellProofs, err := sidecar.CellProofsAt(j)
+					if err != nil {
+						log.Error("Cell proofs corrupted for tracked transaction", "id", id, "err", err)
+						continue
+					}
+					proofs[idx] = cellProofs
+				}
 				blobs[idx] = &sidecar.Blobs[j]
-				proofs[idx] = &sidecar.Proofs[j]
 			}
 		}
 	}
@@ -1358,6 +1419,9 @@ func (p *BlobPool) Add(txs []*types.Transaction, sync bool) []error {
 		errs = make([]error, len(txs))
 	)
 	for i, tx := range txs {
+		if tx, errs[i] = p.convertSidecar(tx); errs[i] != nil {
+			continue
+		}
 		errs[i] = p.add(tx)
 		if errs[i] == nil {
 			adds = append(adds, tx.WithoutBlobTxSidecar())
@@ -1370,6 +1434,142 @@ func (p *BlobPool) Add(txs []*types.Transaction, sync bool) []error {
 	return errs
 }
 
+// convertSidecar converts the legacy sidecar of a blob transaction into the cell
+// proof format if Osaka is active, returning the transaction with the converted
+// sidecar. Transactions not needing a conversion are returned as is.
+//
+// The cheap stateful checks and the legacy proofs are verified before the
+// conversion, to avoid wasting time on computing the cell proofs of transactions
+// which will be rejected anyway. The full validation is done on insertion.
+func (p *BlobPool) convertSidecar(tx *types.Transaction) (*types.Transaction, error) {
+	sidecar := tx.BlobTxSidecar()
+	if sidecar == nil || sidecar.Version != types.BlobSidecarVersion0 {
+		return tx, nil
+	}
+	p.lock.Lock()
+	var (
+		head  = p.head
+		osaka = p.chain.Config().IsOsaka(head.Number, head.Time)
+		err   error
+	)
+	if osaka {
+		err = p.validateTxState(tx)
+	}
+	p.lock.Unlock()
+
+	if !osaka {
+		return tx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(sidecar.Blobs) != len(sidecar.Proofs) {
+		return nil, fmt.Errorf("invalid number of %d blob proofs compared to %d blobs", len(sidecar.Proofs), len(sidecar.Blobs))
+	}
+	if err := sidecar.ValidateBlobCommitmentHashes(tx.BlobHashes()); err != nil {
+		return nil, err
+	}
+	for i := range sidecar.Blobs {
+		if err := kzg4844.VerifyBlobProof(&sidecar.Blobs[i], sidecar.Commitments[i], sidecar.Proofs[i]); err != nil {
+			return nil, fmt.Errorf("invalid blob %d: %v", i, err)
+		}
+	}
+	converted := sidecar.Copy()
+	if err := converted.ToV1(); err != nil {
+		return nil, err
+	}
+	return tx.WithBlobTxSidecar(converted), nil
+}
+
+// convertLegacySidecars converts the sidecars of the given pooled transactions
+// into the cell proof format, as required from Osaka onwards. Transactions
+// already converted or dropped meanwhile are skipped.
+func (p *BlobPool) convertLegacySidecars(hashes []common.Hash) {
+	defer p.wg.Done()
+
+	var (
+		start     = time.Now()
+		converted int
+	)
+	for _, hash := range hashes {
+		select {
+		case <-p.quit:
+			return
+		default:
+		}
+		if p.convertStored(hash) {
+			converted++
+		}
+	}
+	log.Info("Converted pooled blob sidecars to cell proofs", "count", converted, "elapsed", common.PrettyDuration(time.Since(start)))
+}
+
+// convertStored converts the legacy sidecar of a single pooled transaction,
+// replacing its entry in the persistent store. The pool lock is only held
+// while accessing the store, not while computing the cell proofs.
+func (p *BlobPool) convertStored(hash common.Hash) bool {
+	p.lock.RLock()
+	id, ok := p.lookup.storeidOfTx(hash)
+	if !ok {
+		p.lock.RUnlock()
+		return false
+	}
+	data, err := p.store.Get(id)
+	p.lock.RUnlock()
+
+	if err != nil {
+		log.Error("Tracked blob transaction missing from store", "hash", hash, "id", id, "err", err)
+		return false
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(data, tx); err != nil {
+		log.Error("Blobs corrupted for tracked transaction", "hash", hash, "id", id, "err", err)
+		return false
+	}
+	sidecar := tx.BlobTxSidecar()
+	if sidecar == nil || sidecar.Version != types.BlobSidecarVersion0 {
+		return false
+	}
+	sidecar = sidecar.Copy()
+	if err := sidecar.ToV1(); err != nil {
+		log.Error("Failed to convert blob sidecar", "hash", hash, "err", err)
+		return false
+	}
+	tx = tx.WithBlobTxSidecar(sidecar)
+	blob, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		log.Error("Failed to encode converted transaction", "hash", hash, "err", err)
+		return false
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	// The transaction might have been dropped or replaced while converting
+	if current, ok := p.lookup.storeidOfTx(hash); !ok || current != id {
+		return false
+	}
+	from, _ := types.Sender(p.signer, tx) // already validated on insertion
+	for _, meta := range p.index[from] {
+		if meta.hash != hash {
+			continue
+		}
+		newid, err := p.store.Put(blob)
+		if err != nil {
+			log.Error("Failed to store converted transaction", "hash", hash, "err", err)
+			return false
+		}
+		if err := p.store.Delete(id); err != nil {
+			log.Error("Failed to delete legacy transaction", "hash", hash, "id", id, "err", err)
+		}
+		p.stored -= uint64(meta.storageSize)
+		meta.id, meta.size, meta.storageSize = newid, tx.Size(), p.store.Size(newid)
+		p.stored += uint64(meta.storageSize)
+		p.lookup.track(meta)
+		return true
+	}
+	return false
+}
+
 // add inserts a new blob transaction into the pool if it passes validation (both
 // consensus validity and pool restrictions).
 func (p *BlobPool) add(tx *types.Transaction) (err error) {
@@ -1379,7 +1579,7 @@ func (p *BlobPool) add(tx *types.Transaction) (err error) {
 	waitStart := time.Now()
 	p.lock.Lock()
 	addwaitHist.Update(time.Since(waitStart).Nanoseconds())
-	defer p.lock.Unlock()
+	defer p.unlockAndSendDrops()
 
 	defer func(start time.Time) {
 		addtimeHist.Update(time.Since(start).Nanoseconds())
@@ -1468,6 +1668,7 @@ func (p *BlobPool) add(tx *types.Transaction) (err error) {
 		p.lookup.untrack(prev)
 		p.lookup.track(meta)
 		p.stored += uint64(meta.storageSize) - uint64(prev.storageSize)
+		p.recordReplace(prev.hash, meta.hash)
 	} else {
 		// Transaction extends previously scheduled ones
 		p.index[from] = append(p.index[from], meta)
@@ -1566,6 +1767,7 @@ func (p *BlobPool) drop() {
 	}
 	p.stored -= uint64(drop.storageSize)
 	p.lookup.untrack(drop)
+	p.recordDrop(drop.hash, txpool.DropUnderpriced)
 
 	// Remove the transaction from the pool's eviction heap:
 	//   - If the entire account was dropped, pop off the address
@@ -1591,6 +1793,47 @@ func (p *BlobPool) drop() {
 	}
 }
 
+// recordDrop queues a notification about a transaction removed from the pool,
+// to be sent to the subscribers at the end of the current pool operation.
+//
+// Note, this method assumes the pool lock is held!
+func (p *BlobPool) recordDrop(hash common.Hash, reason txpool.DropReason) {
+	p.drops = append(p.drops, txpool.TxDrop{Hash: hash, Reason: reason})
+}
+
+// recordReplace queues a notification about a transaction replaced by another
+// one with the same nonce.
+//
+// Note, this method assumes the pool lock is held!
+func (p *BlobPool) recordReplace(hash common.Hash, replacement common.Hash) {
+	p.drops = append(p.drops, txpool.TxDrop{Hash: hash, Reason: txpool.DropReplaced, Replacement: &replacement})
+}
+
+// takeDrops returns the removal notifications queued since the last call.
+//
+// Note, this method assumes the pool lock is held!
+func (p *BlobPool) takeDrops() []txpool.TxDrop {
+	drops := p.drops
+	p.drops = nil
+	return drops
+}
+
+// sendDrops notifies the subscribers of the removed transactions. It must not
+// be called with the pool lock held, as subscribers may call back into the pool.
+func (p *BlobPool) sendDrops(drops []txpool.TxDrop) {
+	if len(drops) > 0 {
+		p.dropFeed.Send(txpool.DropEvent{Drops: drops})
+	}
+}
+
+// unlockAndSendDrops releases the pool lock and notifies the subscribers of the
+// transactions removed while it was held.
+func (p *BlobPool) unlockAndSendDrops() {
+	drops := p.takeDrops()
+	p.lock.Unlock()
+	p.sendDrops(drops)
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce.
 //
@@ -1742,6 +1985,12 @@ func (p *BlobPool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool
 	}
 }
 
+// SubscribeDrops registers a subscription for events of transactions removed
+// from the pool.
+func (p *BlobPool) SubscribeDrops(ch chan<- txpool.DropEvent) event.Subscription {
+	return p.dropFeed.Subscribe(ch)
+}
+
 // Nonce returns the next nonce of an account, with all transactions executable
 // by the pool already applied on top.
 func (p *BlobPool) Nonce(addr common.Address) uint64 {
@@ -28,6 +28,7 @@ import (
 	"path/filepath"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
@@ -417,7 +418,11 @@ func verifyBlobRetrievals(t *testing.T, pool *BlobPool) {
 	for i := range testBlobVHashes {
 		copy(hashes[i][:], testBlobVHashes[i][:])
 	}
-	blobs, proofs := pool.GetBlobs(hashes)
+	version := types.BlobSidecarVersion0
+	if pool.chain.Config().IsOsaka(pool.head.Number, pool.head.Time) {
+		version = types.BlobSidecarVersion1
+	}
+	blobs, proofs := pool.GetBlobs(hashes, version)
 
 	// Cross validate what we received vs what we wanted
 	if len(blobs) != len(hashes) || len(proofs) != len(hashes) {
@@ -433,8 +438,17 @@ func verifyBlobRetrievals(t *testing.T, pool *BlobPool) {
 			continue
 		}
 		// Item retrieved, make sure it matches the expectation
-		if *blobs[i] != *testBlobs[i] || *proofs[i] != testBlobProofs[i] {
-			t.Errorf("retrieved blob or proof mismatch: item %d, hash %x", i, hash)
+		if *blobs[i] != *testBlobs[i] {
+			t.Errorf("retrieved blob mismatch: item %d, hash %x", i, hash)
+			continue
+		}
+		if version == types.BlobSidecarVersion1 {
+			if len(proofs[i]) != kzg4844.CellProofsPerBlob {
+				t.Errorf("retrieved cell proof count mismatch: item %d, hash %x", i, hash)
+				continue
+			}
+		} else if len(proofs[i]) != 1 || proofs[i][0] != testBlobProofs[i] {
+			t.Errorf("retrieved proof mismatch: item %d, hash %x", i, hash)
 			continue
 		}
 		delete(known, hash)
@@ -1142,6 +1156,104 @@ func TestChangingSlotterSize(t *testing.T) {
 	}
 }
 
+// Tests that legacy blob sidecars are converted to cell proofs once Osaka is
+// active, both for new transactions and for the ones already pooled.
+func TestLegacySidecarConversion(t *testing.T) {
+	// Create a temporary folder for the persistent backend
+	storage := t.TempDir()
+
+	os.MkdirAll(filepath.Join(storage, pendingTransactionStore), 0700)
+	store, _ := billy.Open(billy.Options{Path: filepath.Join(storage, pendingTransactionStore)}, newSlotter(testMaxBlobsPerBlock), nil)
+
+	var (
+		key1, _ = crypto.GenerateKey()
+		key2, _ = crypto.GenerateKey()
+
+		addr1 = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2 = crypto.PubkeyToAddress(key2.PublicKey)
+
+		pooled = makeMultiBlobTx(0, 1, 1000, 100, 1, key1)
+		added  = makeUnsignedTxWithTestBlob(0, 1, 1000, 100, 1)
+	)
+	blob, _ := rlp.EncodeToBytes(pooled)
+	store.Put(blob)
+	store.Close()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.AddBalance(addr1, uint256.NewInt(1_000_000_000), tracing.BalanceChangeUnspecified)
+	statedb.AddBalance(addr2, uint256.NewInt(1_000_000_000), tracing.BalanceChangeUnspecified)
+	statedb.Commit(0, true, false)
+
+	forkTime := uint64(0)
+	config := &params.ChainConfig{
+		ChainID:     big.NewInt(1),
+		LondonBlock: big.NewInt(0),
+		BerlinBlock: big.NewInt(0),
+		CancunTime:  &forkTime,
+		PragueTime:  &forkTime,
+		OsakaTime:   &forkTime,
+		BlobScheduleConfig: &params.BlobScheduleConfig{
+			Cancun: params.DefaultCancunBlobConfig,
+			Prague: params.DefaultPragueBlobConfig,
+			Osaka:  params.DefaultOsakaBlobConfig,
+		},
+	}
+	chain := &testBlockChain{
+		config:  config,
+		basefee: uint256.NewInt(1050),
+		blobfee: uint256.NewInt(105),
+		statedb: statedb,
+	}
+	pool := New(Config{Datadir: storage}, chain, nil)
+	if err := pool.Init(1, chain.CurrentBlock(), newReserver()); err != nil {
+		t.Fatalf("failed to create blob pool: %v", err)
+	}
+	defer pool.Close()
+
+	// Legacy transactions added after Osaka are converted before insertion
+	tx := types.MustSignNewTx(key2, types.LatestSigner(params.MainnetChainConfig), added)
+	if errs := pool.Add([]*types.Transaction{tx}, true); errs[0] != nil {
+		t.Fatalf("failed to add legacy blob transaction: %v", errs[0])
+	}
+	if sidecar := pool.Get(tx.Hash()).BlobTxSidecar(); sidecar.Version != types.BlobSidecarVersion1 {
+		t.Fatalf("added sidecar not converted: version %d", sidecar.Version)
+	}
+	// The stateful checks run before the conversion, rejecting a gapped
+	// transaction before its (invalid) blob proof is even looked at
+	gapped := makeUnsignedTxWithTestBlob(2, 1, 1000, 100, 2)
+	gapped.Sidecar.Proofs[0] = testBlobProofs[1]
+	tx = types.MustSignNewTx(key2, types.LatestSigner(params.MainnetChainConfig), gapped)
+	if errs := pool.Add([]*types.Transaction{tx}, true); !errors.Is(errs[0], core.ErrNonceTooHigh) {
+		t.Fatalf("gapped legacy blob transaction error mismatch: have %v, want %v", errs[0], core.ErrNonceTooHigh)
+	}
+	// Pooled legacy transactions are converted at the fork transition
+	if sidecar := pool.Get(pooled.Hash()).BlobTxSidecar(); sidecar.Version != types.BlobSidecarVersion0 {
+		t.Fatalf("pooled sidecar version mismatch: have %d, want %d", sidecar.Version, types.BlobSidecarVersion0)
+	}
+	if !pool.convertStored(pooled.Hash()) {
+		t.Fatal("failed to convert pooled transaction")
+	}
+	if pool.convertStored(pooled.Hash()) {
+		t.Fatal("pooled transaction converted twice")
+	}
+	verifyPoolInternals(t, pool)
+
+	// Both blobs must be retrievable with cell proofs, and not with legacy ones
+	hashes := []common.Hash{testBlobVHashes[0], testBlobVHashes[1]}
+	if blobs, _ := pool.GetBlobs(hashes, types.BlobSidecarVersion0); blobs[0] != nil || blobs[1] != nil {
+		t.Fatal("converted blobs returned with legacy proofs")
+	}
+	blobs, proofs := pool.GetBlobs(hashes, types.BlobSidecarVersion1)
+	for i := range hashes {
+		if blobs[i] == nil || *blobs[i] != *testBlobs[i] {
+			t.Fatalf("blob %d retrieval mismatch", i)
+		}
+		if err := kzg4844.VerifyCellProofs([]kzg4844.Blob{*blobs[i]}, []kzg4844.Commitment{testBlobCommits[i]}, proofs[i]); err != nil {
+			t.Fatalf("blob %d cell proofs invalid: %v", i, err)
+		}
+	}
+}
+
 // Tests that adding transaction will correctly store it in the persistent store
 // and update all the indices.
 //
@@ -1622,6 +1734,69 @@ func (f *fakeBilly) Put(data []byte) (uint64, error) {
 
 var _ billy.Database = (*fakeBilly)(nil)
 
+// Tests that drop events are delivered after releasing the pool lock, so that
+// subscribers calling back into the pool cannot deadlock it.
+func TestDropEventsUnlocked(t *testing.T) {
+	var (
+		key, _     = crypto.GenerateKey()
+		addr       = crypto.PubkeyToAddress(key.PublicKey)
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	)
+	statedb.AddBalance(addr, uint256.NewInt(1_000_000_000), tracing.BalanceChangeUnspecified)
+	statedb.Commit(0, true, false)
+
+	chain := &testBlockChain{
+		config:  params.MainnetChainConfig,
+		basefee: uint256.NewInt(1050),
+		blobfee: uint256.NewInt(105),
+		statedb: statedb,
+	}
+	pool := New(Config{Datadir: t.TempDir()}, chain, nil)
+	if err := pool.Init(1, chain.CurrentBlock(), newReserver()); err != nil {
+		t.Fatalf("failed to create blob pool: %v", err)
+	}
+	defer pool.Close()
+
+	tx := makeTx(0, 1, 1100, 110, key)
+	if errs := pool.Add([]*types.Transaction{tx}, true); errs[0] != nil {
+		t.Fatalf("failed to add transaction: %v", errs[0])
+	}
+	// Subscribe twice, only reading from the first subscription until the pool
+	// has been accessed. The send to the second one blocks meanwhile.
+	var (
+		first  = make(chan txpool.DropEvent)
+		second = make(chan txpool.DropEvent)
+	)
+	defer pool.SubscribeDrops(first).Unsubscribe()
+	defer pool.SubscribeDrops(second).Unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		pool.SetGasTip(big.NewInt(2)) // drops the transaction
+		close(done)
+	}()
+	select {
+	case ev := <-first:
+		if len(ev.Drops) != 1 || ev.Drops[0].Hash != tx.Hash() || ev.Drops[0].Reason != txpool.DropUnderpriced {
+			t.Fatalf("unexpected drop event: %+v", ev.Drops)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("drop event not delivered")
+	}
+	accessed := make(chan struct{})
+	go func() {
+		pool.Has(tx.Hash())
+		close(accessed)
+	}()
+	select {
+	case <-accessed:
+	case <-time.After(time.Second):
+		t.Fatal("pool locked while sending drop events")
+	}
+	<-second
+	<-done
+}
+
 // Benchmarks the time it takes to assemble the lazy pending transaction list
 // from the pool contents.
 func BenchmarkPoolPending100Mb(b *testing.B) { benchmarkPoolPending(b, 100_000_000) }
@@ -0,0 +1,69 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package blobpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that the limboed blobs survive a restart, so blobs included in blocks
+// that are reorged out after a node restart can still be re-injected.
+func TestLimboPersistence(t *testing.T) {
+	var (
+		dir    = t.TempDir()
+		key, _ = crypto.GenerateKey()
+		tx1    = makeTx(0, 1, 1000, 100, key)
+		tx2    = makeTx(1, 1, 1000, 100, key)
+	)
+	l, err := newLimbo(dir, 1)
+	if err != nil {
+		t.Fatalf("failed to open limbo: %v", err)
+	}
+	if err := l.push(tx1, 1); err != nil {
+		t.Fatalf("failed to push tx1: %v", err)
+	}
+	if err := l.push(tx2, 2); err != nil {
+		t.Fatalf("failed to push tx2: %v", err)
+	}
+	l.Close()
+
+	// Reopen the limbo and finalize the first block: only the blob included
+	// after it may remain
+	if l, err = newLimbo(dir, 1); err != nil {
+		t.Fatalf("failed to reopen limbo: %v", err)
+	}
+	defer l.Close()
+
+	l.finalize(&types.Header{Number: big.NewInt(1)})
+	if _, err := l.pull(tx1.Hash()); err == nil {
+		t.Fatalf("finalized blob still in limbo")
+	}
+	have, err := l.pull(tx2.Hash())
+	if err != nil {
+		t.Fatalf("failed to pull persisted blob: %v", err)
+	}
+	if have.Hash() != tx2.Hash() {
+		t.Fatalf("pulled transaction mismatch: have %x, want %x", have.Hash(), tx2.Hash())
+	}
+	if sidecar := have.BlobTxSidecar(); sidecar == nil || len(sidecar.Blobs) != 1 {
+		t.Fatalf("pulled transaction lost its blobs")
+	}
+}
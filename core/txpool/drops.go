@@ -0,0 +1,138 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// dropHistoryLimit is the number of dropped transactions whose fate is
+// remembered by the pool.
+const dropHistoryLimit = 64 * 1024
+
+// DropReason is the cause of a transaction being removed from the pool.
+type DropReason uint8
+
+const (
+	DropUnknown      DropReason = iota
+	DropIncluded                // Nonce consumed by a transaction included in the chain
+	DropReplaced                // Replaced by another transaction with the same nonce
+	DropUnderpriced             // Evicted in favor of better paying transactions
+	DropLifetime                // Queued for longer than the allowed lifetime
+	DropAccountLimit            // Exceeded the slots allowed for a single account
+	DropPoolLimit               // Exceeded the slots allowed for the entire pool
+	DropUnpayable               // Not covered by the balance of the sender or the gas limit
+	DropConditional             // Inclusion conditions can no longer be satisfied
+	DropInvalid                 // Invalidated by some other chain or pool change
+)
+
+var dropReasonNames = []string{
+	DropUnknown:      "unknown",
+	DropIncluded:     "included",
+	DropReplaced:     "replaced",
+	DropUnderpriced:  "underpriced",
+	DropLifetime:     "lifetime",
+	DropAccountLimit: "account-limit",
+	DropPoolLimit:    "pool-limit",
+	DropUnpayable:    "unpayable",
+	DropConditional:  "conditional",
+	DropInvalid:      "invalid",
+}
+
+// String implements fmt.Stringer.
+func (r DropReason) String() string {
+	if int(r) < len(dropReasonNames) {
+		return dropReasonNames[r]
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(r))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r DropReason) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *DropReason) UnmarshalText(input []byte) error {
+	for i, name := range dropReasonNames {
+		if name == string(input) {
+			*r = DropReason(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown drop reason %q", input)
+}
+
+// TxDrop describes a transaction removed from the pool without being added to
+// the chain by the pool itself.
+type TxDrop struct {
+	Hash        common.Hash  `json:"hash"`
+	Reason      DropReason   `json:"reason"`
+	Replacement *common.Hash `json:"replacement,omitempty"` // Transaction taking the place of the dropped one, if replaced
+}
+
+// DropEvent is posted when a batch of transactions is removed from a pool.
+type DropEvent struct {
+	Drops []TxDrop
+}
+
+// DropRecord is the historical record of a transaction dropped from the pool.
+type DropRecord struct {
+	TxDrop
+	Time time.Time `json:"time"`
+}
+
+// SubscribeDrops registers a subscription for transaction drop events of all
+// the subpools.
+func (p *TxPool) SubscribeDrops(ch chan<- DropEvent) event.Subscription {
+	subs := make([]event.Subscription, len(p.subpools))
+	for i, subpool := range p.subpools {
+		subs[i] = subpool.SubscribeDrops(ch)
+	}
+	return p.subs.Track(event.JoinSubscriptions(subs...))
+}
+
+// Dropped returns the record of a recently dropped transaction, or nil if the
+// transaction was not dropped or its record is already evicted from history.
+func (p *TxPool) Dropped(hash common.Hash) *DropRecord {
+	record, _ := p.drops.Get(hash)
+	return record
+}
+
+// trackDrops records the transactions dropped by the subpools into the bounded
+// drop history until the pool is terminated.
+func (p *TxPool) trackDrops(sub event.Subscription, ch <-chan DropEvent) {
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-ch:
+			now := time.Now()
+			for _, drop := range ev.Drops {
+				p.drops.Add(drop.Hash, &DropRecord{TxDrop: drop, Time: now})
+			}
+		case <-sub.Err():
+			return
+		case <-p.term:
+			return
+		}
+	}
+}
@@ -67,4 +67,12 @@ var (
 	// ErrInflightTxLimitReached is returned when the maximum number of in-flight
 	// transactions is reached for specific accounts.
 	ErrInflightTxLimitReached = errors.New("in-flight transaction limit reached for delegated accounts")
+
+	// ErrKnownAccounts is returned if the storage of the accounts known by the
+	// conditions attached to a transaction doesn't match the current state.
+	ErrKnownAccounts = errors.New("known account storage mismatch")
+
+	// ErrSidecarVersion is returned if the sidecar of a blob transaction is not
+	// in the format required by the current fork.
+	ErrSidecarVersion = errors.New("unexpected blob sidecar version")
 )
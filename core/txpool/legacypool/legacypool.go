@@ -19,6 +19,7 @@ package legacypool
 
 import (
 	"errors"
+	"fmt"
 	"maps"
 	"math"
 	"math/big"
@@ -75,6 +76,10 @@ var (
 	// ErrFutureReplacePending is returned if a future transaction replaces a pending
 	// one. Future transactions should only be able to replace other future transactions.
 	ErrFutureReplacePending = errors.New("future transaction tries to replace pending")
+
+	// ErrConditionalExpired is returned if the inclusion conditions attached to
+	// a transaction can't be satisfied by any future block.
+	ErrConditionalExpired = errors.New("transaction conditional expired")
 )
 
 var (
@@ -103,6 +108,10 @@ var (
 	underpricedTxMeter = metrics.NewRegisteredMeter("txpool/underpriced", nil)
 	overflowedTxMeter  = metrics.NewRegisteredMeter("txpool/overflowed", nil)
 
+	// conditionalDropMeter counts how many transactions are dropped because
+	// their inclusion conditions no longer hold.
+	conditionalDropMeter = metrics.NewRegisteredMeter("txpool/conditional/drop", nil)
+
 	// throttleTxMeter counts how many transactions are rejected due to too-many-changes between
 	// txpool reorgs.
 	throttleTxMeter = metrics.NewRegisteredMeter("txpool/throttle", nil)
@@ -151,6 +160,10 @@ type Config struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	Snapshot         string        // Snapshot of the pool contents to survive node restarts (disabled if empty)
+	SnapshotInterval time.Duration // Time interval to regenerate the pool snapshot
+	SnapshotSize     uint64        // Maximum size of the transactions persisted in the snapshot
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -167,6 +180,9 @@ var DefaultConfig = Config{
 	GlobalQueue:  1024,
 
 	Lifetime: 3 * time.Hour,
+
+	SnapshotInterval: 5 * time.Minute,
+	SnapshotSize:     64 * 1024 * 1024,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -201,6 +217,16 @@ func (config *Config) sanitize() Config {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultConfig.Lifetime)
 		conf.Lifetime = DefaultConfig.Lifetime
 	}
+	if conf.Snapshot != "" {
+		if conf.SnapshotInterval < time.Second {
+			log.Warn("Sanitizing invalid txpool snapshot interval", "provided", conf.SnapshotInterval, "updated", time.Second)
+			conf.SnapshotInterval = time.Second
+		}
+		if conf.SnapshotSize < 1 {
+			log.Warn("Sanitizing invalid txpool snapshot size", "provided", conf.SnapshotSize, "updated", DefaultConfig.SnapshotSize)
+			conf.SnapshotSize = DefaultConfig.SnapshotSize
+		}
+	}
 	return conf
 }
 
@@ -231,6 +257,7 @@ type LegacyPool struct {
 	chain       BlockChain
 	gasTip      atomic.Pointer[uint256.Int]
 	txFeed      event.Feed
+	dropFeed    event.Feed
 	signer      types.Signer
 	mu          sync.RWMutex
 
@@ -254,6 +281,8 @@ type LegacyPool struct {
 	initDoneCh      chan struct{}  // is closed once the pool is initialized (for tests)
 
 	changesSinceReorg int // A counter for how many drops we've performed in-between reorg.
+
+	drops []txpool.TxDrop // Transactions removed since the last drop event was sent
 }
 
 type txpoolResetRequest struct {
@@ -328,6 +357,13 @@ func (pool *LegacyPool) Init(gasTip uint64, head *types.Header, reserver txpool.
 
 	pool.wg.Add(1)
 	go pool.loop()
+
+	// Restore the transactions persisted by the previous run, if any
+	if pool.config.Snapshot != "" {
+		if err := pool.loadSnapshot(); err != nil {
+			log.Warn("Failed to load transaction pool snapshot", "err", err)
+		}
+	}
 	return nil
 }
 
@@ -343,10 +379,19 @@ func (pool *LegacyPool) loop() {
 		// Start the stats reporting and transaction eviction tickers
 		report = time.NewTicker(statsReportInterval)
 		evict  = time.NewTicker(evictionInterval)
+
+		// Start the snapshot ticker if persistence is enabled
+		snapshot <-chan time.Time
 	)
 	defer report.Stop()
 	defer evict.Stop()
 
+	if pool.config.Snapshot != "" {
+		ticker := time.NewTicker(pool.config.SnapshotInterval)
+		defer ticker.Stop()
+		snapshot = ticker.C
+	}
+
 	// Notify tests that the init phase is done
 	close(pool.initDoneCh)
 	for {
@@ -376,11 +421,20 @@ func (pool *LegacyPool) loop() {
 					list := pool.queue[addr].Flatten()
 					for _, tx := range list {
 						pool.removeTx(tx.Hash(), true, true)
+						pool.recordDrop(tx.Hash(), txpool.DropLifetime)
 					}
 					queuedEvictionMeter.Mark(int64(len(list)))
 				}
 			}
+			drops := pool.takeDrops()
 			pool.mu.Unlock()
+			pool.sendDrops(drops)
+
+		// Handle periodic snapshots of the pool contents
+		case <-snapshot:
+			if err := pool.writeSnapshot(); err != nil {
+				log.Warn("Failed to persist transaction pool snapshot", "err", err)
+			}
 		}
 	}
 }
@@ -391,6 +445,12 @@ func (pool *LegacyPool) Close() error {
 	close(pool.reorgShutdownCh)
 	pool.wg.Wait()
 
+	// Persist the final contents of the pool for the next run
+	if pool.config.Snapshot != "" {
+		if err := pool.writeSnapshot(); err != nil {
+			log.Warn("Failed to persist transaction pool snapshot", "err", err)
+		}
+	}
 	log.Info("Transaction pool stopped")
 	return nil
 }
@@ -412,11 +472,16 @@ func (pool *LegacyPool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs
 	return pool.txFeed.Subscribe(ch)
 }
 
+// SubscribeDrops registers a subscription for events of transactions removed
+// from the pool.
+func (pool *LegacyPool) SubscribeDrops(ch chan<- txpool.DropEvent) event.Subscription {
+	return pool.dropFeed.Subscribe(ch)
+}
+
 // SetGasTip updates the minimum gas tip required by the transaction pool for a
 // new transaction, and drops all transactions below this threshold.
 func (pool *LegacyPool) SetGasTip(tip *big.Int) {
 	pool.mu.Lock()
-	defer pool.mu.Unlock()
 
 	var (
 		newTip = uint256.MustFromBig(tip)
@@ -429,9 +494,14 @@ func (pool *LegacyPool) SetGasTip(tip *big.Int) {
 		drop := pool.all.TxsBelowTip(tip)
 		for _, tx := range drop {
 			pool.removeTx(tx.Hash(), false, true)
+			pool.recordDrop(tx.Hash(), txpool.DropUnderpriced)
 		}
 		pool.priced.Removed(len(drop))
 	}
+	drops := pool.takeDrops()
+	pool.mu.Unlock()
+
+	pool.sendDrops(drops)
 	log.Info("Legacy pool tip threshold updated", "tip", newTip)
 }
 
@@ -603,9 +673,31 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction) error {
 	if err := txpool.ValidateTransactionWithState(tx, pool.signer, opts); err != nil {
 		return err
 	}
+	if err := pool.validateConditional(tx); err != nil {
+		return err
+	}
 	return pool.validateAuth(tx)
 }
 
+// validateConditional checks whether the inclusion conditions attached to a
+// transaction, if any, may still hold on top of the current head. Conditions
+// on future blocks are accepted, but the known accounts must match the current
+// state.
+func (pool *LegacyPool) validateConditional(tx *types.Transaction) error {
+	cond := tx.Conditional()
+	if cond == nil {
+		return nil
+	}
+	if err := cond.Validate(); err != nil {
+		return err
+	}
+	head := pool.currentHead.Load()
+	if cond.Expired(head.Number.Uint64()+1, head.Time+1) {
+		return fmt.Errorf("%w: head %d, time %d", ErrConditionalExpired, head.Number, head.Time)
+	}
+	return txpool.ValidateKnownAccounts(cond.KnownAccounts, pool.currentState)
+}
+
 // checkDelegationLimit determines if the tx sender is delegated or has a
 // pending delegation, and if so, ensures they have at most one in-flight
 // **executable** transaction, e.g. disallow stacked and gapped transactions
@@ -769,6 +861,7 @@ func (pool *LegacyPool) add(tx *types.Transaction) (replaced bool, err error) {
 
 			sender, _ := types.Sender(pool.signer, tx)
 			dropped := pool.removeTx(tx.Hash(), false, sender != from) // Don't unreserve the sender of the tx being added if last from the acc
+			pool.recordDrop(tx.Hash(), txpool.DropUnderpriced)
 
 			pool.changesSinceReorg += dropped
 		}
@@ -786,6 +879,7 @@ func (pool *LegacyPool) add(tx *types.Transaction) (replaced bool, err error) {
 		if old != nil {
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
+			pool.recordReplace(old.Hash(), hash)
 			pendingReplaceMeter.Mark(1)
 		}
 		pool.all.Add(tx)
@@ -850,6 +944,7 @@ func (pool *LegacyPool) enqueueTx(hash common.Hash, tx *types.Transaction, addAl
 	if old != nil {
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
+		pool.recordReplace(old.Hash(), hash)
 		queuedReplaceMeter.Mark(1)
 	} else {
 		// Nothing was replaced, bump the queued counter
@@ -887,6 +982,7 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		// An older transaction was better, discard this
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
+		pool.recordReplace(hash, list.txs.Get(tx.Nonce()).Hash())
 		pendingDiscardMeter.Mark(1)
 		return false
 	}
@@ -894,6 +990,7 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 	if old != nil {
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
+		pool.recordReplace(old.Hash(), hash)
 		pendingReplaceMeter.Mark(1)
 	} else {
 		// Nothing was replaced, bump the pending counter
@@ -968,8 +1065,11 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, sync bool) []error {
 	// Process all the new transaction and merge any errors into the original slice
 	pool.mu.Lock()
 	newErrs, dirtyAddrs := pool.addTxsLocked(news)
+	drops := pool.takeDrops()
 	pool.mu.Unlock()
 
+	pool.sendDrops(drops)
+
 	var nilSlot = 0
 	for _, err := range newErrs {
 		for errs[nilSlot] != nil {
@@ -1065,7 +1165,7 @@ func (pool *LegacyPool) GetMetadata(hash common.Hash) *txpool.TxMetadata {
 
 // GetBlobs is not supported by the legacy transaction pool, it is just here to
 // implement the txpool.SubPool interface.
-func (pool *LegacyPool) GetBlobs(vhashes []common.Hash) ([]*kzg4844.Blob, []*kzg4844.Proof) {
+func (pool *LegacyPool) GetBlobs(vhashes []common.Hash, version byte) ([]*kzg4844.Blob, [][]kzg4844.Proof) {
 	return nil, nil
 }
 
@@ -1144,6 +1244,39 @@ func (pool *LegacyPool) removeTx(hash common.Hash, outofbound bool, unreserve bo
 	return 0
 }
 
+// recordDrop queues a notification about a transaction removed from the pool,
+// to be sent to the subscribers once the pool lock is released.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) recordDrop(hash common.Hash, reason txpool.DropReason) {
+	pool.drops = append(pool.drops, txpool.TxDrop{Hash: hash, Reason: reason})
+}
+
+// recordReplace queues a notification about a transaction replaced by another
+// one with the same nonce.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) recordReplace(hash common.Hash, replacement common.Hash) {
+	pool.drops = append(pool.drops, txpool.TxDrop{Hash: hash, Reason: txpool.DropReplaced, Replacement: &replacement})
+}
+
+// takeDrops retrieves and resets the queued drop notifications.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) takeDrops() []txpool.TxDrop {
+	drops := pool.drops
+	pool.drops = nil
+	return drops
+}
+
+// sendDrops notifies the subscribers of the removed transactions. It must not
+// be called with the pool lock held, as subscribers may call back into the pool.
+func (pool *LegacyPool) sendDrops(drops []txpool.TxDrop) {
+	if len(drops) > 0 {
+		pool.dropFeed.Send(txpool.DropEvent{Drops: drops})
+	}
+}
+
 // requestReset requests a pool reset to the new head block.
 // The returned channel is closed when the reset has occurred.
 func (pool *LegacyPool) requestReset(oldHead *types.Header, newHead *types.Header) chan struct{} {
@@ -1286,6 +1419,7 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 	// because of another transaction (e.g. higher gas price).
 	if reset != nil {
 		pool.demoteUnexecutables()
+		pool.dropInvalidConditionals()
 		if reset.newHead != nil {
 			if pool.chainconfig.IsLondon(new(big.Int).Add(reset.newHead.Number, big.NewInt(1))) {
 				pendingBaseFee := eip1559.CalcBaseFee(pool.chainconfig, reset.newHead)
@@ -1308,8 +1442,12 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 
 	dropBetweenReorgHistogram.Update(int64(pool.changesSinceReorg))
 	pool.changesSinceReorg = 0 // Reset change counter
+	drops := pool.takeDrops()
 	pool.mu.Unlock()
 
+	// Notify subsystems for removed transactions
+	pool.sendDrops(drops)
+
 	// Notify subsystems for newly added transactions
 	for _, tx := range promoted {
 		addr, _ := types.Sender(pool.signer, tx)
@@ -1444,12 +1582,14 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		forwards := list.Forward(pool.currentState.GetNonce(addr))
 		for _, tx := range forwards {
 			pool.all.Remove(tx.Hash())
+			pool.recordDrop(tx.Hash(), txpool.DropIncluded)
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
 		// Drop all transactions that are too costly (low balance or out of gas)
 		drops, _ := list.Filter(pool.currentState.GetBalance(addr), gasLimit)
 		for _, tx := range drops {
 			pool.all.Remove(tx.Hash())
+			pool.recordDrop(tx.Hash(), txpool.DropUnpayable)
 		}
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
@@ -1470,6 +1610,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		for _, tx := range caps {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.recordDrop(hash, txpool.DropAccountLimit)
 			log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 		}
 		queuedRateLimitMeter.Mark(int64(len(caps)))
@@ -1532,6 +1673,7 @@ func (pool *LegacyPool) truncatePending() {
 						// Drop the transaction from the global pools too
 						hash := tx.Hash()
 						pool.all.Remove(hash)
+						pool.recordDrop(hash, txpool.DropAccountLimit)
 
 						// Update the account nonce to the dropped transaction
 						pool.pendingNonces.setIfLower(offenders[i], tx.Nonce())
@@ -1557,6 +1699,7 @@ func (pool *LegacyPool) truncatePending() {
 					// Drop the transaction from the global pools too
 					hash := tx.Hash()
 					pool.all.Remove(hash)
+					pool.recordDrop(hash, txpool.DropAccountLimit)
 
 					// Update the account nonce to the dropped transaction
 					pool.pendingNonces.setIfLower(addr, tx.Nonce())
@@ -1599,6 +1742,7 @@ func (pool *LegacyPool) truncateQueue() {
 		if size := uint64(list.Len()); size <= drop {
 			for _, tx := range list.Flatten() {
 				pool.removeTx(tx.Hash(), true, true)
+				pool.recordDrop(tx.Hash(), txpool.DropPoolLimit)
 			}
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
@@ -1608,6 +1752,7 @@ func (pool *LegacyPool) truncateQueue() {
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
 			pool.removeTx(txs[i].Hash(), true, true)
+			pool.recordDrop(txs[i].Hash(), txpool.DropPoolLimit)
 			drop--
 			queuedRateLimitMeter.Mark(1)
 		}
@@ -1632,6 +1777,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 		for _, tx := range olds {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.recordDrop(hash, txpool.DropIncluded)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
@@ -1639,6 +1785,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 		for _, tx := range drops {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.recordDrop(hash, txpool.DropUnpayable)
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
 		}
 		pendingNofundsMeter.Mark(int64(len(drops)))
@@ -1674,6 +1821,25 @@ func (pool *LegacyPool) demoteUnexecutables() {
 	}
 }
 
+// dropInvalidConditionals removes the transactions whose inclusion conditions
+// can no longer hold on top of the current head, along with the pending ones
+// from the same account depending on them.
+func (pool *LegacyPool) dropInvalidConditionals() {
+	var drops []common.Hash
+	pool.all.Range(func(hash common.Hash, tx *types.Transaction) bool {
+		if tx.Conditional() != nil && pool.validateConditional(tx) != nil {
+			drops = append(drops, hash)
+		}
+		return true
+	})
+	for _, hash := range drops {
+		log.Trace("Removed transaction with invalid conditional", "hash", hash)
+		pool.removeTx(hash, true, true)
+		pool.recordDrop(hash, txpool.DropConditional)
+	}
+	conditionalDropMeter.Mark(int64(len(drops)))
+}
+
 // addressByHeartbeat is an account address tagged with its last activity timestamp.
 type addressByHeartbeat struct {
 	address   common.Address
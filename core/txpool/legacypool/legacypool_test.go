@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"math/big"
 	"math/rand"
+	"reflect"
 	"slices"
 	"sync"
 	"sync/atomic"
@@ -30,6 +31,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
@@ -418,6 +420,76 @@ func TestInvalidTransactions(t *testing.T) {
 	}
 }
 
+// Tests that transactions with inclusion conditions are only accepted if they
+// may hold, and dropped when they don't hold anymore.
+func TestConditionalTransactions(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	var (
+		from     = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0xc0de")
+		slot     = common.HexToHash("0x01")
+		value    = common.HexToHash("0x02")
+		maxBlock = hexutil.Uint64(0)
+	)
+	testAddBalance(pool, from, big.NewInt(params.Ether))
+	pool.mu.Lock()
+	pool.currentState.SetState(contract, slot, value)
+	pool.mu.Unlock()
+
+	// Mismatching storage is rejected
+	tx := transaction(0, 100000, key)
+	tx.SetConditional(&types.TransactionConditional{
+		KnownAccounts: map[common.Address]types.KnownAccount{
+			contract: {StorageSlots: map[common.Hash]common.Hash{slot: common.HexToHash("0x03")}},
+		},
+	})
+	if err := pool.addRemoteSync(tx); !errors.Is(err, txpool.ErrKnownAccounts) {
+		t.Fatalf("want %v have %v", txpool.ErrKnownAccounts, err)
+	}
+	// Conditions which can't be met by future blocks are rejected
+	tx = transaction(0, 100000, key)
+	tx.SetConditional(&types.TransactionConditional{BlockNumberMax: &maxBlock})
+	if err := pool.addRemoteSync(tx); !errors.Is(err, ErrConditionalExpired) {
+		t.Fatalf("want %v have %v", ErrConditionalExpired, err)
+	}
+	// Matching storage is accepted
+	tx = transaction(0, 100000, key)
+	tx.SetConditional(&types.TransactionConditional{
+		KnownAccounts: map[common.Address]types.KnownAccount{
+			contract: {StorageSlots: map[common.Hash]common.Hash{slot: value}},
+		},
+	})
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(transaction(1, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if pending, queued := pool.Stats(); pending != 2 || queued != 0 {
+		t.Fatalf("unexpected pool stats: pending %d, queued %d", pending, queued)
+	}
+	// Once the storage changes, the conditional transaction is dropped and
+	// the dependent ones are demoted
+	pool.mu.Lock()
+	pool.currentState.SetState(contract, slot, common.HexToHash("0x04"))
+	pool.mu.Unlock()
+	<-pool.requestReset(nil, nil)
+
+	if pending, queued := pool.Stats(); pending != 0 || queued != 1 {
+		t.Fatalf("unexpected pool stats: pending %d, queued %d", pending, queued)
+	}
+	if pool.Get(tx.Hash()) != nil {
+		t.Fatal("conditional transaction not dropped")
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 func TestQueue(t *testing.T) {
 	t.Parallel()
 
@@ -2047,6 +2119,90 @@ func TestReplacement(t *testing.T) {
 	}
 }
 
+// Tests that transactions removed from the pool are announced to the drop event
+// subscribers, along with the reason of their removal.
+func TestDropEvents(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	drops := make(chan txpool.DropEvent, 32)
+	sub := pool.SubscribeDrops(drops)
+	defer sub.Unsubscribe()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(1000000000))
+
+	var (
+		pending   = pricedTransaction(0, 100000, big.NewInt(1), key)
+		pending2  = pricedTransaction(0, 100000, big.NewInt(2), key)
+		queued    = pricedTransaction(2, 100000, big.NewInt(1), key)
+		queued2   = pricedTransaction(2, 100000, big.NewInt(2), key)
+		unpayable = pricedTransaction(3, 100000, big.NewInt(5000), key)
+
+		pending2Hash = pending2.Hash()
+		queued2Hash  = queued2.Hash()
+	)
+	checkDrops := func(want ...txpool.TxDrop) {
+		t.Helper()
+
+		have := make(map[common.Hash]txpool.TxDrop)
+		for len(have) < len(want) {
+			select {
+			case ev := <-drops:
+				for _, drop := range ev.Drops {
+					have[drop.Hash] = drop
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("drop events missing: have %d, want %d", len(have), len(want))
+			}
+		}
+		if len(have) != len(want) {
+			t.Fatalf("drop count mismatch: have %d, want %d", len(have), len(want))
+		}
+		for _, drop := range want {
+			if !reflect.DeepEqual(have[drop.Hash], drop) {
+				t.Errorf("drop %x mismatch: have %+v, want %+v", drop.Hash, have[drop.Hash], drop)
+			}
+		}
+	}
+	// Replace a pending and a queued transaction
+	if err := pool.addRemoteSync(pending); err != nil {
+		t.Fatalf("failed to add pending transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(pending2); err != nil {
+		t.Fatalf("failed to replace pending transaction: %v", err)
+	}
+	checkDrops(txpool.TxDrop{Hash: pending.Hash(), Reason: txpool.DropReplaced, Replacement: &pending2Hash})
+
+	if err := pool.addRemoteSync(queued); err != nil {
+		t.Fatalf("failed to add queued transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(queued2); err != nil {
+		t.Fatalf("failed to replace queued transaction: %v", err)
+	}
+	checkDrops(txpool.TxDrop{Hash: queued.Hash(), Reason: txpool.DropReplaced, Replacement: &queued2Hash})
+
+	// Drain the balance of the account below the cost of an expensive transaction
+	if err := pool.addRemoteSync(unpayable); err != nil {
+		t.Fatalf("failed to add expensive transaction: %v", err)
+	}
+	testAddBalance(pool, account, big.NewInt(-999000000))
+	<-pool.requestReset(nil, nil)
+	checkDrops(txpool.TxDrop{Hash: unpayable.Hash(), Reason: txpool.DropUnpayable})
+
+	// Raise the minimum tip above the price of the remaining transactions
+	pool.SetGasTip(big.NewInt(3))
+	checkDrops(
+		txpool.TxDrop{Hash: pending2.Hash(), Reason: txpool.DropUnderpriced},
+		txpool.TxDrop{Hash: queued2.Hash(), Reason: txpool.DropUnderpriced},
+	)
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Tests that the pool rejects replacement dynamic fee transactions that don't
 // meet the minimum price bump required.
 func TestReplacementDynamicFee(t *testing.T) {
@@ -0,0 +1,177 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	snapshotTxsGauge     = metrics.NewRegisteredGauge("txpool/snapshot/txs", nil)
+	snapshotSizeGauge    = metrics.NewRegisteredGauge("txpool/snapshot/size", nil)
+	snapshotWriteTimer   = metrics.NewRegisteredTimer("txpool/snapshot/write", nil)
+	snapshotLoadedMeter  = metrics.NewRegisteredMeter("txpool/snapshot/loaded", nil)
+	snapshotDroppedMeter = metrics.NewRegisteredMeter("txpool/snapshot/dropped", nil)
+)
+
+// writeSnapshot persists the contents of the pool into the snapshot file, so
+// that the transactions survive a node restart. Executable transactions take
+// precedence over queued ones, and accounts paying higher tips over the others,
+// if the snapshot does not fit into the configured size cap.
+//
+// Transactions with inclusion conditions are not persisted, as the conditions
+// are not part of the transaction encoding.
+//
+// Blob transactions are not covered by the snapshot: the blob pool keeps both
+// its pending transactions and the limbo of included-but-not-finalized blobs
+// in its own on-disk stores, which already survive a restart.
+func (pool *LegacyPool) writeSnapshot() error {
+	start := time.Now()
+	pending, queued := pool.Content()
+
+	// Generate the new snapshot next to the live one and replace it when done
+	output, err := os.OpenFile(pool.config.Snapshot+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	var (
+		buffer = bufio.NewWriter(output)
+		count  int
+		size   uint64
+	)
+	for _, set := range []map[common.Address][]*types.Transaction{pending, queued} {
+		for _, txs := range snapshotOrder(set) {
+			for _, tx := range txs {
+				if tx.Conditional() != nil {
+					break // Following nonces would be gapped
+				}
+				if size+tx.Size() > pool.config.SnapshotSize {
+					break // Try smaller transactions of other accounts
+				}
+				if err := rlp.Encode(buffer, tx); err != nil {
+					output.Close()
+					return err
+				}
+				count++
+				size += tx.Size()
+			}
+		}
+	}
+	if err := buffer.Flush(); err != nil {
+		output.Close()
+		return err
+	}
+	if err := output.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(pool.config.Snapshot+".new", pool.config.Snapshot); err != nil {
+		return err
+	}
+	snapshotTxsGauge.Update(int64(count))
+	snapshotSizeGauge.Update(int64(size))
+	snapshotWriteTimer.UpdateSince(start)
+
+	log.Debug("Persisted transaction pool snapshot", "transactions", count, "size", common.StorageSize(size), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// snapshotOrder sorts the transaction lists of the accounts by the tip of their
+// first transaction in descending order, breaking ties by address to keep the
+// snapshot deterministic.
+func snapshotOrder(set map[common.Address][]*types.Transaction) [][]*types.Transaction {
+	addrs := make([]common.Address, 0, len(set))
+	for addr, txs := range set {
+		if len(txs) > 0 {
+			addrs = append(addrs, addr)
+		}
+	}
+	slices.SortFunc(addrs, func(a, b common.Address) int {
+		if c := set[b][0].GasTipCapCmp(set[a][0]); c != 0 {
+			return c
+		}
+		return a.Cmp(b)
+	})
+	ordered := make([][]*types.Transaction, len(addrs))
+	for i, addr := range addrs {
+		ordered[i] = set[addr]
+	}
+	return ordered
+}
+
+// loadSnapshot reads back the transactions persisted by a previous run of the
+// pool, injecting them anew. All of them are validated against the current head
+// state, dropping the ones included or invalidated in the meantime.
+func (pool *LegacyPool) loadSnapshot() error {
+	input, err := os.Open(pool.config.Snapshot)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	var (
+		stream  = rlp.NewStream(bufio.NewReader(input), 0)
+		batch   []*types.Transaction
+		total   int
+		dropped int
+		failure error
+	)
+	flush := func() {
+		for _, err := range pool.Add(batch, true) {
+			if err != nil {
+				log.Trace("Failed to add snapshot transaction", "err", err)
+				dropped++
+			}
+		}
+		batch = batch[:0]
+	}
+	for {
+		tx := new(types.Transaction)
+		if err := stream.Decode(tx); err != nil {
+			if err != io.EOF {
+				failure = err
+			}
+			break
+		}
+		total++
+		if batch = append(batch, tx); len(batch) >= 1024 {
+			flush()
+		}
+	}
+	if len(batch) > 0 {
+		flush()
+	}
+	snapshotLoadedMeter.Mark(int64(total - dropped))
+	snapshotDroppedMeter.Mark(int64(dropped))
+
+	log.Info("Loaded transaction pool snapshot", "transactions", total, "dropped", dropped)
+	return failure
+}
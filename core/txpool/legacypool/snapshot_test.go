@@ -0,0 +1,151 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// setupSnapshotPool creates a pool persisting its contents into the given
+// snapshot file, on top of the given state.
+func setupSnapshotPool(t *testing.T, statedb *state.StateDB, path string, size uint64) *LegacyPool {
+	t.Helper()
+
+	config := testTxPoolConfig
+	config.Snapshot = path
+	config.SnapshotSize = size
+
+	blockchain := newTestBlockChain(params.TestChainConfig, 10000000, statedb, new(event.Feed))
+	pool := New(config, blockchain)
+	if err := pool.Init(config.PriceLimit, blockchain.CurrentBlock(), newReserver()); err != nil {
+		t.Fatalf("failed to init pool: %v", err)
+	}
+	<-pool.initDoneCh
+	return pool
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		path       = filepath.Join(t.TempDir(), "txpool.rlp")
+		keys       = make([]*ecdsa.PrivateKey, 3)
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		statedb.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), uint256.NewInt(params.Ether), tracing.BalanceChangeUnspecified)
+	}
+	pool := setupSnapshotPool(t, statedb, path, DefaultConfig.SnapshotSize)
+
+	// Fill the pool with executable and gapped transactions, as well as ones
+	// with inclusion conditions
+	conditional := transaction(0, 100000, keys[1])
+	conditional.SetConditional(&types.TransactionConditional{})
+
+	txs := []*types.Transaction{
+		transaction(0, 100000, keys[0]),
+		transaction(1, 100000, keys[0]),
+		transaction(3, 100000, keys[0]),
+		conditional,
+		transaction(1, 100000, keys[1]),
+		transaction(0, 100000, keys[2]),
+	}
+	for i, err := range pool.Add(txs, true) {
+		if err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+	}
+	if pending, queued := pool.Stats(); pending != 5 || queued != 1 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 5/1", pending, queued)
+	}
+	pool.Close()
+
+	// Include the transaction of the last account while the pool is down and
+	// ensure everything else is restored
+	statedb.SetNonce(crypto.PubkeyToAddress(keys[2].PublicKey), 1, tracing.NonceChangeUnspecified)
+
+	pool = setupSnapshotPool(t, statedb, path, DefaultConfig.SnapshotSize)
+	defer pool.Close()
+
+	pending, queued := pool.Content()
+	if txs := pending[crypto.PubkeyToAddress(keys[0].PublicKey)]; len(txs) != 2 {
+		t.Errorf("executable transactions not restored: %d", len(txs))
+	}
+	if txs := queued[crypto.PubkeyToAddress(keys[0].PublicKey)]; len(txs) != 1 {
+		t.Errorf("gapped transactions not restored: %d", len(txs))
+	}
+	if txs := append(pending[crypto.PubkeyToAddress(keys[1].PublicKey)], queued[crypto.PubkeyToAddress(keys[1].PublicKey)]...); len(txs) != 0 {
+		t.Errorf("conditional transactions restored: %d", len(txs))
+	}
+	if txs := pending[crypto.PubkeyToAddress(keys[2].PublicKey)]; len(txs) != 0 {
+		t.Errorf("included transactions restored: %d", len(txs))
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+func TestSnapshotSizeCap(t *testing.T) {
+	t.Parallel()
+
+	var (
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		path       = filepath.Join(t.TempDir(), "txpool.rlp")
+		cheap, _   = crypto.GenerateKey()
+		pricey, _  = crypto.GenerateKey()
+	)
+	statedb.AddBalance(crypto.PubkeyToAddress(cheap.PublicKey), uint256.NewInt(params.Ether), tracing.BalanceChangeUnspecified)
+	statedb.AddBalance(crypto.PubkeyToAddress(pricey.PublicKey), uint256.NewInt(params.Ether), tracing.BalanceChangeUnspecified)
+
+	txs := []*types.Transaction{
+		pricedTransaction(0, 100000, big.NewInt(1), cheap),
+		pricedTransaction(0, 100000, big.NewInt(2), pricey),
+		pricedTransaction(1, 100000, big.NewInt(2), pricey),
+	}
+	// Only allow the transactions of the better paying account to be persisted
+	limit := txs[1].Size() + txs[2].Size()
+
+	pool := setupSnapshotPool(t, statedb, path, limit)
+	for i, err := range pool.Add(txs, true) {
+		if err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+	}
+	pool.Close()
+
+	pool = setupSnapshotPool(t, statedb, path, limit)
+	defer pool.Close()
+
+	if pending, queued := pool.Stats(); pending != 2 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 2/0", pending, queued)
+	}
+	if pool.Get(txs[0].Hash()) != nil {
+		t.Fatalf("underpriced transaction persisted beyond the size cap")
+	}
+}
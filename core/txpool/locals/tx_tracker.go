@@ -79,13 +79,14 @@ func (tracker *TxTracker) Track(tx *types.Transaction) {
 }
 
 // TrackAll adds a list of transactions to the tracked set.
-// Note: blob-type transactions are ignored.
+// Note: blob-type and conditional transactions are ignored, the conditions of
+// the latter would be lost when resubmitting them from the journal.
 func (tracker *TxTracker) TrackAll(txs []*types.Transaction) {
 	tracker.mu.Lock()
 	defer tracker.mu.Unlock()
 
 	for _, tx := range txs {
-		if tx.Type() == types.BlobTxType {
+		if tx.Type() == types.BlobTxType || tx.Conditional() != nil {
 			continue
 		}
 		// If we're already tracking it, it's a no-op
@@ -133,10 +133,11 @@ type SubPool interface {
 	// given transaction hash.
 	GetMetadata(hash common.Hash) *TxMetadata
 
-	// GetBlobs returns a number of blobs are proofs for the given versioned hashes.
-	// This is a utility method for the engine API, enabling consensus clients to
-	// retrieve blobs from the pools directly instead of the network.
-	GetBlobs(vhashes []common.Hash) ([]*kzg4844.Blob, []*kzg4844.Proof)
+	// GetBlobs returns a number of blobs and proofs for the given versioned hashes,
+	// the proofs being of the requested sidecar version. This is a utility method
+	// for the engine API, enabling consensus clients to retrieve blobs from the
+	// pools directly instead of the network.
+	GetBlobs(vhashes []common.Hash, version byte) ([]*kzg4844.Blob, [][]kzg4844.Proof)
 
 	// ValidateTxBasics checks whether a transaction is valid according to the consensus
 	// rules, but does not check state-dependent validation such as sufficient balance.
@@ -161,6 +162,10 @@ type SubPool interface {
 	// or also for reorged out ones.
 	SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool) event.Subscription
 
+	// SubscribeDrops subscribes to events of transactions being removed from the
+	// pool for any reason other than the pool itself being shut down.
+	SubscribeDrops(ch chan<- DropEvent) event.Subscription
+
 	// Nonce returns the next nonce of an account, with all transactions executable
 	// by the pool already applied on top.
 	Nonce(addr common.Address) uint64
@@ -23,6 +23,7 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -71,6 +72,8 @@ type TxPool struct {
 	stateLock sync.RWMutex   // The lock for protecting state instance
 	state     *state.StateDB // Current state at the blockchain head
 
+	drops *lru.Cache[common.Hash, *DropRecord] // History of recently dropped transactions
+
 	subs event.SubscriptionScope // Subscription scope to unsubscribe all on shutdown
 	quit chan chan error         // Quit channel to tear down the head updater
 	term chan struct{}           // Termination channel to detect a closed pool
@@ -104,6 +107,7 @@ func New(gasTip uint64, chain BlockChain, subpools []SubPool) (*TxPool, error) {
 		quit:     make(chan chan error),
 		term:     make(chan struct{}),
 		sync:     make(chan chan error),
+		drops:    lru.NewCache[common.Hash, *DropRecord](dropHistoryLimit),
 	}
 	reserver := NewReservationTracker()
 	for i, subpool := range subpools {
@@ -114,6 +118,8 @@ func New(gasTip uint64, chain BlockChain, subpools []SubPool) (*TxPool, error) {
 			return nil, err
 		}
 	}
+	dropCh := make(chan DropEvent, 64)
+	go pool.trackDrops(pool.SubscribeDrops(dropCh), dropCh)
 	go pool.loop(head)
 	return pool, nil
 }
@@ -308,16 +314,17 @@ func (p *TxPool) GetMetadata(hash common.Hash) *TxMetadata {
 	return nil
 }
 
-// GetBlobs returns a number of blobs are proofs for the given versioned hashes.
-// This is a utility method for the engine API, enabling consensus clients to
-// retrieve blobs from the pools directly instead of the network.
-func (p *TxPool) GetBlobs(vhashes []common.Hash) ([]*kzg4844.Blob, []*kzg4844.Proof) {
+// GetBlobs returns a number of blobs and proofs for the given versioned hashes,
+// the proofs being of the requested sidecar version. This is a utility method
+// for the engine API, enabling consensus clients to retrieve blobs from the
+// pools directly instead of the network.
+func (p *TxPool) GetBlobs(vhashes []common.Hash, version byte) ([]*kzg4844.Blob, [][]kzg4844.Proof) {
 	for _, subpool := range p.subpools {
 		// It's an ugly to assume that only one pool will be capable of returning
 		// anything meaningful for this call, but anythingh else requires merging
 		// partial responses and that's too annoying to do until we get a second
 		// blobpool (probably never).
-		if blobs, proofs := subpool.GetBlobs(vhashes); blobs != nil {
+		if blobs, proofs := subpool.GetBlobs(vhashes, version); blobs != nil {
 			return blobs, proofs
 		}
 	}
@@ -152,6 +152,15 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 		if len(hashes) > maxBlobs {
 			return fmt.Errorf("too many blobs in transaction: have %d, permitted %d", len(hashes), maxBlobs)
 		}
+		// Ensure the sidecar format matches the active fork: cell proofs are
+		// required from Osaka onwards, and rejected before.
+		if opts.Config.IsOsaka(head.Number, head.Time) {
+			if sidecar.Version != types.BlobSidecarVersion1 {
+				return fmt.Errorf("%w: have %d, want %d", ErrSidecarVersion, sidecar.Version, types.BlobSidecarVersion1)
+			}
+		} else if sidecar.Version != types.BlobSidecarVersion0 {
+			return fmt.Errorf("%w: have %d, want %d", ErrSidecarVersion, sidecar.Version, types.BlobSidecarVersion0)
+		}
 		// Ensure commitments, proofs and hashes are valid
 		if err := validateBlobSidecar(hashes, sidecar); err != nil {
 			return err
@@ -169,12 +178,23 @@ func validateBlobSidecar(hashes []common.Hash, sidecar *types.BlobTxSidecar) err
 	if len(sidecar.Blobs) != len(hashes) {
 		return fmt.Errorf("invalid number of %d blobs compared to %d blob hashes", len(sidecar.Blobs), len(hashes))
 	}
-	if len(sidecar.Proofs) != len(hashes) {
-		return fmt.Errorf("invalid number of %d blob proofs compared to %d blob hashes", len(sidecar.Proofs), len(hashes))
-	}
 	if err := sidecar.ValidateBlobCommitmentHashes(hashes); err != nil {
 		return err
 	}
+	if sidecar.Version == types.BlobSidecarVersion1 {
+		if len(sidecar.Proofs) != len(hashes)*kzg4844.CellProofsPerBlob {
+			return fmt.Errorf("invalid number of %d cell proofs compared to %d blob hashes", len(sidecar.Proofs), len(hashes))
+		}
+		// Blob commitments match with the hashes in the transaction, verify
+		// the cells of the extended blobs via KZG
+		if err := kzg4844.VerifyCellProofs(sidecar.Blobs, sidecar.Commitments, sidecar.Proofs); err != nil {
+			return fmt.Errorf("invalid blob cell proofs: %v", err)
+		}
+		return nil
+	}
+	if len(sidecar.Proofs) != len(hashes) {
+		return fmt.Errorf("invalid number of %d blob proofs compared to %d blob hashes", len(sidecar.Proofs), len(hashes))
+	}
 	// Blob commitments match with the hashes in the transaction, verify the
 	// blobs themselves via KZG
 	for i := range sidecar.Blobs {
@@ -266,3 +286,37 @@ func ValidateTransactionWithState(tx *types.Transaction, signer types.Signer, op
 	}
 	return nil
 }
+
+// ValidateKnownAccounts checks whether the storage of the accounts known by the
+// conditions of a transaction matches the given state.
+//
+// Note, the storage roots are read from the state as is, it's up to the caller
+// to ensure they are up to date with any pending modification.
+func ValidateKnownAccounts(known map[common.Address]types.KnownAccount, state *state.StateDB) error {
+	for addr, account := range known {
+		if account.StorageRoot != nil {
+			if root := state.GetStorageRoot(addr); root != *account.StorageRoot {
+				return fmt.Errorf("%w: account %x storage root %x, want %x", ErrKnownAccounts, addr, root, *account.StorageRoot)
+			}
+			continue
+		}
+		for slot, want := range account.StorageSlots {
+			if have := state.GetState(addr, slot); have != want {
+				return fmt.Errorf("%w: account %x slot %x value %x, want %x", ErrKnownAccounts, addr, slot, have, want)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateConditional checks whether the conditions attached to a transaction
+// hold for inclusion in the given block, on top of the given state.
+func ValidateConditional(cond *types.TransactionConditional, header *types.Header, state *state.StateDB) error {
+	if err := cond.CheckBlockNumber(header.Number.Uint64()); err != nil {
+		return err
+	}
+	if err := cond.CheckTimestamp(header.Time); err != nil {
+		return err
+	}
+	return ValidateKnownAccounts(cond.KnownAccounts, state)
+}
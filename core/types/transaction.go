@@ -56,6 +56,8 @@ type Transaction struct {
 	inner TxData    // Consensus contents of a transaction
 	time  time.Time // Time first seen locally (spam avoidance)
 
+	conditional *TransactionConditional // Inclusion conditions attached locally, if any
+
 	// caches
 	hash atomic.Pointer[common.Hash]
 	size atomic.Uint64
@@ -522,6 +524,18 @@ func (tx *Transaction) Time() time.Time {
 	return tx.time
 }
 
+// SetConditional attaches inclusion conditions to the transaction. The conditions
+// are local to the node and not part of the transaction encoding.
+func (tx *Transaction) SetConditional(cond *TransactionConditional) {
+	tx.conditional = cond
+}
+
+// Conditional returns the inclusion conditions attached to the transaction, or
+// nil if the transaction is unconditional.
+func (tx *Transaction) Conditional() *TransactionConditional {
+	return tx.conditional
+}
+
 // Hash returns the transaction hash.
 func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
@@ -0,0 +1,137 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// MaxConditionalCost is the maximum number of storage roots and slots which can
+// be checked by the conditions of a single transaction.
+const MaxConditionalCost = 1000
+
+var (
+	ErrConditionalTooCostly     = fmt.Errorf("conditional checks more than %d storage roots and slots", MaxConditionalCost)
+	ErrConditionalBlockNumber   = errors.New("block number outside of the conditional bounds")
+	ErrConditionalTimestamp     = errors.New("timestamp outside of the conditional bounds")
+	ErrConditionalInvalidBounds = errors.New("conditional minimum bound above the maximum")
+)
+
+// KnownAccount is the expected storage of an account: either its storage root,
+// or the values of some of its storage slots.
+type KnownAccount struct {
+	StorageRoot  *common.Hash
+	StorageSlots map[common.Hash]common.Hash
+}
+
+// MarshalJSON encodes the account as its storage root if known, or as the map
+// of its storage slots otherwise.
+func (ka KnownAccount) MarshalJSON() ([]byte, error) {
+	if ka.StorageRoot != nil {
+		return json.Marshal(ka.StorageRoot)
+	}
+	return json.Marshal(ka.StorageSlots)
+}
+
+// UnmarshalJSON decodes the account from either a storage root, or a map of
+// storage slots.
+func (ka *KnownAccount) UnmarshalJSON(input []byte) error {
+	var root common.Hash
+	if err := json.Unmarshal(input, &root); err == nil {
+		ka.StorageRoot, ka.StorageSlots = &root, nil
+		return nil
+	}
+	var slots map[common.Hash]common.Hash
+	if err := json.Unmarshal(input, &slots); err != nil {
+		return errors.New("known account must be a storage root or a map of storage slots")
+	}
+	ka.StorageRoot, ka.StorageSlots = nil, slots
+	return nil
+}
+
+// TransactionConditional is a set of conditions which must hold for a
+// transaction to be included in a block. The conditions are attached to the
+// transaction locally and are never part of its encoding.
+type TransactionConditional struct {
+	KnownAccounts  map[common.Address]KnownAccount `json:"knownAccounts"`
+	BlockNumberMin *hexutil.Uint64                 `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *hexutil.Uint64                 `json:"blockNumberMax,omitempty"`
+	TimestampMin   *hexutil.Uint64                 `json:"timestampMin,omitempty"`
+	TimestampMax   *hexutil.Uint64                 `json:"timestampMax,omitempty"`
+}
+
+// Cost returns the number of storage roots and slots checked by the conditions.
+func (c *TransactionConditional) Cost() int {
+	var cost int
+	for _, account := range c.KnownAccounts {
+		if account.StorageRoot != nil {
+			cost++
+		}
+		cost += len(account.StorageSlots)
+	}
+	return cost
+}
+
+// Validate checks the conditions for inconsistencies not depending on the chain.
+func (c *TransactionConditional) Validate() error {
+	if c.BlockNumberMin != nil && c.BlockNumberMax != nil && *c.BlockNumberMin > *c.BlockNumberMax {
+		return ErrConditionalInvalidBounds
+	}
+	if c.TimestampMin != nil && c.TimestampMax != nil && *c.TimestampMin > *c.TimestampMax {
+		return ErrConditionalInvalidBounds
+	}
+	if c.Cost() > MaxConditionalCost {
+		return ErrConditionalTooCostly
+	}
+	return nil
+}
+
+// CheckBlockNumber checks whether the given block number is within the bounds.
+func (c *TransactionConditional) CheckBlockNumber(number uint64) error {
+	if c.BlockNumberMin != nil && number < uint64(*c.BlockNumberMin) {
+		return fmt.Errorf("%w: %d < minimum %d", ErrConditionalBlockNumber, number, *c.BlockNumberMin)
+	}
+	if c.BlockNumberMax != nil && number > uint64(*c.BlockNumberMax) {
+		return fmt.Errorf("%w: %d > maximum %d", ErrConditionalBlockNumber, number, *c.BlockNumberMax)
+	}
+	return nil
+}
+
+// CheckTimestamp checks whether the given block timestamp is within the bounds.
+func (c *TransactionConditional) CheckTimestamp(time uint64) error {
+	if c.TimestampMin != nil && time < uint64(*c.TimestampMin) {
+		return fmt.Errorf("%w: %d < minimum %d", ErrConditionalTimestamp, time, *c.TimestampMin)
+	}
+	if c.TimestampMax != nil && time > uint64(*c.TimestampMax) {
+		return fmt.Errorf("%w: %d > maximum %d", ErrConditionalTimestamp, time, *c.TimestampMax)
+	}
+	return nil
+}
+
+// Expired reports whether the conditions can no longer be satisfied by any block
+// with at least the given number and timestamp.
+func (c *TransactionConditional) Expired(number uint64, time uint64) bool {
+	if c.BlockNumberMax != nil && number > uint64(*c.BlockNumberMax) {
+		return true
+	}
+	return c.TimestampMax != nil && time > uint64(*c.TimestampMax)
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTransactionConditionalJSON(t *testing.T) {
+	input := `{
+		"knownAccounts": {
+			"0x000000000000000000000000000000000000aaaa": "0x0000000000000000000000000000000000000000000000000000000000000001",
+			"0x000000000000000000000000000000000000bbbb": {
+				"0x0000000000000000000000000000000000000000000000000000000000000002": "0x0000000000000000000000000000000000000000000000000000000000000003"
+			}
+		},
+		"blockNumberMin": "0x10",
+		"timestampMax": "0x20"
+	}`
+	var cond TransactionConditional
+	if err := json.Unmarshal([]byte(input), &cond); err != nil {
+		t.Fatalf("failed to decode conditional: %v", err)
+	}
+	root := common.HexToHash("0x01")
+	want := map[common.Address]KnownAccount{
+		common.HexToAddress("0xaaaa"): {StorageRoot: &root},
+		common.HexToAddress("0xbbbb"): {StorageSlots: map[common.Hash]common.Hash{common.HexToHash("0x02"): common.HexToHash("0x03")}},
+	}
+	if !reflect.DeepEqual(cond.KnownAccounts, want) {
+		t.Fatalf("unexpected known accounts: %v", cond.KnownAccounts)
+	}
+	if cond.BlockNumberMin == nil || *cond.BlockNumberMin != 0x10 || cond.TimestampMax == nil || *cond.TimestampMax != 0x20 {
+		t.Fatalf("unexpected bounds: %+v", cond)
+	}
+	if cost := cond.Cost(); cost != 2 {
+		t.Fatalf("unexpected cost, want 2, got %d", cost)
+	}
+	// Round trip through the encoding
+	enc, err := json.Marshal(&cond)
+	if err != nil {
+		t.Fatalf("failed to encode conditional: %v", err)
+	}
+	var dec TransactionConditional
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatalf("failed to decode conditional: %v", err)
+	}
+	if !reflect.DeepEqual(cond, dec) {
+		t.Fatalf("conditional mismatch after round trip: %+v != %+v", dec, cond)
+	}
+	// Bounds checks
+	if err := cond.CheckBlockNumber(0xf); !errors.Is(err, ErrConditionalBlockNumber) {
+		t.Fatalf("unexpected block number check error: %v", err)
+	}
+	if err := cond.CheckBlockNumber(0x10); err != nil {
+		t.Fatalf("unexpected block number check error: %v", err)
+	}
+	if err := cond.CheckTimestamp(0x21); !errors.Is(err, ErrConditionalTimestamp) {
+		t.Fatalf("unexpected timestamp check error: %v", err)
+	}
+	if !cond.Expired(0x10, 0x21) || cond.Expired(0x1, 0x20) {
+		t.Fatal("unexpected expiry")
+	}
+}
@@ -74,6 +74,56 @@ func TestBlobTxSize(t *testing.T) {
 	}
 }
 
+// This test verifies the encoding of version 1 sidecars carrying cell proofs.
+func TestBlobTxSidecarV1Encoding(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	tx := createEmptyBlobTx(key, true)
+
+	proofs := make([]kzg4844.Proof, kzg4844.CellProofsPerBlob)
+	for i := range proofs {
+		proofs[i][0] = byte(i)
+	}
+	sidecar := NewBlobTxSidecar(BlobSidecarVersion1, tx.BlobTxSidecar().Blobs, tx.BlobTxSidecar().Commitments, proofs)
+	txV1 := tx.WithBlobTxSidecar(sidecar)
+
+	if txV1.Hash() != tx.Hash() {
+		t.Fatal("sidecar version changed the tx hash")
+	}
+	enc, err := txV1.MarshalBinary()
+	if err != nil {
+		t.Fatal("failed to encode tx:", err)
+	}
+	if size := txV1.Size(); size != uint64(len(enc)) {
+		t.Error("wrong size with cell proofs:", size, "encoded length:", len(enc))
+	}
+	dec := new(Transaction)
+	if err := dec.UnmarshalBinary(enc); err != nil {
+		t.Fatal("failed to decode tx:", err)
+	}
+	have := dec.BlobTxSidecar()
+	if have == nil || have.Version != BlobSidecarVersion1 || len(have.Proofs) != len(proofs) {
+		t.Fatalf("decoded sidecar mismatch: %+v", have)
+	}
+	cellProofs, err := have.CellProofsAt(0)
+	if err != nil {
+		t.Fatal("failed to get cell proofs:", err)
+	}
+	if cellProofs[7] != proofs[7] {
+		t.Fatal("cell proof mismatch")
+	}
+	// Legacy sidecars must still decode as version 0
+	enc, _ = tx.MarshalBinary()
+	if err := dec.UnmarshalBinary(enc); err != nil {
+		t.Fatal("failed to decode legacy tx:", err)
+	}
+	if dec.BlobTxSidecar().Version != BlobSidecarVersion0 {
+		t.Fatal("legacy sidecar decoded with wrong version")
+	}
+	if _, err := dec.BlobTxSidecar().CellProofsAt(0); err == nil {
+		t.Fatal("cell proofs returned for legacy sidecar")
+	}
+}
+
 var (
 	emptyBlob          = new(kzg4844.Blob)
 	emptyBlobCommit, _ = kzg4844.BlobToCommitment(emptyBlob)
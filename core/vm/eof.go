@@ -105,6 +105,11 @@ func (meta *functionMetadata) checkStackMax(stackMax int) error {
 	return nil
 }
 
+// CodeSections returns the code sections of the container.
+func (c *Container) CodeSections() [][]byte {
+	return c.codeSections
+}
+
 // MarshalBinary encodes an EOF container into binary format.
 func (c *Container) MarshalBinary() []byte {
 	// Build EOF prefix.
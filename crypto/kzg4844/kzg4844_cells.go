@@ -0,0 +1,88 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package kzg4844
+
+import (
+	"errors"
+	"fmt"
+
+	gokzg4844 "github.com/crate-crypto/go-eth-kzg"
+)
+
+// The cell operations of EIP-7594 (PeerDAS) are not provided by the C backend,
+// so they are always served by go-eth-kzg, using the same trusted setup as the
+// blob operations.
+
+// CellProofsPerBlob is the number of cell proofs attached to a blob, one for
+// each cell of its extended form.
+const CellProofsPerBlob = gokzg4844.CellsPerExtBlob
+
+var (
+	errCellProofCount = errors.New("invalid number of cell proofs")
+	errCellCommitment = errors.New("invalid number of commitments")
+)
+
+// ComputeCellProofs returns the KZG proofs of all the cells of the extended
+// blob, as used by the PeerDAS blob sidecars.
+func ComputeCellProofs(blob *Blob) ([]Proof, error) {
+	gokzgIniter.Do(gokzgInit)
+
+	_, proofs, err := context.ComputeCellsAndKZGProofs((*gokzg4844.Blob)(blob), 0)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Proof, len(proofs))
+	for i, proof := range proofs {
+		res[i] = (Proof)(proof)
+	}
+	return res, nil
+}
+
+// VerifyCellProofs verifies a batch of blobs against their commitments and
+// cell proofs. The proofs of each blob are expected to follow each other, in
+// the order of the cells.
+func VerifyCellProofs(blobs []Blob, commitments []Commitment, proofs []Proof) error {
+	gokzgIniter.Do(gokzgInit)
+
+	if len(blobs) != len(commitments) {
+		return fmt.Errorf("%w: have %d, want %d", errCellCommitment, len(commitments), len(blobs))
+	}
+	if len(proofs) != len(blobs)*CellProofsPerBlob {
+		return fmt.Errorf("%w: have %d, want %d", errCellProofCount, len(proofs), len(blobs)*CellProofsPerBlob)
+	}
+	var (
+		cells       = make([]*gokzg4844.Cell, 0, len(proofs))
+		cellIndices = make([]uint64, 0, len(proofs))
+		cellCommits = make([]gokzg4844.KZGCommitment, 0, len(proofs))
+		cellProofs  = make([]gokzg4844.KZGProof, len(proofs))
+	)
+	for i := range blobs {
+		blobCells, err := context.ComputeCells((*gokzg4844.Blob)(&blobs[i]), 0)
+		if err != nil {
+			return fmt.Errorf("invalid blob %d: %v", i, err)
+		}
+		for j := range blobCells {
+			cells = append(cells, blobCells[j])
+			cellIndices = append(cellIndices, uint64(j))
+			cellCommits = append(cellCommits, (gokzg4844.KZGCommitment)(commitments[i]))
+		}
+	}
+	for i, proof := range proofs {
+		cellProofs[i] = (gokzg4844.KZGProof)(proof)
+	}
+	return context.VerifyCellKZGProofBatch(cellCommits, cellIndices, cells, cellProofs)
+}
@@ -21,7 +21,7 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
-	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	gokzg4844 "github.com/crate-crypto/go-eth-kzg"
 )
 
 func randFieldElement() [32]byte {
@@ -193,3 +193,82 @@ func benchmarkVerifyBlobProof(b *testing.B, ckzg bool) {
 		VerifyBlobProof(blob, commitment, proof)
 	}
 }
+
+func TestCellProofs(t *testing.T) {
+	blobs := []Blob{*randBlob(), *randBlob()}
+
+	var (
+		commitments []Commitment
+		proofs      []Proof
+	)
+	for i := range blobs {
+		commitment, err := BlobToCommitment(&blobs[i])
+		if err != nil {
+			t.Fatalf("failed to create KZG commitment from blob: %v", err)
+		}
+		cellProofs, err := ComputeCellProofs(&blobs[i])
+		if err != nil {
+			t.Fatalf("failed to create cell proofs for blob: %v", err)
+		}
+		if len(cellProofs) != CellProofsPerBlob {
+			t.Fatalf("cell proof count mismatch: have %d, want %d", len(cellProofs), CellProofsPerBlob)
+		}
+		commitments = append(commitments, commitment)
+		proofs = append(proofs, cellProofs...)
+	}
+	if err := VerifyCellProofs(blobs, commitments, proofs); err != nil {
+		t.Fatalf("failed to verify cell proofs: %v", err)
+	}
+	// Proofs swapped between cells must be rejected
+	proofs[3], proofs[4] = proofs[4], proofs[3]
+	if err := VerifyCellProofs(blobs, commitments, proofs); err == nil {
+		t.Fatal("swapped cell proofs verified")
+	}
+	proofs[3], proofs[4] = proofs[4], proofs[3]
+
+	// Proofs against the wrong commitment must be rejected
+	if err := VerifyCellProofs(blobs, []Commitment{commitments[1], commitments[0]}, proofs); err == nil {
+		t.Fatal("cell proofs verified against wrong commitments")
+	}
+	if err := VerifyCellProofs(blobs, commitments, proofs[1:]); err == nil {
+		t.Fatal("missing cell proof accepted")
+	}
+}
+
+// Tests the cell proofs of an empty blob, whose polynomial is zero, against the
+// known answer of the point at infinity.
+func TestCellProofsZeroBlob(t *testing.T) {
+	proofs, err := ComputeCellProofs(new(Blob))
+	if err != nil {
+		t.Fatalf("failed to create cell proofs for blob: %v", err)
+	}
+	for i, proof := range proofs {
+		if proof != (Proof{0xc0}) {
+			t.Fatalf("cell proof %d mismatch: have %x, want point at infinity", i, proof)
+		}
+	}
+	if err := VerifyCellProofs([]Blob{{}}, []Commitment{{0xc0}}, proofs); err != nil {
+		t.Fatalf("failed to verify cell proofs: %v", err)
+	}
+}
+
+func BenchmarkComputeCellProofs(b *testing.B) {
+	blob := randBlob()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ComputeCellProofs(blob)
+	}
+}
+
+func BenchmarkVerifyCellProofs(b *testing.B) {
+	var (
+		blob          = randBlob()
+		commitment, _ = BlobToCommitment(blob)
+		proofs, _     = ComputeCellProofs(blob)
+	)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		VerifyCellProofs([]Blob{*blob}, []Commitment{commitment}, proofs)
+	}
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -234,7 +235,7 @@ func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 	if header == nil {
 		return nil, nil, errors.New("header not found")
 	}
-	stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+	stateDb, err := b.stateAt(header.Root)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -256,7 +257,7 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 		if blockNrOrHash.RequireCanonical && b.eth.blockchain.GetCanonicalHash(header.Number.Uint64()) != hash {
 			return nil, nil, errors.New("hash is not currently canonical")
 		}
-		stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+		stateDb, err := b.stateAt(header.Root)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -265,9 +266,24 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 	return nil, nil, errors.New("invalid arguments; neither block nor hash specified")
 }
 
+// stateAt returns the state associated with the given root. If the state is no
+// longer available in the live state database, it falls back to the historical
+// state served from the state histories (path-based archive node only).
+func (b *EthAPIBackend) stateAt(root common.Hash) (*state.StateDB, error) {
+	stateDb, err := b.eth.BlockChain().StateAt(root)
+	if err == nil {
+		return stateDb, nil
+	}
+	historic, herr := b.eth.BlockChain().HistoricState(root)
+	if herr != nil {
+		log.Debug("Historical state is not available", "root", root, "err", herr)
+		return nil, err
+	}
+	return historic, nil
+}
+
 func (b *EthAPIBackend) HistoryPruningCutoff() uint64 {
-	bn, _ := b.eth.blockchain.HistoryPruningCutoff()
-	return bn
+	return b.eth.historyCutoff()
 }
 
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
@@ -310,9 +326,10 @@ func (b *EthAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscri
 func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
 	err := b.eth.txPool.Add([]*types.Transaction{signedTx}, false)[0]
 
-	// If the local transaction tracker is not configured, returns whatever
-	// returned from the txpool.
-	if b.eth.localTxTracker == nil {
+	// If the local transaction tracker is not configured, or the transaction
+	// is conditional and can't be resubmitted, returns whatever returned from
+	// the txpool.
+	if b.eth.localTxTracker == nil || signedTx.Conditional() != nil {
 		return err
 	}
 	// If the transaction fails with an error indicating it is invalid, or if there is
@@ -391,6 +408,18 @@ func (b *EthAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.S
 	return b.eth.txPool.SubscribeTransactions(ch, true)
 }
 
+func (b *EthAPIBackend) TxPoolStatus(hash common.Hash) txpool.TxStatus {
+	return b.eth.txPool.Status(hash)
+}
+
+func (b *EthAPIBackend) TxPoolDropped(hash common.Hash) *txpool.DropRecord {
+	return b.eth.txPool.Dropped(hash)
+}
+
+func (b *EthAPIBackend) SubscribeTxDropEvent(ch chan<- txpool.DropEvent) event.Subscription {
+	return b.eth.txPool.SubscribeDrops(ch)
+}
+
 func (b *EthAPIBackend) SyncProgress() ethereum.SyncProgress {
 	prog := b.eth.Downloader().Progress()
 	if txProg, err := b.eth.blockchain.TxIndexProgress(); err == nil {
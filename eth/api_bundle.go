@@ -0,0 +1,157 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BundleAPI provides an API to submit and simulate transaction bundles, which
+// are included atomically ahead of the pool transactions in built payloads.
+type BundleAPI struct {
+	e *Ethereum
+}
+
+// NewBundleAPI creates a new BundleAPI instance.
+func NewBundleAPI(e *Ethereum) *BundleAPI {
+	return &BundleAPI{e}
+}
+
+// SendBundleArgs represents the arguments of eth_sendBundle.
+type SendBundleArgs struct {
+	Txs               []hexutil.Bytes `json:"txs"`
+	BlockNumber       hexutil.Uint64  `json:"blockNumber"`
+	MinTimestamp      *hexutil.Uint64 `json:"minTimestamp"`
+	MaxTimestamp      *hexutil.Uint64 `json:"maxTimestamp"`
+	RevertingTxHashes []common.Hash   `json:"revertingTxHashes"`
+}
+
+// CallBundleArgs represents the arguments of eth_callBundle.
+type CallBundleArgs struct {
+	Txs              []hexutil.Bytes       `json:"txs"`
+	StateBlockNumber rpc.BlockNumberOrHash `json:"stateBlockNumber"`
+	Timestamp        *hexutil.Uint64       `json:"timestamp"`
+}
+
+// SendBundleResult is the response of eth_sendBundle.
+type SendBundleResult struct {
+	BundleHash common.Hash `json:"bundleHash"`
+}
+
+// CallBundleResult is the response of eth_callBundle.
+type CallBundleResult struct {
+	BundleHash       common.Hash           `json:"bundleHash"`
+	StateBlockNumber hexutil.Uint64        `json:"stateBlockNumber"`
+	TotalGasUsed     hexutil.Uint64        `json:"totalGasUsed"`
+	CoinbaseDiff     *hexutil.Big          `json:"coinbaseDiff"`
+	Results          []*CallBundleTxResult `json:"results"`
+}
+
+// CallBundleTxResult is the outcome of a single transaction in eth_callBundle.
+type CallBundleTxResult struct {
+	TxHash       common.Hash     `json:"txHash"`
+	From         common.Address  `json:"fromAddress"`
+	To           *common.Address `json:"toAddress"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Status       hexutil.Uint64  `json:"status"`
+	CoinbaseDiff *hexutil.Big    `json:"coinbaseDiff"`
+	Logs         []*types.Log    `json:"logs"`
+}
+
+// decodeBundleTxs decodes the binary encoded transactions of a bundle.
+func decodeBundleTxs(encoded []hexutil.Bytes) (types.Transactions, error) {
+	txs := make(types.Transactions, len(encoded))
+	for i, enc := range encoded {
+		txs[i] = new(types.Transaction)
+		if err := txs[i].UnmarshalBinary(enc); err != nil {
+			return nil, fmt.Errorf("tx %d: %w", i, err)
+		}
+	}
+	return txs, nil
+}
+
+// SendBundle submits a bundle for inclusion in the block with the given number.
+func (api *BundleAPI) SendBundle(args SendBundleArgs) (*SendBundleResult, error) {
+	txs, err := decodeBundleTxs(args.Txs)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &miner.Bundle{
+		Txs:               txs,
+		BlockNumber:       uint64(args.BlockNumber),
+		RevertingTxHashes: args.RevertingTxHashes,
+	}
+	if args.MinTimestamp != nil {
+		bundle.MinTimestamp = uint64(*args.MinTimestamp)
+	}
+	if args.MaxTimestamp != nil {
+		bundle.MaxTimestamp = uint64(*args.MaxTimestamp)
+	}
+	if err := api.e.Miner().SendBundle(bundle); err != nil {
+		return nil, err
+	}
+	return &SendBundleResult{BundleHash: bundle.Hash()}, nil
+}
+
+// CallBundle simulates a bundle on top of the given state block, returning the
+// outcome of its transactions. The bundle is not submitted for inclusion.
+func (api *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (*CallBundleResult, error) {
+	txs, err := decodeBundleTxs(args.Txs)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := api.e.APIBackend.HeaderByNumberOrHash(ctx, args.StateBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, fmt.Errorf("state block %v not found", args.StateBlockNumber)
+	}
+	var timestamp uint64
+	if args.Timestamp != nil {
+		timestamp = uint64(*args.Timestamp)
+	}
+	result, err := api.e.Miner().CallBundle(&miner.Bundle{Txs: txs}, parent.Hash(), timestamp)
+	if err != nil {
+		return nil, err
+	}
+	res := &CallBundleResult{
+		BundleHash:       result.Hash,
+		StateBlockNumber: hexutil.Uint64(parent.Number.Uint64()),
+		TotalGasUsed:     hexutil.Uint64(result.GasUsed),
+		CoinbaseDiff:     (*hexutil.Big)(result.CoinbaseDiff),
+	}
+	for i, tx := range result.Txs {
+		res.Results = append(res.Results, &CallBundleTxResult{
+			TxHash:       tx.Receipt.TxHash,
+			From:         tx.From,
+			To:           txs[i].To(),
+			GasUsed:      hexutil.Uint64(tx.Receipt.GasUsed),
+			Status:       hexutil.Uint64(tx.Receipt.Status),
+			CoinbaseDiff: (*hexutil.Big)(tx.CoinbaseDiff),
+			Logs:         tx.Receipt.Logs,
+		})
+	}
+	return res, nil
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/rawdb/eradb"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
@@ -79,11 +80,13 @@ type Ethereum struct {
 	dropper *dropper
 
 	// DB interfaces
-	chainDb ethdb.Database // Block chain database
+	chainDb    ethdb.Database      // Block chain database
+	eraHistory ethdb.AncientReader // Era files serving the pruned chain history, nil if none
 
 	eventMux       *event.TypeMux
 	engine         consensus.Engine
 	accountManager *accounts.Manager
+	tracerAPIs     []rpc.API // RPC APIs exposed by the live tracer
 
 	filterMaps      *filtermaps.FilterMaps
 	closeFilterMaps chan chan struct{}
@@ -117,7 +120,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		log.Warn("Sanitizing invalid miner gas price", "provided", config.Miner.GasPrice, "updated", ethconfig.Defaults.Miner.GasPrice)
 		config.Miner.GasPrice = new(big.Int).Set(ethconfig.Defaults.Miner.GasPrice)
 	}
-	if config.NoPruning && config.TrieDirtyCache > 0 {
+	// The path-based archive node still relies on the dirty buffer for
+	// aggregating state writes, keep it as it is.
+	if config.NoPruning && config.TrieDirtyCache > 0 && config.StateScheme != rawdb.PathScheme {
 		if config.SnapshotCache > 0 {
 			config.TrieCleanCache += config.TrieDirtyCache * 3 / 5
 			config.SnapshotCache += config.TrieDirtyCache * 2 / 5
@@ -149,6 +154,24 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Serve the history missing from the freezer from era files, if configured.
+	var eraHistory ethdb.AncientReader
+	if config.DatabaseEra != "" {
+		network := "unknown"
+		if name, ok := params.NetworkNames[chainConfig.ChainID.String()]; ok {
+			network = name
+		}
+		history, err := eradb.New(stack.ResolvePath(config.DatabaseEra), network)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open era history: %w", err)
+		}
+		if err := history.CheckChain(chainDb); err != nil {
+			history.Close()
+			return nil, fmt.Errorf("era history mismatch: %w", err)
+		}
+		chainDb = rawdb.NewDatabaseWithHistory(chainDb, history)
+		eraHistory = history
+	}
 	engine, err := ethconfig.CreateConsensusEngine(chainConfig, chainDb)
 	if err != nil {
 		return nil, err
@@ -163,6 +186,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	eth := &Ethereum{
 		config:          config,
 		chainDb:         chainDb,
+		eraHistory:      eraHistory,
 		eventMux:        stack.EventMux(),
 		accountManager:  stack.AccountManager(),
 		engine:          engine,
@@ -203,6 +227,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			SnapshotLimit:       config.SnapshotCache,
 			Preimages:           config.Preimages,
 			StateHistory:        config.StateHistory,
+			StateIndexing:       config.NoPruning,
 			StateScheme:         scheme,
 			ChainHistoryMode:    config.HistoryMode,
 		}
@@ -212,11 +237,12 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		if config.VMTraceJsonConfig != "" {
 			traceConfig = json.RawMessage(config.VMTraceJsonConfig)
 		}
-		t, err := tracers.LiveDirectory.New(config.VMTrace, traceConfig)
+		t, err := tracers.LiveDirectory.NewWithContext(config.VMTrace, &tracers.LiveContext{DB: chainDb}, traceConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create tracer %s: %v", config.VMTrace, err)
 		}
-		vmConfig.Tracer = t
+		vmConfig.Tracer = t.Hooks
+		eth.tracerAPIs = t.APIs
 	}
 	// Override the chain config with provided settings.
 	var overrides core.ChainOverrides
@@ -239,7 +265,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		HashScheme:     scheme == rawdb.HashScheme,
 	}
 	chainView := eth.newChainView(eth.blockchain.CurrentBlock())
-	historyCutoff, _ := eth.blockchain.HistoryPruningCutoff()
+	historyCutoff := eth.historyCutoff()
 	var finalBlock uint64
 	if fb := eth.blockchain.CurrentFinalBlock(); fb != nil {
 		finalBlock = fb.Number.Uint64()
@@ -251,6 +277,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
+	if config.TxPool.Snapshot != "" {
+		config.TxPool.Snapshot = stack.ResolvePath(config.TxPool.Snapshot)
+	}
 	legacyPool := legacypool.New(config.TxPool, eth.blockchain)
 
 	if config.BlobPool.Datadir != "" {
@@ -340,11 +369,17 @@ func (s *Ethereum) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// Append any APIs exposed by the live tracer
+	apis = append(apis, s.tracerAPIs...)
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
 			Namespace: "miner",
 			Service:   NewMinerAPI(s),
+		}, {
+			Namespace: "eth",
+			Service:   NewBundleAPI(s),
 		}, {
 			Namespace: "eth",
 			Service:   downloader.NewDownloaderAPI(s.handler.downloader, s.blockchain, s.eventMux),
@@ -361,6 +396,25 @@ func (s *Ethereum) APIs() []rpc.API {
 	}...)
 }
 
+// historyCutoff returns the number of the first block whose history is served
+// locally. It's the history pruning point of the chain, lowered to the first
+// block of the era files if they cover the pruned history.
+func (s *Ethereum) historyCutoff() uint64 {
+	cutoff, _ := s.blockchain.HistoryPruningCutoff()
+	if s.eraHistory == nil {
+		return cutoff
+	}
+	tail, err := s.eraHistory.Tail()
+	if err != nil {
+		return cutoff
+	}
+	items, err := s.eraHistory.Ancients()
+	if err != nil || items < cutoff {
+		return cutoff
+	}
+	return min(cutoff, tail)
+}
+
 func (s *Ethereum) ResetWithGenesisBlock(gb *types.Block) {
 	s.blockchain.ResetWithGenesisBlock(gb)
 }
@@ -443,7 +497,7 @@ func (s *Ethereum) updateFilterMapsHeads() {
 		if head == nil || newHead.Hash() != head.Hash() {
 			head = newHead
 			chainView := s.newChainView(head)
-			historyCutoff, _ := s.blockchain.HistoryPruningCutoff()
+			historyCutoff := s.historyCutoff()
 			var finalBlock uint64
 			if fb := s.blockchain.CurrentFinalBlock(); fb != nil {
 				finalBlock = fb.Number.Uint64()
@@ -93,7 +93,9 @@ var caps = []string{
 	"engine_getPayloadV2",
 	"engine_getPayloadV3",
 	"engine_getPayloadV4",
+	"engine_getPayloadV5",
 	"engine_getBlobsV1",
+	"engine_getBlobsV2",
 	"engine_newPayloadV1",
 	"engine_newPayloadV2",
 	"engine_newPayloadV3",
@@ -239,7 +241,7 @@ func (api *ConsensusAPI) ForkchoiceUpdatedV3(update engine.ForkchoiceStateV1, pa
 		if params.BeaconRoot == nil {
 			return engine.STATUS_INVALID, engine.InvalidPayloadAttributes.With(errors.New("missing beacon root"))
 		}
-		if api.eth.BlockChain().Config().LatestFork(params.Timestamp) != forks.Cancun && api.eth.BlockChain().Config().LatestFork(params.Timestamp) != forks.Prague {
+		if fork := api.eth.BlockChain().Config().LatestFork(params.Timestamp); fork != forks.Cancun && fork != forks.Prague && fork != forks.Osaka {
 			return engine.STATUS_INVALID, engine.UnsupportedFork.With(errors.New("forkchoiceUpdatedV3 must only be called for cancun payloads"))
 		}
 	}
@@ -297,7 +299,7 @@ func (api *ConsensusAPI) ForkchoiceUpdatedWithWitnessV3(update engine.Forkchoice
 		if params.BeaconRoot == nil {
 			return engine.STATUS_INVALID, engine.InvalidPayloadAttributes.With(errors.New("missing beacon root"))
 		}
-		if api.eth.BlockChain().Config().LatestFork(params.Timestamp) != forks.Cancun && api.eth.BlockChain().Config().LatestFork(params.Timestamp) != forks.Prague {
+		if fork := api.eth.BlockChain().Config().LatestFork(params.Timestamp); fork != forks.Cancun && fork != forks.Prague && fork != forks.Osaka {
 			return engine.STATUS_INVALID, engine.UnsupportedFork.With(errors.New("forkchoiceUpdatedV3 must only be called for cancun payloads"))
 		}
 	}
@@ -516,7 +518,31 @@ func (api *ConsensusAPI) GetPayloadV4(payloadID engine.PayloadID) (*engine.Execu
 	if !payloadID.Is(engine.PayloadV3) {
 		return nil, engine.UnsupportedFork
 	}
-	return api.getPayload(payloadID, false)
+	data, err := api.getPayload(payloadID, false)
+	if err != nil {
+		return nil, err
+	}
+	// Osaka payloads carry cell proofs, which are only returned by V5
+	if api.eth.BlockChain().Config().LatestFork(data.ExecutionPayload.Timestamp) == forks.Osaka {
+		return nil, engine.UnsupportedFork
+	}
+	return data, nil
+}
+
+// GetPayloadV5 returns a cached payload by id. The blobs bundle of the payload
+// carries the cell proofs of the blobs.
+func (api *ConsensusAPI) GetPayloadV5(payloadID engine.PayloadID) (*engine.ExecutionPayloadEnvelope, error) {
+	if !payloadID.Is(engine.PayloadV3) {
+		return nil, engine.UnsupportedFork
+	}
+	data, err := api.getPayload(payloadID, false)
+	if err != nil {
+		return nil, err
+	}
+	if api.eth.BlockChain().Config().LatestFork(data.ExecutionPayload.Timestamp) != forks.Osaka {
+		return nil, engine.UnsupportedFork
+	}
+	return data, nil
 }
 
 func (api *ConsensusAPI) getPayload(payloadID engine.PayloadID, full bool) (*engine.ExecutionPayloadEnvelope, error) {
@@ -535,18 +561,45 @@ func (api *ConsensusAPI) GetBlobsV1(hashes []common.Hash) ([]*engine.BlobAndProo
 	}
 	res := make([]*engine.BlobAndProofV1, len(hashes))
 
-	blobs, proofs := api.eth.TxPool().GetBlobs(hashes)
+	blobs, proofs := api.eth.TxPool().GetBlobs(hashes, types.BlobSidecarVersion0)
 	for i := 0; i < len(blobs); i++ {
 		if blobs[i] != nil {
 			res[i] = &engine.BlobAndProofV1{
 				Blob:  (*blobs[i])[:],
-				Proof: (*proofs[i])[:],
+				Proof: proofs[i][0][:],
 			}
 		}
 	}
 	return res, nil
 }
 
+// GetBlobsV2 returns blobs from the transaction pool, along with their cell
+// proofs. If any of the requested blobs is missing, nothing is returned.
+func (api *ConsensusAPI) GetBlobsV2(hashes []common.Hash) ([]*engine.BlobAndProofV2, error) {
+	if len(hashes) > 128 {
+		return nil, engine.TooLargeRequest.With(fmt.Errorf("requested blob count too large: %v", len(hashes)))
+	}
+	res := make([]*engine.BlobAndProofV2, len(hashes))
+
+	blobs, proofs := api.eth.TxPool().GetBlobs(hashes, types.BlobSidecarVersion1)
+	for i := range hashes {
+		// Partial responses are not permitted, the consensus client needs all
+		// the blobs to reconstruct the columns.
+		if i >= len(blobs) || blobs[i] == nil {
+			return nil, nil
+		}
+		cellProofs := make([]hexutil.Bytes, len(proofs[i]))
+		for j := range proofs[i] {
+			cellProofs[j] = proofs[i][j][:]
+		}
+		res[i] = &engine.BlobAndProofV2{
+			Blob:       (*blobs[i])[:],
+			CellProofs: cellProofs,
+		}
+	}
+	return res, nil
+}
+
 // NewPayloadV1 creates an Eth1 block, inserts it in the chain, and returns the status of the chain.
 func (api *ConsensusAPI) NewPayloadV1(params engine.ExecutableData) (engine.PayloadStatusV1, error) {
 	if params.Withdrawals != nil {
@@ -625,7 +678,7 @@ func (api *ConsensusAPI) NewPayloadV4(params engine.ExecutableData, versionedHas
 		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("nil executionRequests post-prague"))
 	}
 
-	if api.eth.BlockChain().Config().LatestFork(params.Timestamp) != forks.Prague {
+	if fork := api.eth.BlockChain().Config().LatestFork(params.Timestamp); fork != forks.Prague && fork != forks.Osaka {
 		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.UnsupportedFork.With(errors.New("newPayloadV4 must only be called for prague payloads"))
 	}
 	requests := convertRequests(executionRequests)
@@ -717,7 +770,7 @@ func (api *ConsensusAPI) NewPayloadWithWitnessV4(params engine.ExecutableData, v
 		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("nil executionRequests post-prague"))
 	}
 
-	if api.eth.BlockChain().Config().LatestFork(params.Timestamp) != forks.Prague {
+	if fork := api.eth.BlockChain().Config().LatestFork(params.Timestamp); fork != forks.Prague && fork != forks.Osaka {
 		return engine.PayloadStatusV1{Status: engine.INVALID}, engine.UnsupportedFork.With(errors.New("newPayloadWithWitnessV4 must only be called for prague payloads"))
 	}
 	requests := convertRequests(executionRequests)
@@ -809,7 +862,7 @@ func (api *ConsensusAPI) ExecuteStatelessPayloadV4(params engine.ExecutableData,
 		return engine.StatelessPayloadStatusV1{Status: engine.INVALID}, engine.InvalidParams.With(errors.New("nil executionRequests post-prague"))
 	}
 
-	if api.eth.BlockChain().Config().LatestFork(params.Timestamp) != forks.Prague {
+	if fork := api.eth.BlockChain().Config().LatestFork(params.Timestamp); fork != forks.Prague && fork != forks.Osaka {
 		return engine.StatelessPayloadStatusV1{Status: engine.INVALID}, engine.UnsupportedFork.With(errors.New("executeStatelessPayloadV4 must only be called for prague payloads"))
 	}
 	requests := convertRequests(executionRequests)
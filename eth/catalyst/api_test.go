@@ -47,6 +47,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
 )
 
 var (
@@ -1540,6 +1541,193 @@ func TestBlockToPayloadWithBlobs(t *testing.T) {
 	}
 }
 
+func TestBlockToPayloadWithCellProofs(t *testing.T) {
+	inner := types.BlobTx{
+		BlobHashes: make([]common.Hash, 2),
+	}
+	txs := []*types.Transaction{types.NewTx(&inner)}
+	sidecars := []*types.BlobTxSidecar{
+		types.NewBlobTxSidecar(types.BlobSidecarVersion1, make([]kzg4844.Blob, 2), make([]kzg4844.Commitment, 2), make([]kzg4844.Proof, 2*kzg4844.CellProofsPerBlob)),
+	}
+	block := types.NewBlock(&types.Header{}, &types.Body{Transactions: txs}, nil, trie.NewStackTrie(nil))
+	envelope := engine.BlockToExecutableData(block, nil, sidecars, nil)
+
+	if got := len(envelope.BlobsBundle.Blobs); got != 2 {
+		t.Fatalf("invalid number of blobs: got %v, want %v", got, 2)
+	}
+	if got, want := len(envelope.BlobsBundle.Proofs), 2*kzg4844.CellProofsPerBlob; got != want {
+		t.Fatalf("invalid number of proofs: got %v, want %v", got, want)
+	}
+}
+
+// setupOsaka creates a node with Osaka activated after the merge chain and
+// mines a first Osaka block on top of it.
+func setupOsaka(t *testing.T) (*node.Node, *eth.Ethereum) {
+	genesis, blocks := generateMergeChain(10, true)
+
+	// Enable all forks up to Osaka on the block after the last one
+	time := blocks[len(blocks)-1].Time() + 1
+	genesis.Config.ShanghaiTime = &time
+	genesis.Config.CancunTime = &time
+	genesis.Config.PragueTime = &time
+	genesis.Config.OsakaTime = &time
+	genesis.Config.BlobScheduleConfig = params.DefaultBlobSchedule
+
+	n, ethservice := startEthService(t, genesis, blocks)
+	setupBlocks(t, ethservice, 1, ethservice.BlockChain().CurrentHeader(), func(parent *types.Header) {}, [][]*types.Withdrawal{{}}, []common.Hash{{}})
+	return n, ethservice
+}
+
+// newTestBlobTx creates a signed blob transaction of the test account, carrying
+// a single blob with a legacy sidecar.
+func newTestBlobTx(t *testing.T, ethservice *eth.Ethereum) *types.Transaction {
+	var (
+		blob       = kzg4844.Blob{0x01}
+		commit, _  = kzg4844.BlobToCommitment(&blob)
+		proof, _   = kzg4844.ComputeBlobProof(&blob, commit)
+		sidecar    = types.NewBlobTxSidecar(types.BlobSidecarVersion0, []kzg4844.Blob{blob}, []kzg4844.Commitment{commit}, []kzg4844.Proof{proof})
+		statedb, _ = ethservice.BlockChain().State()
+		config     = ethservice.BlockChain().Config()
+	)
+	tx, err := types.SignNewTx(testKey, types.LatestSigner(config), &types.BlobTx{
+		ChainID:    uint256.MustFromBig(config.ChainID),
+		Nonce:      statedb.GetNonce(testAddr),
+		GasTipCap:  uint256.NewInt(params.GWei),
+		GasFeeCap:  uint256.NewInt(10 * params.GWei),
+		Gas:        params.TxGas,
+		To:         testAddr,
+		BlobFeeCap: uint256.NewInt(params.GWei),
+		BlobHashes: sidecar.BlobHashes(),
+		Sidecar:    sidecar,
+	})
+	if err != nil {
+		t.Fatalf("failed to sign blob transaction: %v", err)
+	}
+	return tx
+}
+
+func TestGetBlobsV2(t *testing.T) {
+	n, ethservice := setupOsaka(t)
+	defer n.Close()
+
+	api := NewConsensusAPI(ethservice)
+
+	tx := newTestBlobTx(t, ethservice)
+	if errs := ethservice.TxPool().Add([]*types.Transaction{tx}, true); errs[0] != nil {
+		t.Fatalf("failed to add blob transaction: %v", errs[0])
+	}
+	vhash := tx.BlobHashes()[0]
+
+	// Retrieving a known blob returns it along with its cell proofs
+	res, err := api.GetBlobsV2([]common.Hash{vhash})
+	if err != nil {
+		t.Fatalf("failed to get blobs: %v", err)
+	}
+	if len(res) != 1 || res[0] == nil {
+		t.Fatalf("blob missing from response: %v", res)
+	}
+	if len(res[0].CellProofs) != kzg4844.CellProofsPerBlob {
+		t.Fatalf("invalid number of cell proofs: got %d, want %d", len(res[0].CellProofs), kzg4844.CellProofsPerBlob)
+	}
+	var (
+		sidecar = tx.BlobTxSidecar()
+		blob    = kzg4844.Blob(res[0].Blob)
+		proofs  = make([]kzg4844.Proof, len(res[0].CellProofs))
+	)
+	for i, proof := range res[0].CellProofs {
+		proofs[i] = kzg4844.Proof(proof)
+	}
+	if err := kzg4844.VerifyCellProofs([]kzg4844.Blob{blob}, sidecar.Commitments, proofs); err != nil {
+		t.Fatalf("invalid cell proofs returned: %v", err)
+	}
+	// Partial responses are not permitted, a missing blob fails the whole request
+	res, err = api.GetBlobsV2([]common.Hash{vhash, {0x01}})
+	if err != nil {
+		t.Fatalf("failed to get blobs: %v", err)
+	}
+	if res != nil {
+		t.Fatalf("partial response returned: %v", res)
+	}
+	// Oversized requests are rejected
+	if _, err := api.GetBlobsV2(make([]common.Hash, 129)); err == nil {
+		t.Fatal("expected error for oversized request")
+	}
+}
+
+func TestGetPayloadV5(t *testing.T) {
+	n, ethservice := setupOsaka(t)
+	defer n.Close()
+
+	api := NewConsensusAPI(ethservice)
+
+	tx := newTestBlobTx(t, ethservice)
+	if errs := ethservice.TxPool().Add([]*types.Transaction{tx}, true); errs[0] != nil {
+		t.Fatalf("failed to add blob transaction: %v", errs[0])
+	}
+	parent := ethservice.BlockChain().CurrentHeader()
+	blockParams := engine.PayloadAttributes{
+		Timestamp:   parent.Time + 5,
+		Withdrawals: make([]*types.Withdrawal, 0),
+		BeaconRoot:  &common.Hash{42},
+	}
+	fcState := engine.ForkchoiceStateV1{
+		HeadBlockHash: parent.Hash(),
+	}
+	if _, err := api.ForkchoiceUpdatedV3(fcState, &blockParams); err != nil {
+		t.Fatalf("error preparing payload, err=%v", err)
+	}
+	payloadID := (&miner.BuildPayloadArgs{
+		Parent:       fcState.HeadBlockHash,
+		Timestamp:    blockParams.Timestamp,
+		FeeRecipient: blockParams.SuggestedFeeRecipient,
+		Random:       blockParams.Random,
+		Withdrawals:  blockParams.Withdrawals,
+		BeaconRoot:   blockParams.BeaconRoot,
+		Version:      engine.PayloadV3,
+	}).Id()
+	// wait for the full payload to be built
+	if _, err := api.getPayload(payloadID, true); err != nil {
+		t.Fatalf("error getting payload, err=%v", err)
+	}
+	// Osaka payloads must not be served by V4
+	if _, err := api.GetPayloadV4(payloadID); err != engine.UnsupportedFork {
+		t.Fatalf("GetPayloadV4 error mismatch: have %v, want %v", err, engine.UnsupportedFork)
+	}
+	envelope, err := api.GetPayloadV5(payloadID)
+	if err != nil {
+		t.Fatalf("error getting payload, err=%v", err)
+	}
+	if len(envelope.ExecutionPayload.Transactions) != 1 {
+		t.Fatalf("invalid number of transactions: got %d, want 1", len(envelope.ExecutionPayload.Transactions))
+	}
+	bundle := envelope.BlobsBundle
+	if len(bundle.Blobs) != 1 || len(bundle.Commitments) != 1 {
+		t.Fatalf("invalid blobs bundle: %d blobs, %d commitments", len(bundle.Blobs), len(bundle.Commitments))
+	}
+	if len(bundle.Proofs) != kzg4844.CellProofsPerBlob {
+		t.Fatalf("invalid number of proofs: got %d, want %d", len(bundle.Proofs), kzg4844.CellProofsPerBlob)
+	}
+	proofs := make([]kzg4844.Proof, len(bundle.Proofs))
+	for i, proof := range bundle.Proofs {
+		proofs[i] = kzg4844.Proof(proof)
+	}
+	if err := kzg4844.VerifyCellProofs([]kzg4844.Blob{kzg4844.Blob(bundle.Blobs[0])}, []kzg4844.Commitment{kzg4844.Commitment(bundle.Commitments[0])}, proofs); err != nil {
+		t.Fatalf("invalid cell proofs in bundle: %v", err)
+	}
+	// The built payload must be accepted by the chain
+	requests := make([]hexutil.Bytes, len(envelope.Requests))
+	for i, request := range envelope.Requests {
+		requests[i] = request
+	}
+	status, err := api.NewPayloadV4(*envelope.ExecutionPayload, tx.BlobHashes(), blockParams.BeaconRoot, requests)
+	if err != nil {
+		t.Fatalf("error validating payload: %v", err)
+	}
+	if status.Status != engine.VALID {
+		t.Fatalf("invalid payload status: %v", status.Status)
+	}
+}
+
 // This checks that beaconRoot is applied to the state from the engine API.
 func TestParentBeaconBlockRoot(t *testing.T) {
 	//log.SetDefault(log.NewLogger(log.NewTerminalHandlerWithLevel(colorable.NewColorableStderr(), log.LevelTrace, true)))
@@ -178,6 +178,12 @@ func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal, timestamp u
 	if header := c.eth.BlockChain().CurrentBlock(); c.curForkchoiceState.HeadBlockHash != header.Hash() {
 		finalizedHash := c.finalizedBlockHash(header.Number.Uint64())
 		c.setCurrentState(header.Hash(), *finalizedHash)
+
+		// The head might have been inserted without the simulated beacon,
+		// ensure the timestamp is still ahead of it.
+		if timestamp <= header.Time {
+			timestamp = header.Time + 1
+		}
 	}
 
 	// Because transaction insertion, block insertion, and block production will
@@ -120,6 +120,7 @@ type Config struct {
 	DatabaseHandles    int  `toml:"-"`
 	DatabaseCache      int
 	DatabaseFreezer    string
+	DatabaseEra        string // Directory of Era1 files serving the pruned history
 
 	TrieCleanCache int
 	TrieDirtyCache int
@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -40,6 +41,8 @@ var (
 	errInvalidBlockRange      = errors.New("invalid block range params")
 	errPendingLogsUnsupported = errors.New("pending logs are not supported")
 	errExceedMaxTopics        = errors.New("exceed max topics")
+	errExceedMaxBackfill      = errors.New("exceed max backfill range")
+	errBackfillOverflow       = errors.New("too many new logs during backfill")
 )
 
 // The maximum number of topic criteria allowed, vm.LOG4 - vm.LOG0
@@ -256,6 +259,13 @@ func (api *FilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 }
 
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
+//
+// If the criteria has a fromBlock in the past, the matching logs from that block
+// up to the head are replayed first, after which the subscription switches to
+// the new logs. Logs of reorged blocks are delivered again with the removed
+// property set to true, during both phases. At most maxBackfillBlocks blocks are
+// replayed. The subscription is terminated with an error sent to the client if
+// the replay fails, or if more than maxBackfillPending new logs arrive meanwhile.
 func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
@@ -266,7 +276,13 @@ func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subsc
 		rpcSub      = notifier.CreateSubscription()
 		matchedLogs = make(chan []*types.Log)
 	)
-
+	notify := func(log *types.Log) {
+		notifier.Notify(rpcSub.ID, log)
+	}
+	backfill, err := api.newLogsBackfill(ctx, crit, notify)
+	if err != nil {
+		return nil, err
+	}
 	logsSub, err := api.events.SubscribeLogs(ethereum.FilterQuery(crit), matchedLogs)
 	if err != nil {
 		return nil, err
@@ -274,12 +290,50 @@ func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subsc
 
 	go func() {
 		defer logsSub.Unsubscribe()
+
+		// Replay the historical logs first, buffering the new ones meanwhile.
+		var (
+			done     chan error
+			pending  [][]*types.Log
+			buffered int
+		)
+		if backfill != nil {
+			bctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			done = make(chan error, 1)
+			go func() { done <- backfill.run(bctx) }()
+		}
 		for {
 			select {
 			case logs := <-matchedLogs:
+				if done != nil {
+					if buffered += len(logs); buffered > maxBackfillPending {
+						log.Warn("Terminating logs subscription", "id", rpcSub.ID, "err", errBackfillOverflow)
+						notifier.Unsubscribe(rpcSub.ID, errBackfillOverflow)
+						return
+					}
+					pending = append(pending, logs)
+					continue
+				}
+				if backfill != nil {
+					backfill.forward(logs)
+					continue
+				}
 				for _, log := range logs {
-					notifier.Notify(rpcSub.ID, &log)
+					notify(log)
 				}
+			case err := <-done:
+				if err != nil {
+					log.Warn("Failed to replay historical logs", "id", rpcSub.ID, "err", err)
+					notifier.Unsubscribe(rpcSub.ID, err)
+					return
+				}
+				done = nil
+				for _, logs := range pending {
+					backfill.forward(logs)
+				}
+				pending, buffered = nil, 0
 			case <-rpcSub.Err(): // client send an unsubscribe request
 				return
 			}
@@ -289,6 +343,135 @@ func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subsc
 	return rpcSub, nil
 }
 
+const (
+	// logsBackfillChunk is the number of blocks whose logs are replayed at once
+	// when backfilling a logs subscription.
+	logsBackfillChunk = 1024
+
+	// maxBackfillBlocks is the maximum number of blocks below the head whose logs
+	// are replayed by a logs subscription.
+	maxBackfillBlocks = 10000
+
+	// maxBackfillPending is the maximum number of new logs buffered while the
+	// historical logs of a subscription are replayed.
+	maxBackfillPending = 10000
+)
+
+// logsBackfill replays the historical logs of a subscription, and deduplicates
+// the new logs delivered for the replayed blocks.
+type logsBackfill struct {
+	sys    *FilterSystem
+	crit   FilterCriteria
+	notify func(*types.Log)
+
+	next uint64 // Next block to replay
+	end  int64  // Last block to replay, or -1 to follow the head
+	safe uint64 // Blocks up to this number can't be reorged and aren't tracked
+
+	// Hashes of the replayed blocks with logs, above the safe block. It's only
+	// written by run, and only read by forward once run returned.
+	replayed map[common.Hash]struct{}
+}
+
+// newLogsBackfill creates a backfill for the given criteria, or returns nil if
+// the criteria doesn't start in the past.
+func (api *FilterAPI) newLogsBackfill(ctx context.Context, crit FilterCriteria, notify func(*types.Log)) (*logsBackfill, error) {
+	if crit.FromBlock == nil {
+		return nil, nil
+	}
+	var begin uint64
+	switch from := rpc.BlockNumber(crit.FromBlock.Int64()); {
+	case from == rpc.EarliestBlockNumber:
+		begin = api.events.backend.HistoryPruningCutoff()
+	case from >= 0:
+		begin = uint64(from)
+	default:
+		return nil, nil
+	}
+	head := api.sys.backend.CurrentHeader()
+	if head == nil || begin > head.Number.Uint64() {
+		return nil, nil
+	}
+	end, last := int64(-1), head.Number.Uint64()
+	if crit.ToBlock != nil && crit.ToBlock.Sign() >= 0 {
+		end = crit.ToBlock.Int64()
+		last = min(last, uint64(end))
+	}
+	if last >= begin && last-begin >= maxBackfillBlocks {
+		return nil, errExceedMaxBackfill
+	}
+	var safe uint64
+	if final, _ := api.sys.backend.HeaderByNumber(ctx, rpc.FinalizedBlockNumber); final != nil {
+		safe = final.Number.Uint64()
+	}
+	return &logsBackfill{
+		sys:      api.sys,
+		crit:     crit,
+		notify:   notify,
+		next:     begin,
+		end:      end,
+		safe:     safe,
+		replayed: make(map[common.Hash]struct{}),
+	}, nil
+}
+
+// run replays the matching logs up to the end of the range, or the head of the
+// chain if the range is open. It returns once the head is reached.
+func (b *logsBackfill) run(ctx context.Context) error {
+	for {
+		last := b.sys.backend.CurrentHeader().Number.Uint64()
+		if b.end >= 0 && uint64(b.end) < last {
+			last = uint64(b.end)
+		}
+		if b.next > last {
+			return nil
+		}
+		to := min(b.next+logsBackfillChunk-1, last)
+		logs, err := b.sys.NewRangeFilter(int64(b.next), int64(to), b.crit.Addresses, b.crit.Topics).Logs(ctx)
+		if err != nil {
+			return err
+		}
+		for _, log := range logs {
+			if log.BlockNumber > b.safe {
+				b.replayed[log.BlockHash] = struct{}{}
+			}
+			b.notify(log)
+		}
+		b.next = to + 1
+	}
+}
+
+// forward delivers a batch of new logs after the backfill finished. The logs of
+// the replayed blocks are only delivered if they weren't replayed already, and
+// the removed logs only if they were.
+func (b *logsBackfill) forward(logs []*types.Log) {
+	var added, removed []common.Hash
+	for _, log := range logs {
+		if log.BlockNumber >= b.next {
+			b.notify(log) // block not replayed, deliver as is
+			continue
+		}
+		_, seen := b.replayed[log.BlockHash]
+		seen = seen || log.BlockNumber <= b.safe
+		switch {
+		case log.Removed && seen:
+			removed = append(removed, log.BlockHash)
+			b.notify(log)
+		case !log.Removed && !seen:
+			added = append(added, log.BlockHash)
+			b.notify(log)
+		}
+	}
+	// Update the delivered blocks once the whole batch was processed, as the
+	// logs of a block are spread over multiple entries.
+	for _, hash := range removed {
+		delete(b.replayed, hash)
+	}
+	for _, hash := range added {
+		b.replayed[hash] = struct{}{}
+	}
+}
+
 // FilterCriteria represents a request to create a new filter.
 // Same as ethereum.FilterQuery but with UnmarshalJSON() method.
 type FilterCriteria ethereum.FilterQuery
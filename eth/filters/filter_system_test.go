@@ -22,6 +22,7 @@ import (
 	"math/big"
 	"reflect"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -36,6 +37,7 @@ import (
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/triedb"
 )
 
 type testBackend struct {
@@ -583,6 +585,215 @@ func TestLogFilter(t *testing.T) {
 	}
 }
 
+// TestLogsSubscriptionBackfill tests that a logs subscription starting in the
+// past replays the historical logs, then delivers the new ones without
+// duplicating the replayed blocks.
+func TestLogsSubscriptionBackfill(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(db, Config{})
+		api          = NewFilterAPI(sys)
+		addr         = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		genesis      = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+	)
+	_, chain, receipts := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), 10, func(i int, gen *core.BlockGen) {
+		if (i+1)%3 == 0 {
+			receipt := types.NewReceipt(nil, false, 0)
+			receipt.Logs = []*types.Log{{Address: addr, Topics: []common.Hash{{0x01}}}}
+			receipt.Bloom = types.CreateBloom(receipt)
+			gen.AddUncheckedReceipt(receipt)
+			gen.AddUncheckedTx(types.NewTransaction(999, common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+		}
+	})
+	genesis.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	backend.startFilterMaps(0, false, filtermaps.DefaultParams)
+	defer backend.stopFilterMaps()
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	logs := make(chan types.Log)
+	sub, err := client.EthSubscribe(context.Background(), logs, "logs", map[string]any{"fromBlock": "0x1", "address": addr})
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	next := func() types.Log {
+		select {
+		case log := <-logs:
+			return log
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for log")
+		}
+		return types.Log{}
+	}
+	// The historical logs are replayed first.
+	for _, number := range []uint64{3, 6, 9} {
+		if log := next(); log.BlockNumber != number || log.BlockHash != chain[number-1].Hash() || log.Removed {
+			t.Fatalf("unexpected replayed log: %+v", log)
+		}
+	}
+	// New logs of the replayed blocks are skipped, removed ones are delivered.
+	var (
+		topics   = []common.Hash{{0x01}}
+		replayed = &types.Log{Address: addr, Topics: topics, BlockNumber: 9, BlockHash: chain[8].Hash()}
+		removed  = &types.Log{Address: addr, Topics: topics, BlockNumber: 9, BlockHash: chain[8].Hash(), Removed: true}
+		reorged  = &types.Log{Address: addr, Topics: topics, BlockNumber: 9, BlockHash: common.Hash{0x09}}
+		head     = &types.Log{Address: addr, Topics: topics, BlockNumber: 11, BlockHash: common.Hash{0x11}}
+	)
+	expect := func(want *types.Log) {
+		if log := next(); log.BlockHash != want.BlockHash || log.BlockNumber != want.BlockNumber || log.Removed != want.Removed {
+			t.Fatalf("unexpected live log: have %+v, want %+v", log, want)
+		}
+	}
+	backend.logsFeed.Send([]*types.Log{replayed})
+	backend.logsFeed.Send([]*types.Log{head})
+	expect(head)
+
+	backend.rmLogsFeed.Send(core.RemovedLogsEvent{Logs: []*types.Log{removed}})
+	expect(removed)
+
+	backend.logsFeed.Send([]*types.Log{reorged})
+	expect(reorged)
+}
+
+// TestLogsSubscriptionBackfillFailure tests that a logs subscription is ended
+// with an error if its historical logs can't be replayed.
+func TestLogsSubscriptionBackfillFailure(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(db, Config{})
+		api          = NewFilterAPI(sys)
+		addr         = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		genesis      = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+	)
+	_, chain, _ := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), 3, func(i int, gen *core.BlockGen) {
+		receipt := types.NewReceipt(nil, false, 0)
+		receipt.Logs = []*types.Log{{Address: addr, Topics: []common.Hash{{0x01}}}}
+		receipt.Bloom = types.CreateBloom(receipt)
+		gen.AddUncheckedReceipt(receipt)
+		gen.AddUncheckedTx(types.NewTransaction(999, common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+	})
+	// The receipts are missing, so the logs of the blocks can't be retrieved.
+	genesis.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	for _, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+	}
+	backend.startFilterMaps(0, true, filtermaps.DefaultParams)
+	defer backend.stopFilterMaps()
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	logs := make(chan types.Log)
+	sub, err := client.EthSubscribe(context.Background(), logs, "logs", map[string]any{"fromBlock": "0x1", "address": addr})
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	select {
+	case log := <-logs:
+		t.Fatalf("unexpected log: %+v", log)
+	case err := <-sub.Err():
+		if err == nil || !strings.Contains(err.Error(), "failed to get logs") {
+			t.Fatalf("unexpected subscription error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not ended")
+	}
+}
+
+// TestLogsSubscriptionBackfillOverflow tests that a logs subscription is ended
+// with an error if too many new logs arrive while its historical logs are still
+// being replayed.
+func TestLogsSubscriptionBackfillOverflow(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(db, Config{})
+		api          = NewFilterAPI(sys)
+		addr         = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		genesis      = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+	)
+	_, chain, _ := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), 3, nil)
+	genesis.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	for _, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+	}
+	// The log index is never started, blocking the replay of the historical logs.
+	backend.fm = filtermaps.NewFilterMaps(db, backend.CurrentView(), 0, 0, filtermaps.DefaultParams, filtermaps.Config{})
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	logs := make(chan types.Log)
+	sub, err := client.EthSubscribe(context.Background(), logs, "logs", map[string]any{"fromBlock": "0x1", "address": addr})
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	pending := make([]*types.Log, maxBackfillPending+1)
+	for i := range pending {
+		pending[i] = &types.Log{Address: addr, BlockNumber: 4, BlockHash: common.Hash{0x04}, Index: uint(i)}
+	}
+	backend.logsFeed.Send(pending)
+
+	select {
+	case log := <-logs:
+		t.Fatalf("unexpected log: %+v", log)
+	case err := <-sub.Err():
+		if err == nil || err.Error() != errBackfillOverflow.Error() {
+			t.Fatalf("unexpected subscription error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not ended")
+	}
+}
+
 // TestPendingTxFilterDeadlock tests if the event loop hangs when pending
 // txes arrive at the same time that one of multiple filters is timing out.
 // Please refer to #22131 for more details.
@@ -26,7 +26,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/core/forkid"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -52,6 +51,10 @@ const (
 	// All transactions with a higher size will be announced and need to be fetched
 	// by the peer.
 	txMaxBroadcastSize = 4096
+
+	// blockRangeUpdateInterval is the number of blocks the local chain head needs
+	// to advance before the available block range is re-announced to peers.
+	blockRangeUpdateInterval = 32
 )
 
 var syncChallengeTimeout = 15 * time.Second // Time allowance for a node to reply to the sync progress challenge
@@ -103,9 +106,8 @@ type handlerConfig struct {
 }
 
 type handler struct {
-	nodeID     enode.ID
-	networkID  uint64
-	forkFilter forkid.Filter // Fork ID filter, constant across the lifetime of the node
+	nodeID    enode.ID
+	networkID uint64
 
 	snapSync atomic.Bool // Flag whether snap sync is enabled (gets disabled if we already have blocks)
 	synced   atomic.Bool // Flag whether we're considered synchronised (enables transaction processing)
@@ -143,7 +145,6 @@ func newHandler(config *handlerConfig) (*handler, error) {
 	h := &handler{
 		nodeID:         config.NodeID,
 		networkID:      config.Network,
-		forkFilter:     forkid.NewFilter(config.Chain),
 		eventMux:       config.EventMux,
 		database:       config.Database,
 		txpool:         config.TxPool,
@@ -256,14 +257,7 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 	}
 
 	// Execute the Ethereum handshake
-	var (
-		genesis = h.chain.Genesis()
-		head    = h.chain.CurrentHeader()
-		hash    = head.Hash()
-		number  = head.Number.Uint64()
-	)
-	forkID := forkid.NewID(h.chain.Config(), genesis, number, head.Time)
-	if err := peer.Handshake(h.networkID, hash, genesis.Hash(), forkID, h.forkFilter); err != nil {
+	if err := peer.Handshake(h.networkID, h.chain, h.currentBlockRange()); err != nil {
 		peer.Log().Debug("Ethereum handshake failed", "err", err)
 		return err
 	}
@@ -437,6 +431,10 @@ func (h *handler) Start(maxPeers int) {
 	// start sync handlers
 	h.txFetcher.Start()
 
+	// announce changes of the locally available block range
+	h.wg.Add(1)
+	go h.blockRangeLoop()
+
 	// start peer handler tracker
 	h.wg.Add(1)
 	go h.protoTracker()
@@ -489,6 +487,11 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		hash   = make([]byte, 32)
 	)
 	for _, tx := range txs {
+		// Conditional transactions are kept local, peers would lose the
+		// inclusion conditions and include them unconditionally.
+		if tx.Conditional() != nil {
+			continue
+		}
 		var maybeDirect bool
 		switch {
 		case tx.Type() == types.BlobTxType:
@@ -552,6 +555,54 @@ func (h *handler) txBroadcastLoop() {
 	}
 }
 
+// currentBlockRange returns the range of blocks the local node is able to serve.
+// Blocks below the history pruning cutoff are not available.
+func (h *handler) currentBlockRange() eth.BlockRangeUpdatePacket {
+	var (
+		head        = h.chain.CurrentBlock()
+		earliest, _ = h.chain.HistoryPruningCutoff()
+	)
+	return eth.BlockRangeUpdatePacket{
+		EarliestBlock:   min(earliest, head.Number.Uint64()),
+		LatestBlock:     head.Number.Uint64(),
+		LatestBlockHash: head.Hash(),
+	}
+}
+
+// blockRangeLoop announces changes of the locally available block range to
+// all connected peers. To avoid spamming the network, an update is only sent
+// if the chain head advanced by blockRangeUpdateInterval blocks, the head was
+// rewound or the history pruning cutoff changed.
+func (h *handler) blockRangeLoop() {
+	defer h.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, 16)
+	sub := h.chain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	last := h.currentBlockRange()
+	for {
+		select {
+		case <-headCh:
+			current := h.currentBlockRange()
+			if current.EarliestBlock == last.EarliestBlock && current.LatestBlock >= last.LatestBlock &&
+				current.LatestBlock-last.LatestBlock < blockRangeUpdateInterval {
+				continue
+			}
+			last = current
+			for _, peer := range h.peers.all() {
+				if err := peer.SendBlockRangeUpdate(current); err != nil {
+					peer.Log().Debug("Failed to send block range update", "err", err)
+				}
+			}
+		case <-sub.Err():
+			return
+		case <-h.quitSync:
+			return
+		}
+	}
+}
+
 // enableSyncedFeatures enables the post-sync functionalities when the initial
 // sync is finished.
 func (h *handler) enableSyncedFeatures() {
@@ -173,6 +173,7 @@ func (b *testBackend) Handle(*Peer, Packet) error {
 
 // Tests that block headers can be retrieved from a remote chain based on user queries.
 func TestGetBlockHeaders68(t *testing.T) { testGetBlockHeaders(t, ETH68) }
+func TestGetBlockHeaders69(t *testing.T) { testGetBlockHeaders(t, ETH69) }
 
 func testGetBlockHeaders(t *testing.T, protocol uint) {
 	t.Parallel()
@@ -386,6 +387,7 @@ func testGetBlockHeaders(t *testing.T, protocol uint) {
 
 // Tests that block contents can be retrieved from a remote chain based on their hashes.
 func TestGetBlockBodies68(t *testing.T) { testGetBlockBodies(t, ETH68) }
+func TestGetBlockBodies69(t *testing.T) { testGetBlockBodies(t, ETH69) }
 
 func testGetBlockBodies(t *testing.T, protocol uint) {
 	t.Parallel()
@@ -480,6 +482,7 @@ func testGetBlockBodies(t *testing.T, protocol uint) {
 
 // Tests that the transaction receipts can be retrieved based on hashes.
 func TestGetBlockReceipts68(t *testing.T) { testGetBlockReceipts(t, ETH68) }
+func TestGetBlockReceipts69(t *testing.T) { testGetBlockReceipts(t, ETH69) }
 
 func testGetBlockReceipts(t *testing.T, protocol uint) {
 	t.Parallel()
@@ -542,10 +545,24 @@ func testGetBlockReceipts(t *testing.T, protocol uint) {
 		RequestId:          123,
 		GetReceiptsRequest: hashes,
 	})
-	if err := p2p.ExpectMsg(peer.app, ReceiptsMsg, &ReceiptsPacket{
+	var want interface{} = &ReceiptsPacket{
 		RequestId:        123,
 		ReceiptsResponse: receipts,
-	}); err != nil {
+	}
+	if protocol >= ETH69 {
+		list := make(Receipts69Response, len(receipts))
+		for i := range receipts {
+			list[i] = make([]*Receipt69, len(receipts[i]))
+			for j, receipt := range receipts[i] {
+				list[i][j] = newReceipt69(receipt)
+			}
+		}
+		want = &Receipts69Packet{
+			RequestId:          123,
+			Receipts69Response: list,
+		}
+	}
+	if err := p2p.ExpectMsg(peer.app, ReceiptsMsg, want); err != nil {
 		t.Errorf("receipts mismatch: %v", err)
 	}
 }
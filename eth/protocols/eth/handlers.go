@@ -243,19 +243,50 @@ func ServiceGetBlockBodiesQuery(chain *core.BlockChain, query GetBlockBodiesRequ
 	return bodies
 }
 
-func handleGetReceipts(backend Backend, msg Decoder, peer *Peer) error {
+func handleGetReceipts68(backend Backend, msg Decoder, peer *Peer) error {
 	// Decode the block receipts retrieval message
 	var query GetReceiptsPacket
 	if err := msg.Decode(&query); err != nil {
 		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
 	}
-	response := ServiceGetReceiptsQuery(backend.Chain(), query.GetReceiptsRequest)
+	response := ServiceGetReceiptsQuery68(backend.Chain(), query.GetReceiptsRequest)
 	return peer.ReplyReceiptsRLP(query.RequestId, response)
 }
 
-// ServiceGetReceiptsQuery assembles the response to a receipt query. It is
-// exposed to allow external packages to test protocol behavior.
-func ServiceGetReceiptsQuery(chain *core.BlockChain, query GetReceiptsRequest) []rlp.RawValue {
+func handleGetReceipts69(backend Backend, msg Decoder, peer *Peer) error {
+	// Decode the block receipts retrieval message
+	var query GetReceiptsPacket
+	if err := msg.Decode(&query); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	response := ServiceGetReceiptsQuery69(backend.Chain(), query.GetReceiptsRequest)
+	return peer.ReplyReceiptsRLP(query.RequestId, response)
+}
+
+// ServiceGetReceiptsQuery68 assembles the response to a receipt query on eth/68.
+// It is exposed to allow external packages to test protocol behavior.
+func ServiceGetReceiptsQuery68(chain *core.BlockChain, query GetReceiptsRequest) []rlp.RawValue {
+	return serviceGetReceiptsQuery(chain, query, func(receipts types.Receipts) interface{} {
+		return receipts
+	})
+}
+
+// ServiceGetReceiptsQuery69 assembles the response to a receipt query on eth/69,
+// where receipts are sent without their bloom filters. It is exposed to allow
+// external packages to test protocol behavior.
+func ServiceGetReceiptsQuery69(chain *core.BlockChain, query GetReceiptsRequest) []rlp.RawValue {
+	return serviceGetReceiptsQuery(chain, query, func(receipts types.Receipts) interface{} {
+		list := make([]*Receipt69, len(receipts))
+		for i, receipt := range receipts {
+			list[i] = newReceipt69(receipt)
+		}
+		return list
+	})
+}
+
+// serviceGetReceiptsQuery gathers the receipts of the requested blocks, using
+// the given conversion function to produce the network encoding.
+func serviceGetReceiptsQuery(chain *core.BlockChain, query GetReceiptsRequest, convert func(types.Receipts) interface{}) []rlp.RawValue {
 	// Gather state data until the fetch or network limits is reached
 	var (
 		bytes    int
@@ -274,7 +305,7 @@ func ServiceGetReceiptsQuery(chain *core.BlockChain, query GetReceiptsRequest) [
 			}
 		}
 		// If known, encode and queue for response packet
-		if encoded, err := rlp.EncodeToBytes(results); err != nil {
+		if encoded, err := rlp.EncodeToBytes(convert(results)); err != nil {
 			log.Error("Failed to encode receipt", "err", err)
 		} else {
 			receipts = append(receipts, encoded)
@@ -341,27 +372,69 @@ func handleBlockBodies(backend Backend, msg Decoder, peer *Peer) error {
 	}, metadata)
 }
 
-func handleReceipts(backend Backend, msg Decoder, peer *Peer) error {
+func handleReceipts68(backend Backend, msg Decoder, peer *Peer) error {
 	// A batch of receipts arrived to one of our previous requests
 	res := new(ReceiptsPacket)
 	if err := msg.Decode(res); err != nil {
 		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
 	}
+	return dispatchReceipts(peer, res.RequestId, res.ReceiptsResponse)
+}
+
+func handleReceipts69(backend Backend, msg Decoder, peer *Peer) error {
+	// A batch of bloom-less receipts arrived to one of our previous requests
+	res := new(Receipts69Packet)
+	if err := msg.Decode(res); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	// Reconstruct the consensus receipts so the rest of the node does not
+	// need to care about the wire format
+	receipts := make(ReceiptsResponse, len(res.Receipts69Response))
+	for i, list := range res.Receipts69Response {
+		receipts[i] = make([]*types.Receipt, len(list))
+		for j, r := range list {
+			if r == nil {
+				return fmt.Errorf("%w: receipt %d/%d is nil", errDecode, i, j)
+			}
+			receipt, err := r.Receipt()
+			if err != nil {
+				return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+			}
+			receipts[i][j] = receipt
+		}
+	}
+	return dispatchReceipts(peer, res.RequestId, receipts)
+}
+
+// dispatchReceipts delivers a batch of receipts to the request they answer.
+func dispatchReceipts(peer *Peer, id uint64, receipts ReceiptsResponse) error {
 	metadata := func() interface{} {
 		hasher := trie.NewStackTrie(nil)
-		hashes := make([]common.Hash, len(res.ReceiptsResponse))
-		for i, receipt := range res.ReceiptsResponse {
+		hashes := make([]common.Hash, len(receipts))
+		for i, receipt := range receipts {
 			hashes[i] = types.DeriveSha(types.Receipts(receipt), hasher)
 		}
 		return hashes
 	}
 	return peer.dispatchResponse(&Response{
-		id:   res.RequestId,
+		id:   id,
 		code: ReceiptsMsg,
-		Res:  &res.ReceiptsResponse,
+		Res:  &receipts,
 	}, metadata)
 }
 
+func handleBlockRangeUpdate(backend Backend, msg Decoder, peer *Peer) error {
+	var update BlockRangeUpdatePacket
+	if err := msg.Decode(&update); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	if err := update.Validate(); err != nil {
+		return err
+	}
+	peer.lastRange.Store(&update)
+	return nil
+}
+
 func handleNewPooledTransactionHashes(backend Backend, msg Decoder, peer *Peer) error {
 	// New transaction announcement arrived, make sure we have
 	// a valid and fresh chain to handle them
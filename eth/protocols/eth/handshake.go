@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/forkid"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/p2p"
@@ -35,26 +36,73 @@ const (
 )
 
 // Handshake executes the eth protocol handshake, negotiating version number,
-// network IDs, difficulties, head and genesis blocks.
-func (p *Peer) Handshake(network uint64, head common.Hash, genesis common.Hash, forkID forkid.ID, forkFilter forkid.Filter) error {
-	// Send out own handshake in a new thread
-	errc := make(chan error, 2)
-
-	var status StatusPacket // safe to read after two values have been received from errc
+// network IDs, head and genesis blocks. On eth/69 and newer, the locally
+// available block range is exchanged too.
+func (p *Peer) Handshake(network uint64, chain *core.BlockChain, rangeMsg BlockRangeUpdatePacket) error {
+	switch p.version {
+	case ETH69:
+		return p.handshake69(network, chain, rangeMsg)
+	case ETH68:
+		return p.handshake68(network, chain)
+	default:
+		return errors.New("unsupported protocol version")
+	}
+}
 
+func (p *Peer) handshake68(network uint64, chain *core.BlockChain) error {
+	var (
+		genesis    = chain.Genesis()
+		latest     = chain.CurrentBlock()
+		forkID     = forkid.NewID(chain.Config(), genesis, latest.Number.Uint64(), latest.Time)
+		forkFilter = forkid.NewFilter(chain)
+	)
+	var status StatusPacket68 // safe to read after two values have been received from errc
+	errc := make(chan error, 2)
 	go func() {
-		errc <- p2p.Send(p.rw, StatusMsg, &StatusPacket{
+		errc <- p2p.Send(p.rw, StatusMsg, &StatusPacket68{
 			ProtocolVersion: uint32(p.version),
 			NetworkID:       network,
 			TD:              new(big.Int), // unknown for post-merge tail=pruned networks
-			Head:            head,
-			Genesis:         genesis,
+			Head:            latest.Hash(),
+			Genesis:         genesis.Hash(),
+			ForkID:          forkID,
+		})
+	}()
+	go func() {
+		errc <- p.readStatus68(network, &status, genesis.Hash(), forkFilter)
+	}()
+	return waitForHandshake(p, errc)
+}
+
+func (p *Peer) handshake69(network uint64, chain *core.BlockChain, rangeMsg BlockRangeUpdatePacket) error {
+	var (
+		genesis    = chain.Genesis()
+		latest     = chain.CurrentBlock()
+		forkID     = forkid.NewID(chain.Config(), genesis, latest.Number.Uint64(), latest.Time)
+		forkFilter = forkid.NewFilter(chain)
+	)
+	var status StatusPacket69 // safe to read after two values have been received from errc
+	errc := make(chan error, 2)
+	go func() {
+		errc <- p2p.Send(p.rw, StatusMsg, &StatusPacket69{
+			ProtocolVersion: uint32(p.version),
+			NetworkID:       network,
+			Genesis:         genesis.Hash(),
 			ForkID:          forkID,
+			EarliestBlock:   rangeMsg.EarliestBlock,
+			LatestBlock:     rangeMsg.LatestBlock,
+			LatestBlockHash: rangeMsg.LatestBlockHash,
 		})
 	}()
 	go func() {
-		errc <- p.readStatus(network, &status, genesis, forkFilter)
+		errc <- p.readStatus69(network, &status, genesis.Hash(), forkFilter)
 	}()
+	return waitForHandshake(p, errc)
+}
+
+// waitForHandshake waits for both the status send and the status read to
+// complete, or for the handshake timeout to elapse.
+func waitForHandshake(p *Peer, errc <-chan error) error {
 	timeout := time.NewTimer(handshakeTimeout)
 	defer timeout.Stop()
 	for i := 0; i < 2; i++ {
@@ -72,21 +120,31 @@ func (p *Peer) Handshake(network uint64, head common.Hash, genesis common.Hash,
 	return nil
 }
 
-// readStatus reads the remote handshake message.
-func (p *Peer) readStatus(network uint64, status *StatusPacket, genesis common.Hash, forkFilter forkid.Filter) error {
-	msg, err := p.rw.ReadMsg()
-	if err != nil {
+// readStatus68 reads the remote eth/68 handshake message.
+func (p *Peer) readStatus68(network uint64, status *StatusPacket68, genesis common.Hash, forkFilter forkid.Filter) error {
+	if err := p.readStatusMsg(status); err != nil {
 		return err
 	}
-	if msg.Code != StatusMsg {
-		return fmt.Errorf("%w: first msg has code %x (!= %x)", errNoStatusMsg, msg.Code, StatusMsg)
+	if status.NetworkID != network {
+		return fmt.Errorf("%w: %d (!= %d)", errNetworkIDMismatch, status.NetworkID, network)
 	}
-	if msg.Size > maxMessageSize {
-		return fmt.Errorf("%w: %v > %v", errMsgTooLarge, msg.Size, maxMessageSize)
+	if uint(status.ProtocolVersion) != p.version {
+		return fmt.Errorf("%w: %d (!= %d)", errProtocolVersionMismatch, status.ProtocolVersion, p.version)
 	}
-	// Decode the handshake and make sure everything matches
-	if err := msg.Decode(&status); err != nil {
-		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	if status.Genesis != genesis {
+		return fmt.Errorf("%w: %x (!= %x)", errGenesisMismatch, status.Genesis, genesis)
+	}
+	if err := forkFilter(status.ForkID); err != nil {
+		return fmt.Errorf("%w: %v", errForkIDRejected, err)
+	}
+	return nil
+}
+
+// readStatus69 reads the remote eth/69 handshake message and records the
+// block range announced by the remote peer.
+func (p *Peer) readStatus69(network uint64, status *StatusPacket69, genesis common.Hash, forkFilter forkid.Filter) error {
+	if err := p.readStatusMsg(status); err != nil {
+		return err
 	}
 	if status.NetworkID != network {
 		return fmt.Errorf("%w: %d (!= %d)", errNetworkIDMismatch, status.NetworkID, network)
@@ -100,6 +158,36 @@ func (p *Peer) readStatus(network uint64, status *StatusPacket, genesis common.H
 	if err := forkFilter(status.ForkID); err != nil {
 		return fmt.Errorf("%w: %v", errForkIDRejected, err)
 	}
+	initRange := &BlockRangeUpdatePacket{
+		EarliestBlock:   status.EarliestBlock,
+		LatestBlock:     status.LatestBlock,
+		LatestBlockHash: status.LatestBlockHash,
+	}
+	if err := initRange.Validate(); err != nil {
+		return err
+	}
+	p.lastRange.Store(initRange)
+	return nil
+}
+
+// readStatusMsg reads the first message from the remote peer, ensures it is a
+// status message and decodes it into the given packet.
+func (p *Peer) readStatusMsg(dst any) error {
+	msg, err := p.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	defer msg.Discard()
+
+	if msg.Code != StatusMsg {
+		return fmt.Errorf("%w: first msg has code %x (!= %x)", errNoStatusMsg, msg.Code, StatusMsg)
+	}
+	if msg.Size > maxMessageSize {
+		return fmt.Errorf("%w: %v > %v", errMsgTooLarge, msg.Size, maxMessageSize)
+	}
+	if err := msg.Decode(dst); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
 	return nil
 }
 
@@ -118,6 +206,8 @@ func markError(p *Peer, err error) {
 		m.genesisMismatch.Mark(1)
 	case errForkIDRejected:
 		m.forkidRejected.Mark(1)
+	case errInvalidBlockRange:
+		m.blockRangeInvalid.Mark(1)
 	case p2p.DiscReadTimeout:
 		m.timeoutError.Mark(1)
 	default:
@@ -28,9 +28,9 @@ import (
 )
 
 // Tests that handshake failures are detected and reported correctly.
-func TestHandshake68(t *testing.T) { testHandshake(t, ETH68) }
+func TestHandshake68(t *testing.T) { testHandshake68(t, ETH68) }
 
-func testHandshake(t *testing.T, protocol uint) {
+func testHandshake68(t *testing.T, protocol uint) {
 	t.Parallel()
 
 	// Create a test backend only to have some valid genesis chain
@@ -52,19 +52,19 @@ func testHandshake(t *testing.T, protocol uint) {
 			want: errNoStatusMsg,
 		},
 		{
-			code: StatusMsg, data: StatusPacket{10, 1, new(big.Int), head.Hash(), genesis.Hash(), forkID},
+			code: StatusMsg, data: StatusPacket68{10, 1, new(big.Int), head.Hash(), genesis.Hash(), forkID},
 			want: errProtocolVersionMismatch,
 		},
 		{
-			code: StatusMsg, data: StatusPacket{uint32(protocol), 999, new(big.Int), head.Hash(), genesis.Hash(), forkID},
+			code: StatusMsg, data: StatusPacket68{uint32(protocol), 999, new(big.Int), head.Hash(), genesis.Hash(), forkID},
 			want: errNetworkIDMismatch,
 		},
 		{
-			code: StatusMsg, data: StatusPacket{uint32(protocol), 1, new(big.Int), head.Hash(), common.Hash{3}, forkID},
+			code: StatusMsg, data: StatusPacket68{uint32(protocol), 1, new(big.Int), head.Hash(), common.Hash{3}, forkID},
 			want: errGenesisMismatch,
 		},
 		{
-			code: StatusMsg, data: StatusPacket{uint32(protocol), 1, new(big.Int), head.Hash(), genesis.Hash(), forkid.ID{Hash: [4]byte{0x00, 0x01, 0x02, 0x03}}},
+			code: StatusMsg, data: StatusPacket68{uint32(protocol), 1, new(big.Int), head.Hash(), genesis.Hash(), forkid.ID{Hash: [4]byte{0x00, 0x01, 0x02, 0x03}}},
 			want: errForkIDRejected,
 		},
 	}
@@ -80,7 +80,7 @@ func testHandshake(t *testing.T, protocol uint) {
 		// Send the junk test with one peer, check the handshake failure
 		go p2p.Send(app, test.code, test.data)
 
-		err := peer.Handshake(1, head.Hash(), genesis.Hash(), forkID, forkid.NewFilter(backend.chain))
+		err := peer.Handshake(1, backend.chain, BlockRangeUpdatePacket{})
 		if err == nil {
 			t.Errorf("test %d: protocol returned nil error, want %q", i, test.want)
 		} else if !errors.Is(err, test.want) {
@@ -88,3 +88,135 @@ func testHandshake(t *testing.T, protocol uint) {
 		}
 	}
 }
+
+func TestHandshake69(t *testing.T) { testHandshake69(t, ETH69) }
+
+func testHandshake69(t *testing.T, protocol uint) {
+	t.Parallel()
+
+	// Create a test backend only to have some valid genesis chain
+	backend := newTestBackend(3)
+	defer backend.close()
+
+	var (
+		genesis = backend.chain.Genesis()
+		head    = backend.chain.CurrentBlock()
+		forkID  = forkid.NewID(backend.chain.Config(), backend.chain.Genesis(), backend.chain.CurrentHeader().Number.Uint64(), backend.chain.CurrentHeader().Time)
+		number  = head.Number.Uint64()
+	)
+	tests := []struct {
+		code uint64
+		data interface{}
+		want error
+	}{
+		{
+			code: TransactionsMsg, data: []interface{}{},
+			want: errNoStatusMsg,
+		},
+		{
+			code: StatusMsg, data: StatusPacket69{10, 1, genesis.Hash(), forkID, 0, number, head.Hash()},
+			want: errProtocolVersionMismatch,
+		},
+		{
+			code: StatusMsg, data: StatusPacket69{uint32(protocol), 999, genesis.Hash(), forkID, 0, number, head.Hash()},
+			want: errNetworkIDMismatch,
+		},
+		{
+			code: StatusMsg, data: StatusPacket69{uint32(protocol), 1, common.Hash{3}, forkID, 0, number, head.Hash()},
+			want: errGenesisMismatch,
+		},
+		{
+			code: StatusMsg, data: StatusPacket69{uint32(protocol), 1, genesis.Hash(), forkid.ID{Hash: [4]byte{0x00, 0x01, 0x02, 0x03}}, 0, number, head.Hash()},
+			want: errForkIDRejected,
+		},
+		{
+			code: StatusMsg, data: StatusPacket69{uint32(protocol), 1, genesis.Hash(), forkID, number + 1, number, head.Hash()},
+			want: errInvalidBlockRange,
+		},
+		{
+			code: StatusMsg, data: StatusPacket69{uint32(protocol), 1, genesis.Hash(), forkID, 0, number, common.Hash{}},
+			want: errInvalidBlockRange,
+		},
+	}
+	for i, test := range tests {
+		// Create the two peers to shake with each other
+		app, net := p2p.MsgPipe()
+		defer app.Close()
+		defer net.Close()
+
+		peer := NewPeer(protocol, p2p.NewPeer(enode.ID{}, "peer", nil), net, nil)
+		defer peer.Close()
+
+		// Send the junk test with one peer, check the handshake failure
+		go p2p.Send(app, test.code, test.data)
+
+		err := peer.Handshake(1, backend.chain, BlockRangeUpdatePacket{LatestBlock: number, LatestBlockHash: head.Hash()})
+		if err == nil {
+			t.Errorf("test %d: protocol returned nil error, want %q", i, test.want)
+		} else if !errors.Is(err, test.want) {
+			t.Errorf("test %d: wrong error: got %q, want %q", i, err, test.want)
+		}
+	}
+}
+
+// Tests that the block range announced in the eth/69 handshake and in later
+// updates is tracked on the peer.
+func TestBlockRangeUpdate(t *testing.T) {
+	t.Parallel()
+
+	backend := newTestBackend(3)
+	defer backend.close()
+
+	var (
+		head   = backend.chain.CurrentBlock()
+		number = head.Number.Uint64()
+	)
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	local := NewPeer(ETH69, p2p.NewPeer(enode.ID{1}, "local", nil), net, nil)
+	remote := NewPeer(ETH69, p2p.NewPeer(enode.ID{2}, "remote", nil), app, nil)
+	defer local.Close()
+	defer remote.Close()
+
+	errc := make(chan error, 2)
+	go func() {
+		errc <- remote.Handshake(1, backend.chain, BlockRangeUpdatePacket{EarliestBlock: 1, LatestBlock: number, LatestBlockHash: head.Hash()})
+	}()
+	go func() {
+		errc <- local.Handshake(1, backend.chain, BlockRangeUpdatePacket{LatestBlock: number, LatestBlockHash: head.Hash()})
+	}()
+	for i := 0; i < 2; i++ {
+		if err := <-errc; err != nil {
+			t.Fatalf("handshake failed: %v", err)
+		}
+	}
+	if have := local.BlockRange(); have == nil || have.EarliestBlock != 1 || have.LatestBlock != number {
+		t.Fatalf("wrong initial block range: %+v", have)
+	}
+	// Deliver an update and check that the peer's range is changed
+	update := BlockRangeUpdatePacket{EarliestBlock: 2, LatestBlock: number + 10, LatestBlockHash: common.Hash{0x01}}
+	go remote.SendBlockRangeUpdate(update)
+
+	msg, err := net.ReadMsg()
+	if err != nil {
+		t.Fatalf("failed to read update: %v", err)
+	}
+	if err := handleBlockRangeUpdate(backend, msg, local); err != nil {
+		t.Fatalf("failed to handle update: %v", err)
+	}
+	if have := local.BlockRange(); *have != update {
+		t.Fatalf("wrong block range after update: have %+v, want %+v", have, update)
+	}
+	// Invalid updates must be rejected
+	invalid := BlockRangeUpdatePacket{EarliestBlock: 5, LatestBlock: 4, LatestBlockHash: common.Hash{0x01}}
+	go remote.SendBlockRangeUpdate(invalid)
+
+	if msg, err = net.ReadMsg(); err != nil {
+		t.Fatalf("failed to read update: %v", err)
+	}
+	if err := handleBlockRangeUpdate(backend, msg, local); !errors.Is(err, errInvalidBlockRange) {
+		t.Fatalf("wrong error for invalid update: %v", err)
+	}
+}
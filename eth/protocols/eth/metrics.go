@@ -58,6 +58,10 @@ type hsMeters struct {
 
 	// forkidRejected measures the number of differing forkids.
 	forkidRejected *metrics.Meter
+
+	// blockRangeInvalid measures the number of invalid block ranges announced
+	// in the eth/69 status message.
+	blockRangeInvalid *metrics.Meter
 }
 
 // newHandshakeMeters registers and returns handshake meters for the given
@@ -70,6 +74,7 @@ func newHandshakeMeters(base string) *hsMeters {
 		protocolVersionMismatch: metrics.NewRegisteredMeter(base+"error/version", nil),
 		genesisMismatch:         metrics.NewRegisteredMeter(base+"error/genesis", nil),
 		forkidRejected:          metrics.NewRegisteredMeter(base+"error/forkid", nil),
+		blockRangeInvalid:       metrics.NewRegisteredMeter(base+"error/blockrange", nil),
 	}
 }
 
@@ -31,6 +31,7 @@ import (
 // Constants to match up protocol versions and messages
 const (
 	ETH68 = 68
+	ETH69 = 69
 )
 
 // ProtocolName is the official short name of the `eth` protocol used during
@@ -39,11 +40,11 @@ const ProtocolName = "eth"
 
 // ProtocolVersions are the supported versions of the `eth` protocol (first
 // is primary).
-var ProtocolVersions = []uint{ETH68}
+var ProtocolVersions = []uint{ETH69, ETH68}
 
 // protocolLengths are the number of implemented message corresponding to
 // different protocol versions.
-var protocolLengths = map[uint]uint64{ETH68: 17}
+var protocolLengths = map[uint]uint64{ETH68: 17, ETH69: 18}
 
 // maxMessageSize is the maximum cap on the size of a protocol message.
 const maxMessageSize = 10 * 1024 * 1024
@@ -62,6 +63,7 @@ const (
 	PooledTransactionsMsg         = 0x0a
 	GetReceiptsMsg                = 0x0f
 	ReceiptsMsg                   = 0x10
+	BlockRangeUpdateMsg           = 0x11
 )
 
 var (
@@ -73,6 +75,7 @@ var (
 	errNetworkIDMismatch       = errors.New("network ID mismatch")
 	errGenesisMismatch         = errors.New("genesis mismatch")
 	errForkIDRejected          = errors.New("fork ID rejected")
+	errInvalidBlockRange       = errors.New("invalid block range")
 )
 
 // Packet represents a p2p message in the `eth` protocol.
@@ -81,8 +84,8 @@ type Packet interface {
 	Kind() byte   // Kind returns the message type.
 }
 
-// StatusPacket is the network packet for the status message.
-type StatusPacket struct {
+// StatusPacket68 is the network packet for the status message on eth/68.
+type StatusPacket68 struct {
 	ProtocolVersion uint32
 	NetworkID       uint64
 	TD              *big.Int
@@ -91,6 +94,38 @@ type StatusPacket struct {
 	ForkID          forkid.ID
 }
 
+// StatusPacket69 is the network packet for the status message on eth/69 and
+// newer. Compared to eth/68, the total difficulty and head hash are replaced
+// by the range of blocks the node is able to serve.
+type StatusPacket69 struct {
+	ProtocolVersion uint32
+	NetworkID       uint64
+	Genesis         common.Hash
+	ForkID          forkid.ID
+	EarliestBlock   uint64      // Number of the oldest block with available history
+	LatestBlock     uint64      // Number of the most recent available block
+	LatestBlockHash common.Hash // Hash of the most recent available block
+}
+
+// BlockRangeUpdatePacket is an announcement of the node's available block
+// range, sent periodically on eth/69 and newer.
+type BlockRangeUpdatePacket struct {
+	EarliestBlock   uint64      // Number of the oldest block with available history
+	LatestBlock     uint64      // Number of the most recent available block
+	LatestBlockHash common.Hash // Hash of the most recent available block
+}
+
+// Validate checks the block range for internal consistency.
+func (p *BlockRangeUpdatePacket) Validate() error {
+	if p.EarliestBlock > p.LatestBlock {
+		return fmt.Errorf("%w: earliest %d > latest %d", errInvalidBlockRange, p.EarliestBlock, p.LatestBlock)
+	}
+	if p.LatestBlockHash == (common.Hash{}) {
+		return fmt.Errorf("%w: zero latest block hash", errInvalidBlockRange)
+	}
+	return nil
+}
+
 // NewBlockHashesPacket is the network packet for the block announcements.
 type NewBlockHashesPacket []struct {
 	Hash   common.Hash // Hash of one particular block being announced
@@ -262,6 +297,17 @@ type ReceiptsPacket struct {
 // ReceiptsRLPResponse is used for receipts, when we already have it encoded
 type ReceiptsRLPResponse []rlp.RawValue
 
+// Receipts69Response is the network packet for block receipts distribution on
+// eth/69 and newer, where the receipts are transmitted without bloom filters.
+type Receipts69Response [][]*Receipt69
+
+// Receipts69Packet is the network packet for block receipts distribution on
+// eth/69 and newer, with request ID wrapping.
+type Receipts69Packet struct {
+	RequestId uint64
+	Receipts69Response
+}
+
 // ReceiptsRLPPacket is ReceiptsRLPResponse with request ID wrapping.
 type ReceiptsRLPPacket struct {
 	RequestId uint64
@@ -304,8 +350,11 @@ type PooledTransactionsRLPPacket struct {
 	PooledTransactionsRLPResponse
 }
 
-func (*StatusPacket) Name() string { return "Status" }
-func (*StatusPacket) Kind() byte   { return StatusMsg }
+func (*StatusPacket68) Name() string { return "Status" }
+func (*StatusPacket68) Kind() byte   { return StatusMsg }
+
+func (*StatusPacket69) Name() string { return "Status" }
+func (*StatusPacket69) Kind() byte   { return StatusMsg }
 
 func (*NewBlockHashesPacket) Name() string { return "NewBlockHashes" }
 func (*NewBlockHashesPacket) Kind() byte   { return NewBlockHashesMsg }
@@ -342,3 +391,9 @@ func (*GetReceiptsRequest) Kind() byte   { return GetReceiptsMsg }
 
 func (*ReceiptsResponse) Name() string { return "Receipts" }
 func (*ReceiptsResponse) Kind() byte   { return ReceiptsMsg }
+
+func (*Receipts69Response) Name() string { return "Receipts" }
+func (*Receipts69Response) Kind() byte   { return ReceiptsMsg }
+
+func (*BlockRangeUpdatePacket) Name() string { return "BlockRangeUpdate" }
+func (*BlockRangeUpdatePacket) Kind() byte   { return BlockRangeUpdateMsg }
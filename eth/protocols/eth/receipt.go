@@ -0,0 +1,88 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	receiptStatusFailed     = []byte{}
+	receiptStatusSuccessful = []byte{0x01}
+)
+
+// Receipt69 is the eth/69 network encoding of a transaction receipt. Contrary
+// to the consensus encoding, the bloom filter is omitted (it can be recomputed
+// from the logs) and the transaction type is carried as a plain list element
+// instead of a typed envelope.
+type Receipt69 struct {
+	TxType            uint8
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Logs              []*types.Log
+}
+
+// newReceipt69 converts a consensus receipt into its eth/69 network form.
+func newReceipt69(r *types.Receipt) *Receipt69 {
+	status := r.PostState
+	if len(status) == 0 {
+		if r.Status == types.ReceiptStatusFailed {
+			status = receiptStatusFailed
+		} else {
+			status = receiptStatusSuccessful
+		}
+	}
+	logs := r.Logs
+	if logs == nil {
+		logs = []*types.Log{}
+	}
+	return &Receipt69{
+		TxType:            r.Type,
+		PostStateOrStatus: status,
+		CumulativeGasUsed: r.CumulativeGasUsed,
+		Logs:              logs,
+	}
+}
+
+// Receipt converts the network receipt back into a consensus receipt, deriving
+// the bloom filter from the contained logs.
+func (r *Receipt69) Receipt() (*types.Receipt, error) {
+	if r.TxType > types.SetCodeTxType {
+		return nil, fmt.Errorf("invalid receipt type %d", r.TxType)
+	}
+	receipt := &types.Receipt{
+		Type:              r.TxType,
+		CumulativeGasUsed: r.CumulativeGasUsed,
+		Logs:              r.Logs,
+	}
+	switch {
+	case bytes.Equal(r.PostStateOrStatus, receiptStatusSuccessful):
+		receipt.Status = types.ReceiptStatusSuccessful
+	case bytes.Equal(r.PostStateOrStatus, receiptStatusFailed):
+		receipt.Status = types.ReceiptStatusFailed
+	case len(r.PostStateOrStatus) == len(common.Hash{}):
+		receipt.PostState = r.PostStateOrStatus
+	default:
+		return nil, fmt.Errorf("invalid receipt status %x", r.PostStateOrStatus)
+	}
+	receipt.Bloom = types.CreateBloom(receipt)
+	return receipt, nil
+}
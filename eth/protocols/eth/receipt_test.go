@@ -0,0 +1,83 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Tests that receipts survive the eth/69 network encoding and that the derived
+// receipt root matches the original one.
+func TestReceipt69Conversion(t *testing.T) {
+	logs := []*types.Log{
+		{Address: common.Address{0x11}, Topics: []common.Hash{{0x22}}, Data: []byte{0x33}},
+		{Address: common.Address{0x44}, Data: []byte{}},
+	}
+	receipts := types.Receipts{
+		{Type: types.LegacyTxType, PostState: common.Hash{0xaa}.Bytes(), CumulativeGasUsed: 21000, Logs: logs},
+		{Type: types.LegacyTxType, Status: types.ReceiptStatusFailed, CumulativeGasUsed: 42000, Logs: []*types.Log{}},
+		{Type: types.DynamicFeeTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 63000, Logs: logs},
+		{Type: types.BlobTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 84000, Logs: []*types.Log{}},
+	}
+	for _, r := range receipts {
+		r.Bloom = types.CreateBloom(r)
+	}
+	list := make([]*Receipt69, len(receipts))
+	for i, r := range receipts {
+		list[i] = newReceipt69(r)
+	}
+	enc, err := rlp.EncodeToBytes(list)
+	if err != nil {
+		t.Fatalf("failed to encode receipts: %v", err)
+	}
+	var dec []*Receipt69
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("failed to decode receipts: %v", err)
+	}
+	converted := make(types.Receipts, len(dec))
+	for i, r := range dec {
+		if converted[i], err = r.Receipt(); err != nil {
+			t.Fatalf("receipt %d: conversion failed: %v", i, err)
+		}
+		if converted[i].Bloom != receipts[i].Bloom {
+			t.Errorf("receipt %d: bloom mismatch", i)
+		}
+	}
+	hasher := trie.NewStackTrie(nil)
+	if have, want := types.DeriveSha(converted, hasher), types.DeriveSha(receipts, trie.NewStackTrie(nil)); have != want {
+		t.Fatalf("receipt root mismatch: have %x, want %x", have, want)
+	}
+}
+
+// Tests that malformed eth/69 receipts are rejected.
+func TestReceipt69Invalid(t *testing.T) {
+	invalid := []*Receipt69{
+		{TxType: 0x7f, PostStateOrStatus: []byte{0x01}},
+		{TxType: types.LegacyTxType, PostStateOrStatus: []byte{0x02}},
+		{TxType: types.LegacyTxType, PostStateOrStatus: make([]byte, 31)},
+	}
+	for i, r := range invalid {
+		if _, err := r.Receipt(); err == nil {
+			t.Errorf("test %d: expected error", i)
+		}
+	}
+}
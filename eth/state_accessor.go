@@ -182,10 +182,13 @@ func (eth *Ethereum) pathState(block *types.Block) (*state.StateDB, func(), erro
 	if err == nil {
 		return statedb, noopReleaser, nil
 	}
-	// TODO historic state is not supported in path-based scheme.
-	// Fully archive node in pbss will be implemented by relying
-	// on state history, but needs more work on top.
-	return nil, nil, errors.New("historical state not available in path scheme yet")
+	// Fall back to the historical state resolved from the indexed state
+	// histories, which is only available in the path-based archive node.
+	statedb, err = eth.blockchain.HistoricState(block.Root())
+	if err == nil {
+		return statedb, noopReleaser, nil
+	}
+	return nil, nil, fmt.Errorf("historical state is not available: %w", err)
 }
 
 // stateAtBlock retrieves the state database associated with a certain block.
@@ -0,0 +1,192 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/tracers/live"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestCallIndexTracer(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		sender  = crypto.PubkeyToAddress(key.PublicKey)
+		caller  = common.HexToAddress("0xaaaa")
+		callee  = common.HexToAddress("0xbbbb")
+		other   = common.HexToAddress("0xcccc")
+		engine  = beacon.New(ethash.NewFaker())
+		db      = rawdb.NewMemoryDatabase()
+		genesis = &core.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc: types.GenesisAlloc{
+				sender: {Balance: big.NewInt(params.Ether)},
+				// CALL(0xffff, callee, 1, 0, 0, 0, 0)
+				caller: {
+					Balance: big.NewInt(params.Ether),
+					Code:    append(append(common.FromHex("60006000600060006001"), append([]byte{byte(vm.PUSH20)}, callee.Bytes()...)...), common.FromHex("61fffff100")...),
+				},
+			},
+		}
+	)
+	// Keep the frames of the last three blocks only
+	tracer, err := tracers.LiveDirectory.NewWithContext("callindex", &tracers.LiveContext{DB: db}, json.RawMessage(`{"retention": 3, "maxBlockRange": 3}`))
+	if err != nil {
+		t.Fatalf("failed to create call index tracer: %v", err)
+	}
+	chain, err := core.NewBlockChain(db, core.DefaultCacheConfigWithScheme(rawdb.PathScheme), genesis, nil, engine, vm.Config{Tracer: tracer.Hooks}, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	signer := types.LatestSigner(genesis.Config)
+	_, blocks, _ := core.GenerateChainWithGenesis(genesis, engine, 4, func(i int, b *core.BlockGen) {
+		to := caller
+		if i == 1 {
+			to = other
+		}
+		tx, _ := types.SignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    b.TxNonce(sender),
+			To:       &to,
+			Value:    big.NewInt(100),
+			Gas:      100000,
+			GasPrice: b.BaseFee(),
+		})
+		b.AddTx(tx)
+	})
+	if n, err := chain.InsertChain(blocks[:3]); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	api := tracer.APIs[0].Service.(*live.TraceAPI)
+
+	// Queries without address filters are rejected
+	first, last := rpc.BlockNumber(1), rpc.BlockNumber(3)
+	if _, err := api.Filter(live.TraceFilterArgs{FromBlock: &first, ToBlock: &last}); err == nil {
+		t.Fatal("filter without addresses accepted")
+	}
+	// All the frames of the chain
+	results, err := api.Filter(live.TraceFilterArgs{FromBlock: &first, ToBlock: &last, FromAddress: []common.Address{sender, caller}})
+	if err != nil {
+		t.Fatalf("failed to filter traces: %v", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("unexpected number of frames, want 5, got %d", len(results))
+	}
+	frame := results[1]
+	if frame.BlockNumber != 1 || frame.BlockHash != blocks[0].Hash() || frame.TransactionHash != blocks[0].Transactions()[0].Hash() {
+		t.Fatalf("unexpected frame position: %+v", frame)
+	}
+	if frame.Type != "call" || frame.Subtraces != 0 || frame.Error != "" {
+		t.Fatalf("unexpected frame: %+v", frame)
+	}
+	action, ok := frame.Action.(*live.CallAction)
+	if !ok || action.CallType != "call" || action.From != caller || action.To != callee || action.Value.ToInt().Uint64() != 1 {
+		t.Fatalf("unexpected frame action: %+v", frame.Action)
+	}
+	if _, ok := frame.Result.(*live.CallResult); !ok {
+		t.Fatalf("unexpected frame result: %+v", frame.Result)
+	}
+	if len(frame.TraceAddress) != 1 || frame.TraceAddress[0] != 0 {
+		t.Fatalf("unexpected trace address: %v", frame.TraceAddress)
+	}
+	// The frames are encoded in the trace format of OpenEthereum
+	var (
+		have, _ = json.Marshal(results[0])
+		want    = fmt.Sprintf(`{"action":{"callType":"call","from":"%s","gas":"0x%x","input":"0x","to":"%s","value":"0x64"},"blockHash":"%s","blockNumber":1,"result":{"gasUsed":"0x%x","output":"0x"},"subtraces":1,"traceAddress":[],"transactionHash":"%s","transactionPosition":0,"type":"call"}`,
+			strings.ToLower(sender.Hex()), uint64(results[0].Action.(*live.CallAction).Gas), strings.ToLower(caller.Hex()), blocks[0].Hash().Hex(),
+			uint64(results[0].Result.(*live.CallResult).GasUsed), blocks[0].Transactions()[0].Hash().Hex())
+	)
+	if string(have) != want {
+		t.Fatalf("unexpected encoded frame\nhave: %s\nwant: %s", have, want)
+	}
+	// Frames filtered by recipient
+	results, err = api.Filter(live.TraceFilterArgs{FromBlock: &first, ToBlock: &last, ToAddress: []common.Address{callee}})
+	if err != nil {
+		t.Fatalf("failed to filter traces: %v", err)
+	}
+	if len(results) != 2 || results[0].BlockNumber != 1 || results[1].BlockNumber != 3 {
+		t.Fatalf("unexpected frames: %+v", results)
+	}
+	// Frames filtered by sender and block range
+	from, to := rpc.BlockNumber(2), rpc.BlockNumber(3)
+	results, err = api.Filter(live.TraceFilterArgs{FromBlock: &from, ToBlock: &to, FromAddress: []common.Address{sender}})
+	if err != nil {
+		t.Fatalf("failed to filter traces: %v", err)
+	}
+	if len(results) != 2 || results[0].Action.(*live.CallAction).To != other || results[1].Action.(*live.CallAction).To != caller {
+		t.Fatalf("unexpected frames: %+v", results)
+	}
+	// Paginated frames
+	after, count := uint64(1), uint64(2)
+	results, err = api.Filter(live.TraceFilterArgs{FromBlock: &first, ToBlock: &last, FromAddress: []common.Address{sender, caller}, After: &after, Count: &count})
+	if err != nil {
+		t.Fatalf("failed to filter traces: %v", err)
+	}
+	if len(results) != 2 || results[0].Action.(*live.CallAction).To != callee || results[1].Action.(*live.CallAction).To != other {
+		t.Fatalf("unexpected frames: %+v", results)
+	}
+	// Frames of the head block by default
+	results, err = api.Filter(live.TraceFilterArgs{FromAddress: []common.Address{sender}})
+	if err != nil {
+		t.Fatalf("failed to filter traces: %v", err)
+	}
+	if len(results) != 1 || results[0].BlockNumber != 3 {
+		t.Fatalf("unexpected frames: %+v", results)
+	}
+	// Ranges exceeding the limit are rejected
+	genesisNum := rpc.BlockNumber(0)
+	if _, err := api.Filter(live.TraceFilterArgs{FromBlock: &genesisNum, ToBlock: &last, FromAddress: []common.Address{sender}}); err == nil {
+		t.Fatal("filter exceeding the block range limit accepted")
+	}
+	// Extend the chain, the frames of the first block should be pruned
+	if n, err := chain.InsertChain(blocks[3:]); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	if _, err := api.Filter(live.TraceFilterArgs{FromBlock: &first, ToBlock: &last, FromAddress: []common.Address{sender}}); err == nil {
+		t.Fatal("filter of pruned blocks accepted")
+	}
+	if blob := rawdb.ReadCallTraces(db, 1, blocks[0].Hash()); len(blob) != 0 {
+		t.Fatal("call frames of pruned block retained")
+	}
+	var retained int
+	rawdb.IterateCallTraceAddress(db, callee, 0, 4, func(number uint64, hash common.Hash) bool {
+		if number == 1 {
+			t.Fatal("address index of pruned block retained")
+		}
+		retained++
+		return true
+	})
+	if retained != 2 {
+		t.Fatalf("unexpected number of indexed blocks, want 2, got %d", retained)
+	}
+}
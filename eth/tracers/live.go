@@ -21,16 +21,35 @@ import (
 	"errors"
 
 	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type ctorFunc func(config json.RawMessage) (*tracing.Hooks, error)
 
+// ctxCtorFunc is the constructor of a live tracer which requires access to the
+// resources of the node.
+type ctxCtorFunc func(ctx *LiveContext, config json.RawMessage) (*LiveTracer, error)
+
+// LiveContext contains the resources of the node which are made available to
+// the live tracers.
+type LiveContext struct {
+	DB ethdb.Database // The chain database of the node
+}
+
+// LiveTracer is an instantiated live tracer, along with the RPC APIs it exposes.
+type LiveTracer struct {
+	Hooks *tracing.Hooks
+	APIs  []rpc.API
+}
+
 // LiveDirectory is the collection of tracers which can be used
 // during normal block import operations.
-var LiveDirectory = liveDirectory{elems: make(map[string]ctorFunc)}
+var LiveDirectory = liveDirectory{elems: make(map[string]ctorFunc), ctxElems: make(map[string]ctxCtorFunc)}
 
 type liveDirectory struct {
-	elems map[string]ctorFunc
+	elems    map[string]ctorFunc
+	ctxElems map[string]ctxCtorFunc
 }
 
 // Register registers a tracer constructor by name.
@@ -38,6 +57,12 @@ func (d *liveDirectory) Register(name string, f ctorFunc) {
 	d.elems[name] = f
 }
 
+// RegisterWithContext registers by name the constructor of a tracer which
+// requires access to the node resources.
+func (d *liveDirectory) RegisterWithContext(name string, f ctxCtorFunc) {
+	d.ctxElems[name] = f
+}
+
 // New instantiates a tracer by name.
 func (d *liveDirectory) New(name string, config json.RawMessage) (*tracing.Hooks, error) {
 	if len(config) == 0 {
@@ -46,5 +71,27 @@ func (d *liveDirectory) New(name string, config json.RawMessage) (*tracing.Hooks
 	if f, ok := d.elems[name]; ok {
 		return f(config)
 	}
+	if _, ok := d.ctxElems[name]; ok {
+		return nil, errors.New("tracer requires node context")
+	}
+	return nil, errors.New("not found")
+}
+
+// NewWithContext instantiates a tracer by name, providing it with access to the
+// node resources if required.
+func (d *liveDirectory) NewWithContext(name string, ctx *LiveContext, config json.RawMessage) (*LiveTracer, error) {
+	if len(config) == 0 {
+		config = json.RawMessage("{}")
+	}
+	if f, ok := d.ctxElems[name]; ok {
+		return f(ctx, config)
+	}
+	if f, ok := d.elems[name]; ok {
+		hooks, err := f(config)
+		if err != nil {
+			return nil, err
+		}
+		return &LiveTracer{Hooks: hooks}, nil
+	}
 	return nil, errors.New("not found")
 }
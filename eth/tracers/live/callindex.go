@@ -0,0 +1,583 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package live
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func init() {
+	tracers.LiveDirectory.RegisterWithContext("callindex", newCallIndexTracer)
+}
+
+const (
+	// defaultMaxTraceResults is the default maximum number of call frames
+	// returned by a single trace_filter request.
+	defaultMaxTraceResults = 10000
+
+	// defaultMaxTraceBlockRange is the default maximum number of blocks a
+	// single trace_filter request may cover.
+	defaultMaxTraceBlockRange = 10000
+
+	// defaultTraceRetention is the default number of recent blocks whose call
+	// frames are kept in the index, matching the default transaction history.
+	defaultTraceRetention = 2350000
+)
+
+var (
+	errMissingAddressFilter = errors.New("trace filter requires fromAddress or toAddress")
+	errPrunedTraces         = errors.New("block range beyond the retained trace history")
+)
+
+// indexedCallFrame is a call frame as stored in the call trace index.
+type indexedCallFrame struct {
+	TxIndex      uint64
+	TxHash       common.Hash
+	TraceAddress []uint64 // Position of the frame in the call tree of the transaction
+	Type         byte     // Opcode which opened the frame
+	From         common.Address
+	To           common.Address
+	Value        *big.Int
+	Gas          uint64
+	GasUsed      uint64
+	Input        []byte
+	Output       []byte
+	Subtraces    uint64 // Number of frames opened directly by the frame
+	Error        string
+}
+
+// callIndexTracer is a live tracer which records the call frames of all the
+// imported blocks into an index in the chain database. The indexed frames can
+// be queried via the trace_filter RPC method.
+//
+// The frames are stored per block, along with an index of the blocks in which
+// an address is involved either as the sender or as the recipient of a frame.
+// Blocks which end up on a side chain are indexed too, queries are filtered
+// against the canonical chain.
+type callIndexTracer struct {
+	db        ethdb.Database
+	retention uint64 // Number of recent blocks to keep indexed, zero for all
+
+	block   *types.Block       // The block being processed, nil if none
+	frames  []indexedCallFrame // Call frames recorded in the block
+	addrs   map[common.Address]struct{}
+	txIndex uint64      // Index of the next transaction in the block
+	txHash  common.Hash // Hash of the transaction being processed
+	stack   []int       // Indices of the active frames
+	counts  []uint64    // Number of subcalls of the active frames
+	system  bool        // Whether a system call is being processed
+	started bool        // Whether a transaction has been started
+}
+
+type callIndexTracerConfig struct {
+	MaxResults    uint64  `json:"maxResults"`    // Maximum number of frames returned by a query, defaults to 10000
+	MaxBlockRange uint64  `json:"maxBlockRange"` // Maximum number of blocks covered by a query, defaults to 10000
+	Retention     *uint64 `json:"retention"`     // Number of recent blocks to keep indexed (0 = all), defaults to 2350000
+}
+
+func newCallIndexTracer(ctx *tracers.LiveContext, cfg json.RawMessage) (*tracers.LiveTracer, error) {
+	var config callIndexTracerConfig
+	if err := json.Unmarshal(cfg, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %v", err)
+	}
+	if ctx == nil || ctx.DB == nil {
+		return nil, errors.New("call index tracer requires the chain database")
+	}
+	if config.MaxResults == 0 {
+		config.MaxResults = defaultMaxTraceResults
+	}
+	if config.MaxBlockRange == 0 {
+		config.MaxBlockRange = defaultMaxTraceBlockRange
+	}
+	retention := uint64(defaultTraceRetention)
+	if config.Retention != nil {
+		retention = *config.Retention
+	}
+	t := &callIndexTracer{db: ctx.DB, retention: retention}
+	return &tracers.LiveTracer{
+		Hooks: &tracing.Hooks{
+			OnBlockStart:        t.onBlockStart,
+			OnBlockEnd:          t.onBlockEnd,
+			OnTxStart:           t.onTxStart,
+			OnTxEnd:             t.onTxEnd,
+			OnEnter:             t.onEnter,
+			OnExit:              t.onExit,
+			OnSystemCallStartV2: t.onSystemCallStart,
+			OnSystemCallEnd:     t.onSystemCallEnd,
+		},
+		APIs: []rpc.API{{
+			Namespace: "trace",
+			Service:   &TraceAPI{db: ctx.DB, maxResults: config.MaxResults, maxBlockRange: config.MaxBlockRange, retention: retention},
+		}},
+	}, nil
+}
+
+func (t *callIndexTracer) onBlockStart(ev tracing.BlockEvent) {
+	t.block = ev.Block
+	t.frames = nil
+	t.addrs = make(map[common.Address]struct{})
+	t.txIndex = 0
+}
+
+func (t *callIndexTracer) onBlockEnd(err error) {
+	block := t.block
+	t.block = nil
+
+	// Skip indexing if the block failed to be processed.
+	if block == nil || err != nil {
+		return
+	}
+	batch := t.db.NewBatch()
+	if len(t.frames) > 0 {
+		blob, err := rlp.EncodeToBytes(t.frames)
+		if err != nil {
+			log.Error("Failed to encode call frames", "number", block.NumberU64(), "hash", block.Hash(), "err", err)
+			return
+		}
+		rawdb.WriteCallTraces(batch, block.NumberU64(), block.Hash(), blob)
+		for addr := range t.addrs {
+			rawdb.WriteCallTraceAddress(batch, addr, block.NumberU64(), block.Hash())
+		}
+	}
+	// Drop the frames of the block falling out of the retention window
+	if t.retention != 0 && block.NumberU64() >= t.retention {
+		t.prune(batch, block.NumberU64()-t.retention)
+	}
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to write call trace index", "number", block.NumberU64(), "hash", block.Hash(), "err", err)
+	}
+}
+
+// prune removes the call frames of all the blocks with the given number, along
+// with their address index entries.
+func (t *callIndexTracer) prune(batch ethdb.Batch, number uint64) {
+	for _, hash := range rawdb.ReadCallTraceHashes(t.db, number) {
+		var frames []indexedCallFrame
+		if err := rlp.DecodeBytes(rawdb.ReadCallTraces(t.db, number, hash), &frames); err != nil {
+			log.Error("Failed to decode call frames", "number", number, "hash", hash, "err", err)
+		}
+		for _, frame := range frames {
+			rawdb.DeleteCallTraceAddress(batch, frame.From, number, hash)
+			rawdb.DeleteCallTraceAddress(batch, frame.To, number, hash)
+		}
+		rawdb.DeleteCallTraces(batch, number, hash)
+	}
+}
+
+func (t *callIndexTracer) onTxStart(vm *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	t.txHash = tx.Hash()
+	t.stack = t.stack[:0]
+	t.counts = t.counts[:0]
+	t.started = true
+}
+
+func (t *callIndexTracer) onTxEnd(receipt *types.Receipt, err error) {
+	t.txIndex++
+	t.started = false
+}
+
+func (t *callIndexTracer) onSystemCallStart(vm *tracing.VMContext) {
+	t.system = true
+}
+
+func (t *callIndexTracer) onSystemCallEnd() {
+	t.system = false
+}
+
+func (t *callIndexTracer) onEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.block == nil || t.system || !t.started {
+		return
+	}
+	var address []uint64
+	if n := len(t.stack); n > 0 {
+		parent := t.frames[t.stack[n-1]].TraceAddress
+		address = make([]uint64, len(parent)+1)
+		copy(address, parent)
+		address[len(parent)] = t.counts[n-1]
+		t.counts[n-1]++
+	}
+	if value == nil {
+		value = new(big.Int)
+	}
+	t.frames = append(t.frames, indexedCallFrame{
+		TxIndex:      t.txIndex,
+		TxHash:       t.txHash,
+		TraceAddress: address,
+		Type:         typ,
+		From:         from,
+		To:           to,
+		Value:        new(big.Int).Set(value),
+		Gas:          gas,
+		Input:        common.CopyBytes(input),
+	})
+	t.stack = append(t.stack, len(t.frames)-1)
+	t.counts = append(t.counts, 0)
+	t.addrs[from] = struct{}{}
+	t.addrs[to] = struct{}{}
+}
+
+func (t *callIndexTracer) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.block == nil || t.system || !t.started || len(t.stack) == 0 {
+		return
+	}
+	frame := &t.frames[t.stack[len(t.stack)-1]]
+	frame.Subtraces = t.counts[len(t.counts)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	t.counts = t.counts[:len(t.counts)-1]
+
+	frame.GasUsed = gasUsed
+	frame.Output = common.CopyBytes(output)
+
+	// Errors not reverting the frame (e.g. pre-homestead contract storage
+	// OOG) are not reported.
+	if err != nil && reverted {
+		frame.Error = traceError(err)
+	}
+}
+
+// traceError converts an execution error into the error message reported in
+// the traces of OpenEthereum.
+func traceError(err error) string {
+	var (
+		invalidOp *vm.ErrInvalidOpCode
+		underflow *vm.ErrStackUnderflow
+		overflow  *vm.ErrStackOverflow
+	)
+	switch {
+	case errors.Is(err, vm.ErrExecutionReverted):
+		return "Reverted"
+	case errors.Is(err, vm.ErrOutOfGas), errors.Is(err, vm.ErrCodeStoreOutOfGas):
+		return "Out of gas"
+	case errors.Is(err, vm.ErrInvalidJump):
+		return "Bad jump destination"
+	case errors.Is(err, vm.ErrDepth):
+		return "Call stack limit"
+	case errors.Is(err, vm.ErrWriteProtection):
+		return "Mutable call in static context"
+	case errors.As(err, &invalidOp):
+		return "Bad instruction"
+	case errors.As(err, &underflow):
+		return "Stack underflow"
+	case errors.As(err, &overflow):
+		return "Out of stack"
+	default:
+		return err.Error()
+	}
+}
+
+// TraceAPI provides access to the call frames recorded by the call index
+// tracer.
+type TraceAPI struct {
+	db            ethdb.Database
+	maxResults    uint64
+	maxBlockRange uint64
+	retention     uint64
+}
+
+// TraceFilterArgs represents the arguments of a trace_filter request.
+type TraceFilterArgs struct {
+	FromBlock   *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock     *rpc.BlockNumber `json:"toBlock"`
+	FromAddress []common.Address `json:"fromAddress"`
+	ToAddress   []common.Address `json:"toAddress"`
+	After       *uint64          `json:"after"`
+	Count       *uint64          `json:"count"`
+}
+
+// TraceResult is a call frame returned by trace_filter, in the trace format of
+// OpenEthereum. The action and the result depend on the type of the frame, the
+// result being null if the frame failed.
+type TraceResult struct {
+	Action              any         `json:"action"`
+	BlockHash           common.Hash `json:"blockHash"`
+	BlockNumber         uint64      `json:"blockNumber"`
+	Error               string      `json:"error,omitempty"`
+	Result              any         `json:"result"`
+	Subtraces           uint64      `json:"subtraces"`
+	TraceAddress        []uint64    `json:"traceAddress"`
+	TransactionHash     common.Hash `json:"transactionHash"`
+	TransactionPosition uint64      `json:"transactionPosition"`
+	Type                string      `json:"type"`
+}
+
+// CallAction is the action of a message call frame.
+type CallAction struct {
+	CallType string         `json:"callType"`
+	From     common.Address `json:"from"`
+	Gas      hexutil.Uint64 `json:"gas"`
+	Input    hexutil.Bytes  `json:"input"`
+	To       common.Address `json:"to"`
+	Value    *hexutil.Big   `json:"value"`
+}
+
+// CallResult is the result of a successful message call frame.
+type CallResult struct {
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Output  hexutil.Bytes  `json:"output"`
+}
+
+// CreateAction is the action of a contract creation frame.
+type CreateAction struct {
+	CreationMethod string         `json:"creationMethod"`
+	From           common.Address `json:"from"`
+	Gas            hexutil.Uint64 `json:"gas"`
+	Init           hexutil.Bytes  `json:"init"`
+	Value          *hexutil.Big   `json:"value"`
+}
+
+// CreateResult is the result of a successful contract creation frame.
+type CreateResult struct {
+	Address common.Address `json:"address"`
+	Code    hexutil.Bytes  `json:"code"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+}
+
+// SuicideAction is the action of a self-destruct frame.
+type SuicideAction struct {
+	Address       common.Address `json:"address"`
+	Balance       *hexutil.Big   `json:"balance"`
+	RefundAddress common.Address `json:"refundAddress"`
+}
+
+// Filter returns the call frames of the canonical blocks within the requested
+// range, matching the given senders and recipients. A frame matches if it is
+// sent from any of the given senders and to any of the given recipients, an
+// empty list matching all addresses, though at least one of the lists must be
+// given. The range defaults to the head block and may not exceed the configured
+// maximum. The results are ordered by their position in the chain and can be
+// paginated via after and count.
+func (api *TraceAPI) Filter(args TraceFilterArgs) ([]*TraceResult, error) {
+	if len(args.FromAddress) == 0 && len(args.ToAddress) == 0 {
+		return nil, errMissingAddressFilter
+	}
+	head, err := api.resolveBlockNumber(rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	from, to := head, head
+	if args.FromBlock != nil {
+		if from, err = api.resolveBlockNumber(*args.FromBlock); err != nil {
+			return nil, err
+		}
+	}
+	if args.ToBlock != nil {
+		if to, err = api.resolveBlockNumber(*args.ToBlock); err != nil {
+			return nil, err
+		}
+	}
+	if from > to {
+		return nil, errors.New("invalid block range")
+	}
+	if to-from >= api.maxBlockRange {
+		return nil, fmt.Errorf("block range exceeds the limit of %d blocks", api.maxBlockRange)
+	}
+	if api.retention != 0 && head >= api.retention && from <= head-api.retention {
+		return nil, errPrunedTraces
+	}
+	var (
+		limit   = api.maxResults
+		skip    uint64
+		senders = addressSet(args.FromAddress)
+		targets = addressSet(args.ToAddress)
+		results = make([]*TraceResult, 0)
+	)
+	if args.Count != nil {
+		if *args.Count > api.maxResults {
+			return nil, fmt.Errorf("count exceeds the limit of %d", api.maxResults)
+		}
+		limit = *args.Count
+	}
+	if args.After != nil {
+		skip = *args.After
+	}
+	// Collect the frames of the candidate blocks until the limit is reached.
+	// If the count is not specified, exceeding the limit is an error as the
+	// results would be silently truncated.
+	for _, number := range api.candidates(from, to, args.FromAddress, args.ToAddress) {
+		hash := rawdb.ReadCanonicalHash(api.db, number)
+		blob := rawdb.ReadCallTraces(api.db, number, hash)
+		if len(blob) == 0 {
+			continue
+		}
+		var frames []indexedCallFrame
+		if err := rlp.DecodeBytes(blob, &frames); err != nil {
+			return nil, fmt.Errorf("failed to decode call frames of block %d: %v", number, err)
+		}
+		for _, frame := range frames {
+			if !matchAddress(senders, frame.From) || !matchAddress(targets, frame.To) {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if uint64(len(results)) == limit {
+				if args.Count == nil {
+					return nil, fmt.Errorf("query returned more than %d results", api.maxResults)
+				}
+				return results, nil
+			}
+			results = append(results, newTraceResult(number, hash, frame))
+		}
+	}
+	return results, nil
+}
+
+// candidates returns the numbers of the blocks within the given range which may
+// contain matching frames, in ascending order. At least one of the address
+// filters must be non-empty.
+func (api *TraceAPI) candidates(from, to uint64, senders, targets []common.Address) []uint64 {
+	// The blocks in which all the frames are filtered out by the address
+	// filters can be skipped, use the shorter filter to find the others.
+	addrs := senders
+	if len(addrs) == 0 || (len(targets) != 0 && len(targets) < len(addrs)) {
+		addrs = targets
+	}
+	seen := make(map[uint64]struct{})
+	for _, addr := range addrs {
+		rawdb.IterateCallTraceAddress(api.db, addr, from, to, func(number uint64, hash common.Hash) bool {
+			if rawdb.ReadCanonicalHash(api.db, number) == hash {
+				seen[number] = struct{}{}
+			}
+			return true
+		})
+	}
+	numbers := make([]uint64, 0, len(seen))
+	for n := range seen {
+		numbers = append(numbers, n)
+	}
+	slices.Sort(numbers)
+	return numbers
+}
+
+// resolveBlockNumber converts the block number or tag into a block number.
+func (api *TraceAPI) resolveBlockNumber(number rpc.BlockNumber) (uint64, error) {
+	var hash common.Hash
+	switch number {
+	case rpc.EarliestBlockNumber:
+		return 0, nil
+	case rpc.LatestBlockNumber, rpc.PendingBlockNumber:
+		hash = rawdb.ReadHeadBlockHash(api.db)
+	case rpc.FinalizedBlockNumber, rpc.SafeBlockNumber:
+		hash = rawdb.ReadFinalizedBlockHash(api.db)
+	default:
+		if number < 0 {
+			return 0, fmt.Errorf("unsupported block number %d", number)
+		}
+		return uint64(number), nil
+	}
+	n := rawdb.ReadHeaderNumber(api.db, hash)
+	if n == nil {
+		return 0, fmt.Errorf("block %s not found", number)
+	}
+	return *n, nil
+}
+
+func newTraceResult(number uint64, hash common.Hash, frame indexedCallFrame) *TraceResult {
+	address := frame.TraceAddress
+	if address == nil {
+		address = []uint64{}
+	}
+	result := &TraceResult{
+		BlockHash:           hash,
+		BlockNumber:         number,
+		Error:               frame.Error,
+		Subtraces:           frame.Subtraces,
+		TraceAddress:        address,
+		TransactionHash:     frame.TxHash,
+		TransactionPosition: frame.TxIndex,
+	}
+	op := vm.OpCode(frame.Type)
+	switch op {
+	case vm.CREATE, vm.CREATE2:
+		result.Type = "create"
+		result.Action = &CreateAction{
+			CreationMethod: strings.ToLower(op.String()),
+			From:           frame.From,
+			Gas:            hexutil.Uint64(frame.Gas),
+			Init:           frame.Input,
+			Value:          (*hexutil.Big)(frame.Value),
+		}
+		if frame.Error == "" {
+			result.Result = &CreateResult{
+				Address: frame.To,
+				Code:    frame.Output,
+				GasUsed: hexutil.Uint64(frame.GasUsed),
+			}
+		}
+	case vm.SELFDESTRUCT:
+		result.Type = "suicide"
+		result.Action = &SuicideAction{
+			Address:       frame.From,
+			Balance:       (*hexutil.Big)(frame.Value),
+			RefundAddress: frame.To,
+		}
+	default:
+		result.Type = "call"
+		result.Action = &CallAction{
+			CallType: strings.ToLower(op.String()),
+			From:     frame.From,
+			Gas:      hexutil.Uint64(frame.Gas),
+			Input:    frame.Input,
+			To:       frame.To,
+			Value:    (*hexutil.Big)(frame.Value),
+		}
+		if frame.Error == "" {
+			result.Result = &CallResult{
+				GasUsed: hexutil.Uint64(frame.GasUsed),
+				Output:  frame.Output,
+			}
+		}
+	}
+	return result
+}
+
+func addressSet(addrs []common.Address) map[common.Address]struct{} {
+	if len(addrs) == 0 {
+		return nil
+	}
+	set := make(map[common.Address]struct{}, len(addrs))
+	for _, addr := range addrs {
+		set[addr] = struct{}{}
+	}
+	return set
+}
+
+func matchAddress(set map[common.Address]struct{}, addr common.Address) bool {
+	if set == nil {
+		return true
+	}
+	_, ok := set[addr]
+	return ok
+}
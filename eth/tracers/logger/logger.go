@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers/sourcemap"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/holiman/uint256"
 )
@@ -49,6 +50,9 @@ type Config struct {
 	Limit            int  // maximum size of output, but zero means unlimited
 	// Chain overrides, can be used to execute a trace using future fork rules
 	Overrides *params.ChainConfig `json:"overrides,omitempty"`
+	// Compiler artifacts, used to annotate the logs with Solidity source
+	// locations and to produce stack traces of reverted calls
+	Sources *sourcemap.Artifacts `json:"sources,omitempty"`
 }
 
 //go:generate go run github.com/fjl/gencodec -type StructLog -field-override structLogMarshaling -out gen_structlog.go
@@ -68,6 +72,7 @@ type StructLog struct {
 	Depth         int                         `json:"depth"`
 	RefundCounter uint64                      `json:"refund"`
 	Err           error                       `json:"-"`
+	Source        *sourcemap.Location         `json:"source,omitempty"`
 }
 
 // overrides for gencodec
@@ -97,6 +102,9 @@ func (s *StructLog) ErrorString() string {
 // WriteTo writes the human-readable log data into the supplied writer.
 func (s *StructLog) WriteTo(writer io.Writer) {
 	fmt.Fprintf(writer, "%-16spc=%08d gas=%v cost=%v", s.Op, s.Pc, s.Gas, s.GasCost)
+	if s.Source != nil {
+		fmt.Fprintf(writer, " at %v", s.Source)
+	}
 	if s.Err != nil {
 		fmt.Fprintf(writer, " ERROR: %v", s.Err)
 	}
@@ -143,17 +151,18 @@ func (s *StructLog) WriteTo(writer io.Writer) {
 // storage:
 // Legacy has a storage field while non-legacy doesn't.
 type structLogLegacy struct {
-	Pc            uint64             `json:"pc"`
-	Op            string             `json:"op"`
-	Gas           uint64             `json:"gas"`
-	GasCost       uint64             `json:"gasCost"`
-	Depth         int                `json:"depth"`
-	Error         string             `json:"error,omitempty"`
-	Stack         *[]string          `json:"stack,omitempty"`
-	ReturnData    string             `json:"returnData,omitempty"`
-	Memory        *[]string          `json:"memory,omitempty"`
-	Storage       *map[string]string `json:"storage,omitempty"`
-	RefundCounter uint64             `json:"refund,omitempty"`
+	Pc            uint64              `json:"pc"`
+	Op            string              `json:"op"`
+	Gas           uint64              `json:"gas"`
+	GasCost       uint64              `json:"gasCost"`
+	Depth         int                 `json:"depth"`
+	Error         string              `json:"error,omitempty"`
+	Stack         *[]string           `json:"stack,omitempty"`
+	ReturnData    string              `json:"returnData,omitempty"`
+	Memory        *[]string           `json:"memory,omitempty"`
+	Storage       *map[string]string  `json:"storage,omitempty"`
+	RefundCounter uint64              `json:"refund,omitempty"`
+	Source        *sourcemap.Location `json:"source,omitempty"`
 }
 
 // toLegacyJSON converts the structLog to legacy json-encoded legacy form.
@@ -166,6 +175,7 @@ func (s *StructLog) toLegacyJSON() json.RawMessage {
 		Depth:         s.Depth,
 		Error:         s.ErrorString(),
 		RefundCounter: s.RefundCounter,
+		Source:        s.Source,
 	}
 	if s.Stack != nil {
 		stack := make([]string, len(s.Stack))
@@ -216,6 +226,8 @@ type StructLogger struct {
 	logs       []json.RawMessage // buffer of json-encoded logs
 	resultSize int
 
+	sources *sourceTracker // Maps the execution to the sources if artifacts are configured
+
 	interrupt atomic.Bool // Atomic flag to signal execution interruption
 	reason    error       // Textual reason for the interruption
 	skip      bool        // skip processing hooks.
@@ -237,6 +249,9 @@ func NewStructLogger(cfg *Config) *StructLogger {
 	if cfg != nil {
 		logger.cfg = *cfg
 	}
+	if logger.cfg.Sources != nil {
+		logger.sources = newSourceTracker(logger.cfg.Sources)
+	}
 	return logger
 }
 
@@ -246,11 +261,20 @@ func (l *StructLogger) Hooks() *tracing.Hooks {
 		OnTxEnd:             l.OnTxEnd,
 		OnSystemCallStartV2: l.OnSystemCallStart,
 		OnSystemCallEnd:     l.OnSystemCallEnd,
+		OnEnter:             l.OnEnter,
 		OnExit:              l.OnExit,
 		OnOpcode:            l.OnOpcode,
 	}
 }
 
+// OnEnter is called when a call frame starts processing.
+func (l *StructLogger) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if l.skip || l.sources == nil {
+		return
+	}
+	l.sources.onEnter(typ, to)
+}
+
 // OnOpcode logs a new structured log message and pushes it out to the environment
 //
 // OnOpcode also tracks SLOAD/SSTORE ops to track storage change.
@@ -263,6 +287,11 @@ func (l *StructLogger) OnOpcode(pc uint64, opcode byte, gas, cost uint64, scope
 	if l.skip {
 		return
 	}
+	// Frames are tracked even past the size limit, to report the stack trace
+	// of a failure
+	if l.sources != nil {
+		l.sources.onOpcode(pc, scope)
+	}
 	// check if already accumulated the size of the response.
 	if l.cfg.Limit != 0 && l.resultSize > l.cfg.Limit {
 		return
@@ -274,7 +303,10 @@ func (l *StructLogger) OnOpcode(pc uint64, opcode byte, gas, cost uint64, scope
 		stack        = scope.StackData()
 		stackLen     = len(stack)
 	)
-	log := StructLog{pc, op, gas, cost, nil, len(memory), nil, nil, nil, depth, l.env.StateDB.GetRefund(), err}
+	log := StructLog{pc, op, gas, cost, nil, len(memory), nil, nil, nil, depth, l.env.StateDB.GetRefund(), err, nil}
+	if l.sources != nil {
+		log.Source = l.sources.location()
+	}
 	if l.cfg.EnableMemory {
 		log.Memory = memory
 	}
@@ -325,14 +357,20 @@ func (l *StructLogger) OnOpcode(pc uint64, opcode byte, gas, cost uint64, scope
 
 // OnExit is called a call frame finishes processing.
 func (l *StructLogger) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
-	if depth != 0 {
+	if l.skip {
 		return
 	}
-	if l.skip {
+	if l.sources != nil {
+		l.sources.onExit(depth, output, err)
+	}
+	if depth != 0 {
 		return
 	}
 	l.output = output
 	l.err = err
+	if trace := l.stackTrace(); trace != nil && l.writer != nil {
+		fmt.Fprint(l.writer, trace)
+	}
 	// TODO @holiman, should we output the per-scope output?
 	//if l.cfg.Debug {
 	//	fmt.Printf("%#x\n", output)
@@ -358,9 +396,19 @@ func (l *StructLogger) GetResult() (json.RawMessage, error) {
 		Failed:      failed,
 		ReturnValue: returnData,
 		StructLogs:  l.logs,
+		Revert:      l.stackTrace(),
 	})
 }
 
+// stackTrace returns the Solidity level stack trace of the failed execution,
+// or nil if the execution succeeded or no artifacts were configured.
+func (l *StructLogger) stackTrace() *sourcemap.StackTrace {
+	if l.sources == nil || l.err == nil {
+		return nil
+	}
+	return l.sources.stackTrace()
+}
+
 // Stop terminates execution of the tracer at the first opportune moment.
 func (l *StructLogger) Stop(err error) {
 	l.reason = err
@@ -524,8 +572,9 @@ func (t *mdLogger) OnFault(pc uint64, op byte, gas, cost uint64, scope tracing.O
 // while replaying a transaction in debug mode as well as transaction
 // execution status, the amount of gas used and the return value
 type ExecutionResult struct {
-	Gas         uint64            `json:"gas"`
-	Failed      bool              `json:"failed"`
-	ReturnValue hexutil.Bytes     `json:"returnValue"`
-	StructLogs  []json.RawMessage `json:"structLogs"`
+	Gas         uint64                `json:"gas"`
+	Failed      bool                  `json:"failed"`
+	ReturnValue hexutil.Bytes         `json:"returnValue"`
+	StructLogs  []json.RawMessage     `json:"structLogs"`
+	Revert      *sourcemap.StackTrace `json:"revert,omitempty"`
 }
@@ -17,14 +17,21 @@
 package logger
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"math/big"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/eth/tracers/sourcemap"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/holiman/uint256"
 )
@@ -92,3 +99,79 @@ func TestStructLogMarshalingOmitEmpty(t *testing.T) {
 		})
 	}
 }
+
+// Tests that the logs are annotated with the Solidity sources, and that the
+// stack trace of a revert bubbling up through the call frames is reported.
+func TestStructLoggerSources(t *testing.T) {
+	input, err := os.ReadFile("../sourcemap/testdata/solc-input.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := os.ReadFile("../sourcemap/testdata/solc-output.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifacts, err := sourcemap.Parse(input, output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		caller = common.HexToAddress("0xca11e4")
+		callee = common.HexToAddress("0xca11ee0000000000000000000000000000000001")
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.SetCode(callee, common.FromHex("0x7fe8620800000000000000000000000000000000000000000000000000000000006000527c0100000000000000000000000000000000000000000000000000000000602052600060405360006041536000604253600560435360446000fd"))
+	statedb.SetCode(caller, common.FromHex("0x60008080808073ca11ee00000000000000000000000000000000015af1503d600060003e3d6000fd"))
+
+	var (
+		stream  bytes.Buffer
+		loggers = []*StructLogger{
+			NewStructLogger(&Config{Sources: artifacts}),
+			NewStreamingStructLogger(&Config{Sources: artifacts}, &stream),
+		}
+	)
+	for _, logger := range loggers {
+		_, _, err = runtime.Call(caller, nil, &runtime.Config{
+			ChainConfig: params.MergedTestChainConfig,
+			State:       statedb.Copy(),
+			GasLimit:    1_000_000,
+			EVMConfig:   vm.Config{Tracer: logger.Hooks()},
+		})
+		if !errors.Is(err, vm.ErrExecutionReverted) {
+			t.Fatalf("unexpected execution error: %v", err)
+		}
+	}
+	blob, err := loggers[0].GetResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		StructLogs []struct {
+			Op     string              `json:"op"`
+			Depth  int                 `json:"depth"`
+			Source *sourcemap.Location `json:"source"`
+		} `json:"structLogs"`
+		Revert *sourcemap.StackTrace `json:"revert"`
+	}
+	if err := json.Unmarshal(blob, &result); err != nil {
+		t.Fatal(err)
+	}
+	for _, log := range result.StructLogs {
+		if log.Source == nil {
+			t.Fatalf("%s at depth %d not annotated", log.Op, log.Depth)
+		}
+	}
+	want := &sourcemap.StackTrace{
+		Reason: "Insufficient(available: 1, required: 5)",
+		Frames: []string{"Callee.fail (test.sol:8:9)", "Caller.run (test.sol:20:9)"},
+	}
+	if !reflect.DeepEqual(result.Revert, want) {
+		t.Fatalf("stack trace mismatch: have %+v, want %+v", result.Revert, want)
+	}
+	if !strings.HasSuffix(stream.String(), want.String()) {
+		t.Fatalf("stack trace not streamed, have:\n%s", stream.String())
+	}
+	if !strings.Contains(stream.String(), " at Callee.fail (test.sol:8:9)") {
+		t.Fatalf("streamed logs not annotated, have:\n%s", stream.String())
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers/sourcemap"
+)
+
+// sourceFrame is a call frame tracked for mapping its execution to the sources.
+type sourceFrame struct {
+	addr     common.Address
+	create   bool                // Whether the frame executes an init code
+	resolved bool                // Whether the code was looked up in the artifacts
+	contract *sourcemap.Contract // Contract the code was compiled from, nil if unknown
+	executed bool                // Whether any instruction was executed
+	pc       uint64              // Program counter of the last executed instruction
+}
+
+// location returns the source location of the last executed instruction.
+func (f *sourceFrame) location() *sourcemap.Location {
+	if f.contract == nil || !f.executed {
+		return nil
+	}
+	return f.contract.Locate(f.pc, f.create)
+}
+
+// String formats the frame as an entry of a stack trace.
+func (f *sourceFrame) String() string {
+	if loc := f.location(); loc != nil {
+		return loc.String()
+	}
+	switch {
+	case f.contract != nil:
+		return fmt.Sprintf("%s (%s, pc %d)", f.contract.Name, f.addr.Hex(), f.pc)
+	case f.executed:
+		return fmt.Sprintf("%s (pc %d)", f.addr.Hex(), f.pc)
+	default:
+		return f.addr.Hex()
+	}
+}
+
+// codeKey identifies an executed code for caching its artifacts lookup.
+type codeKey struct {
+	hash   common.Hash
+	create bool
+}
+
+// sourceTracker follows the call frames of an execution to map the executed
+// instructions to the Solidity sources, and assembles the stack trace of the
+// failed calls.
+type sourceTracker struct {
+	artifacts *sourcemap.Artifacts
+	contracts map[codeKey]*sourcemap.Contract
+	frames    []*sourceFrame
+
+	revert       *sourcemap.StackTrace // Stack trace of the last failed call
+	revertDepth  int                   // Depth of the outermost frame in the stack trace
+	revertOutput []byte                // Output of the outermost frame in the stack trace
+}
+
+func newSourceTracker(artifacts *sourcemap.Artifacts) *sourceTracker {
+	return &sourceTracker{
+		artifacts: artifacts,
+		contracts: make(map[codeKey]*sourcemap.Contract),
+	}
+}
+
+// onEnter starts tracking a new call frame.
+func (t *sourceTracker) onEnter(typ byte, to common.Address) {
+	create := vm.OpCode(typ) == vm.CREATE || vm.OpCode(typ) == vm.CREATE2
+	t.frames = append(t.frames, &sourceFrame{addr: to, create: create})
+}
+
+// onOpcode records the instruction executed by the current frame, looking up
+// the contract of its code on the first one.
+func (t *sourceTracker) onOpcode(pc uint64, scope tracing.OpContext) {
+	if len(t.frames) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	if !frame.resolved {
+		code := scope.ContractCode()
+		key := codeKey{crypto.Keccak256Hash(code), frame.create}
+		contract, ok := t.contracts[key]
+		if !ok {
+			contract = t.artifacts.Lookup(code, frame.create)
+			t.contracts[key] = contract
+		}
+		frame.contract, frame.resolved = contract, true
+	}
+	frame.pc, frame.executed = pc, true
+}
+
+// location returns the source location of the last executed instruction.
+func (t *sourceTracker) location() *sourcemap.Location {
+	if len(t.frames) == 0 {
+		return nil
+	}
+	return t.frames[len(t.frames)-1].location()
+}
+
+// onExit stops tracking the current call frame. If it failed, it's added to the
+// stack trace of its failed callee when bubbling up the same revert data, or
+// starts a new stack trace otherwise.
+func (t *sourceTracker) onExit(depth int, output []byte, err error) {
+	if len(t.frames) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+
+	if err == nil {
+		return
+	}
+	if t.revert != nil && t.revertDepth == depth+1 && bytes.Equal(t.revertOutput, output) {
+		t.revert.Frames = append(t.revert.Frames, frame.String())
+		t.revertDepth = depth
+		return
+	}
+	reason := err.Error()
+	if errors.Is(err, vm.ErrExecutionReverted) {
+		reason = t.artifacts.DecodeRevert(frame.contract, output)
+	}
+	t.revert = &sourcemap.StackTrace{Reason: reason, Frames: []string{frame.String()}}
+	t.revertDepth = depth
+	t.revertOutput = common.CopyBytes(output)
+}
+
+// stackTrace returns the stack trace of the failed execution, or nil if the
+// outermost call didn't fail.
+func (t *sourceTracker) stackTrace() *sourcemap.StackTrace {
+	if t.revertDepth != 0 {
+		return nil
+	}
+	return t.revert
+}
@@ -0,0 +1,368 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("gasProfileTracer", newGasProfileTracer, false)
+}
+
+// Output formats of the gasProfileTracer.
+const (
+	gasProfileFormatJSON   = "json"
+	gasProfileFormatFolded = "folded"
+)
+
+// Labels of the gas not spent by the opcodes of any frame.
+const (
+	gasProfileIntrinsic = "[intrinsic]"
+	gasProfileFloor     = "[floor]"
+)
+
+// gasProfileConfig are the configuration options of the gasProfileTracer.
+type gasProfileConfig struct {
+	Format string `json:"format"` // Output format, either "json" (default) or "folded"
+}
+
+// gasProfileFrame tracks an active call frame.
+type gasProfileFrame struct {
+	path     string         // Folded call path of the frame, from the top-level call
+	code     common.Address // Address of the executed code
+	function string         // Label of the invoked function within the code
+	charged  uint64         // Gas charged to the frame and its children so far
+
+	pendingOp   string // Call opcode whose cost still includes the gas forwarded to the callee
+	pendingCost uint64 // Cost of the pending call opcode
+	faultOp     string // Opcode the frame failed at, if any
+}
+
+// gasProfile is the structured output of the gasProfileTracer.
+type gasProfile struct {
+	Gas          uint64                    `json:"gas"`          // Total gas spent, before refunds
+	GasUsed      uint64                    `json:"gasUsed"`      // Gas used by the transaction, after refunds
+	IntrinsicGas uint64                    `json:"intrinsicGas"` // Gas charged before executing any code
+	Refund       uint64                    `json:"refund"`       // Gas refunded at the end of the transaction
+	Contracts    map[common.Address]uint64 `json:"contracts"`    // Gas spent executing the code of each contract
+	Functions    map[string]uint64         `json:"functions"`    // Gas spent executing each function, keyed by code address and selector
+	Opcodes      map[string]uint64         `json:"opcodes"`      // Gas spent by each opcode
+	Calls        map[string]uint64         `json:"calls"`        // Gas spent by the frames themselves, keyed by folded call path
+}
+
+// gasProfileTracer attributes the gas spent by a transaction to the code
+// locations spending it. The cost of each opcode, including the dynamic part
+// like memory expansion and cold account or storage access, is charged to the
+// opcode, to the executing function (code address and selector), to the code
+// address and to the call path leading to it. The gas forwarded to callees is
+// charged to the callees instead of the calling opcodes.
+//
+// Gas spent by a frame outside of its opcodes (e.g. precompile execution,
+// code deposit of contract creations or gas burnt by failing opcodes) is
+// charged to the frame itself, or to the failing opcode.
+//
+// With the "folded" format, the result is a string of folded stacks, one line
+// per call path and opcode with the gas spent, consumable by flamegraph tools.
+//
+// Example:
+//
+//	> debug.traceTransaction("0x4ea3bd02d5d6fa32d7f5db1a2a6a3e6e5e6e0b9a8c0f8b0f7a3c7e9b05b1b0f5", {tracer: "gasProfileTracer", tracerConfig: {format: "folded"}})
+//	"[intrinsic] 21464\n0xa0b8...eb48:0xa9059cbb;CALLDATALOAD 6\n0xa0b8...eb48:0xa9059cbb;SSTORE 22100\n..."
+type gasProfileTracer struct {
+	config    gasProfileConfig
+	callstack []*gasProfileFrame
+	profile   gasProfile
+	stacks    map[string]uint64 // Gas spent per folded stack, including the opcode leaves
+
+	txGas     uint64      // Gas limit of the transaction
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+
+	chainConfig       *params.ChainConfig
+	activePrecompiles []common.Address // Updated on tx start based on given rules
+}
+
+// newGasProfileTracer returns a native go tracer which profiles the gas spent
+// by a tx per code location.
+func newGasProfileTracer(ctx *tracers.Context, cfg json.RawMessage, chainConfig *params.ChainConfig) (*tracers.Tracer, error) {
+	var config gasProfileConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	switch config.Format {
+	case "":
+		config.Format = gasProfileFormatJSON
+	case gasProfileFormatJSON, gasProfileFormatFolded:
+	default:
+		return nil, fmt.Errorf("unknown gas profile format %q", config.Format)
+	}
+	t := &gasProfileTracer{
+		config:      config,
+		chainConfig: chainConfig,
+	}
+	t.reset()
+
+	return &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: t.OnTxStart,
+			OnTxEnd:   t.OnTxEnd,
+			OnEnter:   t.OnEnter,
+			OnExit:    t.OnExit,
+			OnOpcode:  t.OnOpcode,
+			OnFault:   t.OnFault,
+		},
+		GetResult: t.GetResult,
+		Stop:      t.Stop,
+	}, nil
+}
+
+// reset clears the collected profile.
+func (t *gasProfileTracer) reset() {
+	t.callstack = nil
+	t.stacks = make(map[string]uint64)
+	t.profile = gasProfile{
+		Contracts: make(map[common.Address]uint64),
+		Functions: make(map[string]uint64),
+		Opcodes:   make(map[string]uint64),
+		Calls:     make(map[string]uint64),
+	}
+}
+
+// OnTxStart is called before the transaction is executed.
+func (t *gasProfileTracer) OnTxStart(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	// The tracer may be reused for repeated executions, e.g. by cmd/evm
+	t.reset()
+	t.txGas = tx.Gas()
+
+	rules := t.chainConfig.Rules(env.BlockNumber, env.Random != nil, env.Time)
+	t.activePrecompiles = vm.ActivePrecompiles(rules)
+}
+
+// OnEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *gasProfileTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.interrupt.Load() {
+		return
+	}
+	op := vm.OpCode(typ)
+	if op == vm.SELFDESTRUCT {
+		return // Selfdestruct doesn't open a new scope, nor does it spend gas
+	}
+	if depth == 0 {
+		// Anything charged before entering the top-level call is intrinsic
+		if t.txGas > gas {
+			t.profile.IntrinsicGas = t.txGas - gas
+			t.stacks[gasProfileIntrinsic] += t.profile.IntrinsicGas
+		}
+	} else if size := len(t.callstack); size > 0 {
+		// The cost of the call opcodes includes the gas forwarded to the callee,
+		// which is charged to the callee instead. Creations deduct the forwarded
+		// gas separately from the opcode cost.
+		parent := t.callstack[size-1]
+		if parent.pendingOp != "" {
+			cost := parent.pendingCost
+			if cost > gas {
+				cost -= gas
+			} else {
+				cost = 0
+			}
+			t.charge(parent, parent.pendingOp, cost)
+			parent.pendingOp, parent.pendingCost = "", 0
+		}
+	}
+	var (
+		label    = bytesToHex(to.Bytes())
+		function string
+	)
+	switch {
+	case op == vm.CREATE || op == vm.CREATE2:
+		function = "create"
+	case slices.Contains(t.activePrecompiles, to):
+		function = "precompile"
+	case len(input) >= 4:
+		function = bytesToHex(input[:4])
+	default:
+		function = "fallback"
+	}
+	label += ":" + function
+
+	path := label
+	if size := len(t.callstack); size > 0 {
+		path = t.callstack[size-1].path + ";" + label
+	}
+	t.callstack = append(t.callstack, &gasProfileFrame{
+		path:     path,
+		code:     to,
+		function: function,
+	})
+}
+
+// OnExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *gasProfileTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.interrupt.Load() {
+		return
+	}
+	size := len(t.callstack)
+	if size == 0 || depth != size-1 {
+		return // Exiting a selfdestruct, which was never pushed
+	}
+	frame := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+
+	// Call opcodes always enter a scope, but be safe in case they didn't
+	if frame.pendingOp != "" {
+		t.charge(frame, frame.pendingOp, frame.pendingCost)
+	}
+	// Charge any gas spent outside of the opcodes to the failing opcode if
+	// there's one, or to the frame itself
+	if gasUsed > frame.charged {
+		t.charge(frame, frame.faultOp, gasUsed-frame.charged)
+	}
+	// Propagate the gas used to the parent, so its own leftovers are computed
+	// correctly on exit
+	if size > 1 {
+		t.callstack[size-2].charged += gasUsed
+	}
+}
+
+// OnOpcode is called before executing an opcode, with its total cost.
+func (t *gasProfileTracer) OnOpcode(pc uint64, opcode byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	if t.interrupt.Load() {
+		return
+	}
+	size := len(t.callstack)
+	if size == 0 {
+		return
+	}
+	frame := t.callstack[size-1]
+	op := vm.OpCode(opcode)
+
+	// Failing opcodes may report a cost above the available gas, leave the
+	// burnt gas to be charged on exit
+	if err != nil {
+		frame.faultOp = op.String()
+		return
+	}
+	switch op {
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		frame.pendingOp, frame.pendingCost = op.String(), cost
+	default:
+		t.charge(frame, op.String(), cost)
+	}
+}
+
+// OnFault is called when an opcode fails during its execution.
+func (t *gasProfileTracer) OnFault(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, depth int, err error) {
+	if t.interrupt.Load() {
+		return
+	}
+	if size := len(t.callstack); size > 0 {
+		t.callstack[size-1].faultOp = vm.OpCode(op).String()
+	}
+}
+
+// OnTxEnd is called after the transaction is executed.
+func (t *gasProfileTracer) OnTxEnd(receipt *types.Receipt, err error) {
+	if t.interrupt.Load() || receipt == nil {
+		return
+	}
+	t.profile.GasUsed = receipt.GasUsed
+	for _, gas := range t.stacks {
+		t.profile.Gas += gas
+	}
+	// The gas used is lower than spent if some of it was refunded, and higher
+	// if the calldata floor price was charged instead of the execution costs
+	switch {
+	case t.profile.Gas > receipt.GasUsed:
+		t.profile.Refund = t.profile.Gas - receipt.GasUsed
+	case t.profile.Gas < receipt.GasUsed:
+		t.stacks[gasProfileFloor] += receipt.GasUsed - t.profile.Gas
+		t.profile.Gas = receipt.GasUsed
+	}
+}
+
+// charge attributes the given gas to an opcode of the frame. An empty opcode
+// charges the frame itself.
+func (t *gasProfileTracer) charge(frame *gasProfileFrame, op string, gas uint64) {
+	frame.charged += gas
+	if gas == 0 {
+		return
+	}
+	stack := frame.path
+	if op != "" {
+		stack += ";" + op
+		t.profile.Opcodes[op] += gas
+	}
+	t.stacks[stack] += gas
+	t.profile.Calls[frame.path] += gas
+	t.profile.Contracts[frame.code] += gas
+	t.profile.Functions[bytesToHex(frame.code.Bytes())+":"+frame.function] += gas
+}
+
+// folded returns the profile as folded stacks, sorted by stack.
+func (t *gasProfileTracer) folded() string {
+	stacks := make([]string, 0, len(t.stacks))
+	for stack := range t.stacks {
+		stacks = append(stacks, stack)
+	}
+	slices.Sort(stacks)
+
+	var b strings.Builder
+	for _, stack := range stacks {
+		fmt.Fprintf(&b, "%s %d\n", stack, t.stacks[stack])
+	}
+	return b.String()
+}
+
+// GetResult returns the json-encoded gas profile, and any error arising from
+// the encoding or forceful termination (via `Stop`).
+func (t *gasProfileTracer) GetResult() (json.RawMessage, error) {
+	var (
+		res []byte
+		err error
+	)
+	if t.config.Format == gasProfileFormatFolded {
+		res, err = json.Marshal(t.folded())
+	} else {
+		res, err = json.Marshal(t.profile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *gasProfileTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/program"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+type gasProfileResult struct {
+	Gas          uint64                    `json:"gas"`
+	GasUsed      uint64                    `json:"gasUsed"`
+	IntrinsicGas uint64                    `json:"intrinsicGas"`
+	Refund       uint64                    `json:"refund"`
+	Contracts    map[common.Address]uint64 `json:"contracts"`
+	Functions    map[string]uint64         `json:"functions"`
+	Opcodes      map[string]uint64         `json:"opcodes"`
+	Calls        map[string]uint64         `json:"calls"`
+}
+
+// hexAddr formats an address the way the gas profiles label the code.
+func hexAddr(addr common.Address) string {
+	return strings.ToLower(addr.Hex())
+}
+
+// runGasProfile executes a call into a contract calling a storage writer, the
+// identity precompile and a failing contract, returning the gas profile in the
+// requested format along with the gas used.
+func runGasProfile(t *testing.T, format string) (json.RawMessage, uint64) {
+	var (
+		caller = common.HexToAddress("0xa000")
+		writer = common.HexToAddress("0xb000")
+		failer = common.HexToAddress("0xc000")
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.SetCode(writer, program.New().Sstore(0, 42).Op(vm.STOP).Bytes())
+	statedb.SetCode(failer, program.New().Op(vm.INVALID).Bytes())
+	statedb.SetCode(caller, program.New().
+		Mstore(common.FromHex("0xdeadbeef"), 0).
+		Call(nil, writer, 0, 0, 4, 0, 0).Op(vm.POP).
+		Call(nil, common.BytesToAddress([]byte{4}), 0, 0, 32, 0, 32).Op(vm.POP).
+		Call(nil, failer, 0, 0, 0, 0, 0).Op(vm.POP).
+		Op(vm.STOP).Bytes())
+
+	config, _ := json.Marshal(map[string]string{"format": format})
+	tracer, err := tracers.DefaultDirectory.New("gasProfileTracer", &tracers.Context{}, config, params.MergedTestChainConfig)
+	require.NoError(t, err)
+
+	cfg := &runtime.Config{
+		ChainConfig: params.MergedTestChainConfig,
+		State:       statedb,
+		GasLimit:    1_000_000,
+		EVMConfig:   vm.Config{Tracer: tracer.Hooks},
+	}
+	_, leftOver, err := runtime.Call(caller, common.FromHex("0x11223344"), cfg)
+	require.NoError(t, err)
+
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+	return res, cfg.GasLimit - leftOver
+}
+
+func TestGasProfileTracer(t *testing.T) {
+	res, used := runGasProfile(t, "json")
+
+	var profile gasProfileResult
+	require.NoError(t, json.Unmarshal(res, &profile))
+
+	var (
+		caller = common.HexToAddress("0xa000")
+		writer = common.HexToAddress("0xb000")
+		failer = common.HexToAddress("0xc000")
+	)
+	require.Equal(t, used, profile.GasUsed)
+	require.Equal(t, used, profile.Gas)
+	require.Zero(t, profile.IntrinsicGas)
+	require.Zero(t, profile.Refund)
+
+	// All gas is accounted for in each of the breakdowns
+	for name, breakdown := range map[string]map[string]uint64{"functions": profile.Functions, "calls": profile.Calls} {
+		var sum uint64
+		for _, gas := range breakdown {
+			sum += gas
+		}
+		require.Equal(t, used, sum, name)
+	}
+	var sum uint64
+	for _, gas := range profile.Contracts {
+		sum += gas
+	}
+	require.Equal(t, used, sum)
+
+	// The storage write includes the cold slot access, two pushes precede it
+	require.Equal(t, uint64(params.SstoreSetGasEIP2200+params.ColdSloadCostEIP2929), profile.Opcodes["SSTORE"])
+	require.Equal(t, uint64(params.SstoreSetGasEIP2200+params.ColdSloadCostEIP2929+6), profile.Contracts[writer])
+	require.Equal(t, profile.Contracts[writer], profile.Functions[hexAddr(writer)+":0xdeadbeef"])
+
+	// The failing contract burns all of its gas on the invalid opcode
+	require.Equal(t, profile.Contracts[failer], profile.Opcodes["INVALID"])
+	require.Greater(t, profile.Contracts[failer], uint64(900_000))
+
+	// The calls themselves only cost the access of the callees
+	require.Less(t, profile.Opcodes["CALL"], uint64(3*params.ColdAccountAccessCostEIP2929))
+	require.Equal(t, uint64(18), profile.Contracts[common.BytesToAddress([]byte{4})])
+	require.Contains(t, profile.Calls, hexAddr(caller)+":0x11223344;"+hexAddr(writer)+":0xdeadbeef")
+}
+
+func TestGasProfileTracerFolded(t *testing.T) {
+	res, used := runGasProfile(t, "folded")
+
+	var folded string
+	require.NoError(t, json.Unmarshal(res, &folded))
+
+	var (
+		sum    uint64
+		stacks = make(map[string]uint64)
+	)
+	for _, line := range strings.Split(strings.TrimSuffix(folded, "\n"), "\n") {
+		idx := strings.LastIndexByte(line, ' ')
+		require.Positive(t, idx, line)
+
+		gas, err := strconv.ParseUint(line[idx+1:], 10, 64)
+		require.NoError(t, err, line)
+		stacks[line[:idx]] = gas
+		sum += gas
+	}
+	require.Equal(t, used, sum)
+
+	var (
+		root   = hexAddr(common.HexToAddress("0xa000")) + ":0x11223344"
+		writer = root + ";" + hexAddr(common.HexToAddress("0xb000")) + ":0xdeadbeef"
+		failer = root + ";" + hexAddr(common.HexToAddress("0xc000")) + ":fallback"
+	)
+	require.Equal(t, uint64(params.SstoreSetGasEIP2200+params.ColdSloadCostEIP2929), stacks[writer+";SSTORE"])
+	require.Contains(t, stacks, failer+";INVALID")
+	require.Contains(t, stacks, root+";"+hexAddr(common.BytesToAddress([]byte{4}))+":precompile")
+	require.Contains(t, stacks, root+";CALL")
+}
+
+func TestGasProfileTracerConfig(t *testing.T) {
+	_, err := tracers.DefaultDirectory.New("gasProfileTracer", &tracers.Context{}, json.RawMessage(`{"format":"svg"}`), params.MainnetChainConfig)
+	require.Error(t, err)
+}
@@ -0,0 +1,332 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("tokenTransferTracer", newTokenTransferTracer, false)
+}
+
+// The kinds of transfers reported by the tokenTransferTracer.
+const (
+	transferKindEther   = "ether"
+	transferKindERC20   = "erc20"
+	transferKindERC721  = "erc721"
+	transferKindERC1155 = "erc1155"
+)
+
+var (
+	// erc20TransferTopic is the event signature of both the ERC-20 and the
+	// ERC-721 Transfer events. The two are distinguished by the number of
+	// indexed parameters: ERC-721 additionally indexes the token id.
+	erc20TransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+	// erc1155TransferSingleTopic and erc1155TransferBatchTopic are the event
+	// signatures of the ERC-1155 transfer events.
+	erc1155TransferSingleTopic = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+	erc1155TransferBatchTopic  = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+
+	// erc1155BatchArgs is the layout of the non-indexed TransferBatch parameters.
+	erc1155BatchArgs = func() abi.Arguments {
+		typ, _ := abi.NewType("uint256[]", "", nil)
+		return abi.Arguments{{Name: "ids", Type: typ}, {Name: "values", Type: typ}}
+	}()
+)
+
+// tokenTransfer is a single value movement observed during the execution of
+// a transaction.
+type tokenTransfer struct {
+	Kind     string          `json:"type"`
+	Token    *common.Address `json:"token,omitempty"`    // Token contract, nil for ether transfers
+	Operator *common.Address `json:"operator,omitempty"` // Operator of ERC-1155 transfers
+	From     common.Address  `json:"from"`
+	To       common.Address  `json:"to"`
+	TokenID  *hexutil.Big    `json:"tokenId,omitempty"` // Token id of ERC-721 and ERC-1155 transfers
+	Value    *hexutil.Big    `json:"value,omitempty"`   // Transferred amount, nil for ERC-721 transfers
+	CallType string          `json:"callType,omitempty"`
+	Depth    int             `json:"depth"` // Call depth at which the transfer took place
+	Frame    int             `json:"frame"` // Index of the source call frame, in callTracer order
+}
+
+// tokenTransferFrame tracks an active call frame.
+type tokenTransferFrame struct {
+	index int // Index of the frame, in the order of entering
+	start int // Number of transfers recorded before entering the frame
+}
+
+// tokenTransferTracer collects the ether transfers, including the ones made by
+// internal calls and selfdestructs, along with the ERC-20, ERC-721 and ERC-1155
+// transfer events emitted during the execution of a transaction. The transfers
+// made within reverted call frames are discarded.
+//
+// The frame of a transfer is the index of the call frame it originates from,
+// counting the call frames in the order they are entered, starting with the
+// top-level call at 0. It matches the pre-order traversal of the callTracer
+// output.
+//
+// Example:
+//
+//	> debug.traceTransaction("0xc9a1ee0b8f8d1bd1fbd4d6a2b4ed5d4b7a6cfbfc6aa2ae2a3cf8b0f7a3c7e9b0", {tracer: "tokenTransferTracer"})
+//	[{
+//	  type: "ether",
+//	  from: "0x5a0b54d5dc17e0aadc383d2db43b0a0d3e029c4c",
+//	  to: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+//	  value: "0xde0b6b3a7640000",
+//	  callType: "CALL",
+//	  depth: 0,
+//	  frame: 0
+//	}, {
+//	  type: "erc20",
+//	  token: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+//	  from: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+//	  to: "0x5a0b54d5dc17e0aadc383d2db43b0a0d3e029c4c",
+//	  value: "0x3b9aca00",
+//	  depth: 0,
+//	  frame: 0
+//	}]
+type tokenTransferTracer struct {
+	transfers []tokenTransfer
+	callstack []tokenTransferFrame
+	frames    int         // Number of frames entered so far
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+}
+
+// newTokenTransferTracer returns a native go tracer which collects the value
+// and token transfers of a tx.
+func newTokenTransferTracer(ctx *tracers.Context, cfg json.RawMessage, chainConfig *params.ChainConfig) (*tracers.Tracer, error) {
+	t := &tokenTransferTracer{
+		transfers: make([]tokenTransfer, 0),
+	}
+	return &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxEnd: t.OnTxEnd,
+			OnEnter: t.OnEnter,
+			OnExit:  t.OnExit,
+			OnLog:   t.OnLog,
+		},
+		GetResult: t.GetResult,
+		Stop:      t.Stop,
+	}, nil
+}
+
+// OnEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *tokenTransferTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// Skip if tracing was interrupted
+	if t.interrupt.Load() {
+		return
+	}
+	frame := tokenTransferFrame{index: t.frames, start: len(t.transfers)}
+	t.frames++
+
+	// Selfdestruct doesn't open a new scope, the transfer is made on behalf
+	// of the current frame.
+	op := vm.OpCode(typ)
+	if op == vm.SELFDESTRUCT {
+		if value != nil && value.Sign() > 0 && len(t.callstack) > 0 {
+			t.addEther(op, from, to, value, depth-1, t.callstack[len(t.callstack)-1].index)
+		}
+		return
+	}
+	t.callstack = append(t.callstack, frame)
+
+	// Delegate and static calls carry no value, the value of callcode is
+	// sent back to the caller itself.
+	switch op {
+	case vm.DELEGATECALL, vm.STATICCALL, vm.CALLCODE:
+		return
+	}
+	if value != nil && value.Sign() > 0 {
+		t.addEther(op, from, to, value, depth, frame.index)
+	}
+}
+
+// OnExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *tokenTransferTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.interrupt.Load() {
+		return
+	}
+	size := len(t.callstack)
+	if size == 0 {
+		return
+	}
+	// Selfdestructs are not pushed to the callstack, match the depth to tell
+	// whether the exited scope is the active frame.
+	if depth != size-1 {
+		return
+	}
+	frame := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+
+	// Discard all the transfers made within the reverted frame, including
+	// the value transfer of the frame itself.
+	if reverted {
+		t.transfers = t.transfers[:frame.start]
+	}
+}
+
+// OnTxEnd is called after the transaction is executed.
+func (t *tokenTransferTracer) OnTxEnd(receipt *types.Receipt, err error) {
+	// Error happened during tx validation.
+	if err != nil {
+		return
+	}
+	if receipt != nil && receipt.Status == types.ReceiptStatusFailed {
+		t.transfers = t.transfers[:0]
+	}
+}
+
+// OnLog is called when a log is emitted, decoding the token transfer events.
+func (t *tokenTransferTracer) OnLog(log *types.Log) {
+	if t.interrupt.Load() || len(t.callstack) == 0 || len(log.Topics) == 0 {
+		return
+	}
+	var (
+		token = log.Address
+		depth = len(t.callstack) - 1
+		frame = t.callstack[depth].index
+	)
+	switch log.Topics[0] {
+	case erc20TransferTopic:
+		switch {
+		case len(log.Topics) == 3 && len(log.Data) == 32:
+			t.transfers = append(t.transfers, tokenTransfer{
+				Kind:  transferKindERC20,
+				Token: &token,
+				From:  common.BytesToAddress(log.Topics[1].Bytes()),
+				To:    common.BytesToAddress(log.Topics[2].Bytes()),
+				Value: (*hexutil.Big)(new(big.Int).SetBytes(log.Data)),
+				Depth: depth,
+				Frame: frame,
+			})
+		case len(log.Topics) == 4 && len(log.Data) == 0:
+			t.transfers = append(t.transfers, tokenTransfer{
+				Kind:    transferKindERC721,
+				Token:   &token,
+				From:    common.BytesToAddress(log.Topics[1].Bytes()),
+				To:      common.BytesToAddress(log.Topics[2].Bytes()),
+				TokenID: (*hexutil.Big)(log.Topics[3].Big()),
+				Depth:   depth,
+				Frame:   frame,
+			})
+		}
+
+	case erc1155TransferSingleTopic:
+		if len(log.Topics) != 4 || len(log.Data) != 64 {
+			return
+		}
+		operator := common.BytesToAddress(log.Topics[1].Bytes())
+		t.transfers = append(t.transfers, tokenTransfer{
+			Kind:     transferKindERC1155,
+			Token:    &token,
+			Operator: &operator,
+			From:     common.BytesToAddress(log.Topics[2].Bytes()),
+			To:       common.BytesToAddress(log.Topics[3].Bytes()),
+			TokenID:  (*hexutil.Big)(new(big.Int).SetBytes(log.Data[:32])),
+			Value:    (*hexutil.Big)(new(big.Int).SetBytes(log.Data[32:])),
+			Depth:    depth,
+			Frame:    frame,
+		})
+
+	case erc1155TransferBatchTopic:
+		if len(log.Topics) != 4 {
+			return
+		}
+		ids, values, err := unpackTransferBatch(log.Data)
+		if err != nil {
+			return
+		}
+		operator := common.BytesToAddress(log.Topics[1].Bytes())
+		for i := range ids {
+			t.transfers = append(t.transfers, tokenTransfer{
+				Kind:     transferKindERC1155,
+				Token:    &token,
+				Operator: &operator,
+				From:     common.BytesToAddress(log.Topics[2].Bytes()),
+				To:       common.BytesToAddress(log.Topics[3].Bytes()),
+				TokenID:  (*hexutil.Big)(ids[i]),
+				Value:    (*hexutil.Big)(values[i]),
+				Depth:    depth,
+				Frame:    frame,
+			})
+		}
+	}
+}
+
+// GetResult returns the json-encoded list of transfers, and any error arising
+// from the encoding or forceful termination (via `Stop`).
+func (t *tokenTransferTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.transfers)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *tokenTransferTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
+
+// addEther records an ether transfer.
+func (t *tokenTransferTracer) addEther(op vm.OpCode, from, to common.Address, value *big.Int, depth int, frame int) {
+	t.transfers = append(t.transfers, tokenTransfer{
+		Kind:     transferKindEther,
+		From:     from,
+		To:       to,
+		Value:    (*hexutil.Big)(new(big.Int).Set(value)),
+		CallType: op.String(),
+		Depth:    depth,
+		Frame:    frame,
+	})
+}
+
+// unpackTransferBatch decodes the token ids and amounts of an ERC-1155
+// TransferBatch event.
+func unpackTransferBatch(data []byte) ([]*big.Int, []*big.Int, error) {
+	unpacked, err := erc1155BatchArgs.Unpack(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids, ok := unpacked[0].([]*big.Int)
+	if !ok {
+		return nil, nil, errors.New("invalid token ids")
+	}
+	values, ok := unpacked[1].([]*big.Int)
+	if !ok || len(values) != len(ids) {
+		return nil, nil, errors.New("invalid token amounts")
+	}
+	return ids, values, nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+type tokenTransferResult struct {
+	Type     string          `json:"type"`
+	Token    *common.Address `json:"token"`
+	Operator *common.Address `json:"operator"`
+	From     common.Address  `json:"from"`
+	To       common.Address  `json:"to"`
+	TokenID  string          `json:"tokenId"`
+	Value    string          `json:"value"`
+	CallType string          `json:"callType"`
+	Depth    int             `json:"depth"`
+	Frame    int             `json:"frame"`
+}
+
+func TestTokenTransferTracer(t *testing.T) {
+	tracer, err := tracers.DefaultDirectory.New("tokenTransferTracer", &tracers.Context{}, nil, params.MainnetChainConfig)
+	require.NoError(t, err)
+
+	var (
+		sender   = common.HexToAddress("0x1000")
+		router   = common.HexToAddress("0x2000")
+		token    = common.HexToAddress("0x3000")
+		nft      = common.HexToAddress("0x4000")
+		multi    = common.HexToAddress("0x5000")
+		transfer = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+		single   = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+		batch    = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+	)
+
+	// Frame 0: top-level call with value
+	tracer.OnEnter(0, byte(vm.CALL), sender, router, nil, 100000, big.NewInt(100))
+
+	// Frame 1: ERC-20 transfer
+	tracer.OnEnter(1, byte(vm.CALL), router, token, nil, 50000, big.NewInt(0))
+	tracer.OnLog(&types.Log{
+		Address: token,
+		Topics:  []common.Hash{transfer, common.BytesToHash(router.Bytes()), common.BytesToHash(sender.Bytes())},
+		Data:    common.LeftPadBytes([]byte{0x2a}, 32),
+	})
+	tracer.OnExit(1, nil, 1000, nil, false)
+
+	// Frame 2: reverted call carrying value and emitting an ERC-721 transfer
+	tracer.OnEnter(1, byte(vm.CALL), router, nft, nil, 50000, big.NewInt(5))
+	tracer.OnLog(&types.Log{
+		Address: nft,
+		Topics:  []common.Hash{transfer, common.BytesToHash(router.Bytes()), common.BytesToHash(sender.Bytes()), common.BigToHash(big.NewInt(7))},
+	})
+	tracer.OnExit(1, nil, 1000, vm.ErrExecutionReverted, true)
+
+	// Frame 3: ERC-721 and ERC-1155 transfers, delegate call without value
+	tracer.OnEnter(1, byte(vm.DELEGATECALL), router, multi, nil, 50000, big.NewInt(100))
+	tracer.OnLog(&types.Log{
+		Address: nft,
+		Topics:  []common.Hash{transfer, common.BytesToHash(router.Bytes()), common.BytesToHash(sender.Bytes()), common.BigToHash(big.NewInt(8))},
+	})
+	tracer.OnLog(&types.Log{
+		Address: multi,
+		Topics:  []common.Hash{single, common.BytesToHash(router.Bytes()), common.BytesToHash(router.Bytes()), common.BytesToHash(sender.Bytes())},
+		Data:    append(common.LeftPadBytes([]byte{0x1}, 32), common.LeftPadBytes([]byte{0x2}, 32)...),
+	})
+	tracer.OnLog(&types.Log{
+		Address: multi,
+		Topics:  []common.Hash{batch, common.BytesToHash(router.Bytes()), common.BytesToHash(router.Bytes()), common.BytesToHash(sender.Bytes())},
+		Data: common.FromHex("0x" +
+			"0000000000000000000000000000000000000000000000000000000000000040" +
+			"00000000000000000000000000000000000000000000000000000000000000a0" +
+			"0000000000000000000000000000000000000000000000000000000000000002" +
+			"0000000000000000000000000000000000000000000000000000000000000003" +
+			"0000000000000000000000000000000000000000000000000000000000000004" +
+			"0000000000000000000000000000000000000000000000000000000000000002" +
+			"0000000000000000000000000000000000000000000000000000000000000005" +
+			"0000000000000000000000000000000000000000000000000000000000000006"),
+	})
+	tracer.OnExit(1, nil, 1000, nil, false)
+
+	// Frame 4: selfdestruct of the top-level contract
+	tracer.OnEnter(1, byte(vm.SELFDESTRUCT), router, sender, nil, 0, big.NewInt(95))
+	tracer.OnExit(1, nil, 0, nil, false)
+
+	tracer.OnExit(0, nil, 10000, nil, false)
+	tracer.OnTxEnd(&types.Receipt{Status: types.ReceiptStatusSuccessful}, nil)
+
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+
+	var have []tokenTransferResult
+	require.NoError(t, json.Unmarshal(res, &have))
+
+	want := []tokenTransferResult{
+		{Type: "ether", From: sender, To: router, Value: "0x64", CallType: "CALL", Depth: 0, Frame: 0},
+		{Type: "erc20", Token: &token, From: router, To: sender, Value: "0x2a", Depth: 1, Frame: 1},
+		{Type: "erc721", Token: &nft, From: router, To: sender, TokenID: "0x8", Depth: 1, Frame: 3},
+		{Type: "erc1155", Token: &multi, Operator: &router, From: router, To: sender, TokenID: "0x1", Value: "0x2", Depth: 1, Frame: 3},
+		{Type: "erc1155", Token: &multi, Operator: &router, From: router, To: sender, TokenID: "0x3", Value: "0x5", Depth: 1, Frame: 3},
+		{Type: "erc1155", Token: &multi, Operator: &router, From: router, To: sender, TokenID: "0x4", Value: "0x6", Depth: 1, Frame: 3},
+		{Type: "ether", From: router, To: sender, Value: "0x5f", CallType: "SELFDESTRUCT", Depth: 0, Frame: 0},
+	}
+	require.Equal(t, want, have)
+}
+
+func TestTokenTransferTracerFailedTx(t *testing.T) {
+	tracer, err := tracers.DefaultDirectory.New("tokenTransferTracer", &tracers.Context{}, nil, params.MainnetChainConfig)
+	require.NoError(t, err)
+
+	var (
+		sender = common.HexToAddress("0x1000")
+		router = common.HexToAddress("0x2000")
+	)
+	tracer.OnEnter(0, byte(vm.CALL), sender, router, nil, 100000, big.NewInt(100))
+	tracer.OnEnter(1, byte(vm.CALL), router, sender, nil, 50000, big.NewInt(50))
+	tracer.OnExit(1, nil, 1000, nil, false)
+	tracer.OnExit(0, nil, 10000, vm.ErrExecutionReverted, true)
+	tracer.OnTxEnd(&types.Receipt{Status: types.ReceiptStatusFailed}, nil)
+
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+	require.JSONEq(t, "[]", string(res))
+}
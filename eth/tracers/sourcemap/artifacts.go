@@ -0,0 +1,493 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package sourcemap
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// solcInput is the subset of the solc standard-json input carrying the
+// contents of the compiled sources.
+type solcInput struct {
+	Sources map[string]struct {
+		Content string `json:"content"`
+	} `json:"sources"`
+}
+
+// solcOutput is the subset of the solc standard-json output needed to map the
+// execution of the compiled contracts back to their sources.
+type solcOutput struct {
+	Sources map[string]struct {
+		ID  int             `json:"id"`
+		AST json.RawMessage `json:"ast"`
+	} `json:"sources"`
+	Contracts map[string]map[string]struct {
+		ABI json.RawMessage `json:"abi"`
+		EVM struct {
+			Bytecode         solcBytecode `json:"bytecode"`
+			DeployedBytecode solcBytecode `json:"deployedBytecode"`
+		} `json:"evm"`
+	} `json:"contracts"`
+}
+
+// solcBytecode is the code of a compiled contract, along with its source map
+// and the ranges filled in only at link or deployment time.
+type solcBytecode struct {
+	Object              string                                `json:"object"`
+	SourceMap           string                                `json:"sourceMap"`
+	LinkReferences      map[string]map[string][]solcCodeRange `json:"linkReferences"`
+	ImmutableReferences map[string][]solcCodeRange            `json:"immutableReferences"`
+}
+
+// solcCodeRange is a byte range within a compiled code.
+type solcCodeRange struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// program is a compiled code along with the mapping of its instructions to
+// the sources.
+type program struct {
+	code    []byte
+	mask    []bool  // Bytes not known at compile time, e.g. library addresses and immutables
+	entries []entry // Source map entries, indexed by instruction
+	indexes []int   // Instruction indexes, indexed by program counter
+}
+
+// newProgram decodes a compiled code and its source map.
+func newProgram(bytecode *solcBytecode) (*program, error) {
+	// Unlinked library addresses are marked by placeholders in the hex code,
+	// zero them out and mask them along with the immutables
+	object := []byte(strings.TrimPrefix(bytecode.Object, "0x"))
+	for i, c := range object {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			object[i] = '0'
+		}
+	}
+	code := make([]byte, hex.DecodedLen(len(object)))
+	if _, err := hex.Decode(code, object); err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return nil, nil // Abstract contract or interface
+	}
+	p := &program{
+		code:    code,
+		mask:    make([]bool, len(code)),
+		indexes: instructionIndexes(code),
+	}
+	masked := func(r solcCodeRange) {
+		for i := r.Start; i < r.Start+r.Length && i < len(p.mask); i++ {
+			p.mask[i] = true
+		}
+	}
+	for _, libs := range bytecode.LinkReferences {
+		for _, ranges := range libs {
+			for _, r := range ranges {
+				masked(r)
+			}
+		}
+	}
+	for _, ranges := range bytecode.ImmutableReferences {
+		for _, r := range ranges {
+			masked(r)
+		}
+	}
+	var err error
+	if p.entries, err = decodeSourceMap(bytecode.SourceMap); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// matches reports whether the given code was compiled from the program. If
+// prefix is set, the code may be followed by extra data, e.g. the constructor
+// arguments of a creation code.
+func (p *program) matches(code []byte, prefix bool) bool {
+	if len(code) < len(p.code) || (!prefix && len(code) != len(p.code)) {
+		return false
+	}
+	for i, b := range p.code {
+		if b != code[i] && !p.mask[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// entry returns the source map entry of the instruction at the given program
+// counter, or nil if it's not an instruction of the program.
+func (p *program) entry(pc uint64) *entry {
+	if pc >= uint64(len(p.indexes)) {
+		return nil
+	}
+	index := p.indexes[pc]
+	if index < 0 || index >= len(p.entries) {
+		return nil
+	}
+	return &p.entries[index]
+}
+
+// span is a source range defining a named scope, e.g. a function.
+type span struct {
+	start, end int
+	name       string
+}
+
+// sourceFile is a source file of the compilation.
+type sourceFile struct {
+	path  string
+	lines []int  // Byte offsets of the line starts, empty if the content is unknown
+	spans []span // Contract, function and modifier definitions
+}
+
+// position returns the 1-based line and column of the byte offset, or zeroes
+// if the content of the file is unknown.
+func (f *sourceFile) position(offset int) (int, int) {
+	if len(f.lines) == 0 {
+		return 0, 0
+	}
+	line := sort.Search(len(f.lines), func(i int) bool { return f.lines[i] > offset })
+	return line, offset - f.lines[line-1] + 1
+}
+
+// scope returns the name of the innermost definition enclosing the offset.
+func (f *sourceFile) scope(offset int) string {
+	var best *span
+	for i := range f.spans {
+		s := &f.spans[i]
+		if s.start <= offset && offset < s.end && (best == nil || s.end-s.start < best.end-best.start) {
+			best = s
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.name
+}
+
+// collectSpans walks a solc compact AST, gathering the source ranges of the
+// contract, function and modifier definitions.
+func (f *sourceFile) collectSpans(node any, contract string) {
+	switch n := node.(type) {
+	case map[string]any:
+		typ, _ := n["nodeType"].(string)
+		name, _ := n["name"].(string)
+		switch typ {
+		case "ContractDefinition":
+			contract = name
+			f.addSpan(n["src"], name)
+		case "FunctionDefinition", "ModifierDefinition":
+			if name == "" {
+				name, _ = n["kind"].(string) // constructor, fallback or receive
+			}
+			if contract != "" {
+				name = contract + "." + name
+			}
+			f.addSpan(n["src"], name)
+		}
+		for _, child := range n {
+			f.collectSpans(child, contract)
+		}
+	case []any:
+		for _, child := range n {
+			f.collectSpans(child, contract)
+		}
+	}
+}
+
+// addSpan adds a named definition spanning the given solc source range.
+func (f *sourceFile) addSpan(src any, name string) {
+	s, _ := src.(string)
+	fields := strings.Split(s, ":")
+	if len(fields) < 2 {
+		return
+	}
+	start, err1 := strconv.Atoi(fields[0])
+	length, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil || start < 0 {
+		return
+	}
+	f.spans = append(f.spans, span{start: start, end: start + length, name: name})
+}
+
+// Artifacts are the compiler outputs of a set of contracts, used to map their
+// execution back to the sources.
+type Artifacts struct {
+	contracts []*Contract
+	files     map[int]*sourceFile
+}
+
+// Parse creates the source mapping artifacts from the solc standard-json
+// output of a compilation. The standard-json input is optional, without it
+// source locations are reported without lines and columns.
+func Parse(input, output []byte) (*Artifacts, error) {
+	var out solcOutput
+	if err := json.Unmarshal(output, &out); err != nil {
+		return nil, fmt.Errorf("invalid solc output: %v", err)
+	}
+	var in solcInput
+	if len(input) > 0 {
+		if err := json.Unmarshal(input, &in); err != nil {
+			return nil, fmt.Errorf("invalid solc input: %v", err)
+		}
+	}
+	a := &Artifacts{files: make(map[int]*sourceFile)}
+	for path, source := range out.Sources {
+		file := &sourceFile{path: path}
+		if src, ok := in.Sources[path]; ok {
+			file.lines = []int{0}
+			for i, c := range []byte(src.Content) {
+				if c == '\n' {
+					file.lines = append(file.lines, i+1)
+				}
+			}
+		}
+		if len(source.AST) > 0 {
+			var ast any
+			if err := json.Unmarshal(source.AST, &ast); err != nil {
+				return nil, fmt.Errorf("invalid AST of %s: %v", path, err)
+			}
+			file.collectSpans(ast, "")
+		}
+		a.files[source.ID] = file
+	}
+	for path, contracts := range out.Contracts {
+		for name, compiled := range contracts {
+			c := &Contract{Name: name, File: path, artifacts: a}
+			if len(compiled.ABI) > 0 {
+				if err := json.Unmarshal(compiled.ABI, &c.abi); err != nil {
+					return nil, fmt.Errorf("invalid ABI of %s:%s: %v", path, name, err)
+				}
+			}
+			var err error
+			if c.runtime, err = newProgram(&compiled.EVM.DeployedBytecode); err != nil {
+				return nil, fmt.Errorf("invalid runtime code of %s:%s: %v", path, name, err)
+			}
+			if c.creation, err = newProgram(&compiled.EVM.Bytecode); err != nil {
+				return nil, fmt.Errorf("invalid creation code of %s:%s: %v", path, name, err)
+			}
+			if c.runtime != nil || c.creation != nil {
+				a.contracts = append(a.contracts, c)
+			}
+		}
+	}
+	if len(a.contracts) == 0 {
+		return nil, errors.New("no compiled contracts in solc output")
+	}
+	// Lookups return the first match, make them deterministic
+	sort.Slice(a.contracts, func(i, j int) bool {
+		if a.contracts[i].File != a.contracts[j].File {
+			return a.contracts[i].File < a.contracts[j].File
+		}
+		return a.contracts[i].Name < a.contracts[j].Name
+	})
+	return a, nil
+}
+
+// UnmarshalJSON parses the artifacts from a JSON object with an "output" field
+// holding the solc standard-json output and an optional "input" field holding
+// the standard-json input.
+func (a *Artifacts) UnmarshalJSON(data []byte) error {
+	var enc struct {
+		Input  json.RawMessage `json:"input"`
+		Output json.RawMessage `json:"output"`
+	}
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return err
+	}
+	if len(enc.Output) == 0 {
+		return errors.New("missing solc output")
+	}
+	parsed, err := Parse(enc.Input, enc.Output)
+	if err != nil {
+		return err
+	}
+	*a = *parsed
+	return nil
+}
+
+// Lookup returns the contract the given code was compiled from, or nil if it
+// is unknown. Creation codes may be followed by the constructor arguments.
+func (a *Artifacts) Lookup(code []byte, create bool) *Contract {
+	for _, c := range a.contracts {
+		p := c.runtime
+		if create {
+			p = c.creation
+		}
+		if p != nil && p.matches(code, create) {
+			return c
+		}
+	}
+	return nil
+}
+
+// Contract is a compiled contract of the artifacts.
+type Contract struct {
+	Name string // Name of the contract
+	File string // Path of the source file defining the contract
+
+	abi       abi.ABI
+	runtime   *program
+	creation  *program
+	artifacts *Artifacts
+}
+
+// Location is a position within the Solidity sources.
+type Location struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`     // 1-based line, zero if the source content is unknown
+	Column   int    `json:"column,omitempty"`   // 1-based byte column, zero if the source content is unknown
+	Function string `json:"function,omitempty"` // Enclosing function or modifier, qualified by the contract
+}
+
+// String implements fmt.Stringer, formatting the location the way stack traces
+// usually do.
+func (l *Location) String() string {
+	pos := l.File
+	if l.Line > 0 {
+		pos = fmt.Sprintf("%s:%d:%d", l.File, l.Line, l.Column)
+	}
+	if l.Function == "" {
+		return pos
+	}
+	return fmt.Sprintf("%s (%s)", l.Function, pos)
+}
+
+// Locate returns the source location of the instruction at the given program
+// counter, or nil if the instruction doesn't originate from a known source.
+func (c *Contract) Locate(pc uint64, create bool) *Location {
+	p := c.runtime
+	if create {
+		p = c.creation
+	}
+	if p == nil {
+		return nil
+	}
+	e := p.entry(pc)
+	if e == nil || e.file < 0 {
+		return nil
+	}
+	file := c.artifacts.files[e.file]
+	if file == nil {
+		return nil // Compiler generated source
+	}
+	line, column := file.position(e.start)
+	return &Location{
+		File:     file.path,
+		Line:     line,
+		Column:   column,
+		Function: file.scope(e.start),
+	}
+}
+
+// errorSelector and panicSelector are the selectors of the builtin Solidity
+// revert errors.
+var (
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// DecodeRevert formats the revert data returned by the contract, decoding the
+// builtin Error and Panic errors as well as the custom errors defined in any
+// of the contracts of the artifacts. The contract may be nil if the reverting
+// code is unknown.
+func (a *Artifacts) DecodeRevert(c *Contract, data []byte) string {
+	if len(data) == 0 {
+		return "reverted without reason"
+	}
+	if len(data) >= 4 {
+		switch {
+		case bytes.Equal(data[:4], errorSelector):
+			if reason, err := abi.UnpackRevert(data); err == nil {
+				return fmt.Sprintf("Error(%q)", reason)
+			}
+		case bytes.Equal(data[:4], panicSelector):
+			if reason, err := abi.UnpackRevert(data); err == nil {
+				return fmt.Sprintf("Panic(%s)", reason)
+			}
+		default:
+			// Custom errors are looked up in the reverting contract first, as
+			// the selectors of different errors may collide
+			contracts := a.contracts
+			if c != nil {
+				contracts = append([]*Contract{c}, contracts...)
+			}
+			for _, contract := range contracts {
+				if decoded, ok := decodeCustomError(&contract.abi, data); ok {
+					return decoded
+				}
+			}
+		}
+	}
+	return fmt.Sprintf("reverted with data %#x", data)
+}
+
+// decodeCustomError decodes the revert data as a custom error of the ABI.
+func decodeCustomError(contract *abi.ABI, data []byte) (string, bool) {
+	errABI, err := contract.ErrorByID([4]byte(data[:4]))
+	if err != nil {
+		return "", false
+	}
+	values, err := errABI.Inputs.Unpack(data[4:])
+	if err != nil {
+		return "", false
+	}
+	args := make([]string, len(values))
+	for i, value := range values {
+		var formatted string
+		switch v := value.(type) {
+		case []byte:
+			formatted = fmt.Sprintf("%#x", v)
+		case fmt.Stringer:
+			formatted = v.String()
+		default:
+			formatted = fmt.Sprintf("%v", v)
+		}
+		if name := errABI.Inputs[i].Name; name != "" {
+			formatted = name + ": " + formatted
+		}
+		args[i] = formatted
+	}
+	return fmt.Sprintf("%s(%s)", errABI.Name, strings.Join(args, ", ")), true
+}
+
+// StackTrace is a Solidity level stack trace of a reverted call.
+type StackTrace struct {
+	Reason string   `json:"reason"` // Decoded revert reason of the innermost frame
+	Frames []string `json:"frames"` // Locations of the reverted frames, innermost first
+}
+
+// String implements fmt.Stringer, formatting the stack trace the way
+// JavaScript engines do.
+func (st *StackTrace) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Error: %s\n", st.Reason)
+	for _, frame := range st.Frames {
+		fmt.Fprintf(&b, "    at %s\n", frame)
+	}
+	return b.String()
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package sourcemap maps EVM execution back to Solidity sources, using the
+// compiler artifacts produced by solc in standard-json mode.
+package sourcemap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// entry is a decoded item of a solc source map, describing the source range an
+// instruction was generated from.
+type entry struct {
+	start  int  // Byte offset of the range in the source file
+	length int  // Byte length of the range
+	file   int  // Index of the source file, -1 if the instruction has no source
+	jump   byte // Jump type: 'i' into a function, 'o' out of it, '-' regular
+}
+
+// decodeSourceMap decodes a compressed solc source map, in which the items are
+// separated by semicolons and the fields of an item by colons. Empty and
+// missing fields take the value of the previous item.
+func decodeSourceMap(srcmap string) ([]entry, error) {
+	if srcmap == "" {
+		return nil, nil
+	}
+	var (
+		items   = strings.Split(srcmap, ";")
+		entries = make([]entry, len(items))
+		prev    = entry{file: -1, jump: '-'}
+	)
+	for i, item := range items {
+		cur := prev
+		for j, field := range strings.Split(item, ":") {
+			if field == "" {
+				continue
+			}
+			var err error
+			switch j {
+			case 0:
+				cur.start, err = strconv.Atoi(field)
+			case 1:
+				cur.length, err = strconv.Atoi(field)
+			case 2:
+				cur.file, err = strconv.Atoi(field)
+			case 3:
+				if len(field) != 1 {
+					err = fmt.Errorf("invalid jump type %q", field)
+				}
+				cur.jump = field[0]
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid source map item %d: %v", i, err)
+			}
+		}
+		entries[i] = cur
+		prev = cur
+	}
+	return entries, nil
+}
+
+// instructionIndexes maps each program counter of the code to the index of the
+// instruction starting at it, or -1 if it points into push data.
+func instructionIndexes(code []byte) []int {
+	indexes := make([]int, len(code))
+	for pc, index := 0, 0; pc < len(code); index++ {
+		indexes[pc] = index
+
+		next := pc + 1
+		if op := vm.OpCode(code[pc]); op >= vm.PUSH1 && op <= vm.PUSH32 {
+			next += int(op - vm.PUSH0)
+		}
+		for pc++; pc < next && pc < len(code); pc++ {
+			indexes[pc] = -1
+		}
+	}
+	return indexes
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package sourcemap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Runtime codes of the contracts in the testdata artifacts. The caller embeds
+// the address of the callee as an immutable.
+var (
+	calleeCode = common.FromHex("0x7fe8620800000000000000000000000000000000000000000000000000000000006000527c0100000000000000000000000000000000000000000000000000000000602052600060405360006041536000604253600560435360446000fd")
+	callerCode = common.FromHex("0x60008080808073ca11ee00000000000000000000000000000000015af1503d600060003e3d6000fd")
+)
+
+func loadTestArtifacts(t *testing.T, withSources bool) *Artifacts {
+	t.Helper()
+
+	output, err := os.ReadFile("testdata/solc-output.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var input []byte
+	if withSources {
+		if input, err = os.ReadFile("testdata/solc-input.json"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	artifacts, err := Parse(input, output)
+	if err != nil {
+		t.Fatalf("failed to parse artifacts: %v", err)
+	}
+	return artifacts
+}
+
+func TestDecodeSourceMap(t *testing.T) {
+	entries, err := decodeSourceMap("1:2:0:-;:3;;4::1:i;::-1:o;5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []entry{
+		{1, 2, 0, '-'},
+		{1, 3, 0, '-'},
+		{1, 3, 0, '-'},
+		{4, 3, 1, 'i'},
+		{4, 3, -1, 'o'},
+		{5, 3, -1, 'o'},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("entries mismatch:\nhave %+v\nwant %+v", entries, want)
+	}
+	for _, srcmap := range []string{"1:x", "1:2:0:io"} {
+		if _, err := decodeSourceMap(srcmap); err == nil {
+			t.Errorf("invalid source map %q accepted", srcmap)
+		}
+	}
+}
+
+func TestInstructionIndexes(t *testing.T) {
+	code := []byte{byte(vm.PUSH2), 0xaa, 0xbb, byte(vm.ADD), byte(vm.PUSH1), 0x01, byte(vm.PUSH32), 0x01}
+	want := []int{0, -1, -1, 1, 2, -1, 3, -1}
+	if have := instructionIndexes(code); !reflect.DeepEqual(have, want) {
+		t.Fatalf("indexes mismatch: have %v, want %v", have, want)
+	}
+}
+
+func TestLookupAndLocate(t *testing.T) {
+	artifacts := loadTestArtifacts(t, true)
+
+	callee := artifacts.Lookup(calleeCode, false)
+	if callee == nil || callee.Name != "Callee" || callee.File != "test.sol" {
+		t.Fatalf("callee not found: %+v", callee)
+	}
+	// Immutables are filled in at deployment, they must not affect the lookup
+	code := common.CopyBytes(callerCode)
+	copy(code[7:27], common.HexToAddress("0x1234").Bytes())
+	if caller := artifacts.Lookup(code, false); caller == nil || caller.Name != "Caller" {
+		t.Fatalf("caller with different immutable not found: %+v", caller)
+	}
+	// Creation codes may be followed by constructor arguments, runtime codes not
+	var create []byte
+	for _, c := range artifacts.contracts {
+		if c.Name == "Callee" {
+			create = append(common.CopyBytes(c.creation.code), make([]byte, 32)...)
+		}
+	}
+	if c := artifacts.Lookup(create, true); c != callee {
+		t.Fatalf("creation code not found: %+v", c)
+	}
+	if c := artifacts.Lookup(append(common.CopyBytes(calleeCode), 0), false); c != nil {
+		t.Fatalf("runtime code with trailing data found: %+v", c)
+	}
+	if c := artifacts.Lookup(calleeCode, true); c != nil {
+		t.Fatalf("runtime code found as creation code: %+v", c)
+	}
+	// The dispatching instruction maps to the function, the revert to its statement
+	for _, tt := range []struct {
+		pc   uint64
+		want *Location
+	}{
+		{0, &Location{File: "test.sol", Line: 7, Column: 5, Function: "Callee.fail"}},
+		{uint64(len(calleeCode) - 1), &Location{File: "test.sol", Line: 8, Column: 9, Function: "Callee.fail"}},
+		{1, nil}, // Push data
+		{uint64(len(calleeCode)), nil},
+	} {
+		if have := callee.Locate(tt.pc, false); !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("pc %d: location mismatch: have %v, want %v", tt.pc, have, tt.want)
+		}
+	}
+	if have, want := callee.Locate(uint64(len(calleeCode)-1), false).String(), "Callee.fail (test.sol:8:9)"; have != want {
+		t.Errorf("formatted location mismatch: have %q, want %q", have, want)
+	}
+	// Without the sources, the lines and columns are unknown
+	callee = loadTestArtifacts(t, false).Lookup(calleeCode, false)
+	if have, want := callee.Locate(0, false).String(), "Callee.fail (test.sol)"; have != want {
+		t.Errorf("formatted location mismatch: have %q, want %q", have, want)
+	}
+}
+
+func TestDecodeRevert(t *testing.T) {
+	artifacts := loadTestArtifacts(t, false)
+	callee := artifacts.Lookup(calleeCode, false)
+
+	custom := append(common.FromHex("0xe8620800"), append(common.LeftPadBytes([]byte{1}, 32), common.LeftPadBytes([]byte{5}, 32)...)...)
+	for _, tt := range []struct {
+		contract *Contract
+		data     []byte
+		want     string
+	}{
+		{callee, nil, "reverted without reason"},
+		{callee, common.FromHex("0x08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000036e6f700000000000000000000000000000000000000000000000000000000000"), `Error("nop")`},
+		{callee, common.FromHex("0x4e487b710000000000000000000000000000000000000000000000000000000000000011"), "Panic(arithmetic underflow or overflow)"},
+		{callee, custom, "Insufficient(available: 1, required: 5)"},
+		{nil, custom, "Insufficient(available: 1, required: 5)"},
+		{callee, custom[:36], fmt.Sprintf("reverted with data %#x", custom[:36])},
+		{callee, common.FromHex("0xdeadbeef"), "reverted with data 0xdeadbeef"},
+	} {
+		if have := artifacts.DecodeRevert(tt.contract, tt.data); have != tt.want {
+			t.Errorf("revert %x: reason mismatch: have %q, want %q", tt.data, have, tt.want)
+		}
+	}
+}
+
+func TestArtifactsJSON(t *testing.T) {
+	input, _ := os.ReadFile("testdata/solc-input.json")
+	output, _ := os.ReadFile("testdata/solc-output.json")
+
+	var artifacts Artifacts
+	if err := json.Unmarshal([]byte(fmt.Sprintf(`{"input":%s,"output":%s}`, input, output)), &artifacts); err != nil {
+		t.Fatalf("failed to unmarshal artifacts: %v", err)
+	}
+	if c := artifacts.Lookup(callerCode, false); c == nil || c.Locate(0, false).Line != 19 {
+		t.Fatalf("caller not located: %+v", c)
+	}
+	if err := json.Unmarshal([]byte(`{"input":{}}`), &artifacts); err == nil {
+		t.Fatal("artifacts without output accepted")
+	}
+	if err := json.Unmarshal([]byte(`{"output":{"contracts":{}}}`), &artifacts); err == nil {
+		t.Fatal("artifacts without contracts accepted")
+	}
+}
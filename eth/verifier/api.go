@@ -0,0 +1,43 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package verifier
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// API exposes the results of the stateless verifier over RPC.
+type API struct {
+	verifier *Verifier
+}
+
+// Head returns the result of the most recently verified block.
+func (api *API) Head() *Result {
+	return api.verifier.Head()
+}
+
+// GetResult returns the verification result of a recently verified block.
+func (api *API) GetResult(hash common.Hash) *Result {
+	return api.verifier.Result(hash)
+}
+
+// GetResultByNumber returns the verification result of the most recently
+// verified block with the given number.
+func (api *API) GetResultByNumber(number hexutil.Uint64) *Result {
+	return api.verifier.ResultByNumber(uint64(number))
+}
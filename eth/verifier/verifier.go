@@ -0,0 +1,358 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package verifier implements a stateless block verifier, which follows the
+// chain head of a remote node and validates each block by executing it against
+// the witness served by that node.
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// maxBacklog is the maximum number of blocks verified when catching up with
+	// the remote head. Older blocks are skipped.
+	maxBacklog = 16
+
+	// resultCacheSize is the number of recent verification results retained.
+	resultCacheSize = 1024
+
+	// requestTimeout is the time allowance for fetching a block and its witness.
+	requestTimeout = 30 * time.Second
+)
+
+// Config contains the settings of the stateless verifier.
+type Config struct {
+	Endpoint string        // RPC endpoint serving blocks and execution witnesses
+	Interval time.Duration // Interval between polls for a new head block
+}
+
+// Defaults contains the default settings of the stateless verifier.
+var Defaults = Config{
+	Interval: 4 * time.Second,
+}
+
+// Result is the outcome of statelessly verifying a block.
+type Result struct {
+	Number      hexutil.Uint64 `json:"number"`
+	Hash        common.Hash    `json:"hash"`
+	ParentHash  common.Hash    `json:"parentHash"`
+	Valid       bool           `json:"valid"`
+	Error       string         `json:"error,omitempty"`
+	StateRoot   common.Hash    `json:"stateRoot"`
+	ReceiptRoot common.Hash    `json:"receiptsRoot"`
+	Elapsed     time.Duration  `json:"elapsed"`
+}
+
+// backend is the remote source of blocks and witnesses.
+type backend interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	ExecutionWitness(ctx context.Context, hash common.Hash) (*stateless.Witness, error)
+}
+
+// rpcBackend retrieves blocks and witnesses from a remote node over RPC.
+type rpcBackend struct {
+	*ethclient.Client
+	geth *gethclient.Client
+}
+
+func (b *rpcBackend) ExecutionWitness(ctx context.Context, hash common.Hash) (*stateless.Witness, error) {
+	return b.geth.ExecutionWitness(ctx, hash)
+}
+
+// Verifier keeps no state of its own. It follows the head of a remote node and
+// verifies each new block by executing it statelessly against the witness
+// fetched from the same node.
+type Verifier struct {
+	config   *params.ChainConfig
+	settings Config
+	client   *rpc.Client
+	backend  backend
+
+	head    *Result                          // Most recently verified block
+	results *lru.Cache[common.Hash, *Result] // Recent results by block hash
+	numbers *lru.Cache[uint64, common.Hash]  // Recent block hashes by number
+	lock    sync.RWMutex
+
+	closed chan struct{}
+	wg     sync.WaitGroup
+}
+
+// New creates a stateless verifier for the given chain, and registers it along
+// with its RPC API into the node.
+func New(stack *node.Node, config *params.ChainConfig, settings Config) (*Verifier, error) {
+	if settings.Endpoint == "" {
+		return nil, errors.New("no witness endpoint configured")
+	}
+	if settings.Interval == 0 {
+		settings.Interval = Defaults.Interval
+	}
+	v := newVerifier(config, settings, nil)
+
+	stack.RegisterAPIs([]rpc.API{{
+		Namespace: "stateless",
+		Service:   &API{v},
+	}})
+	stack.RegisterLifecycle(v)
+	return v, nil
+}
+
+func newVerifier(config *params.ChainConfig, settings Config, backend backend) *Verifier {
+	return &Verifier{
+		config:   config,
+		settings: settings,
+		backend:  backend,
+		results:  lru.NewCache[common.Hash, *Result](resultCacheSize),
+		numbers:  lru.NewCache[uint64, common.Hash](resultCacheSize),
+		closed:   make(chan struct{}),
+	}
+}
+
+// Start connects to the remote node and launches the verification loop.
+func (v *Verifier) Start() error {
+	if v.backend == nil {
+		client, err := rpc.Dial(v.settings.Endpoint)
+		if err != nil {
+			return fmt.Errorf("failed to dial witness endpoint: %w", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		eth := ethclient.NewClient(client)
+		chainID, err := eth.ChainID(ctx)
+		if err != nil {
+			client.Close()
+			return fmt.Errorf("failed to retrieve remote chain ID: %w", err)
+		}
+		if v.config.ChainID != nil && chainID.Cmp(v.config.ChainID) != 0 {
+			client.Close()
+			return fmt.Errorf("remote chain ID mismatch: have %v, want %v", chainID, v.config.ChainID)
+		}
+		v.client = client
+		v.backend = &rpcBackend{Client: eth, geth: gethclient.New(client)}
+	}
+	log.Info("Starting stateless verifier", "endpoint", v.settings.Endpoint)
+
+	v.wg.Add(1)
+	go v.loop()
+	return nil
+}
+
+// Stop terminates the verification loop and disconnects from the remote node.
+func (v *Verifier) Stop() error {
+	close(v.closed)
+	v.wg.Wait()
+
+	if v.client != nil {
+		v.client.Close()
+	}
+	return nil
+}
+
+// loop polls the remote node for new head blocks, verifying them as they
+// arrive.
+func (v *Verifier) loop() {
+	defer v.wg.Done()
+
+	ticker := time.NewTicker(v.settings.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := v.update(); err != nil {
+			log.Warn("Failed to verify remote head", "err", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-v.closed:
+			return
+		}
+	}
+}
+
+// update verifies all the blocks up to the remote head which were not verified
+// yet, skipping the ones too far behind.
+func (v *Verifier) update() error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	head, err := v.backend.HeaderByNumber(ctx, nil)
+	cancel()
+	if err != nil {
+		return err
+	}
+	if _, ok := v.results.Get(head.Hash()); ok {
+		return nil
+	}
+	number := head.Number.Uint64()
+
+	from := uint64(1)
+	if number >= maxBacklog {
+		from = number - maxBacklog + 1
+	}
+	if last := v.Head(); last != nil {
+		if uint64(last.Number) < number {
+			from = max(from, uint64(last.Number)+1)
+		} else {
+			from = number // reorg to a head not above the last one
+		}
+	}
+	for n := from; n <= number; n++ {
+		select {
+		case <-v.closed:
+			return nil
+		default:
+		}
+		block, witness, err := v.fetch(n)
+		if err != nil {
+			return err
+		}
+		v.record(v.verify(block, witness))
+	}
+	return nil
+}
+
+// fetch retrieves a block and its execution witness from the remote node.
+func (v *Verifier) fetch(number uint64) (*types.Block, *stateless.Witness, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	block, err := v.backend.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve block %d: %w", number, err)
+	}
+	witness, err := v.backend.ExecutionWitness(ctx, block.Hash())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve witness of block %d: %w", number, err)
+	}
+	return block, witness, nil
+}
+
+// verify executes the block against the witness and checks the results.
+func (v *Verifier) verify(block *types.Block, witness *stateless.Witness) *Result {
+	start := time.Now()
+	stateRoot, receiptRoot, err := Verify(v.config, block, witness)
+
+	res := &Result{
+		Number:      hexutil.Uint64(block.NumberU64()),
+		Hash:        block.Hash(),
+		ParentHash:  block.ParentHash(),
+		Valid:       err == nil,
+		StateRoot:   stateRoot,
+		ReceiptRoot: receiptRoot,
+		Elapsed:     time.Since(start),
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// record stores a verification result and updates the head.
+func (v *Verifier) record(res *Result) {
+	v.lock.Lock()
+	if v.head != nil && v.head.Hash != res.ParentHash {
+		log.Info("Remote chain reorganised", "number", uint64(res.Number), "hash", res.Hash, "parent", res.ParentHash, "previous", v.head.Hash)
+	}
+	v.head = res
+	v.lock.Unlock()
+
+	v.results.Add(res.Hash, res)
+	v.numbers.Add(uint64(res.Number), res.Hash)
+
+	verifyTimer.Update(res.Elapsed)
+	headGauge.Update(int64(res.Number))
+	if res.Valid {
+		validMeter.Mark(1)
+		log.Info("Verified block statelessly", "number", uint64(res.Number), "hash", res.Hash, "elapsed", common.PrettyDuration(res.Elapsed))
+	} else {
+		invalidMeter.Mark(1)
+		log.Error("Stateless block verification failed", "number", uint64(res.Number), "hash", res.Hash, "err", res.Error)
+	}
+}
+
+// Head returns the result of the most recently verified block.
+func (v *Verifier) Head() *Result {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+
+	return v.head
+}
+
+// Result returns the verification result of a recent block, or nil if the block
+// was not verified.
+func (v *Verifier) Result(hash common.Hash) *Result {
+	res, _ := v.results.Get(hash)
+	return res
+}
+
+// ResultByNumber returns the verification result of the most recently verified
+// block with the given number, or nil if none was verified.
+func (v *Verifier) ResultByNumber(number uint64) *Result {
+	hash, ok := v.numbers.Get(number)
+	if !ok {
+		return nil
+	}
+	return v.Result(hash)
+}
+
+// Verify executes a block statelessly against the given witness, checking that
+// the witness builds on the block's parent and that the execution produces the
+// state and receipt roots committed to by the block. The computed roots are
+// returned even if they don't match.
+func Verify(config *params.ChainConfig, block *types.Block, witness *stateless.Witness) (common.Hash, common.Hash, error) {
+	if len(witness.Headers) == 0 {
+		return common.Hash{}, common.Hash{}, errors.New("witness without parent header")
+	}
+	if parent := witness.Headers[0]; parent.Hash() != block.ParentHash() {
+		return common.Hash{}, common.Hash{}, fmt.Errorf("witness parent mismatch: have %x, want %x", parent.Hash(), block.ParentHash())
+	}
+	// Remove the computed fields from the block to force their recalculation
+	context := block.Header()
+	context.Root = common.Hash{}
+	context.ReceiptHash = common.Hash{}
+
+	task := types.NewBlockWithHeader(context).WithBody(*block.Body())
+	stateRoot, receiptRoot, err := core.ExecuteStateless(config, vm.Config{}, task, witness)
+	if err != nil {
+		return common.Hash{}, common.Hash{}, err
+	}
+	if stateRoot != block.Root() {
+		return stateRoot, receiptRoot, fmt.Errorf("state root mismatch: have %x, want %x", stateRoot, block.Root())
+	}
+	if receiptRoot != block.ReceiptHash() {
+		return stateRoot, receiptRoot, fmt.Errorf("receipt root mismatch: have %x, want %x", receiptRoot, block.ReceiptHash())
+	}
+	return stateRoot, receiptRoot, nil
+}
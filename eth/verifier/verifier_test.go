@@ -0,0 +1,191 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package verifier
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+
+	// testCode stores the block number into the slot of the timestamp
+	testCode = common.FromHex("4342556000")
+)
+
+// testBackend serves a locally generated chain and its witnesses.
+type testBackend struct {
+	blocks    []*types.Block
+	witnesses map[common.Hash]*stateless.Witness
+	head      int
+}
+
+// newTestBackend generates a chain of n blocks, each containing a transfer and a
+// contract call writing storage, along with the witnesses of the blocks.
+func newTestBackend(t *testing.T, n int) *testBackend {
+	t.Helper()
+
+	var (
+		contract = common.HexToAddress("0xc0de")
+		genesis  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				testAddr: {Balance: big.NewInt(params.Ether)},
+				contract: {Code: testCode},
+			},
+		}
+		signer = types.LatestSigner(params.TestChainConfig)
+	)
+	_, blocks, _ := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), n, func(i int, gen *core.BlockGen) {
+		transfer := types.MustSignNewTx(testKey, signer, &types.LegacyTx{
+			Nonce:    gen.TxNonce(testAddr),
+			To:       &common.Address{byte(i)},
+			Value:    big.NewInt(1),
+			Gas:      params.TxGas,
+			GasPrice: gen.BaseFee(),
+		})
+		gen.AddTx(transfer)
+		call := types.MustSignNewTx(testKey, signer, &types.LegacyTx{
+			Nonce:    gen.TxNonce(testAddr),
+			To:       &contract,
+			Gas:      100000,
+			GasPrice: gen.BaseFee(),
+		})
+		gen.AddTx(call)
+	})
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	backend := &testBackend{
+		blocks:    append([]*types.Block{chain.Genesis()}, blocks...),
+		witnesses: make(map[common.Hash]*stateless.Witness),
+		head:      n,
+	}
+	for _, block := range blocks {
+		witness, err := chain.InsertBlockWithoutSetHead(block, true)
+		if err != nil {
+			t.Fatalf("failed to insert block %d: %v", block.NumberU64(), err)
+		}
+		backend.witnesses[block.Hash()] = witness
+	}
+	return backend
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil {
+		return b.blocks[b.head].Header(), nil
+	}
+	return b.blocks[number.Int64()].Header(), nil
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return b.blocks[number.Int64()], nil
+}
+
+func (b *testBackend) ExecutionWitness(ctx context.Context, hash common.Hash) (*stateless.Witness, error) {
+	witness, ok := b.witnesses[hash]
+	if !ok {
+		return nil, errors.New("unknown block")
+	}
+	return witness.Copy(), nil
+}
+
+// tamper replaces the block at the given number by one committing to a wrong
+// state root, executable with the same witness.
+func (b *testBackend) tamper(number int) {
+	block := b.blocks[number]
+	header := block.Header()
+	header.Root = common.Hash{0xba, 0xd}
+
+	b.blocks[number] = types.NewBlockWithHeader(header).WithBody(*block.Body())
+	b.witnesses[b.blocks[number].Hash()] = b.witnesses[block.Hash()]
+}
+
+func TestVerify(t *testing.T) {
+	backend := newTestBackend(t, 2)
+	block := backend.blocks[2]
+
+	witness, _ := backend.ExecutionWitness(context.Background(), block.Hash())
+	stateRoot, receiptRoot, err := Verify(params.TestChainConfig, block, witness)
+	if err != nil {
+		t.Fatalf("valid block rejected: %v", err)
+	}
+	if stateRoot != block.Root() || receiptRoot != block.ReceiptHash() {
+		t.Fatalf("wrong roots: have %x/%x, want %x/%x", stateRoot, receiptRoot, block.Root(), block.ReceiptHash())
+	}
+	// A witness of another block must not be accepted
+	other, _ := backend.ExecutionWitness(context.Background(), backend.blocks[1].Hash())
+	if _, _, err := Verify(params.TestChainConfig, block, other); err == nil || !strings.Contains(err.Error(), "witness parent mismatch") {
+		t.Fatalf("foreign witness accepted: %v", err)
+	}
+	// A block committing to a wrong state root must be rejected
+	backend.tamper(2)
+	witness, _ = backend.ExecutionWitness(context.Background(), backend.blocks[2].Hash())
+	if _, _, err := Verify(params.TestChainConfig, backend.blocks[2], witness); err == nil || !strings.Contains(err.Error(), "state root mismatch") {
+		t.Fatalf("invalid block accepted: %v", err)
+	}
+}
+
+func TestVerifierUpdate(t *testing.T) {
+	backend := newTestBackend(t, 4)
+	backend.tamper(4)
+	backend.head = 3
+
+	v := newVerifier(params.TestChainConfig, Defaults, backend)
+	if err := v.update(); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	for n := uint64(1); n <= 3; n++ {
+		res := v.ResultByNumber(n)
+		if res == nil || !res.Valid || res.Hash != backend.blocks[n].Hash() {
+			t.Fatalf("block %d: unexpected result %+v", n, res)
+		}
+	}
+	if head := v.Head(); head == nil || uint64(head.Number) != 3 {
+		t.Fatalf("unexpected head %+v", head)
+	}
+	// Advance the remote head to the invalid block
+	backend.head = 4
+	if err := v.update(); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	head := v.Head()
+	if head == nil || uint64(head.Number) != 4 || head.Valid || head.Error == "" {
+		t.Fatalf("invalid block not reported: %+v", head)
+	}
+	if res := v.Result(backend.blocks[4].Hash()); res != head {
+		t.Fatalf("result not retrievable by hash")
+	}
+}
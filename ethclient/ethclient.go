@@ -470,6 +470,11 @@ func (ec *Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuer
 	if err != nil {
 		return nil, err
 	}
+	// Subscriptions with a fromBlock replay the historical logs first, only
+	// request it if explicitly set.
+	if q.FromBlock == nil {
+		delete(arg.(map[string]interface{}), "fromBlock")
+	}
 	sub, err := ec.c.EthSubscribe(ctx, ch, "logs", arg)
 	if err != nil {
 		// Defensively prefer returning nil interface explicitly on error-path, instead
@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -187,6 +188,23 @@ func (ec *Client) SetHead(ctx context.Context, number *big.Int) error {
 	return ec.c.CallContext(ctx, nil, "debug_setHead", toBlockNumArg(number))
 }
 
+// ExecutionWitness retrieves the witness required to statelessly execute the
+// block with the given hash.
+func (ec *Client) ExecutionWitness(ctx context.Context, hash common.Hash) (*stateless.Witness, error) {
+	var ext *stateless.ExtWitness
+	if err := ec.c.CallContext(ctx, &ext, "debug_executionWitness", hash); err != nil {
+		return nil, err
+	}
+	if ext == nil {
+		return nil, ethereum.NotFound
+	}
+	witness := new(stateless.Witness)
+	if err := witness.FromExtWitness(ext); err != nil {
+		return nil, err
+	}
+	return witness, nil
+}
+
 // GetNodeInfo retrieves the node info of a geth node.
 func (ec *Client) GetNodeInfo(ctx context.Context) (*p2p.NodeInfo, error) {
 	var result p2p.NodeInfo
@@ -29,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
@@ -100,7 +101,7 @@ func generateTestChain() (*core.Genesis, []*types.Block) {
 }
 
 func TestGethClient(t *testing.T) {
-	backend, _ := newTestBackend(t)
+	backend, blocks := newTestBackend(t)
 	client := backend.Attach()
 	defer backend.Close()
 	defer client.Close()
@@ -145,6 +146,9 @@ func TestGethClient(t *testing.T) {
 		}, {
 			"TestCallContractWithBlockOverrides",
 			func(t *testing.T) { testCallContractWithBlockOverrides(t, client) },
+		}, {
+			"TestExecutionWitness",
+			func(t *testing.T) { testExecutionWitness(t, client, blocks[1]) },
 		},
 		// The testaccesslist is a bit time-sensitive: the newTestBackend imports
 		// one block. The `testAccessList` fails if the miner has not yet created a
@@ -163,6 +167,27 @@ func TestGethClient(t *testing.T) {
 	}
 }
 
+func testExecutionWitness(t *testing.T, client *rpc.Client, block *types.Block) {
+	ec := New(client)
+	witness, err := ec.ExecutionWitness(context.Background(), block.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(witness.Headers) == 0 || witness.Headers[0].Hash() != block.ParentHash() {
+		t.Fatalf("witness parent header mismatch")
+	}
+	header := block.Header()
+	header.Root, header.ReceiptHash = common.Hash{}, common.Hash{}
+
+	stateRoot, receiptRoot, err := core.ExecuteStateless(params.AllEthashProtocolChanges, vm.Config{}, types.NewBlockWithHeader(header).WithBody(*block.Body()), witness)
+	if err != nil {
+		t.Fatalf("stateless execution failed: %v", err)
+	}
+	if stateRoot != block.Root() || receiptRoot != block.ReceiptHash() {
+		t.Fatalf("stateless roots mismatch: have %x/%x, want %x/%x", stateRoot, receiptRoot, block.Root(), block.ReceiptHash())
+	}
+}
+
 func testAccessList(t *testing.T, client *rpc.Client) {
 	ec := New(client)
 
@@ -0,0 +1,117 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulated
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// simAPI exposes the snapshot, mining and cheat code operations of the
+// simulated backend over RPC, in the "sim" namespace.
+type simAPI struct {
+	sim *Backend
+}
+
+// impersonateArgs represents the arguments of a transaction sent on behalf of
+// an account without its key.
+type impersonateArgs struct {
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Gas   *hexutil.Uint64 `json:"gas"`
+	Value *hexutil.Big    `json:"value"`
+	Data  hexutil.Bytes   `json:"data"`
+	Input hexutil.Bytes   `json:"input"`
+}
+
+// Mine seals a new block, returning its hash.
+func (api *simAPI) Mine() common.Hash {
+	return api.sim.Commit()
+}
+
+// Snapshot takes a snapshot of the current chain, returning its id.
+func (api *simAPI) Snapshot() hexutil.Uint64 {
+	return hexutil.Uint64(api.sim.Snapshot())
+}
+
+// Revert reverts the chain to the snapshot with the given id.
+func (api *simAPI) Revert(id hexutil.Uint64) error {
+	return api.sim.Revert(uint64(id))
+}
+
+// SetBalance sets the balance of the given account.
+func (api *simAPI) SetBalance(addr common.Address, balance hexutil.Big) error {
+	return api.sim.SetBalance(addr, balance.ToInt())
+}
+
+// SetNonce sets the nonce of the given account.
+func (api *simAPI) SetNonce(addr common.Address, nonce hexutil.Uint64) error {
+	return api.sim.SetNonce(addr, uint64(nonce))
+}
+
+// SetCode sets the code of the given account.
+func (api *simAPI) SetCode(addr common.Address, code hexutil.Bytes) error {
+	return api.sim.SetCode(addr, code)
+}
+
+// SetStorageAt sets the value of the given storage slot of an account.
+func (api *simAPI) SetStorageAt(addr common.Address, slot common.Hash, value common.Hash) error {
+	return api.sim.SetStorageAt(addr, slot, value)
+}
+
+// Impersonate sends a transaction on behalf of the sender of the given message,
+// returning the hash of the transaction.
+func (api *simAPI) Impersonate(args impersonateArgs) (common.Hash, error) {
+	if args.Data != nil && args.Input != nil && !bytes.Equal(args.Data, args.Input) {
+		return common.Hash{}, errors.New(`both "data" and "input" are set and not equal`)
+	}
+	msg := ethereum.CallMsg{
+		From: args.From,
+		To:   args.To,
+		Data: args.Input,
+	}
+	if msg.Data == nil {
+		msg.Data = args.Data
+	}
+	if args.Gas != nil {
+		msg.Gas = uint64(*args.Gas)
+	}
+	if args.Value != nil {
+		msg.Value = args.Value.ToInt()
+	}
+	receipt, err := api.sim.Impersonate(msg)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return receipt.TxHash, nil
+}
+
+// SetAutomine enables or disables sealing a new block for every transaction.
+func (api *simAPI) SetAutomine(enabled bool) {
+	api.sim.SetAutomine(enabled)
+}
+
+// SetIntervalMining configures sealing a new block at every interval, given in
+// seconds. Interval mining is disabled if the interval is zero.
+func (api *simAPI) SetIntervalMining(interval uint64) {
+	api.sim.SetIntervalMining(time.Duration(interval) * time.Second)
+}
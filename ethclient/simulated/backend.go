@@ -17,22 +17,48 @@
 package simulated
 
 import (
+	"context"
 	"errors"
+	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/catalyst"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/holiman/uint256"
+)
+
+var (
+	// errCheatsUnsupported is returned if a cheat code is invoked on a simulated
+	// backend whose chain can't be modified by the backend itself.
+	errCheatsUnsupported = errors.New("cheat codes are not supported by the consensus engine")
+
+	// errPendingTransactions is returned if the state is modified while there
+	// are pending transactions, which would otherwise be included ahead of the
+	// modification.
+	errPendingTransactions = errors.New("could not modify state with pending transactions")
+
+	// errUnknownSnapshot is returned if the snapshot to revert to is unknown.
+	errUnknownSnapshot = errors.New("unknown snapshot")
+
+	// errSealingFailed is returned if no block could be sealed.
+	errSealingFailed = errors.New("failed to seal block")
 )
 
 // Client exposes the methods provided by the Ethereum RPC client.
@@ -63,8 +89,19 @@ type simClient struct {
 // other code that interacts with the Ethereum chain.
 type Backend struct {
 	node   *node.Node
+	eth    *eth.Ethereum
 	beacon *catalyst.SimulatedBeacon
+	cheats *cheatProcessor
 	client simClient
+
+	snapshots []common.Hash // Head blocks of the taken snapshots
+	lock      sync.Mutex    // Lock protecting the chain from concurrent modifications
+
+	automine bool          // Whether a block is sealed for every new transaction
+	interval time.Duration // Block period of interval mining, zero if disabled
+	mineQuit chan struct{} // Quit channel of the mining loop, nil if not running
+	mineWg   sync.WaitGroup
+	mineLock sync.Mutex // Lock protecting the mining mode
 }
 
 // NewBackend creates a new simulated blockchain that can be used as a backend for
@@ -109,11 +146,22 @@ func newWithNode(stack *node.Node, conf *eth.Config, blockPeriod uint64) (*Backe
 	if err != nil {
 		return nil, err
 	}
-	// Register the filter system
+	sim := &Backend{node: stack, eth: backend}
+
+	// Run the chain with the cheat code support, unless it's a clique chain
+	// on which the cheat blocks can't be sealed.
+	if chain := backend.BlockChain(); chain.Config().Clique == nil {
+		sim.cheats = newCheatProcessor(chain)
+		chain.SetBlockValidatorAndProcessorForTesting(chain.Validator(), sim.cheats)
+	}
+	// Register the filter system and the cheat codes
 	filterSystem := filters.NewFilterSystem(backend.APIBackend, filters.Config{})
 	stack.RegisterAPIs([]rpc.API{{
 		Namespace: "eth",
 		Service:   filters.NewFilterAPI(filterSystem),
+	}, {
+		Namespace: "sim",
+		Service:   &simAPI{sim: sim},
 	}})
 	// Start the node
 	if err := stack.Start(); err != nil {
@@ -128,16 +176,16 @@ func newWithNode(stack *node.Node, conf *eth.Config, blockPeriod uint64) (*Backe
 	if err := beacon.Fork(backend.BlockChain().GetCanonicalHash(0)); err != nil {
 		return nil, err
 	}
-	return &Backend{
-		node:   stack,
-		beacon: beacon,
-		client: simClient{ethclient.NewClient(stack.Attach())},
-	}, nil
+	sim.beacon = beacon
+	sim.client = simClient{ethclient.NewClient(stack.Attach())}
+	return sim, nil
 }
 
 // Close shuts down the simBackend.
 // The simulated backend can't be used afterwards.
 func (n *Backend) Close() error {
+	n.stopMining()
+
 	if n.client.Client != nil {
 		n.client.Close()
 		n.client = simClient{}
@@ -156,11 +204,17 @@ func (n *Backend) Close() error {
 
 // Commit seals a block and moves the chain forward to a new empty block.
 func (n *Backend) Commit() common.Hash {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
 	return n.beacon.Commit()
 }
 
 // Rollback removes all pending transactions, reverting to the last committed state.
 func (n *Backend) Rollback() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
 	n.beacon.Rollback()
 }
 
@@ -177,15 +231,294 @@ func (n *Backend) Rollback() {
 // There is a % chance that the side chain becomes canonical at the same length
 // to simulate live network behavior.
 func (n *Backend) Fork(parentHash common.Hash) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
 	return n.beacon.Fork(parentHash)
 }
 
 // AdjustTime changes the block timestamp and creates a new block.
 // It can only be called on empty blocks.
 func (n *Backend) AdjustTime(adjustment time.Duration) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
 	return n.beacon.AdjustTime(adjustment)
 }
 
+// Snapshot takes a snapshot of the current chain, returning its id. The chain
+// can be reverted to the snapshot via Revert.
+func (n *Backend) Snapshot() uint64 {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.snapshots = append(n.snapshots, n.eth.BlockChain().CurrentBlock().Hash())
+	return uint64(len(n.snapshots))
+}
+
+// Revert reverts the chain to the snapshot with the given id, dropping all the
+// pending transactions. The snapshot and all the ones taken after it are
+// consumed and can't be reverted to again.
+func (n *Backend) Revert(id uint64) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if id == 0 || id > uint64(len(n.snapshots)) {
+		return errUnknownSnapshot
+	}
+	n.beacon.Rollback()
+	if err := n.beacon.Fork(n.snapshots[id-1]); err != nil {
+		return err
+	}
+	n.snapshots = n.snapshots[:id-1]
+	return nil
+}
+
+// SetBalance sets the balance of the given account. The modification is made
+// in a newly sealed block.
+func (n *Backend) SetBalance(addr common.Address, balance *big.Int) error {
+	value, overflow := uint256.FromBig(balance)
+	if overflow {
+		return errors.New("balance overflow")
+	}
+	return n.cheat(func(state *state.StateDB) {
+		state.SetBalance(addr, value, tracing.BalanceChangeUnspecified)
+	})
+}
+
+// SetNonce sets the nonce of the given account. The modification is made in
+// a newly sealed block.
+func (n *Backend) SetNonce(addr common.Address, nonce uint64) error {
+	return n.cheat(func(state *state.StateDB) {
+		state.SetNonce(addr, nonce, tracing.NonceChangeUnspecified)
+	})
+}
+
+// SetCode sets the code of the given account. The modification is made in a
+// newly sealed block.
+func (n *Backend) SetCode(addr common.Address, code []byte) error {
+	code = common.CopyBytes(code)
+	return n.cheat(func(state *state.StateDB) {
+		state.SetCode(addr, code)
+	})
+}
+
+// SetStorageAt sets the value of the given storage slot of an account. The
+// modification is made in a newly sealed block.
+func (n *Backend) SetStorageAt(addr common.Address, slot common.Hash, value common.Hash) error {
+	return n.cheat(func(state *state.StateDB) {
+		state.SetState(addr, slot, value)
+	})
+}
+
+// Impersonate sends a transaction on behalf of the sender of the message, without
+// the need of the sender's key. The transaction is included in a newly sealed
+// block, returning its receipt. The sender pays for the gas like for any other
+// transaction; if no gas limit is given, it's estimated.
+//
+// Note, the impersonated transaction carries no valid signature, so the sender
+// can't be recovered from it outside of the simulated chain processing. Methods
+// such as eth_getTransactionByHash report the zero address as its sender, and
+// it can't be traced.
+func (n *Backend) Impersonate(msg ethereum.CallMsg) (*types.Receipt, error) {
+	if n.cheats == nil {
+		return nil, errCheatsUnsupported
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if err := n.checkPending(); err != nil {
+		return nil, err
+	}
+	var (
+		chain  = n.eth.BlockChain()
+		config = chain.Config()
+		head   = chain.CurrentBlock()
+	)
+	if !config.IsLondon(new(big.Int).Add(head.Number, common.Big1)) {
+		return nil, errCheatsUnsupported
+	}
+	statedb, err := chain.StateAt(head.Root)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Gas == 0 {
+		gas, err := n.client.EstimateGas(context.Background(), msg)
+		if err != nil {
+			return nil, err
+		}
+		msg.Gas = gas
+	}
+	value := new(big.Int)
+	if msg.Value != nil {
+		value.Set(msg.Value)
+	}
+	tx := newImpersonatedTx(msg.From, &types.DynamicFeeTx{
+		ChainID:    config.ChainID,
+		Nonce:      statedb.GetNonce(msg.From),
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  eip1559.CalcBaseFee(config, head),
+		Gas:        msg.Gas,
+		To:         msg.To,
+		Value:      value,
+		Data:       msg.Data,
+		AccessList: msg.AccessList,
+	})
+	block, err := n.cheats.seal(nil, types.Transactions{tx})
+	if err != nil {
+		return nil, err
+	}
+	receipts := chain.GetReceiptsByHash(block.Hash())
+	if len(receipts) != 1 {
+		return nil, errSealingFailed
+	}
+	return receipts[0], nil
+}
+
+// cheat applies the cheat to the state of the chain head, sealing the result in
+// a new block.
+func (n *Backend) cheat(c cheat) error {
+	if n.cheats == nil {
+		return errCheatsUnsupported
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if err := n.checkPending(); err != nil {
+		return err
+	}
+	_, err := n.cheats.seal([]cheat{c}, nil)
+	return err
+}
+
+// checkPending ensures that there are no pending transactions, which would be
+// invalidated by sealing a cheat block on top of the chain head.
+func (n *Backend) checkPending() error {
+	if err := n.eth.TxPool().Sync(); err != nil {
+		return err
+	}
+	if len(n.eth.TxPool().Pending(txpool.PendingFilter{})) != 0 {
+		return errPendingTransactions
+	}
+	return nil
+}
+
+// SetAutomine enables or disables sealing a new block for every transaction
+// submitted to the simulated backend.
+func (n *Backend) SetAutomine(enabled bool) {
+	n.mineLock.Lock()
+	defer n.mineLock.Unlock()
+
+	n.automine = enabled
+	n.restartMining()
+}
+
+// SetIntervalMining configures the simulated backend to seal a new block at
+// every interval. Interval mining is disabled if the interval is zero.
+func (n *Backend) SetIntervalMining(interval time.Duration) {
+	n.mineLock.Lock()
+	defer n.mineLock.Unlock()
+
+	n.interval = interval
+	n.restartMining()
+}
+
+// stopMining terminates the mining loop if it's running.
+func (n *Backend) stopMining() {
+	n.mineLock.Lock()
+	defer n.mineLock.Unlock()
+
+	n.automine, n.interval = false, 0
+	n.restartMining()
+}
+
+// restartMining terminates the running mining loop and launches a new one with
+// the current mining mode, if mining is enabled. The mine lock is assumed to be
+// held.
+func (n *Backend) restartMining() {
+	if n.mineQuit != nil {
+		close(n.mineQuit)
+		n.mineWg.Wait()
+		n.mineQuit = nil
+	}
+	if !n.automine && n.interval == 0 {
+		return
+	}
+	n.mineQuit = make(chan struct{})
+	n.mineWg.Add(1)
+	go n.mine(n.automine, n.interval, n.mineQuit)
+}
+
+// mine is the mining loop, sealing blocks for the new transactions if automine
+// is enabled and at every interval if interval mining is enabled.
+func (n *Backend) mine(automine bool, interval time.Duration, quit chan struct{}) {
+	defer n.mineWg.Done()
+
+	var (
+		newTxs   chan core.NewTxsEvent
+		newTxSub event.Subscription
+		ticker   <-chan time.Time
+		doCommit = make(chan struct{}, 1)
+		done     = make(chan struct{})
+	)
+	if automine {
+		newTxs = make(chan core.NewTxsEvent)
+		newTxSub = n.eth.TxPool().SubscribeTransactions(newTxs, true)
+	}
+	if interval > 0 {
+		timer := time.NewTicker(interval)
+		defer timer.Stop()
+		ticker = timer.C
+	}
+	// Seal the blocks on a background thread, the transaction pool might block
+	// on delivering the transaction events during sealing.
+	go func() {
+		defer close(done)
+		for range doCommit {
+			n.commitPending()
+		}
+	}()
+	for {
+		select {
+		case <-newTxs:
+		case <-ticker:
+		case <-quit:
+			if newTxSub != nil {
+				newTxSub.Unsubscribe()
+			}
+			close(doCommit)
+			<-done
+			return
+		}
+		select {
+		case doCommit <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// commitPending seals blocks until all the executable transactions are included.
+// A single block is sealed if there are no executable transactions.
+func (n *Backend) commitPending() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for {
+		head := n.beacon.Commit()
+		if err := n.eth.TxPool().Sync(); err != nil {
+			return
+		}
+		// Stop if there are no executable transactions left, or the sealed
+		// block didn't include any of them.
+		if executable, _ := n.eth.TxPool().Stats(); executable == 0 {
+			return
+		}
+		if block := n.eth.BlockChain().GetBlockByHash(head); block == nil || len(block.Transactions()) == 0 {
+			return
+		}
+	}
+}
+
 // Client returns a client that accesses the simulated chain.
 func (n *Backend) Client() Client {
 	return n.client
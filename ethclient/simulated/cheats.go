@@ -0,0 +1,263 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulated
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// The cheat codes modify the state in ways the consensus rules don't allow, so
+// they can't be applied by the transactions of a regular block. Instead, they
+// are applied directly to the state of the chain head by the backend, which
+// seals the result into a block of its own and inserts it into the chain.
+//
+// A block must be reproducible from its parent state, as it's re-executed when
+// e.g. the state is regenerated or a witness is generated for it. The chain is
+// therefore run with a processor which re-applies the cheats recorded for the
+// block in the same way as they were applied when the block was built.
+//
+// Impersonated transactions are real transactions included in these blocks, so
+// they yield receipts and logs like any other. Instead of a signature, they carry
+// their sender in the R value along with V and S values of 0 and 1. These pass
+// the sanity checks of the transaction decoders, yet a signature having an R
+// value this small is practically impossible. They are executed with a signer
+// accepting such senders.
+// Note, the rest of the node recovers senders with the standard signer, which
+// rejects impersonated transactions, e.g. they can't be traced.
+
+// cheat is a direct state modification requested via the cheat codes of the
+// simulated backend.
+type cheat func(state *state.StateDB)
+
+// impersonatedSender returns the sender carried by an impersonated transaction,
+// or false if the transaction is not impersonated.
+func impersonatedSender(tx *types.Transaction) (common.Address, bool) {
+	v, r, s := tx.RawSignatureValues()
+	if v.Sign() != 0 || s.Cmp(common.Big1) != 0 || r.BitLen() > 8*common.AddressLength {
+		return common.Address{}, false
+	}
+	return common.BigToAddress(r), true
+}
+
+// newImpersonatedTx creates a transaction sent by the given account, carrying the
+// sender instead of a signature.
+func newImpersonatedTx(from common.Address, inner *types.DynamicFeeTx) *types.Transaction {
+	inner.V, inner.R, inner.S = new(big.Int), new(big.Int).SetBytes(from.Bytes()), big.NewInt(1)
+	return types.NewTx(inner)
+}
+
+// impersonationSigner is a transaction signer accepting impersonated transactions
+// on top of the signed ones.
+type impersonationSigner struct {
+	types.Signer
+}
+
+// Sender implements types.Signer, returning the sender carried by impersonated
+// transactions and recovering the sender of all others.
+func (s impersonationSigner) Sender(tx *types.Transaction) (common.Address, error) {
+	if from, ok := impersonatedSender(tx); ok {
+		return from, nil
+	}
+	return s.Signer.Sender(tx)
+}
+
+// applyCheats executes the transactions of a cheat block on top of the cheats,
+// including the system calls of the regular block processing. The consensus
+// engine specific finalization is left to the caller.
+func applyCheats(chain *core.BlockChain, header *types.Header, blockHash common.Hash, txs types.Transactions, cheats []cheat, statedb *state.StateDB, cfg vm.Config) (*core.ProcessResult, error) {
+	var (
+		config   = chain.Config()
+		signer   = impersonationSigner{types.MakeSigner(config, header.Number, header.Time)}
+		gp       = new(core.GasPool).AddGas(header.GasLimit)
+		usedGas  = new(uint64)
+		receipts types.Receipts
+		allLogs  []*types.Log
+	)
+	var tracingStateDB = vm.StateDB(statedb)
+	if hooks := cfg.Tracer; hooks != nil {
+		tracingStateDB = state.NewHookedState(statedb, hooks)
+	}
+	evm := vm.NewEVM(core.NewEVMBlockContext(header, chain, nil), tracingStateDB, config, cfg)
+
+	if header.ParentBeaconRoot != nil {
+		core.ProcessBeaconBlockRoot(*header.ParentBeaconRoot, evm)
+	}
+	if config.IsPrague(header.Number, header.Time) {
+		core.ProcessParentBlockHash(header.ParentHash, evm)
+	}
+	for _, c := range cheats {
+		c(statedb)
+	}
+	statedb.Finalise(true)
+
+	for i, tx := range txs {
+		msg, err := core.TransactionToMessage(tx, signer, header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+		// Impersonated accounts might be contracts.
+		if _, ok := impersonatedSender(tx); ok {
+			msg.SkipFromEOACheck = true
+		}
+		statedb.SetTxContext(tx.Hash(), i)
+
+		receipt, err := core.ApplyTransactionWithEVM(msg, gp, statedb, header.Number, blockHash, tx, usedGas, evm)
+		if err != nil {
+			return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+		receipts = append(receipts, receipt)
+		allLogs = append(allLogs, receipt.Logs...)
+	}
+	var requests [][]byte
+	if config.IsPrague(header.Number, header.Time) {
+		requests = [][]byte{}
+		if err := core.ParseDepositLogs(&requests, allLogs, config); err != nil {
+			return nil, err
+		}
+		if err := core.ProcessWithdrawalQueue(&requests, evm); err != nil {
+			return nil, err
+		}
+		if err := core.ProcessConsolidationQueue(&requests, evm); err != nil {
+			return nil, err
+		}
+	}
+	return &core.ProcessResult{
+		Receipts: receipts,
+		Requests: requests,
+		Logs:     allLogs,
+		GasUsed:  *usedGas,
+	}, nil
+}
+
+// cheatProcessor is the block processor of the simulated chain. It re-applies
+// the cheats of the blocks sealed by the backend and processes all the other
+// blocks with the standard processor.
+type cheatProcessor struct {
+	chain    *core.BlockChain
+	fallback core.Processor
+	blocks   map[common.Hash][]cheat // Cheats of the blocks sealed by the backend
+	lock     sync.RWMutex
+}
+
+// newCheatProcessor creates the cheat processor of the given chain, falling back
+// to the processor the chain was created with.
+func newCheatProcessor(chain *core.BlockChain) *cheatProcessor {
+	return &cheatProcessor{
+		chain:    chain,
+		fallback: chain.Processor(),
+		blocks:   make(map[common.Hash][]cheat),
+	}
+}
+
+// Process implements core.Processor, applying the cheats recorded for the block
+// along with its transactions.
+func (p *cheatProcessor) Process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (*core.ProcessResult, error) {
+	p.lock.RLock()
+	cheats, ok := p.blocks[block.Hash()]
+	p.lock.RUnlock()
+
+	if !ok {
+		return p.fallback.Process(block, statedb, cfg)
+	}
+	header := block.Header()
+	res, err := applyCheats(p.chain, header, block.Hash(), block.Transactions(), cheats, statedb, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var tracingStateDB = vm.StateDB(statedb)
+	if hooks := cfg.Tracer; hooks != nil {
+		tracingStateDB = state.NewHookedState(statedb, hooks)
+	}
+	p.chain.Engine().Finalize(p.chain, header, tracingStateDB, block.Body())
+	return res, nil
+}
+
+// seal applies the cheats and the given transactions on top of the chain head,
+// and inserts the resulting block into the chain.
+func (p *cheatProcessor) seal(cheats []cheat, txs types.Transactions) (*types.Block, error) {
+	var (
+		config = p.chain.Config()
+		parent = p.chain.CurrentBlock()
+	)
+	statedb, err := p.chain.StateAt(parent.Root)
+	if err != nil {
+		return nil, err
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   parent.GasLimit,
+		Time:       max(uint64(time.Now().Unix()), parent.Time+1),
+		Difficulty: common.Big0,
+	}
+	rand.Read(header.MixDigest[:])
+
+	if config.IsLondon(header.Number) {
+		header.BaseFee = eip1559.CalcBaseFee(config, parent)
+	}
+	if config.IsCancun(header.Number, header.Time) {
+		var excessBlobGas uint64
+		if config.IsCancun(parent.Number, parent.Time) {
+			excessBlobGas = eip4844.CalcExcessBlobGas(config, parent, header.Time)
+		}
+		header.BlobGasUsed = new(uint64)
+		header.ExcessBlobGas = &excessBlobGas
+		header.ParentBeaconRoot = new(common.Hash)
+	}
+	res, err := applyCheats(p.chain, header, common.Hash{}, txs, cheats, statedb, vm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	header.GasUsed = res.GasUsed
+	if res.Requests != nil {
+		reqHash := types.CalcRequestsHash(res.Requests)
+		header.RequestsHash = &reqHash
+	}
+	body := &types.Body{Transactions: txs}
+	if config.IsShanghai(header.Number, header.Time) {
+		body.Withdrawals = make([]*types.Withdrawal, 0)
+	}
+	block, err := p.chain.Engine().FinalizeAndAssemble(p.chain, header, statedb, body, res.Receipts)
+	if err != nil {
+		return nil, err
+	}
+	// Record the cheats before inserting the block, they are applied again
+	// when the block is processed.
+	p.lock.Lock()
+	p.blocks[block.Hash()] = cheats
+	p.lock.Unlock()
+
+	if _, err := p.chain.InsertChain(types.Blocks{block}); err != nil {
+		p.lock.Lock()
+		delete(p.blocks, block.Hash())
+		p.lock.Unlock()
+		return nil, err
+	}
+	return block, nil
+}
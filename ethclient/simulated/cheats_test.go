@@ -0,0 +1,309 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulated
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestSnapshotRevert(t *testing.T) {
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+
+	var (
+		ctx    = context.Background()
+		client = sim.Client()
+	)
+	id := sim.Snapshot()
+
+	tx, err := newTx(sim, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	if _, err := client.TransactionReceipt(ctx, tx.Hash()); err != nil {
+		t.Fatalf("transaction not included: %v", err)
+	}
+	if err := sim.Revert(id); err != nil {
+		t.Fatalf("failed to revert: %v", err)
+	}
+	if num, _ := client.BlockNumber(ctx); num != 0 {
+		t.Fatalf("unexpected block number after revert, want 0, got %d", num)
+	}
+	if nonce, _ := client.NonceAt(ctx, testAddr, nil); nonce != 0 {
+		t.Fatalf("unexpected nonce after revert, want 0, got %d", nonce)
+	}
+	// The snapshot is consumed by the revert
+	if err := sim.Revert(id); err == nil {
+		t.Fatal("reverted to a consumed snapshot")
+	}
+	// The chain should progress normally after the revert
+	sim.Commit()
+	if num, _ := client.BlockNumber(ctx); num != 1 {
+		t.Fatalf("unexpected block number, want 1, got %d", num)
+	}
+}
+
+func TestCheatCodes(t *testing.T) {
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+
+	var (
+		ctx     = context.Background()
+		client  = sim.Client()
+		addr    = common.HexToAddress("0x1234")
+		balance = big.NewInt(1000000)
+		slot    = common.HexToHash("0x01")
+		value   = common.HexToHash("0x02")
+
+		// PUSH1 0x2a PUSH1 0 MSTORE PUSH1 0x20 PUSH1 0 RETURN
+		code = common.FromHex("602a60005260206000f3")
+	)
+	if err := sim.SetBalance(addr, balance); err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.SetNonce(addr, 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.SetCode(addr, code); err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.SetStorageAt(addr, slot, value); err != nil {
+		t.Fatal(err)
+	}
+	if have, _ := client.BalanceAt(ctx, addr, nil); have.Cmp(balance) != 0 {
+		t.Fatalf("unexpected balance, want %v, got %v", balance, have)
+	}
+	if have, _ := client.NonceAt(ctx, addr, nil); have != 7 {
+		t.Fatalf("unexpected nonce, want 7, got %d", have)
+	}
+	if have, _ := client.CodeAt(ctx, addr, nil); !bytes.Equal(have, code) {
+		t.Fatalf("unexpected code, want %x, got %x", code, have)
+	}
+	if have, _ := client.StorageAt(ctx, addr, slot, nil); common.BytesToHash(have) != value {
+		t.Fatalf("unexpected storage, want %x, got %x", value, have)
+	}
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &addr}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if common.BytesToHash(output) != common.BigToHash(big.NewInt(42)) {
+		t.Fatalf("unexpected call output %x", output)
+	}
+	// The blocks carrying the cheats should be reproducible
+	if err := checkReplay(sim); err != nil {
+		t.Fatal(err)
+	}
+	// Modifying the state is rejected while transactions are pending
+	tx, err := newTx(sim, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.SetBalance(addr, common.Big0); err != errPendingTransactions {
+		t.Fatalf("unexpected error, want %v, got %v", errPendingTransactions, err)
+	}
+}
+
+func TestImpersonate(t *testing.T) {
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+
+	var (
+		ctx     = context.Background()
+		client  = sim.Client()
+		from    = common.HexToAddress("0xdead")
+		to      = common.HexToAddress("0xbeef")
+		value   = big.NewInt(1000)
+		balance = big.NewInt(params.Ether)
+
+		// CALLER PUSH1 0 PUSH1 0 LOG1 STOP
+		code = common.FromHex("3360006000a100")
+	)
+	if err := sim.SetBalance(from, balance); err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.SetCode(to, code); err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := sim.Impersonate(ethereum.CallMsg{From: from, To: &to, Value: value})
+	if err != nil {
+		t.Fatalf("failed to impersonate: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("impersonated transaction failed")
+	}
+	// The transaction is included with its receipt and logs
+	head, _ := client.HeaderByNumber(ctx, nil)
+	if receipt.BlockHash != head.Hash() {
+		t.Fatalf("unexpected receipt block, want %x, got %x", head.Hash(), receipt.BlockHash)
+	}
+	if _, _, err := client.TransactionByHash(ctx, receipt.TxHash); err != nil {
+		t.Fatalf("impersonated transaction not found: %v", err)
+	}
+	if len(receipt.Logs) != 1 || receipt.Logs[0].Topics[0] != common.BytesToHash(from.Bytes()) {
+		t.Fatalf("unexpected logs %v", receipt.Logs)
+	}
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{Addresses: []common.Address{to}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].TxHash != receipt.TxHash {
+		t.Fatalf("unexpected filtered logs %v", logs)
+	}
+	if have, _ := client.BalanceAt(ctx, to, nil); have.Cmp(value) != 0 {
+		t.Fatalf("unexpected recipient balance, want %v, got %v", value, have)
+	}
+	// The sender pays for the value and the gas
+	fee := new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+	want := new(big.Int).Sub(new(big.Int).Sub(balance, value), fee)
+	if have, _ := client.BalanceAt(ctx, from, nil); have.Cmp(want) != 0 {
+		t.Fatalf("unexpected sender balance, want %v, got %v", want, have)
+	}
+	if have, _ := client.NonceAt(ctx, from, nil); have != 1 {
+		t.Fatalf("unexpected sender nonce, want 1, got %d", have)
+	}
+	// Failed executions are reported
+	if _, err := sim.Impersonate(ethereum.CallMsg{From: from, To: &to, Value: balance}); err == nil {
+		t.Fatal("expected insufficient funds error")
+	}
+	if err := checkReplay(sim); err != nil {
+		t.Fatal(err)
+	}
+	// Regular transactions are still processed after the cheat blocks
+	tx, err := newTx(sim, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if _, err := client.TransactionReceipt(ctx, tx.Hash()); err != nil {
+		t.Fatalf("transaction not included: %v", err)
+	}
+}
+
+// checkReplay re-executes all the blocks of the simulated chain on top of their
+// parent states, ensuring that they yield the same state roots.
+func checkReplay(sim *Backend) error {
+	chain := sim.eth.BlockChain()
+	for number := uint64(1); number <= chain.CurrentBlock().Number.Uint64(); number++ {
+		block := chain.GetBlockByNumber(number)
+		parent := chain.GetHeaderByHash(block.ParentHash())
+		statedb, err := chain.StateAt(parent.Root)
+		if err != nil {
+			return err
+		}
+		if _, err := chain.Processor().Process(block, statedb, vm.Config{}); err != nil {
+			return fmt.Errorf("failed to process block %d: %v", number, err)
+		}
+		if root := statedb.IntermediateRoot(true); root != block.Root() {
+			return fmt.Errorf("state root mismatch in block %d, want %x, got %x", number, block.Root(), root)
+		}
+		if _, err := chain.GenerateWitness(block); err != nil {
+			return fmt.Errorf("failed to generate witness for block %d: %v", number, err)
+		}
+	}
+	return nil
+}
+
+func TestAutomine(t *testing.T) {
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+
+	var (
+		ctx    = context.Background()
+		client = sim.Client()
+	)
+	sim.SetAutomine(true)
+
+	tx, err := newTx(sim, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	timeout := time.After(5 * time.Second)
+	for {
+		if _, err := client.TransactionReceipt(ctx, tx.Hash()); err == nil {
+			break
+		}
+		select {
+		case <-timeout:
+			t.Fatal("transaction not mined")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	sim.SetAutomine(false)
+}
+
+func TestCheatCodesRPC(t *testing.T) {
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+
+	var (
+		ctx    = context.Background()
+		client = sim.Client()
+		rpc    = sim.node.Attach()
+		addr   = common.HexToAddress("0x1234")
+	)
+	defer rpc.Close()
+
+	var id hexutil.Uint64
+	if err := rpc.CallContext(ctx, &id, "sim_snapshot"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rpc.CallContext(ctx, nil, "sim_setBalance", addr, (*hexutil.Big)(big.NewInt(100))); err != nil {
+		t.Fatal(err)
+	}
+	if have, _ := client.BalanceAt(ctx, addr, nil); have.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("unexpected balance, want 100, got %v", have)
+	}
+	if err := rpc.CallContext(ctx, nil, "sim_revert", id); err != nil {
+		t.Fatal(err)
+	}
+	if have, _ := client.BalanceAt(ctx, addr, nil); have.Sign() != 0 {
+		t.Fatalf("unexpected balance after revert, want 0, got %v", have)
+	}
+	var hash common.Hash
+	if err := rpc.CallContext(ctx, &hash, "sim_mine"); err != nil {
+		t.Fatal(err)
+	}
+	if head, _ := client.HeaderByNumber(ctx, nil); head.Hash() != hash {
+		t.Fatalf("unexpected head, want %x, got %x", hash, head.Hash())
+	}
+}
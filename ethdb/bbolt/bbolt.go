@@ -0,0 +1,661 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package bbolt implements the key-value database layer based on bbolt, an
+// mmap-based copy-on-write B+tree storage engine.
+//
+// Compared to the LSM based engines, reads never wait on background compactions
+// and iterators operate on consistent snapshots of the database, at the expense
+// of slower writes.
+package bbolt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// FileName is the name of the database file within the database directory.
+	FileName = "bbolt.db"
+
+	// initialMmapSize is the size of the address space reserved for the database
+	// up front: 1TiB on 64 bit platforms and 1GiB on 32 bit ones.
+	//
+	// Growing the database beyond its mapping requires a remap, which waits for
+	// all open read transactions to finish, including the ones held by live
+	// iterators. A write issued while an iterator is open on the same goroutine
+	// (e.g. iterate-and-delete loops) would deadlock on the remap, so writes which
+	// may trigger one fail while iterators are open, see checkRemap.
+	initialMmapSize = 1 << (30 + 10*(^uint(0)>>63))
+
+	// mmapStep is the increment by which bbolt grows the mapping beyond 1GiB.
+	mmapStep = 1 << 30
+
+	// remapSlack is the margin kept below the end of the mapping when checking
+	// whether a write may trigger a remap, accounting for the pages rewritten on
+	// top of the written data.
+	remapSlack = 64 << 20
+
+	// maxBatchDelay is the maximum time a single key write waits for others to
+	// be coalesced with into the same synced transaction.
+	maxBatchDelay = time.Millisecond
+
+	// openTimeout is the time to wait for the file lock held by another process
+	// before failing to open the database.
+	openTimeout = time.Second
+
+	// metricsGatheringInterval specifies the interval to retrieve bbolt database
+	// transaction and freelist stats to report to the user.
+	metricsGatheringInterval = 3 * time.Second
+)
+
+var (
+	// dataBucket is the bucket holding all the key-value pairs of the database.
+	dataBucket = []byte("ethdb")
+
+	// errNotFound is returned if a key is requested that is not found in the
+	// database.
+	errNotFound = errors.New("not found")
+
+	// errClosed is returned if an operation is attempted on a closed database.
+	errClosed = errors.New("database closed")
+
+	// errMissingBucket is returned if a database opened in read-only mode was
+	// never initialized.
+	errMissingBucket = errors.New("missing data bucket")
+
+	// errRemapWithIterators is returned if a write may grow the database beyond
+	// its memory map while iterators are open.
+	errRemapWithIterators = errors.New("database remap blocked by open iterators")
+)
+
+// Database is a persistent key-value store based on the bbolt storage engine.
+// Apart from basic data storage functionality it also supports batch writes and
+// iterating over snapshots of the keyspace in binary-alphabetical order.
+type Database struct {
+	fn       string    // filename for reporting
+	db       *bbolt.DB // Underlying bbolt storage engine
+	mmapSize int64     // Size of the initial memory map of the database
+	pageSize int64     // Size of the database pages
+
+	diskSizeGauge  *metrics.Gauge // Gauge for tracking the size of the database file
+	freePagesGauge *metrics.Gauge // Gauge for tracking the number of free and pending pages
+	openTxsGauge   *metrics.Gauge // Gauge for tracking the number of open read transactions
+	writeTimeMeter *metrics.Meter // Meter for measuring the time spent writing to disk
+	spillTimeMeter *metrics.Meter // Meter for measuring the time spent spilling dirty nodes
+
+	quitLock sync.RWMutex    // Mutex protecting the quit channel and the closed flag
+	quitChan chan chan error // Quit channel to stop the metrics collection before closing the database
+	closed   bool            // keep track of whether we're Closed
+
+	iterLock  sync.Mutex             // Mutex protecting the live iterator set
+	iterators map[*iterator]struct{} // Live iterators to release on close
+
+	log log.Logger // Contextual logger tracking the database path
+}
+
+// New returns a wrapped bbolt DB object, stored in a single file within the given
+// directory. The namespace is the prefix that the metrics reporting should use
+// for surfacing internal stats.
+func New(dir string, namespace string, readonly bool, ephemeral bool) (*Database, error) {
+	return newDatabase(dir, namespace, readonly, ephemeral, initialMmapSize)
+}
+
+// newDatabase opens the database with the given initial memory map size.
+func newDatabase(dir string, namespace string, readonly bool, ephemeral bool, mmapSize int) (*Database, error) {
+	if !readonly {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, err
+		}
+	}
+	opts := &bbolt.Options{
+		Timeout:         openTimeout,
+		ReadOnly:        readonly,
+		InitialMmapSize: mmapSize,
+		FreelistType:    bbolt.FreelistMapType,
+		NoFreelistSync:  true,
+		NoSync:          ephemeral,
+	}
+	inner, err := bbolt.Open(filepath.Join(dir, FileName), 0600, opts)
+	if err != nil {
+		return nil, err
+	}
+	inner.MaxBatchDelay = maxBatchDelay
+
+	db := &Database{
+		fn:        dir,
+		db:        inner,
+		mmapSize:  int64(mmapSize),
+		pageSize:  int64(inner.Info().PageSize),
+		quitChan:  make(chan chan error),
+		iterators: make(map[*iterator]struct{}),
+		log:       log.New("database", dir),
+	}
+	if err := db.init(readonly); err != nil {
+		inner.Close()
+		return nil, err
+	}
+	db.log.Info("Opened bbolt database", "size", common.StorageSize(db.size()))
+
+	db.diskSizeGauge = metrics.GetOrRegisterGauge(namespace+"disk/size", nil)
+	db.freePagesGauge = metrics.GetOrRegisterGauge(namespace+"freelist/pages", nil)
+	db.openTxsGauge = metrics.GetOrRegisterGauge(namespace+"txs/open", nil)
+	db.writeTimeMeter = metrics.GetOrRegisterMeter(namespace+"write/time", nil)
+	db.spillTimeMeter = metrics.GetOrRegisterMeter(namespace+"spill/time", nil)
+
+	// Start up the metrics gathering and return
+	go db.meter(metricsGatheringInterval)
+	return db, nil
+}
+
+// init ensures the data bucket exists, creating it unless in read-only mode.
+func (d *Database) init(readonly bool) error {
+	if readonly {
+		return d.db.View(func(tx *bbolt.Tx) error {
+			if tx.Bucket(dataBucket) == nil {
+				return errMissingBucket
+			}
+			return nil
+		})
+	}
+	return d.db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(dataBucket)
+		return err
+	})
+}
+
+// size returns the current size of the database file.
+func (d *Database) size() int64 {
+	var size int64
+	d.db.View(func(tx *bbolt.Tx) error {
+		size = tx.Size()
+		return nil
+	})
+	return size
+}
+
+// Close stops the metrics collection, releases any leaked iterators and closes
+// all io accesses to the underlying key-value store.
+func (d *Database) Close() error {
+	d.quitLock.Lock()
+	defer d.quitLock.Unlock()
+	// Allow double closing, simplifies things
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	if d.quitChan != nil {
+		errc := make(chan error)
+		d.quitChan <- errc
+		if err := <-errc; err != nil {
+			d.log.Error("Metrics collection failed", "err", err)
+		}
+		d.quitChan = nil
+	}
+	// Open read transactions would block closing the database forever
+	d.iterLock.Lock()
+	if len(d.iterators) > 0 {
+		d.log.Error("Releasing leaked iterators", "count", len(d.iterators))
+		for it := range d.iterators {
+			it.release()
+		}
+		clear(d.iterators)
+	}
+	d.iterLock.Unlock()
+
+	return d.db.Close()
+}
+
+// Has retrieves if a key is present in the key-value store.
+func (d *Database) Has(key []byte) (bool, error) {
+	d.quitLock.RLock()
+	defer d.quitLock.RUnlock()
+	if d.closed {
+		return false, errClosed
+	}
+	var found bool
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		_, found = get(tx, key)
+		return nil
+	})
+	return found, err
+}
+
+// Get retrieves the given key if it's present in the key-value store.
+func (d *Database) Get(key []byte) ([]byte, error) {
+	d.quitLock.RLock()
+	defer d.quitLock.RUnlock()
+	if d.closed {
+		return nil, errClosed
+	}
+	var value []byte
+	err := d.db.View(func(tx *bbolt.Tx) error {
+		blob, found := get(tx, key)
+		if !found {
+			return errNotFound
+		}
+		// The value is only valid during the transaction
+		value = common.CopyBytes(blob)
+		if value == nil {
+			value = []byte{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// get looks up a key in the data bucket. Values stored empty are returned as
+// nil, so the presence of the key is reported separately.
+func get(tx *bbolt.Tx, key []byte) ([]byte, bool) {
+	k, v := tx.Bucket(dataBucket).Cursor().Seek(key)
+	if k == nil || !bytes.Equal(k, key) {
+		return nil, false
+	}
+	return v, true
+}
+
+// Put inserts the given value into the key-value store.
+func (d *Database) Put(key []byte, value []byte) error {
+	d.quitLock.RLock()
+	defer d.quitLock.RUnlock()
+	if d.closed {
+		return errClosed
+	}
+	return d.batch(d.growth(1, len(key)+len(value)), func(bucket *bbolt.Bucket) error {
+		return bucket.Put(key, value)
+	})
+}
+
+// Delete removes the key from the key-value store.
+func (d *Database) Delete(key []byte) error {
+	d.quitLock.RLock()
+	defer d.quitLock.RUnlock()
+	if d.closed {
+		return errClosed
+	}
+	return d.batch(d.growth(1, len(key)), func(bucket *bbolt.Bucket) error {
+		return bucket.Delete(key)
+	})
+}
+
+// DeleteRange deletes all of the keys (and values) in the range [start,end)
+// (inclusive on start, exclusive on end).
+func (d *Database) DeleteRange(start, end []byte) error {
+	d.quitLock.RLock()
+	defer d.quitLock.RUnlock()
+	if d.closed {
+		return errClosed
+	}
+	return d.update(0, func(bucket *bbolt.Bucket) error {
+		cursor := bucket.Cursor()
+		for k, _ := cursor.Seek(start); k != nil && bytes.Compare(k, end) < 0; k, _ = cursor.Next() {
+			if err := cursor.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// update executes fn within a read-write transaction on the data bucket. The
+// growth is an estimate of the space the transaction may allocate. Unless the
+// database is ephemeral, the transaction is synced to disk.
+func (d *Database) update(growth int64, fn func(bucket *bbolt.Bucket) error) error {
+	return d.db.Update(func(tx *bbolt.Tx) error {
+		if err := d.checkRemap(tx, growth); err != nil {
+			return err
+		}
+		return fn(tx.Bucket(dataBucket))
+	})
+}
+
+// batch is like update, but coalesces the transaction with the concurrent ones,
+// amortizing the cost of syncing them to disk. As fn may be re-executed if a
+// coalesced transaction fails, it must be idempotent.
+func (d *Database) batch(growth int64, fn func(bucket *bbolt.Bucket) error) error {
+	return d.db.Batch(func(tx *bbolt.Tx) error {
+		if err := d.checkRemap(tx, growth); err != nil {
+			return err
+		}
+		return fn(tx.Bucket(dataBucket))
+	})
+}
+
+// growth estimates the space allocated by a transaction writing the given number
+// of keys with the given size, accounting for the rewritten leaf and branch pages.
+func (d *Database) growth(keys int, size int) int64 {
+	return int64(size) + int64(keys)*2*d.pageSize
+}
+
+// checkRemap fails the write transaction if it may grow the database beyond its
+// memory map while iterators are open. The remap waits for all read transactions
+// to finish, which never happens if an iterator is held by the writing goroutine
+// itself. As the owner of an iterator is unknown, all such writes fail instead.
+func (d *Database) checkRemap(tx *bbolt.Tx, growth int64) error {
+	size := tx.Size()
+	if size+growth+remapSlack < mmapSize(size, d.mmapSize) {
+		return nil
+	}
+	d.iterLock.Lock()
+	defer d.iterLock.Unlock()
+
+	if len(d.iterators) > 0 {
+		d.log.Warn("Rejecting write remapping the database with open iterators", "size", common.StorageSize(size), "iterators", len(d.iterators))
+		return errRemapWithIterators
+	}
+	return nil
+}
+
+// mmapSize returns the minimum size of the memory map of a database of the given
+// size, following the growth strategy of bbolt: the map is at least the initial
+// size, doubled up to 1GiB and grown in 1GiB steps beyond.
+func mmapSize(size int64, initial int64) int64 {
+	size = max(size, initial)
+	for i := 15; i <= 30; i++ {
+		if size <= 1<<i {
+			return 1 << i
+		}
+	}
+	return (size + mmapStep - 1) / mmapStep * mmapStep
+}
+
+// NewBatch creates a write-only key-value store that buffers changes to its host
+// database until a final write is called.
+func (d *Database) NewBatch() ethdb.Batch {
+	return &batch{db: d}
+}
+
+// NewBatchWithSize creates a write-only database batch with pre-allocated buffer.
+func (d *Database) NewBatchWithSize(size int) ethdb.Batch {
+	return &batch{db: d}
+}
+
+// Stat returns the internal transaction and freelist statistics of bbolt in a
+// text format.
+func (d *Database) Stat() (string, error) {
+	d.quitLock.RLock()
+	defer d.quitLock.RUnlock()
+	if d.closed {
+		return "", errClosed
+	}
+	var (
+		stats = d.db.Stats()
+		txs   = stats.TxStats
+	)
+	return fmt.Sprintf("size: %v\nfree pages: %d (pending %d, %v allocated)\n"+
+		"read txs: %d (open %d)\npage allocs: %d (%v)\nnode splits: %d, spills: %d (%v), rebalances: %d (%v)\nwrites: %d (%v)\n",
+		common.StorageSize(d.size()), stats.FreePageN, stats.PendingPageN, common.StorageSize(stats.FreeAlloc),
+		stats.TxN, stats.OpenTxN, txs.GetPageCount(), common.StorageSize(txs.GetPageAlloc()),
+		txs.GetSplit(), txs.GetSpill(), txs.GetSpillTime(), txs.GetRebalance(), txs.GetRebalanceTime(),
+		txs.GetWrite(), txs.GetWriteTime()), nil
+}
+
+// Compact is a noop for bbolt: pages freed by deletions and overwrites are reused
+// by later writes, and the database file never shrinks in place.
+func (d *Database) Compact(start []byte, limit []byte) error {
+	return nil
+}
+
+// Path returns the path to the database directory.
+func (d *Database) Path() string {
+	return d.fn
+}
+
+// meter periodically retrieves internal bbolt counters and reports them to
+// the metrics subsystem.
+func (d *Database) meter(refresh time.Duration) {
+	var errc chan error
+	timer := time.NewTimer(refresh)
+	defer timer.Stop()
+
+	// Iterate ad infinitum and collect the stats
+	var (
+		prev   bbolt.Stats
+		warned bool
+		limit  = mmapSize(0, d.mmapSize) / 10 * 9
+	)
+	for errc == nil {
+		stats := d.db.Stats()
+		diff := stats.Sub(&prev)
+		prev = stats
+
+		size := d.size()
+		if size > limit && !warned {
+			d.log.Warn("Database nearing its memory map size, writes will fail while iterators are open", "size", common.StorageSize(size), "mmap", common.StorageSize(d.mmapSize))
+			warned = true
+		}
+		d.diskSizeGauge.Update(size)
+		d.freePagesGauge.Update(int64(stats.FreePageN + stats.PendingPageN))
+		d.openTxsGauge.Update(int64(stats.OpenTxN))
+		d.writeTimeMeter.Mark(int64(diff.TxStats.GetWriteTime()))
+		d.spillTimeMeter.Mark(int64(diff.TxStats.GetSpillTime()))
+
+		// Sleep a bit, then repeat the stats collection
+		select {
+		case errc = <-d.quitChan:
+			// Quit requesting, stop hammering the database
+		case <-timer.C:
+			timer.Reset(refresh)
+			// Timeout, gather a new set of stats
+		}
+	}
+	errc <- nil
+}
+
+// keyvalue is a key-value tuple tagged with a deletion field to allow creating
+// database write batches.
+type keyvalue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch is a write-only batch that commits changes to its host database
+// when Write is called. A batch cannot be used concurrently.
+type batch struct {
+	db     *Database
+	writes []keyvalue
+	size   int
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyvalue{common.CopyBytes(key), common.CopyBytes(value), false})
+	b.size += len(key) + len(value)
+	return nil
+}
+
+// Delete inserts the key removal into the batch for later committing.
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyvalue{common.CopyBytes(key), nil, true})
+	b.size += len(key)
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int {
+	return b.size
+}
+
+// Write flushes any accumulated data to disk in a single transaction.
+func (b *batch) Write() error {
+	b.db.quitLock.RLock()
+	defer b.db.quitLock.RUnlock()
+	if b.db.closed {
+		return errClosed
+	}
+	return b.db.update(b.db.growth(len(b.writes), b.size), func(bucket *bbolt.Bucket) error {
+		for _, kv := range b.writes {
+			if kv.delete {
+				if err := bucket.Delete(kv.key); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := bucket.Put(kv.key, kv.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w ethdb.KeyValueWriter) error {
+	for _, kv := range b.writes {
+		if kv.delete {
+			if err := w.Delete(kv.key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.Put(kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterator is a cursor over a read transaction of the database, seeing the
+// snapshot of the key space at the time of its creation. The transaction is
+// held open until the iterator is released.
+//
+// The iterator is not thread-safe.
+type iterator struct {
+	db     *Database
+	tx     *bbolt.Tx
+	cursor *bbolt.Cursor
+
+	prefix  []byte
+	start   []byte // Key to seek to on the first move
+	started bool   // Whether the cursor was already positioned
+	key     []byte
+	value   []byte
+	done    bool
+	err     error
+}
+
+// NewIterator creates a binary-alphabetical iterator over a subset
+// of database content with a particular key prefix, starting at a particular
+// initial key (or after, if it does not exist).
+func (d *Database) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
+	d.quitLock.RLock()
+	defer d.quitLock.RUnlock()
+	if d.closed {
+		return &iterator{done: true, err: errClosed}
+	}
+	tx, err := d.db.Begin(false)
+	if err != nil {
+		return &iterator{done: true, err: err}
+	}
+	it := &iterator{
+		db:     d,
+		tx:     tx,
+		cursor: tx.Bucket(dataBucket).Cursor(),
+		prefix: common.CopyBytes(prefix),
+		start:  append(common.CopyBytes(prefix), start...),
+	}
+	d.iterLock.Lock()
+	d.iterators[it] = struct{}{}
+	d.iterLock.Unlock()
+
+	return it
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *iterator) Next() bool {
+	if it.done {
+		return false
+	}
+	var k, v []byte
+	if !it.started {
+		k, v = it.cursor.Seek(it.start)
+		it.started = true
+	} else {
+		k, v = it.cursor.Next()
+	}
+	if k == nil || !bytes.HasPrefix(k, it.prefix) {
+		it.key, it.value, it.done = nil, nil, true
+		return false
+	}
+	it.key, it.value = k, v
+	return true
+}
+
+// Error returns any accumulated error. Exhausting all the key/value pairs
+// is not considered to be an error.
+func (it *iterator) Error() error {
+	return it.err
+}
+
+// Key returns the key of the current key/value pair, or nil if done. The caller
+// should not modify the contents of the returned slice, and its contents may
+// change on the next call to Next.
+func (it *iterator) Key() []byte {
+	return it.key
+}
+
+// Value returns the value of the current key/value pair, or nil if done. The
+// caller should not modify the contents of the returned slice, and its contents
+// may change on the next call to Next.
+func (it *iterator) Value() []byte {
+	return it.value
+}
+
+// Release releases associated resources. Release should always succeed and can
+// be called multiple times without causing error.
+func (it *iterator) Release() {
+	if it.db == nil {
+		return
+	}
+	it.db.iterLock.Lock()
+	defer it.db.iterLock.Unlock()
+
+	delete(it.db.iterators, it)
+	it.release()
+}
+
+// release rolls back the read transaction of the iterator.
+//
+// Note, this method assumes the database iterator lock is held!
+func (it *iterator) release() {
+	if it.tx != nil {
+		it.tx.Rollback()
+		it.tx, it.cursor = nil, nil
+	}
+	it.key, it.value, it.done = nil, nil, true
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bbolt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/dbtest"
+)
+
+func TestBboltDB(t *testing.T) {
+	t.Run("DatabaseSuite", func(t *testing.T) {
+		dbtest.TestDatabaseSuite(t, func() ethdb.KeyValueStore {
+			db, err := New(t.TempDir(), "", false, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return db
+		})
+	})
+}
+
+// Tests that iterators see the snapshot of the database at their creation, and
+// that writes are not blocked by live iterators.
+func TestBboltIteratorSnapshot(t *testing.T) {
+	db, err := New(t.TempDir(), "", false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, key := range []string{"a", "b", "c"} {
+		db.Put([]byte(key), []byte(key))
+	}
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+
+	db.Put([]byte("b"), []byte("updated"))
+	db.Delete([]byte("c"))
+	db.Put([]byte("d"), []byte("d"))
+
+	var keys, values []byte
+	for it.Next() {
+		keys = append(keys, it.Key()...)
+		values = append(values, it.Value()...)
+	}
+	if !bytes.Equal(keys, []byte("abc")) || !bytes.Equal(values, []byte("abc")) {
+		t.Fatalf("snapshot mismatch: have keys %q values %q, want %q", keys, values, "abc")
+	}
+	if value, _ := db.Get([]byte("b")); !bytes.Equal(value, []byte("updated")) {
+		t.Fatalf("write mismatch: have %q, want %q", value, "updated")
+	}
+}
+
+// Tests that closing the database releases any leaked iterators instead of
+// blocking on their transactions.
+func TestBboltLeakedIterator(t *testing.T) {
+	dir := t.TempDir()
+	db, err := New(dir, "", false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Put([]byte("key"), []byte("value"))
+
+	it := db.NewIterator(nil, nil)
+	if err := db.Close(); err != nil {
+		t.Fatalf("failed to close database: %v", err)
+	}
+	if it.Next() {
+		t.Fatal("released iterator yielded data")
+	}
+	it.Release()
+
+	// Reopen the database read-only and ensure the data persisted
+	db, err = New(dir, "", true, false)
+	if err != nil {
+		t.Fatalf("failed to reopen database: %v", err)
+	}
+	defer db.Close()
+
+	if value, err := db.Get([]byte("key")); err != nil || !bytes.Equal(value, []byte("value")) {
+		t.Fatalf("value mismatch: have %q, %v, want %q", value, err, "value")
+	}
+}
+
+// Tests that both the coalesced single key writes and the batch writes of a
+// persistent database survive reopening it.
+func TestBboltDurability(t *testing.T) {
+	dir := t.TempDir()
+	db, err := New(dir, "", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Put([]byte("single"), []byte("value"))
+	db.Put([]byte("deleted"), []byte("value"))
+	db.Delete([]byte("deleted"))
+
+	batch := db.NewBatch()
+	batch.Put([]byte("batch"), []byte("value"))
+	if err := batch.Write(); err != nil {
+		t.Fatalf("failed to write batch: %v", err)
+	}
+	db.Close()
+
+	if db, err = New(dir, "", true, false); err != nil {
+		t.Fatalf("failed to reopen database: %v", err)
+	}
+	defer db.Close()
+
+	for _, key := range []string{"single", "batch"} {
+		if value, err := db.Get([]byte(key)); err != nil || !bytes.Equal(value, []byte("value")) {
+			t.Fatalf("value mismatch for %q: have %q, %v, want %q", key, value, err, "value")
+		}
+	}
+	if has, _ := db.Has([]byte("deleted")); has {
+		t.Fatal("deleted key persisted")
+	}
+}
+
+// Tests that writes growing the database beyond its memory map fail while
+// iterators are open, instead of deadlocking on the remap.
+func TestBboltRemapWithIterators(t *testing.T) {
+	db, err := newDatabase(t.TempDir(), "", false, true, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	// Write enough data to outgrow the memory map with an iterator open
+	batch := db.NewBatch()
+	for i := 0; i < 1024; i++ {
+		batch.Put([]byte{byte(i >> 8), byte(i)}, make([]byte, 4096))
+	}
+	it := db.NewIterator(nil, nil)
+	if err := batch.Write(); !errors.Is(err, errRemapWithIterators) {
+		t.Fatalf("unexpected batch error: have %v, want %v", err, errRemapWithIterators)
+	}
+	if err := db.Put([]byte("key"), []byte("updated")); !errors.Is(err, errRemapWithIterators) {
+		t.Fatalf("unexpected write error: have %v, want %v", err, errRemapWithIterators)
+	}
+	it.Release()
+
+	// Once the iterator is released, the database can be remapped
+	if err := batch.Write(); err != nil {
+		t.Fatalf("failed to write batch: %v", err)
+	}
+	if err := db.Put([]byte("key"), []byte("updated")); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if value, _ := db.Get([]byte("key")); !bytes.Equal(value, []byte("updated")) {
+		t.Fatalf("value mismatch: have %q, want %q", value, "updated")
+	}
+}
+
+func TestMmapSize(t *testing.T) {
+	tests := []struct {
+		size, initial, want int64
+	}{
+		{0, 1 << 20, 1 << 20},
+		{1<<20 + 1, 1 << 20, 1 << 21},
+		{1 << 30, 1 << 20, 1 << 30},
+		{1<<30 + 1, 1 << 20, 2 << 30},
+		{0, 1 << 40, 1 << 40},
+		{1<<40 + 1, 1 << 40, 1<<40 + 1<<30},
+	}
+	for _, test := range tests {
+		if have := mmapSize(test.size, test.initial); have != test.want {
+			t.Errorf("size %d, initial %d: have %d, want %d", test.size, test.initial, have, test.want)
+		}
+	}
+}
+
+func BenchmarkBboltDB(b *testing.B) {
+	dbtest.BenchDatabaseSuite(b, func() ethdb.KeyValueStore {
+		db, err := New(b.TempDir(), "", false, true)
+		if err != nil {
+			b.Fatal(err)
+		}
+		return db
+	})
+}
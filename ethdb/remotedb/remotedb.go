@@ -15,39 +15,92 @@
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
 // Package remotedb implements the key-value database layer based on a remote geth
-// node. Under the hood, it utilises the `debug_dbGet` method to implement a
-// read-only database.
-// There really are no guarantees in this database, since the local geth does not
-// exclusive access, but it can be used for basic diagnostics of a remote node.
+// node. Under the hood, it utilises the `db` RPC namespace exposed by a node on
+// its authenticated endpoint (enabled via --remotedb.serve) to implement a
+// writable key-value database with read-only access to the ancient store. If the
+// remote node does not serve the `db` namespace, basic reads fall back to the
+// `debug_dbGet`, `debug_dbAncient` and `debug_dbAncients` methods.
+//
+// There really are no guarantees in this database beyond the atomicity of single
+// batches, since the local geth does not have exclusive access, but it can be
+// used for diagnostics and maintenance of a remote node without stopping it.
+// Note, writes go straight to the database of the remote node, bypassing all of
+// its in-memory caches, so the server refuses writes to the chain and state data
+// the node is actively using.
 package remotedb
 
 import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
-// Database is a key-value lookup for a remote database via debug_dbGet.
+// methodNotFoundCode is the JSON-RPC error code of calls to unknown methods.
+const methodNotFoundCode = -32601
+
+// errNotSupported is returned for operations not available on remote databases.
+var errNotSupported = fmt.Errorf("remote database: %w", errors.ErrUnsupported)
+
+// Database is a key-value store backed by the database of a remote node.
 type Database struct {
 	remote *rpc.Client
+	legacy atomic.Bool // Whether the remote only serves the debug namespace
+}
+
+// fallback reports whether the request failed because the remote node does not
+// serve the db namespace, switching the reads over to the debug namespace if so.
+func (db *Database) fallback(err error) bool {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != methodNotFoundCode {
+		return false
+	}
+	if !db.legacy.Swap(true) {
+		log.Warn("Remote database namespace unavailable, falling back to read-only debug methods", "namespace", Namespace)
+	}
+	return true
 }
 
+// Has retrieves if a key is present in the key-value data store.
 func (db *Database) Has(key []byte) (bool, error) {
-	if _, err := db.Get(key); err != nil {
-		return false, err
+	if db.legacy.Load() {
+		if _, err := db.Get(key); err != nil {
+			return false, err
+		}
+		return true, nil
 	}
-	return true, nil
+	var resp bool
+	err := db.remote.Call(&resp, Namespace+"_has", hexutil.Bytes(key))
+	if db.fallback(err) {
+		return db.Has(key)
+	}
+	return resp, err
 }
 
+// Get retrieves the given key if it's present in the key-value data store.
 func (db *Database) Get(key []byte) ([]byte, error) {
-	var resp hexutil.Bytes
-	err := db.remote.Call(&resp, "debug_dbGet", hexutil.Bytes(key))
+	var (
+		resp hexutil.Bytes
+		err  error
+	)
+	if db.legacy.Load() {
+		err = db.remote.Call(&resp, "debug_dbGet", hexutil.Bytes(key))
+	} else if err = db.remote.Call(&resp, Namespace+"_get", hexutil.Bytes(key)); db.fallback(err) {
+		return db.Get(key)
+	}
 	if err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// HasAncient returns an indicator whether the specified data exists in the
+// ancient store.
 func (db *Database) HasAncient(kind string, number uint64) (bool, error) {
 	if _, err := db.Ancient(kind, number); err != nil {
 		return false, err
@@ -55,97 +108,305 @@ func (db *Database) HasAncient(kind string, number uint64) (bool, error) {
 	return true, nil
 }
 
+// Ancient retrieves an ancient binary blob from the append-only immutable files.
 func (db *Database) Ancient(kind string, number uint64) ([]byte, error) {
-	var resp hexutil.Bytes
-	err := db.remote.Call(&resp, "debug_dbAncient", kind, number)
+	var (
+		resp hexutil.Bytes
+		err  error
+	)
+	if db.legacy.Load() {
+		err = db.remote.Call(&resp, "debug_dbAncient", kind, number)
+	} else if err = db.remote.Call(&resp, Namespace+"_ancient", kind, hexutil.Uint64(number)); db.fallback(err) {
+		return db.Ancient(kind, number)
+	}
 	if err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// AncientRange retrieves multiple items in sequence, starting from the index
+// 'start'. Large ranges are retrieved in multiple requests.
 func (db *Database) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
-	panic("not supported")
+	var (
+		items [][]byte
+		size  uint64
+	)
+	for uint64(len(items)) < count {
+		var (
+			request = min(count-uint64(len(items)), maxPageItems)
+			budget  uint64
+			resp    []hexutil.Bytes
+		)
+		if maxBytes > 0 {
+			budget = maxBytes - size
+		}
+		err := db.remote.Call(&resp, Namespace+"_ancientRange", kind, hexutil.Uint64(start+uint64(len(items))), hexutil.Uint64(request), hexutil.Uint64(budget))
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp {
+			// Only the very first item is allowed to exceed the size cap
+			if maxBytes > 0 && len(items) > 0 && size+uint64(len(item)) > maxBytes {
+				return items, nil
+			}
+			items = append(items, item)
+			size += uint64(len(item))
+		}
+		// Stop if the remote ran out of items or the size cap was reached
+		if uint64(len(resp)) < request || (maxBytes > 0 && size >= maxBytes) {
+			break
+		}
+	}
+	return items, nil
 }
 
+// Ancients returns the ancient item numbers in the ancient store.
 func (db *Database) Ancients() (uint64, error) {
-	var resp uint64
-	err := db.remote.Call(&resp, "debug_dbAncients")
-	return resp, err
+	if db.legacy.Load() {
+		var resp uint64
+		err := db.remote.Call(&resp, "debug_dbAncients")
+		return resp, err
+	}
+	var resp hexutil.Uint64
+	err := db.remote.Call(&resp, Namespace+"_ancients")
+	if db.fallback(err) {
+		return db.Ancients()
+	}
+	return uint64(resp), err
 }
 
+// Tail returns the number of first stored item in the ancient store.
 func (db *Database) Tail() (uint64, error) {
-	panic("not supported")
+	var resp hexutil.Uint64
+	err := db.remote.Call(&resp, Namespace+"_tail")
+	return uint64(resp), err
 }
 
+// AncientSize returns the ancient size of the specified category.
 func (db *Database) AncientSize(kind string) (uint64, error) {
-	panic("not supported")
+	var resp hexutil.Uint64
+	err := db.remote.Call(&resp, Namespace+"_ancientSize", kind)
+	return uint64(resp), err
 }
 
+// ReadAncients runs the given read operation. Note, the remote ancient store may
+// be modified by the remote node during the operation.
 func (db *Database) ReadAncients(fn func(op ethdb.AncientReaderOp) error) (err error) {
 	return fn(db)
 }
 
+// Put inserts the given value into the key-value data store.
 func (db *Database) Put(key []byte, value []byte) error {
-	panic("not supported")
+	return db.remote.Call(nil, Namespace+"_put", hexutil.Bytes(key), hexutil.Bytes(value))
 }
 
+// Delete removes the key from the key-value data store.
 func (db *Database) Delete(key []byte) error {
-	panic("not supported")
+	return db.remote.Call(nil, Namespace+"_delete", hexutil.Bytes(key))
 }
 
+// DeleteRange deletes all of the keys (and values) in the range [start,end).
 func (db *Database) DeleteRange(start, end []byte) error {
-	panic("not supported")
+	return db.remote.Call(nil, Namespace+"_deleteRange", hexutil.Bytes(start), hexutil.Bytes(end))
 }
 
+// ModifyAncients is not supported, the ancient store of the remote node is
+// read-only.
 func (db *Database) ModifyAncients(f func(ethdb.AncientWriteOp) error) (int64, error) {
-	panic("not supported")
+	return 0, errNotSupported
 }
 
+// TruncateHead is not supported, the ancient store of the remote node is
+// read-only.
 func (db *Database) TruncateHead(n uint64) (uint64, error) {
-	panic("not supported")
+	return 0, errNotSupported
 }
 
+// TruncateTail is not supported, the ancient store of the remote node is
+// read-only.
 func (db *Database) TruncateTail(n uint64) (uint64, error) {
-	panic("not supported")
+	return 0, errNotSupported
 }
 
+// Sync is a noop, key-value writes are persisted by the remote node.
 func (db *Database) Sync() error {
 	return nil
 }
 
+// NewBatch creates a write-only key-value store that buffers changes to the
+// remote database until a final write is called.
 func (db *Database) NewBatch() ethdb.Batch {
-	panic("not supported")
+	return &batch{db: db}
 }
 
+// NewBatchWithSize creates a write-only database batch with pre-allocated buffer.
 func (db *Database) NewBatchWithSize(size int) ethdb.Batch {
-	panic("not supported")
+	return &batch{db: db}
 }
 
+// NewIterator creates a binary-alphabetical iterator over a subset of database
+// content with a particular key prefix, starting at a particular initial key.
+// The entries are retrieved from the remote database page by page.
 func (db *Database) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
-	panic("not supported")
+	return &iterator{
+		db:     db,
+		prefix: common.CopyBytes(prefix),
+		next:   common.CopyBytes(start),
+		pos:    -1,
+	}
 }
 
+// Stat returns the statistic data of the remote database.
 func (db *Database) Stat() (string, error) {
-	return "", nil
+	var resp string
+	err := db.remote.Call(&resp, Namespace+"_stat")
+	return resp, err
 }
 
+// AncientDatadir is not supported, the ancient store is not accessible locally.
 func (db *Database) AncientDatadir() (string, error) {
-	panic("not supported")
+	return "", errNotSupported
 }
 
+// Compact flattens the remote data store for the given key range.
 func (db *Database) Compact(start []byte, limit []byte) error {
-	return nil
+	return db.remote.Call(nil, Namespace+"_compact", hexutil.Bytes(start), hexutil.Bytes(limit))
 }
 
+// Close closes the connection to the remote node.
 func (db *Database) Close() error {
 	db.remote.Close()
 	return nil
 }
 
+// New creates a database backed by the database of the remote node the client
+// is connected to.
 func New(client *rpc.Client) ethdb.Database {
 	if client == nil {
 		return nil
 	}
 	return &Database{remote: client}
 }
+
+// batch is a write-only batch that commits changes to the remote database
+// atomically when Write is called. A batch cannot be used concurrently.
+type batch struct {
+	db   *Database
+	ops  []BatchOp
+	size int
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *batch) Put(key, value []byte) error {
+	b.ops = append(b.ops, BatchOp{Key: common.CopyBytes(key), Value: common.CopyBytes(value)})
+	b.size += len(key) + len(value)
+	return nil
+}
+
+// Delete inserts a key removal into the batch for later committing.
+func (b *batch) Delete(key []byte) error {
+	b.ops = append(b.ops, BatchOp{Key: common.CopyBytes(key), Delete: true})
+	b.size += len(key)
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int {
+	return b.size
+}
+
+// Write flushes any accumulated data to the remote database in one request.
+func (b *batch) Write() error {
+	if len(b.ops) == 0 {
+		return nil
+	}
+	return b.db.remote.Call(nil, Namespace+"_write", b.ops)
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.ops = b.ops[:0]
+	b.size = 0
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w ethdb.KeyValueWriter) error {
+	for _, op := range b.ops {
+		if op.Delete {
+			if err := w.Delete(op.Key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.Put(op.Key, op.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterator walks the entries of the remote database, retrieving them in pages.
+type iterator struct {
+	db     *Database
+	prefix []byte
+	next   []byte // Start of the next page to retrieve, nil if exhausted
+	page   *Page  // Current page of entries
+	pos    int    // Position of the current entry in the page
+	err    error
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.page == nil || it.pos+1 >= len(it.page.Keys) {
+		// Current page exhausted, retrieve the next one if there's any
+		if it.page != nil && it.page.Next == nil {
+			it.pos = len(it.page.Keys)
+			return false
+		}
+		page := new(Page)
+		if err := it.db.remote.Call(page, Namespace+"_iterate", hexutil.Bytes(it.prefix), hexutil.Bytes(it.next), maxPageItems); err != nil {
+			it.err = err
+			it.page = nil
+			return false
+		}
+		if len(page.Keys) != len(page.Values) {
+			it.err = errors.New("remote returned malformed iteration page")
+			it.page = nil
+			return false
+		}
+		it.page, it.pos, it.next = page, -1, page.Next
+	}
+	it.pos++
+	return true
+}
+
+// Error returns any accumulated error.
+func (it *iterator) Error() error {
+	return it.err
+}
+
+// Key returns the key of the current key/value pair, or nil if done.
+func (it *iterator) Key() []byte {
+	if it.page == nil || it.pos < 0 || it.pos >= len(it.page.Keys) {
+		return nil
+	}
+	return it.page.Keys[it.pos]
+}
+
+// Value returns the value of the current key/value pair, or nil if done.
+func (it *iterator) Value() []byte {
+	if it.page == nil || it.pos < 0 || it.pos >= len(it.page.Values) {
+		return nil
+	}
+	return it.page.Values[it.pos]
+}
+
+// Release releases associated resources.
+func (it *iterator) Release() {
+	it.page, it.next = new(Page), nil
+}
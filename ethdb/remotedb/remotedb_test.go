@@ -0,0 +1,262 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotedb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/dbtest"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// newTestDatabase serves the given database over an in-process RPC server and
+// returns a remote database connected to it.
+func newTestDatabase(t *testing.T, db ethdb.Database) ethdb.Database {
+	return newProtectedTestDatabase(t, db, nil)
+}
+
+// newProtectedTestDatabase is like newTestDatabase, but refuses writes to the
+// keys reported by the protected function.
+func newProtectedTestDatabase(t *testing.T, db ethdb.Database, protected func([]byte) bool) ethdb.Database {
+	server := rpc.NewServer()
+	if err := server.RegisterName(Namespace, NewServer(db, protected)); err != nil {
+		t.Fatalf("failed to register database server: %v", err)
+	}
+	t.Cleanup(server.Stop)
+	return New(rpc.DialInProc(server))
+}
+
+func TestRemoteDB(t *testing.T) {
+	t.Run("DatabaseSuite", func(t *testing.T) {
+		dbtest.TestDatabaseSuite(t, func() ethdb.KeyValueStore {
+			return newTestDatabase(t, rawdb.NewMemoryDatabase())
+		})
+	})
+}
+
+// Tests that iterations spanning multiple pages return the same entries as a
+// local iteration.
+func TestRemoteIteratorPaging(t *testing.T) {
+	local := rawdb.NewMemoryDatabase()
+	for i := 0; i < 2*maxPageItems+10; i++ {
+		key := binary.BigEndian.AppendUint32([]byte{byte(i % 2)}, uint32(i))
+		local.Put(key, bytes.Repeat([]byte{byte(i)}, i%7))
+	}
+	remote := newTestDatabase(t, local)
+
+	for _, tt := range []struct{ prefix, start []byte }{
+		{nil, nil},
+		{[]byte{1}, nil},
+		{[]byte{0}, binary.BigEndian.AppendUint32(nil, maxPageItems-1)},
+		{nil, []byte{1, 0, 0, 0x20}},
+		{[]byte{2}, nil},
+	} {
+		var (
+			want = local.NewIterator(tt.prefix, tt.start)
+			have = remote.NewIterator(tt.prefix, tt.start)
+			n    int
+		)
+		for want.Next() {
+			if !have.Next() {
+				t.Fatalf("prefix %x start %x: remote exhausted after %d entries: %v", tt.prefix, tt.start, n, have.Error())
+			}
+			if !bytes.Equal(have.Key(), want.Key()) || !bytes.Equal(have.Value(), want.Value()) {
+				t.Fatalf("prefix %x start %x: entry %d mismatch: have %x=%x, want %x=%x", tt.prefix, tt.start, n, have.Key(), have.Value(), want.Key(), want.Value())
+			}
+			n++
+		}
+		if have.Next() {
+			t.Fatalf("prefix %x start %x: remote not exhausted after %d entries", tt.prefix, tt.start, n)
+		}
+		if err := have.Error(); err != nil {
+			t.Fatalf("prefix %x start %x: iteration failed: %v", tt.prefix, tt.start, err)
+		}
+		want.Release()
+		have.Release()
+	}
+}
+
+// Tests that ancient ranges are retrieved in multiple requests if needed, and
+// that the size caps are honoured the same way as by a local store.
+func TestRemoteAncientRange(t *testing.T) {
+	local, err := rawdb.NewDatabaseWithFreezer(memorydb.New(), t.TempDir(), "", false)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer local.Close()
+
+	headers := make([]*types.Header, maxPageItems+100)
+	for i := range headers {
+		headers[i] = &types.Header{Number: big.NewInt(int64(i)), Extra: make([]byte, i%32)}
+	}
+	if _, err := rawdb.WriteAncientHeaderChain(local, headers); err != nil {
+		t.Fatalf("failed to write ancients: %v", err)
+	}
+	remote := newTestDatabase(t, local)
+
+	// Size cap reached only in the second page of the range
+	var capped uint64
+	for i := 0; i < maxPageItems+50; i++ {
+		blob, _ := local.Ancient(rawdb.ChainFreezerHeaderTable, uint64(i))
+		capped += uint64(len(blob))
+	}
+	if have, _ := remote.Ancients(); have != uint64(len(headers)) {
+		t.Fatalf("ancient count mismatch: have %d, want %d", have, len(headers))
+	}
+	for _, tt := range []struct{ start, count, maxBytes uint64 }{
+		{0, 10, 0},
+		{0, uint64(len(headers)), 0},
+		{50, uint64(len(headers)), 0},
+		{10, maxPageItems + 50, 0},
+		{0, uint64(len(headers)), 1},
+		{0, uint64(len(headers)), 100_000},
+		{100, maxPageItems + 10, 600_000},
+		{0, uint64(len(headers)), capped},
+		{0, uint64(len(headers)), capped + 1},
+	} {
+		want, err := local.AncientRange(rawdb.ChainFreezerHeaderTable, tt.start, tt.count, tt.maxBytes)
+		if err != nil {
+			t.Fatalf("%+v: local retrieval failed: %v", tt, err)
+		}
+		have, err := remote.AncientRange(rawdb.ChainFreezerHeaderTable, tt.start, tt.count, tt.maxBytes)
+		if err != nil {
+			t.Fatalf("%+v: remote retrieval failed: %v", tt, err)
+		}
+		if !reflect.DeepEqual(have, want) {
+			t.Fatalf("%+v: items mismatch: have %d items, want %d", tt, len(have), len(want))
+		}
+	}
+	if _, err := remote.ModifyAncients(func(ethdb.AncientWriteOp) error { return nil }); err != errNotSupported {
+		t.Fatalf("ancient modification not rejected: %v", err)
+	}
+}
+
+// legacyServer mimics the debug namespace database methods of nodes not serving
+// the db namespace.
+type legacyServer struct {
+	db ethdb.Database
+}
+
+func (s *legacyServer) DbGet(key string) (hexutil.Bytes, error) {
+	return s.db.Get(common.FromHex(key))
+}
+
+func (s *legacyServer) DbAncient(kind string, number uint64) (hexutil.Bytes, error) {
+	return s.db.Ancient(kind, number)
+}
+
+func (s *legacyServer) DbAncients() (uint64, error) {
+	return s.db.Ancients()
+}
+
+// Tests that reads fall back to the debug namespace if the remote node does not
+// serve the db namespace.
+func TestRemoteLegacyFallback(t *testing.T) {
+	local, err := rawdb.NewDatabaseWithFreezer(memorydb.New(), t.TempDir(), "", false)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer local.Close()
+
+	headers := []*types.Header{{Number: big.NewInt(0)}, {Number: big.NewInt(1)}}
+	if _, err := rawdb.WriteAncientHeaderChain(local, headers); err != nil {
+		t.Fatalf("failed to write ancients: %v", err)
+	}
+	local.Put([]byte("key"), []byte("value"))
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("debug", &legacyServer{local}); err != nil {
+		t.Fatalf("failed to register legacy server: %v", err)
+	}
+	defer server.Stop()
+	remote := New(rpc.DialInProc(server))
+
+	if value, err := remote.Get([]byte("key")); err != nil || !bytes.Equal(value, []byte("value")) {
+		t.Fatalf("value mismatch: have %q, %v, want %q", value, err, "value")
+	}
+	if has, err := remote.Has([]byte("key")); err != nil || !has {
+		t.Fatalf("key presence mismatch: have %v, %v, want true", has, err)
+	}
+	if has, _ := remote.Has([]byte("missing")); has {
+		t.Fatal("missing key reported present")
+	}
+	if have, err := remote.Ancients(); err != nil || have != uint64(len(headers)) {
+		t.Fatalf("ancient count mismatch: have %d, %v, want %d", have, err, len(headers))
+	}
+	want, _ := local.Ancient(rawdb.ChainFreezerHeaderTable, 1)
+	if have, err := remote.Ancient(rawdb.ChainFreezerHeaderTable, 1); err != nil || !bytes.Equal(have, want) {
+		t.Fatalf("ancient mismatch: have %x, %v, want %x", have, err, want)
+	}
+	// Writes are not available through the debug namespace
+	if err := remote.Put([]byte("key"), []byte("updated")); err == nil {
+		t.Fatal("write accepted by legacy remote")
+	}
+}
+
+// Tests that writes to the live chain and state data of the serving node are
+// refused, while other data can still be modified.
+func TestRemoteProtectedWrites(t *testing.T) {
+	var (
+		local  = rawdb.NewMemoryDatabase()
+		remote = newProtectedTestDatabase(t, local, rawdb.IsLiveKey)
+		header = &types.Header{Number: big.NewInt(1)}
+	)
+	rawdb.WriteHeader(local, header)
+	rawdb.WriteHeadHeaderHash(local, header.Hash())
+
+	if err := remote.Put([]byte("LastHeader"), common.Hash{0x01}.Bytes()); err == nil {
+		t.Fatal("head header overwrite accepted")
+	}
+	if err := remote.Delete(header.Hash().Bytes()); err == nil {
+		t.Fatal("trie node deletion accepted")
+	}
+	batch := remote.NewBatch()
+	batch.Put([]byte("key"), []byte("value"))
+	batch.Put(append(rawdb.CodePrefix, common.Hash{0x01}.Bytes()...), []byte{0x00})
+	if err := batch.Write(); err == nil {
+		t.Fatal("batch with code write accepted")
+	}
+	if has, _ := local.Has([]byte("key")); has {
+		t.Fatal("refused batch partially applied")
+	}
+	if err := remote.DeleteRange([]byte("a"), []byte("i")); err == nil {
+		t.Fatal("range deletion over headers accepted")
+	}
+	if rawdb.ReadHeader(local, header.Hash(), 1) == nil || rawdb.ReadHeadHeaderHash(local) != header.Hash() {
+		t.Fatal("protected data modified")
+	}
+	// Data not used by the chain can be modified freely
+	if err := remote.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if err := remote.DeleteRange([]byte("k"), []byte("l")); err != nil {
+		t.Fatalf("failed to delete range: %v", err)
+	}
+	if has, _ := local.Has([]byte("key")); has {
+		t.Fatal("range deletion not applied")
+	}
+}
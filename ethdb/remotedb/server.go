@@ -0,0 +1,253 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotedb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// Namespace is the RPC namespace the database server is exposed under.
+const Namespace = "db"
+
+const (
+	// maxPageItems is the maximum number of entries returned in a single
+	// iteration page.
+	maxPageItems = 4096
+
+	// maxPageBytes is the soft cap on the total size of the keys and values
+	// returned in a single iteration page. At least one entry is always
+	// returned, regardless of its size.
+	maxPageBytes = 4 * 1024 * 1024
+)
+
+var (
+	// errTooManyItems is returned if more ancient items are requested at once
+	// than allowed in a single page.
+	errTooManyItems = errors.New("too many ancient items requested")
+
+	// errProtectedKey is returned if a write targets data the serving node is
+	// actively using.
+	errProtectedKey = errors.New("write to live chain or state data refused")
+)
+
+// BatchOp is a single write operation of an atomic batch.
+type BatchOp struct {
+	Key    hexutil.Bytes `json:"key"`
+	Value  hexutil.Bytes `json:"value,omitempty"`
+	Delete bool          `json:"delete,omitempty"`
+}
+
+// Page is a chunk of database entries returned by an iteration request.
+type Page struct {
+	Keys   []hexutil.Bytes `json:"keys"`
+	Values []hexutil.Bytes `json:"values"`
+	Next   hexutil.Bytes   `json:"next,omitempty"` // Start of the next page relative to the prefix, nil if exhausted
+}
+
+// Server exposes a local database to remote clients over RPC. It is meant to
+// be registered on the authenticated RPC endpoint only, as it grants write
+// access to the key-value store.
+//
+// Writes are applied to the database directly, bypassing the in-memory caches
+// of the node (e.g. the blockchain and trie caches), which are not invalidated.
+// Since modifying chain or state data under a live node corrupts it, writes to
+// keys reported as protected are refused: writes are meant for maintenance of
+// data the node does not actively use.
+type Server struct {
+	db        ethdb.Database
+	protected func(key []byte) bool
+}
+
+// NewServer creates an RPC service serving the given database, refusing writes
+// to the keys the protected function reports. A nil function allows all writes.
+func NewServer(db ethdb.Database, protected func(key []byte) bool) *Server {
+	if protected == nil {
+		protected = func([]byte) bool { return false }
+	}
+	return &Server{db: db, protected: protected}
+}
+
+// checkWrite returns an error if the given key is protected from remote writes.
+func (s *Server) checkWrite(key []byte) error {
+	if s.protected(key) {
+		return fmt.Errorf("%w: %#x", errProtectedKey, key)
+	}
+	return nil
+}
+
+// Has retrieves if a key is present in the key-value data store.
+func (s *Server) Has(key hexutil.Bytes) (bool, error) {
+	return s.db.Has(key)
+}
+
+// Get retrieves the given key if it's present in the key-value data store.
+func (s *Server) Get(key hexutil.Bytes) (hexutil.Bytes, error) {
+	return s.db.Get(key)
+}
+
+// Put inserts the given value into the key-value data store.
+func (s *Server) Put(key hexutil.Bytes, value hexutil.Bytes) error {
+	if err := s.checkWrite(key); err != nil {
+		return err
+	}
+	return s.db.Put(key, value)
+}
+
+// Delete removes the key from the key-value data store.
+func (s *Server) Delete(key hexutil.Bytes) error {
+	if err := s.checkWrite(key); err != nil {
+		return err
+	}
+	return s.db.Delete(key)
+}
+
+// DeleteRange deletes all of the keys in the range [start,end). The range is
+// scanned up front and refused if it contains any protected key.
+func (s *Server) DeleteRange(start, end hexutil.Bytes) error {
+	if err := s.checkRange(start, end); err != nil {
+		return err
+	}
+	return s.db.DeleteRange(start, end)
+}
+
+// checkRange returns an error if any key in the range [start,end) is protected
+// from remote writes.
+func (s *Server) checkRange(start, end []byte) error {
+	it := s.db.NewIterator(nil, start)
+	defer it.Release()
+
+	for it.Next() {
+		if end != nil && bytes.Compare(it.Key(), end) >= 0 {
+			break
+		}
+		if err := s.checkWrite(it.Key()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// Write applies the given operations atomically to the key-value data store.
+func (s *Server) Write(ops []BatchOp) error {
+	batch := s.db.NewBatch()
+	for _, op := range ops {
+		if err := s.checkWrite(op.Key); err != nil {
+			return err
+		}
+		var err error
+		if op.Delete {
+			err = batch.Delete(op.Key)
+		} else {
+			err = batch.Put(op.Key, op.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// Iterate returns a page of entries with the given key prefix, starting at
+// the given key (relative to the prefix). At most limit entries are returned,
+// capped by the server side page limits. If the iteration is not exhausted,
+// the returned page contains the start of the next one.
+//
+// Pages are read from distinct database iterators, so a paginated iteration
+// does not observe a consistent snapshot of a database modified concurrently.
+func (s *Server) Iterate(prefix, start hexutil.Bytes, limit int) (*Page, error) {
+	if limit <= 0 || limit > maxPageItems {
+		limit = maxPageItems
+	}
+	it := s.db.NewIterator(prefix, start)
+	defer it.Release()
+
+	var (
+		page = &Page{Keys: []hexutil.Bytes{}, Values: []hexutil.Bytes{}}
+		size int
+	)
+	for it.Next() {
+		// Iterators may reuse the returned slices, but the page is encoded only
+		// after the iterator is released, so everything needs to be copied
+		if len(page.Keys) >= limit || size >= maxPageBytes {
+			page.Next = common.CopyBytes(it.Key()[len(prefix):])
+			break
+		}
+		page.Keys = append(page.Keys, common.CopyBytes(it.Key()))
+		page.Values = append(page.Values, common.CopyBytes(it.Value()))
+		size += len(it.Key()) + len(it.Value())
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// Stat returns the statistic data of the database.
+func (s *Server) Stat() (string, error) {
+	return s.db.Stat()
+}
+
+// Compact flattens the underlying data store for the given key range.
+func (s *Server) Compact(start, limit hexutil.Bytes) error {
+	return s.db.Compact(start, limit)
+}
+
+// Ancient retrieves an ancient binary blob from the append-only immutable files.
+func (s *Server) Ancient(kind string, number hexutil.Uint64) (hexutil.Bytes, error) {
+	return s.db.Ancient(kind, uint64(number))
+}
+
+// AncientRange retrieves multiple items in sequence, starting from the index
+// 'start'. At most maxPageItems can be requested at once.
+func (s *Server) AncientRange(kind string, start, count, maxBytes hexutil.Uint64) ([]hexutil.Bytes, error) {
+	if count > maxPageItems {
+		return nil, errTooManyItems
+	}
+	items, err := s.db.AncientRange(kind, uint64(start), uint64(count), uint64(maxBytes))
+	if err != nil {
+		return nil, err
+	}
+	blobs := make([]hexutil.Bytes, len(items))
+	for i, item := range items {
+		blobs[i] = item
+	}
+	return blobs, nil
+}
+
+// Ancients returns the ancient item numbers in the ancient store.
+func (s *Server) Ancients() (hexutil.Uint64, error) {
+	n, err := s.db.Ancients()
+	return hexutil.Uint64(n), err
+}
+
+// Tail returns the number of first stored item in the ancient store.
+func (s *Server) Tail() (hexutil.Uint64, error) {
+	n, err := s.db.Tail()
+	return hexutil.Uint64(n), err
+}
+
+// AncientSize returns the ancient size of the specified category.
+func (s *Server) AncientSize(kind string) (hexutil.Uint64, error) {
+	n, err := s.db.AncientSize(kind)
+	return hexutil.Uint64(n), err
+}
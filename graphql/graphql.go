@@ -169,6 +169,10 @@ func (l *Log) Data(ctx context.Context) hexutil.Bytes {
 	return l.log.Data
 }
 
+func (l *Log) Removed(ctx context.Context) bool {
+	return l.log.Removed
+}
+
 // AccessTuple represents EIP-2930
 type AccessTuple struct {
 	address     common.Address
@@ -1279,6 +1283,9 @@ func (p *Pending) EstimateGas(ctx context.Context, args struct {
 type Resolver struct {
 	backend      ethapi.Backend
 	filterSystem *filters.FilterSystem
+
+	eventsOnce sync.Once
+	events     *filters.EventSystem // Created by the first subscription
 }
 
 func (r *Resolver) Block(ctx context.Context, args struct {
@@ -16,7 +16,8 @@
 
 package graphql
 
-const schema string = `
+// schemaTypes holds the types shared by the HTTP and the WebSocket schemas.
+const schemaTypes string = `
     # Bytes32 is a 32 byte binary string, represented as 0x-prefixed hexadecimal.
     scalar Bytes32
     # Address is a 20 byte Ethereum address, represented as 0x-prefixed hexadecimal.
@@ -33,11 +34,6 @@ const schema string = `
     # 0x-prefixed hexadecimal.
     scalar Long
 
-    schema {
-        query: Query
-        mutation: Mutation
-    }
-
     # Account is an Ethereum account at a particular block.
     type Account {
         # Address is the address owning the account.
@@ -69,6 +65,9 @@ const schema string = `
         data: Bytes!
         # Transaction is the transaction that generated this log entry.
         transaction: Transaction!
+        # Removed is true if the log was reverted by a chain reorganisation.
+        # Only subscriptions deliver removed logs.
+        removed: Boolean!
     }
 
     # EIP-2718
@@ -358,6 +357,14 @@ const schema string = `
         # successful execution of a transaction for the pending state.
         estimateGas(data: CallData!): Long!
     }
+`
+
+// schema is the schema served over HTTP, answering queries and mutations.
+const schema string = schemaTypes + `
+    schema {
+        query: Query
+        mutation: Mutation
+    }
 
     type Query {
         # Block fetches an Ethereum block by number or by hash. If neither is
@@ -389,3 +396,33 @@ const schema string = `
         sendRawTransaction(data: Bytes!): Bytes32!
     }
 `
+
+// subscriptionSchema is the schema served over WebSocket, streaming the chain
+// events to the subscribers. As the subscription fields are resolved by the
+// same root object as the query fields, and the subscription logs clash with
+// the query ones, the queries and mutations are only served over HTTP.
+const subscriptionSchema string = schemaTypes + `
+    schema {
+        query: SubscriptionQuery
+        subscription: Subscription
+    }
+
+    # SubscriptionQuery is the query root of the WebSocket endpoint. The full
+    # query root is served over HTTP.
+    type SubscriptionQuery {
+        # ChainID returns the current chain ID for transaction replay protection.
+        chainID: BigInt!
+    }
+
+    type Subscription {
+        # NewBlocks streams the blocks added to the canonical chain, including
+        # the new blocks of a chain reorganisation.
+        newBlocks: Block!
+        # Logs streams the log entries of the new canonical blocks matching the
+        # provided filter, and the removed ones of the blocks reorganised out.
+        logs(filter: BlockFilterCriteria!): Log!
+        # PendingTransactions streams the transactions added to the transaction
+        # pool.
+        pendingTransactions: Transaction!
+    }
+`
@@ -33,10 +33,15 @@ import (
 )
 
 type handler struct {
-	Schema *graphql.Schema
+	Schema        *graphql.Schema
+	subscriptions *wsHandler // Serves the websocket connections, if set
 }
 
 func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.subscriptions != nil && isWebsocket(r) {
+		h.subscriptions.ServeHTTP(w, r)
+		return
+	}
 	var params struct {
 		Query         string                 `json:"query"`
 		OperationName string                 `json:"operationName"`
@@ -111,16 +116,21 @@ func New(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterS
 	return err
 }
 
-// newHandler returns a new `http.Handler` that will answer GraphQL queries.
-// It additionally exports an interactive query browser on the / endpoint.
+// newHandler returns a new `http.Handler` that will answer GraphQL queries,
+// and subscriptions over WebSocket. It additionally exports an interactive
+// query browser on the / endpoint.
 func newHandler(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cors, vhosts []string) (*handler, error) {
-	q := Resolver{backend, filterSystem}
+	q := Resolver{backend: backend, filterSystem: filterSystem}
 
 	s, err := graphql.ParseSchema(schema, &q)
 	if err != nil {
 		return nil, err
 	}
-	h := handler{Schema: s}
+	subs, err := graphql.ParseSchema(subscriptionSchema, &SubscriptionResolver{&q})
+	if err != nil {
+		return nil, err
+	}
+	h := handler{Schema: s, subscriptions: newWSHandler(subs, cors)}
 	handler := node.NewHTTPHandlerStack(h, cors, vhosts, nil)
 
 	stack.RegisterHandler("GraphQL UI", "/graphql/ui", GraphiQL{})
@@ -0,0 +1,133 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// subscriptionBuffer is the number of events buffered for a subscriber. The
+// subscriptions falling further behind are ended, to not stall the event system
+// feeding all of them.
+const subscriptionBuffer = 256
+
+// eventSystem returns the event system feeding the subscriptions, creating it
+// on the first use.
+func (r *Resolver) eventSystem() *filters.EventSystem {
+	r.eventsOnce.Do(func() {
+		r.events = filters.NewEventSystem(r.filterSystem)
+	})
+	return r.events
+}
+
+// SubscriptionResolver is the root object of the WebSocket schema, resolving
+// the subscriptions.
+type SubscriptionResolver struct {
+	*Resolver
+}
+
+// NewBlocks streams the blocks added to the canonical chain.
+func (r *SubscriptionResolver) NewBlocks(ctx context.Context) <-chan *Block {
+	headers := make(chan *types.Header)
+	sub := r.eventSystem().SubscribeNewHeads(headers)
+
+	return forwardEvents(ctx, sub, headers, func(header *types.Header) []*Block {
+		hash := header.Hash()
+		numberOrHash := rpc.BlockNumberOrHashWithHash(hash, false)
+		return []*Block{{
+			r:            r.Resolver,
+			numberOrHash: &numberOrHash,
+			hash:         hash,
+			header:       header,
+		}}
+	})
+}
+
+// Logs streams the logs of the canonical chain matching the filter.
+func (r *SubscriptionResolver) Logs(ctx context.Context, args struct{ Filter BlockFilterCriteria }) (<-chan *Log, error) {
+	var crit ethereum.FilterQuery
+	if args.Filter.Addresses != nil {
+		crit.Addresses = *args.Filter.Addresses
+	}
+	if args.Filter.Topics != nil {
+		crit.Topics = *args.Filter.Topics
+	}
+	logs := make(chan []*types.Log)
+	sub, err := r.eventSystem().SubscribeLogs(crit, logs)
+	if err != nil {
+		return nil, err
+	}
+	return forwardEvents(ctx, sub, logs, func(logs []*types.Log) []*Log {
+		ret := make([]*Log, 0, len(logs))
+		for _, log := range logs {
+			ret = append(ret, &Log{
+				r:           r.Resolver,
+				transaction: &Transaction{r: r.Resolver, hash: log.TxHash},
+				log:         log,
+			})
+		}
+		return ret
+	}), nil
+}
+
+// PendingTransactions streams the transactions added to the transaction pool.
+func (r *SubscriptionResolver) PendingTransactions(ctx context.Context) <-chan *Transaction {
+	txs := make(chan []*types.Transaction)
+	sub := r.eventSystem().SubscribePendingTxs(txs)
+
+	return forwardEvents(ctx, sub, txs, func(txs []*types.Transaction) []*Transaction {
+		ret := make([]*Transaction, 0, len(txs))
+		for _, tx := range txs {
+			ret = append(ret, &Transaction{r: r.Resolver, hash: tx.Hash(), tx: tx})
+		}
+		return ret
+	})
+}
+
+// forwardEvents feeds the events of a filter subscription to a GraphQL
+// subscription until its request ends. If the subscriber falls behind by more
+// than subscriptionBuffer events, the subscription is ended.
+func forwardEvents[E any, T any](ctx context.Context, sub *filters.Subscription, events chan E, convert func(E) []T) <-chan T {
+	out := make(chan T, subscriptionBuffer)
+	go func() {
+		defer close(out)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				for _, item := range convert(ev) {
+					select {
+					case out <- item:
+					default:
+						log.Debug("Ending lagging GraphQL subscription", "id", sub.ID)
+						return
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
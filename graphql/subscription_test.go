@@ -0,0 +1,279 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// Tests that the subscriptions stream the blocks, logs and pool transactions.
+func TestGraphQLSubscriptions(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		emitter = common.HexToAddress("0x0000000000000000000000000000000000000e77")
+		topic   = common.BigToHash(big.NewInt(0x2a))
+	)
+	stack := createNode(t)
+	defer stack.Close()
+
+	genesis := &core.Genesis{
+		Config:     params.AllEthashProtocolChanges,
+		GasLimit:   11500000,
+		Difficulty: big.NewInt(1048576),
+		Alloc: types.GenesisAlloc{
+			address: {Balance: big.NewInt(params.Ether)},
+			// The address 0xe77 emits a log with the topic 0x2a
+			emitter: {
+				Code: []byte{byte(vm.PUSH1), 0x2a, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.LOG1)},
+			},
+		},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	ethBackend, err := eth.New(stack, &ethconfig.Config{
+		Genesis:        genesis,
+		NetworkId:      1337,
+		TrieCleanCache: 5,
+		TrieDirtyCache: 5,
+		TrieTimeout:    60 * time.Minute,
+		SnapshotCache:  5,
+		RPCGasCap:      1000000,
+		StateScheme:    rawdb.HashScheme,
+	})
+	if err != nil {
+		t.Fatalf("could not create eth backend: %v", err)
+	}
+	filterSystem := filters.NewFilterSystem(ethBackend.APIBackend, filters.Config{})
+	handler, err := newHandler(stack, ethBackend.APIBackend, filterSystem, []string{}, []string{})
+	if err != nil {
+		t.Fatalf("could not create graphql service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	schema := handler.subscriptions.schema
+
+	// The resolvers subscribe to the events before Subscribe returns
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subscribe := func(query string) <-chan interface{} {
+		t.Helper()
+		responses, err := schema.Subscribe(ctx, query, "", nil)
+		if err != nil {
+			t.Fatalf("could not subscribe to %s: %v", query, err)
+		}
+		return responses
+	}
+	next := func(responses <-chan interface{}) string {
+		t.Helper()
+		select {
+		case resp := <-responses:
+			response := resp.(*graphql.Response)
+			if len(response.Errors) > 0 {
+				t.Fatalf("subscription failed: %v", response.Errors)
+			}
+			return string(response.Data)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no response")
+		}
+		return ""
+	}
+	var (
+		blocks  = subscribe(`subscription { newBlocks { number } }`)
+		logs    = subscribe(fmt.Sprintf(`subscription { logs(filter: { addresses: ["%v"] }) { topics removed transaction { hash } } }`, emitter))
+		pending = subscribe(`subscription { pendingTransactions { hash from { address } } }`)
+	)
+	signer := types.LatestSigner(genesis.Config)
+	chain, _ := core.GenerateChain(genesis.Config, ethBackend.BlockChain().Genesis(), beacon.New(ethash.NewFaker()), ethBackend.ChainDb(), 2, func(i int, gen *core.BlockGen) {
+		tx, _ := types.SignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &emitter,
+			Gas:      50000,
+			GasPrice: gen.BaseFee(),
+		})
+		gen.AddTx(tx)
+	})
+	if _, err := ethBackend.BlockChain().InsertChain(chain); err != nil {
+		t.Fatalf("could not import blocks: %v", err)
+	}
+	for i, block := range chain {
+		if have, want := next(blocks), fmt.Sprintf(`{"newBlocks":{"number":"%#x"}}`, i+1); have != want {
+			t.Errorf("block %d: have %s, want %s", i, have, want)
+		}
+		want := fmt.Sprintf(`{"logs":{"topics":["%v"],"removed":false,"transaction":{"hash":"%v"}}}`, topic, block.Transactions()[0].Hash())
+		if have := next(logs); have != want {
+			t.Errorf("log %d: have %s, want %s", i, have, want)
+		}
+	}
+	tx, _ := types.SignNewTx(key, signer, &types.LegacyTx{
+		Nonce:    uint64(len(chain)),
+		To:       &emitter,
+		Gas:      50000,
+		GasPrice: big.NewInt(2 * params.InitialBaseFee),
+	})
+	if err := ethBackend.APIBackend.SendTx(ctx, tx); err != nil {
+		t.Fatalf("could not send transaction: %v", err)
+	}
+	want := fmt.Sprintf(`{"pendingTransactions":{"hash":"%v","from":{"address":"%v"}}}`, tx.Hash(), strings.ToLower(address.Hex()))
+	if have := next(pending); have != want {
+		t.Errorf("pending transaction: have %s, want %s", have, want)
+	}
+
+	// Ending the request ends the subscriptions
+	cancel()
+	for _, responses := range []<-chan interface{}{blocks, logs, pending} {
+		for range responses {
+		}
+	}
+}
+
+// Tests the GraphQL over WebSocket protocol.
+func TestGraphQLWebsocket(t *testing.T) {
+	stack := createNode(t)
+	defer stack.Close()
+
+	genesis := &core.Genesis{
+		Config:     params.AllEthashProtocolChanges,
+		GasLimit:   11500000,
+		Difficulty: big.NewInt(1048576),
+	}
+	newGQLService(t, stack, false, genesis, 1, func(i int, gen *core.BlockGen) {})
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	url := "ws" + strings.TrimPrefix(stack.HTTPEndpoint(), "http") + "/graphql"
+
+	dial := func(protocols ...string) *websocket.Conn {
+		t.Helper()
+		dialer := websocket.Dialer{Subprotocols: protocols}
+		conn, _, err := dialer.Dial(url, nil)
+		if err != nil {
+			t.Fatalf("could not dial: %v", err)
+		}
+		return conn
+	}
+	send := func(conn *websocket.Conn, msg string) {
+		t.Helper()
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+			t.Fatalf("could not send %s: %v", msg, err)
+		}
+	}
+	receive := func(conn *websocket.Conn, want string) {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("could not receive %s: %v", want, err)
+		}
+		// Compare the decoded messages to ignore the field order
+		var have, wantMsg interface{}
+		json.Unmarshal(data, &have)
+		json.Unmarshal([]byte(want), &wantMsg)
+		if fmt.Sprint(have) != fmt.Sprint(wantMsg) {
+			t.Fatalf("wrong message: have %s, want %s", data, want)
+		}
+	}
+	closed := func(conn *websocket.Conn, code int) {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, _, err := conn.ReadMessage()
+		if !websocket.IsCloseError(err, code) {
+			t.Fatalf("wrong close error: have %v, want code %d", err, code)
+		}
+	}
+
+	// Operations run after the acknowledged initialisation
+	conn := dial(wsProtocol)
+	defer conn.Close()
+	send(conn, `{"type":"connection_init"}`)
+	receive(conn, `{"type":"connection_ack"}`)
+	send(conn, `{"type":"ping","payload":{"n":1}}`)
+	receive(conn, `{"type":"pong","payload":{"n":1}}`)
+	send(conn, `{"id":"q","type":"subscribe","payload":{"query":"{ chainID }"}}`)
+	receive(conn, `{"id":"q","type":"next","payload":{"data":{"chainID":"0x539"}}}`)
+	receive(conn, `{"id":"q","type":"complete"}`)
+	send(conn, `{"id":"e","type":"subscribe","payload":{"query":"subscription { unknown }"}}`)
+	receive(conn, `{"id":"e","type":"error","payload":[{"message":"Cannot query field \"unknown\" on type \"Subscription\".","locations":[{"line":1,"column":16}]}]}`)
+
+	// The completed subscriptions end silently, while the duplicate ones close
+	// the connection
+	send(conn, `{"id":"s","type":"subscribe","payload":{"query":"subscription { newBlocks { number } }"}}`)
+	send(conn, `{"id":"s","type":"complete"}`)
+	send(conn, `{"id":"s","type":"subscribe","payload":{"query":"subscription { newBlocks { number } }"}}`)
+	send(conn, `{"id":"s","type":"subscribe","payload":{"query":"subscription { newBlocks { number } }"}}`)
+	closed(conn, wsCloseDuplicateID)
+
+	// Running too many operations at once closes the connection
+	conn = dial(wsProtocol)
+	defer conn.Close()
+	send(conn, `{"type":"connection_init"}`)
+	receive(conn, `{"type":"connection_ack"}`)
+	for i := 0; i <= wsMaxSubscriptions; i++ {
+		send(conn, fmt.Sprintf(`{"id":"s%d","type":"subscribe","payload":{"query":"subscription { newBlocks { number } }"}}`, i))
+	}
+	closed(conn, wsCloseTooManyRequests)
+
+	// Protocol violations close the connection
+	for _, tt := range []struct {
+		protocol string
+		messages []string
+		code     int
+	}{
+		{"", nil, wsCloseBadProtocol},
+		{wsProtocol, []string{`{"id":"s","type":"subscribe","payload":{"query":"{ chainID }"}}`}, wsCloseUnauthorized},
+		{wsProtocol, []string{`{"type":"connection_init"}`, `{"type":"connection_init"}`}, wsCloseDuplicateInit},
+		{wsProtocol, []string{`{"type":"connection_init"}`, `invalid`}, wsCloseBadRequest},
+		{wsProtocol, []string{`{"type":"connection_init"}`, `{"type":"unknown"}`}, wsCloseBadRequest},
+	} {
+		var conn *websocket.Conn
+		if tt.protocol == "" {
+			conn = dial()
+		} else {
+			conn = dial(tt.protocol)
+		}
+		for _, msg := range tt.messages {
+			send(conn, msg)
+		}
+		if len(tt.messages) > 1 {
+			receive(conn, `{"type":"connection_ack"}`)
+		}
+		closed(conn, tt.code)
+		conn.Close()
+	}
+}
@@ -0,0 +1,372 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+	gqlErrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+// wsProtocol is the subprotocol of the GraphQL over WebSocket protocol, as
+// implemented by the graphql-ws library:
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const wsProtocol = "graphql-transport-ws"
+
+const (
+	wsInitTimeout  = 10 * time.Second
+	wsWriteTimeout = 10 * time.Second
+	wsPingInterval = 30 * time.Second
+	wsReadLimit    = 1024 * 1024
+
+	// wsMaxSubscriptions is the maximum number of operations running at once on
+	// a single connection.
+	wsMaxSubscriptions = 128
+)
+
+// Message types of the GraphQL over WebSocket protocol.
+const (
+	wsConnectionInit = "connection_init"
+	wsConnectionAck  = "connection_ack"
+	wsPing           = "ping"
+	wsPong           = "pong"
+	wsSubscribe      = "subscribe"
+	wsNext           = "next"
+	wsError          = "error"
+	wsComplete       = "complete"
+)
+
+// Close codes of the GraphQL over WebSocket protocol.
+const (
+	wsCloseBadRequest      = 4400
+	wsCloseUnauthorized    = 4401
+	wsCloseBadProtocol     = 4406
+	wsCloseInitTimeout     = 4408
+	wsCloseDuplicateID     = 4409
+	wsCloseDuplicateInit   = 4429
+	wsCloseTooManyRequests = 4429
+	wsCloseInternalFailure = 4500
+)
+
+// wsMessage is a message of the GraphQL over WebSocket protocol.
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// wsSubscribePayload is the operation requested by a subscribe message.
+type wsSubscribePayload struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// wsHandler serves the subscriptions of a schema over WebSocket.
+type wsHandler struct {
+	schema   *graphql.Schema
+	upgrader websocket.Upgrader
+}
+
+// newWSHandler creates a WebSocket handler accepting the connections of the
+// given CORS origins, or of the same origin if none are configured.
+func newWSHandler(schema *graphql.Schema, origins []string) *wsHandler {
+	return &wsHandler{
+		schema: schema,
+		upgrader: websocket.Upgrader{
+			Subprotocols: []string{wsProtocol},
+			CheckOrigin:  wsOriginChecker(origins),
+		},
+	}
+}
+
+// wsOriginChecker returns a handshake validator accepting the requests without
+// origin, from the allowed origins and from the same origin as the request.
+func wsOriginChecker(allowed []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		for _, o := range allowed {
+			if o == "*" || strings.EqualFold(o, origin) {
+				return true
+			}
+		}
+		u, err := url.Parse(origin)
+		if err == nil && strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		log.Warn("Rejected GraphQL WebSocket connection", "origin", origin)
+		return false
+	}
+}
+
+// isWebsocket checks whether a request asks for a websocket upgrade.
+func isWebsocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
+func (h *wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader already replied with the error
+		log.Debug("GraphQL WebSocket upgrade failed", "err", err)
+		return
+	}
+	c := newWSConn(h.schema, conn)
+	if conn.Subprotocol() != wsProtocol {
+		c.closeWith(wsCloseBadProtocol, "Subprotocol not acceptable")
+		return
+	}
+	c.serve()
+}
+
+// wsConn is a GraphQL over WebSocket connection.
+type wsConn struct {
+	schema *graphql.Schema
+	conn   *websocket.Conn
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	writeLock sync.Mutex // Serialises the writes to the connection
+
+	lock sync.Mutex
+	subs map[string]*wsSubscription // Running operations by client-chosen ID
+}
+
+// wsSubscription is an operation running on a connection.
+type wsSubscription struct {
+	cancel context.CancelFunc
+}
+
+func newWSConn(schema *graphql.Schema, conn *websocket.Conn) *wsConn {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &wsConn{
+		schema: schema,
+		conn:   conn,
+		ctx:    ctx,
+		cancel: cancel,
+		subs:   make(map[string]*wsSubscription),
+	}
+}
+
+// serve handles the messages of the client until the connection closes, and
+// then ends its operations.
+func (c *wsConn) serve() {
+	defer c.close()
+
+	c.conn.SetReadLimit(wsReadLimit)
+	c.conn.SetReadDeadline(time.Now().Add(wsInitTimeout))
+
+	c.wg.Add(1)
+	go c.pingLoop()
+
+	acked := false
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			var netErr net.Error
+			if !acked && errors.As(err, &netErr) && netErr.Timeout() {
+				c.closeWith(wsCloseInitTimeout, "Connection initialisation timeout")
+			}
+			return
+		}
+		var msg wsMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.closeWith(wsCloseBadRequest, "Invalid message received")
+			return
+		}
+		switch msg.Type {
+		case wsConnectionInit:
+			if acked {
+				c.closeWith(wsCloseDuplicateInit, "Too many initialisation requests")
+				return
+			}
+			acked = true
+			c.conn.SetReadDeadline(time.Time{})
+			c.send(wsMessage{Type: wsConnectionAck})
+
+		case wsPing:
+			c.send(wsMessage{Type: wsPong, Payload: msg.Payload})
+
+		case wsPong:
+
+		case wsSubscribe:
+			if !acked {
+				c.closeWith(wsCloseUnauthorized, "Unauthorized")
+				return
+			}
+			var payload wsSubscribePayload
+			if msg.ID == "" || json.Unmarshal(msg.Payload, &payload) != nil {
+				c.closeWith(wsCloseBadRequest, "Invalid message received")
+				return
+			}
+			if code := c.subscribe(msg.ID, payload); code != 0 {
+				if code == wsCloseDuplicateID {
+					c.closeWith(code, fmt.Sprintf("Subscriber for %s already exists", msg.ID))
+				} else {
+					c.closeWith(code, "Too many subscriptions")
+				}
+				return
+			}
+
+		case wsComplete:
+			c.unsubscribe(msg.ID, nil)
+
+		default:
+			c.closeWith(wsCloseBadRequest, "Invalid message received")
+			return
+		}
+	}
+}
+
+// subscribe starts an operation, unless one with the same ID is running or the
+// connection already runs the maximum number of operations. On failure, the code
+// to close the connection with is returned.
+func (c *wsConn) subscribe(id string, payload wsSubscribePayload) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.subs[id]; ok {
+		return wsCloseDuplicateID
+	}
+	if len(c.subs) >= wsMaxSubscriptions {
+		return wsCloseTooManyRequests
+	}
+	ctx, cancel := context.WithCancel(c.ctx)
+	sub := &wsSubscription{cancel: cancel}
+	c.subs[id] = sub
+
+	c.wg.Add(1)
+	go c.run(ctx, id, sub, payload)
+	return 0
+}
+
+// unsubscribe ends an operation. If sub is set, the operation is only removed
+// if its ID was not reused after it ended.
+func (c *wsConn) unsubscribe(id string, sub *wsSubscription) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if running, ok := c.subs[id]; ok && (sub == nil || running == sub) {
+		running.cancel()
+		delete(c.subs, id)
+	}
+}
+
+// run executes an operation, sending its results until it ends or the client
+// completes it.
+func (c *wsConn) run(ctx context.Context, id string, sub *wsSubscription, payload wsSubscribePayload) {
+	defer c.wg.Done()
+	defer c.unsubscribe(id, sub)
+
+	responses, err := c.schema.Subscribe(ctx, payload.Query, payload.OperationName, payload.Variables)
+	if err != nil {
+		c.sendPayload(id, wsError, []*gqlErrors.QueryError{{Message: err.Error()}})
+		return
+	}
+	first := true
+	for resp := range responses {
+		// Drain the responses of the completed operations, to let the
+		// executor end
+		if ctx.Err() != nil {
+			continue
+		}
+		response := resp.(*graphql.Response)
+		if first && response.Data == nil && len(response.Errors) > 0 {
+			// The operation failed to start, e.g. on a validation error
+			c.sendPayload(id, wsError, response.Errors)
+			sub.cancel()
+			continue
+		}
+		first = false
+		c.sendPayload(id, wsNext, response)
+	}
+	if ctx.Err() == nil {
+		c.send(wsMessage{ID: id, Type: wsComplete})
+	}
+}
+
+// pingLoop keeps the idle connection alive.
+func (c *wsConn) pingLoop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// sendPayload sends a message with the JSON encoding of payload.
+func (c *wsConn) sendPayload(id string, typ string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Warn("Failed to encode GraphQL response", "err", err)
+		c.closeWith(wsCloseInternalFailure, "Internal server error")
+		return
+	}
+	c.send(wsMessage{ID: id, Type: typ, Payload: data})
+}
+
+// send writes a message, closing the connection if the write fails.
+func (c *wsConn) send(msg wsMessage) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if err := c.conn.WriteJSON(msg); err != nil {
+		log.Debug("Failed to write GraphQL WebSocket message", "err", err)
+		c.conn.Close()
+	}
+}
+
+// closeWith closes the connection with a protocol error.
+func (c *wsConn) closeWith(code int, reason string) {
+	c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(wsWriteTimeout))
+	c.conn.Close()
+}
+
+// close closes the connection and waits for its operations to end.
+func (c *wsConn) close() {
+	c.cancel()
+	c.conn.Close()
+	c.wg.Wait()
+}
@@ -0,0 +1,229 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package erae
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/era/e2store"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
+)
+
+// Builder is used to create EraE archives of post-merge block data.
+//
+// EraE files are e2store files, following closely the structure of Era1 files.
+// Since post-merge blocks are not tied together by the total difficulty, the
+// TotalDifficulty entries are replaced by a Proof entry tying the block to the
+// beacon block which included it. Receipts are stored in their slim form, with
+// the bloom filters omitted as they can be recomputed from the logs.
+//
+// The structure can be summarized through this definition:
+//
+//	erae := Version | block-tuple* | other-entries* | Accumulator | BlockIndex
+//	block-tuple :=  CompressedHeader | CompressedBody | CompressedSlimReceipts | Proof
+//
+// Each basic element is its own e2store entry:
+//
+//	Version                = { type: 0x3265, data: nil }
+//	CompressedHeader       = { type: 0x03,   data: snappyFramed(rlp(header)) }
+//	CompressedBody         = { type: 0x04,   data: snappyFramed(rlp(body)) }
+//	CompressedSlimReceipts = { type: 0x0a,   data: snappyFramed(rlp([tx-type, post-state-or-status, cumulative-gas, logs]*)) }
+//	Proof                  = { type: 0x0b,   data: rlp([beacon-root, branch]) }
+//	Accumulator            = { type: 0x07,   data: hash_tree_root(List(BeaconRecord, 8192)) }
+//	BlockIndex             = { type: 0x3266, data: block-index }
+//
+// The beacon root of a Proof is the root of the beacon block which included
+// the execution block, and is zero if unknown. If the root is known, the branch
+// is the merkle proof of the execution block hash within that beacon block, at
+// generalized index 3228 for bellatrix and capella blocks and 6444 since deneb.
+//
+// The accumulator is the SSZ hash tree root of the beacon records of the
+// blocks, defined as:
+//
+//	BeaconRecord = { block-hash: Bytes32, beacon-root: Bytes32 }
+//
+// The block index has the same format as the one of Era1 files:
+//
+//	block-index := starting-number | index | index | index ... | count
+//
+// starting-number is the first block number in the archive. Every index is a
+// defined relative to beginning of the record. The total number of block
+// entries in the file is recorded with count.
+//
+// Due to the accumulator size limit of 8192, the maximum number of blocks in
+// an EraE archive is also 8192.
+type Builder struct {
+	w        *e2store.Writer
+	startNum *uint64
+	indexes  []uint64
+	hashes   []common.Hash
+	roots    []common.Hash
+	written  int
+
+	buf    *bytes.Buffer
+	snappy *snappy.Writer
+}
+
+// NewBuilder returns a new Builder instance.
+func NewBuilder(w io.Writer) *Builder {
+	buf := bytes.NewBuffer(nil)
+	return &Builder{
+		w:      e2store.NewWriter(w),
+		buf:    buf,
+		snappy: snappy.NewBufferedWriter(buf),
+	}
+}
+
+// Add writes a post-merge block entry, its receipts and its beacon proof to
+// the archive. A nil proof is stored as an unknown beacon root, any other proof
+// must verify against the block hash.
+func (b *Builder) Add(block *types.Block, receipts types.Receipts, proof *BlockProof) error {
+	if block.Difficulty().Sign() != 0 {
+		return fmt.Errorf("block %d is not a post-merge block", block.NumberU64())
+	}
+	eh, err := rlp.EncodeToBytes(block.Header())
+	if err != nil {
+		return err
+	}
+	eb, err := rlp.EncodeToBytes(block.Body())
+	if err != nil {
+		return err
+	}
+	er, err := EncodeReceipts(receipts)
+	if err != nil {
+		return err
+	}
+	return b.AddRLP(eh, eb, er, block.NumberU64(), block.Hash(), proof)
+}
+
+// AddRLP writes a post-merge block entry, its slim encoded receipts and its
+// beacon proof to the archive. A nil proof is stored as an unknown beacon root,
+// any other proof must verify against the block hash.
+func (b *Builder) AddRLP(header, body, receipts []byte, number uint64, hash common.Hash, proof *BlockProof) error {
+	if proof == nil {
+		proof = new(BlockProof)
+	} else if err := proof.Verify(hash); err != nil {
+		return fmt.Errorf("invalid beacon proof of block %d: %w", number, err)
+	}
+	// Write EraE version entry before first block.
+	if b.startNum == nil {
+		n, err := b.w.Write(era.TypeVersion, nil)
+		if err != nil {
+			return err
+		}
+		startNum := number
+		b.startNum = &startNum
+		b.written += n
+	}
+	if len(b.indexes) >= MaxEraESize {
+		return fmt.Errorf("exceeds maximum batch size of %d", MaxEraESize)
+	}
+	ep, err := rlp.EncodeToBytes(proof)
+	if err != nil {
+		return err
+	}
+	b.indexes = append(b.indexes, uint64(b.written))
+	b.hashes = append(b.hashes, hash)
+	b.roots = append(b.roots, proof.BeaconRoot)
+
+	// Write block data.
+	if err := b.snappyWrite(era.TypeCompressedHeader, header); err != nil {
+		return err
+	}
+	if err := b.snappyWrite(era.TypeCompressedBody, body); err != nil {
+		return err
+	}
+	if err := b.snappyWrite(TypeCompressedSlimReceipts, receipts); err != nil {
+		return err
+	}
+	// Also write the proof, but don't snappy encode.
+	n, err := b.w.Write(TypeProof, ep)
+	b.written += n
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Finalize computes the accumulator and block index values, then writes the
+// corresponding e2store entries.
+func (b *Builder) Finalize() (common.Hash, error) {
+	if b.startNum == nil {
+		return common.Hash{}, errors.New("finalize called on empty builder")
+	}
+	// Compute accumulator root and write entry.
+	root, err := ComputeAccumulator(b.hashes, b.roots)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error calculating accumulator root: %w", err)
+	}
+	n, err := b.w.Write(era.TypeAccumulator, root[:])
+	b.written += n
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error writing accumulator: %w", err)
+	}
+	// Get beginning of index entry to calculate block relative offset.
+	base := int64(b.written)
+
+	// Construct block index, encoded as "start | index | index | ... | count",
+	// with each offset relative to the beginning of the index.
+	var (
+		count = len(b.indexes)
+		index = make([]byte, 16+count*8)
+	)
+	binary.LittleEndian.PutUint64(index, *b.startNum)
+	for i, offset := range b.indexes {
+		relative := int64(offset) - base
+		binary.LittleEndian.PutUint64(index[8+i*8:], uint64(relative))
+	}
+	binary.LittleEndian.PutUint64(index[8+count*8:], uint64(count))
+
+	// Finally, write the block index entry.
+	if _, err := b.w.Write(era.TypeBlockIndex, index); err != nil {
+		return common.Hash{}, fmt.Errorf("unable to write block index: %w", err)
+	}
+	return root, nil
+}
+
+// snappyWrite is a small helper to take care snappy encoding and writing an e2store entry.
+func (b *Builder) snappyWrite(typ uint16, in []byte) error {
+	var (
+		buf = b.buf
+		s   = b.snappy
+	)
+	buf.Reset()
+	s.Reset(buf)
+	if _, err := b.snappy.Write(in); err != nil {
+		return fmt.Errorf("error snappy encoding: %w", err)
+	}
+	if err := s.Flush(); err != nil {
+		return fmt.Errorf("error flushing snappy encoding: %w", err)
+	}
+	n, err := b.w.Write(typ, b.buf.Bytes())
+	b.written += n
+	if err != nil {
+		return fmt.Errorf("error writing e2store entry: %w", err)
+	}
+	return nil
+}
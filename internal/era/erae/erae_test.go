@@ -0,0 +1,195 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package erae
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestEraEBuilder(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.CreateTemp(t.TempDir(), "erae-test")
+	if err != nil {
+		t.Fatalf("error creating temp file: %v", err)
+	}
+	defer f.Close()
+
+	var (
+		builder  = NewBuilder(f)
+		start    = uint64(1000)
+		blocks   []*types.Block
+		receipts []types.Receipts
+		proofs   []*BlockProof
+	)
+	for i := 0; i < 128; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(start + uint64(i)), Difficulty: new(big.Int)}
+		tx := types.NewTransaction(0, common.Address{byte(i)}, nil, 0, nil, nil)
+		block := types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: []*types.Transaction{tx}})
+		receipt := &types.Receipt{
+			Type:              types.DynamicFeeTxType,
+			Status:            uint64(i % 2),
+			CumulativeGasUsed: uint64(i),
+			Logs:              []*types.Log{{Address: common.Address{byte(i)}, Topics: []common.Hash{{byte(i)}}, Data: []byte{byte(i)}}},
+		}
+		receipt.Bloom = types.CreateBloom(receipt)
+
+		blocks = append(blocks, block)
+		receipts = append(receipts, types.Receipts{receipt})
+		proofs = append(proofs, testBlockProof(block.Hash(), blockHashIndexDeneb))
+		if err := builder.Add(block, receipts[i], proofs[i]); err != nil {
+			t.Fatalf("error adding entry: %v", err)
+		}
+	}
+	// Pre-merge blocks are rejected.
+	pow := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1)})
+	if err := builder.Add(pow, nil, nil); err == nil {
+		t.Fatal("expected error adding pre-merge block")
+	}
+	root, err := builder.Finalize()
+	if err != nil {
+		t.Fatalf("error finalizing erae: %v", err)
+	}
+
+	e, err := Open(f.Name())
+	if err != nil {
+		t.Fatalf("failed to open era: %v", err)
+	}
+	defer e.Close()
+
+	if e.Start() != start || e.Count() != uint64(len(blocks)) {
+		t.Fatalf("wrong metadata: start %d count %d", e.Start(), e.Count())
+	}
+	if have, err := e.Accumulator(); err != nil || have != root {
+		t.Fatalf("wrong accumulator: have %x, want %x (err %v)", have, root, err)
+	}
+	it, err := NewIterator(e)
+	if err != nil {
+		t.Fatalf("failed to make iterator: %v", err)
+	}
+	for i := range blocks {
+		num := start + uint64(i)
+		if !it.Next() {
+			t.Fatalf("expected more entries")
+		}
+		if it.Error() != nil {
+			t.Fatalf("unexpected error %v", it.Error())
+		}
+		block, rs, err := it.BlockAndReceipts()
+		if err != nil {
+			t.Fatalf("error reading block %d: %v", num, err)
+		}
+		if block.Hash() != blocks[i].Hash() {
+			t.Fatalf("mismatched block %d hash", num)
+		}
+		if !reflect.DeepEqual(rs, receipts[i]) {
+			t.Fatalf("mismatched receipts %d: want %v, got %v", num, receipts[i], rs)
+		}
+		proof, err := it.Proof()
+		if err != nil {
+			t.Fatalf("error reading proof %d: %v", num, err)
+		}
+		if !reflect.DeepEqual(proof, proofs[i]) {
+			t.Fatalf("mismatched proof %d", num)
+		}
+		// Check random access.
+		if header, err := e.GetHeaderByNumber(num); err != nil || header.Hash() != blocks[i].Hash() {
+			t.Fatalf("mismatched header %d (err %v)", num, err)
+		}
+		if rs, err := e.GetReceiptsByNumber(num); err != nil || !reflect.DeepEqual(rs, receipts[i]) {
+			t.Fatalf("mismatched receipts %d (err %v)", num, err)
+		}
+		if proof, err := e.GetProofByNumber(num); err != nil || proof.BeaconRoot != proofs[i].BeaconRoot {
+			t.Fatalf("mismatched proof %d (err %v)", num, err)
+		}
+	}
+	if it.Next() {
+		t.Fatal("expected end of iteration")
+	}
+	if _, err := e.GetBlockByNumber(start - 1); err == nil {
+		t.Fatal("expected out-of-bounds error")
+	}
+}
+
+func TestBlockProof(t *testing.T) {
+	t.Parallel()
+
+	hash := common.Hash{0xaa}
+	for _, index := range []uint64{blockHashIndexBellatrix, blockHashIndexDeneb} {
+		proof := testBlockProof(hash, index)
+		if err := proof.Verify(hash); err != nil {
+			t.Fatalf("valid proof at index %d rejected: %v", index, err)
+		}
+		if err := proof.Verify(common.Hash{0xbb}); err == nil {
+			t.Fatalf("invalid block hash at index %d accepted", index)
+		}
+		if err := (&BlockProof{BeaconRoot: proof.BeaconRoot}).Verify(hash); err == nil {
+			t.Fatal("proof without branch accepted")
+		}
+		if err := (&BlockProof{Branch: proof.Branch}).Verify(hash); err == nil {
+			t.Fatal("proof without beacon root accepted")
+		}
+		if err := (&BlockProof{BeaconRoot: proof.BeaconRoot, Branch: proof.Branch[1:]}).Verify(hash); err == nil {
+			t.Fatal("proof with truncated branch accepted")
+		}
+	}
+	// Invalid proofs are rejected by the builder.
+	f, err := os.CreateTemp(t.TempDir(), "erae-proof-test")
+	if err != nil {
+		t.Fatalf("error creating temp file: %v", err)
+	}
+	defer f.Close()
+
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Difficulty: new(big.Int)})
+	if err := NewBuilder(f).Add(block, nil, &BlockProof{BeaconRoot: common.Hash{1}}); err == nil {
+		t.Fatal("expected error adding block with invalid proof")
+	}
+}
+
+// testBlockProof constructs a beacon root from an arbitrary branch of the block
+// hash at the given generalized index.
+func testBlockProof(hash common.Hash, index uint64) *BlockProof {
+	var (
+		branch []common.Hash
+		node   = hash
+	)
+	for ; index > 1; index >>= 1 {
+		sibling := common.Hash{byte(index), hash[0]}
+		branch = append(branch, sibling)
+		if index&1 == 0 {
+			node = sha256.Sum256(append(node[:], sibling[:]...))
+		} else {
+			node = sha256.Sum256(append(sibling[:], node[:]...))
+		}
+	}
+	return &BlockProof{BeaconRoot: node, Branch: branch}
+}
+
+func TestEraEFilename(t *testing.T) {
+	t.Parallel()
+
+	if have, want := Filename("mainnet", 1, common.Hash{1}), "mainnet-00001-01000000.erae"; have != want {
+		t.Errorf("invalid filename: want %s, got %s", want, have)
+	}
+}
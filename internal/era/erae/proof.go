@@ -0,0 +1,115 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package erae
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/ethereum/go-ethereum/beacon/merkle"
+	"github.com/ethereum/go-ethereum/common"
+	ssz "github.com/ferranbt/fastssz"
+)
+
+// Generalized indices of the execution block hash within a beacon block:
+// body (12) -> execution_payload (201) -> block_hash. The execution payload
+// container has 16 leaves up to capella and 32 leaves since deneb.
+const (
+	blockHashIndexBellatrix = 3228
+	blockHashIndexDeneb     = 6444
+)
+
+// BlockProof ties an execution block to the beacon block which included it.
+type BlockProof struct {
+	BeaconRoot common.Hash   // Root of the including beacon block, zero if unknown
+	Branch     []common.Hash // Merkle branch of the block hash in the beacon block
+}
+
+// Verify checks the merkle branch of the given execution block hash against the
+// beacon root of the proof. The generalized index of the block hash is derived
+// from the length of the branch.
+func (p *BlockProof) Verify(hash common.Hash) error {
+	if p.BeaconRoot == (common.Hash{}) {
+		return errors.New("missing beacon root")
+	}
+	var index uint64
+	switch len(p.Branch) {
+	case 0:
+		return errors.New("missing block hash branch")
+	case bits.Len64(blockHashIndexBellatrix) - 1:
+		index = blockHashIndexBellatrix
+	case bits.Len64(blockHashIndexDeneb) - 1:
+		index = blockHashIndexDeneb
+	default:
+		return fmt.Errorf("invalid block hash branch length %d", len(p.Branch))
+	}
+	branch := make(merkle.Values, len(p.Branch))
+	for i, node := range p.Branch {
+		branch[i] = merkle.Value(node)
+	}
+	if err := merkle.VerifyProof(p.BeaconRoot, index, branch, merkle.Value(hash)); err != nil {
+		return fmt.Errorf("invalid block hash branch: %w", err)
+	}
+	return nil
+}
+
+// ComputeAccumulator calculates the SSZ hash tree root of the EraE beacon
+// records, tying each block hash to the root of its beacon block.
+func ComputeAccumulator(hashes []common.Hash, roots []common.Hash) (common.Hash, error) {
+	if len(hashes) != len(roots) {
+		return common.Hash{}, errors.New("must have equal number hashes as beacon roots")
+	}
+	if len(hashes) > MaxEraESize {
+		return common.Hash{}, fmt.Errorf("too many records: have %d, max %d", len(hashes), MaxEraESize)
+	}
+	hh := ssz.NewHasher()
+	for i := range hashes {
+		rec := beaconRecord{hashes[i], roots[i]}
+		root, err := rec.HashTreeRoot()
+		if err != nil {
+			return common.Hash{}, err
+		}
+		hh.Append(root[:])
+	}
+	hh.MerkleizeWithMixin(0, uint64(len(hashes)), uint64(MaxEraESize))
+	return hh.HashRoot()
+}
+
+// beaconRecord is an individual record for a historical beacon root.
+type beaconRecord struct {
+	Hash       common.Hash
+	BeaconRoot common.Hash
+}
+
+// GetTree completes the ssz.HashRoot interface, but is unused.
+func (r *beaconRecord) GetTree() (*ssz.Node, error) {
+	return nil, nil
+}
+
+// HashTreeRoot ssz hashes the beaconRecord object.
+func (r *beaconRecord) HashTreeRoot() ([32]byte, error) {
+	return ssz.HashWithDefaultHasher(r)
+}
+
+// HashTreeRootWith ssz hashes the beaconRecord object with a hasher.
+func (r *beaconRecord) HashTreeRootWith(hh ssz.HashWalker) (err error) {
+	hh.PutBytes(r.Hash[:])
+	hh.PutBytes(r.BeaconRoot[:])
+	hh.Merkleize(0)
+	return
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package erae
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// slimReceipt is the archived form of a receipt. Contrary to the consensus
+// encoding, the transaction type is a plain field and the bloom filter is
+// omitted, as it can be recomputed from the logs.
+type slimReceipt struct {
+	Type              uint8
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Logs              []*types.Log
+}
+
+// EncodeReceipts returns the slim RLP encoding of the given receipts.
+func EncodeReceipts(receipts types.Receipts) ([]byte, error) {
+	slim := make([]*slimReceipt, len(receipts))
+	for i, r := range receipts {
+		status := r.PostState
+		if len(status) == 0 {
+			status = []byte{}
+			if r.Status == types.ReceiptStatusSuccessful {
+				status = []byte{0x01}
+			}
+		}
+		logs := r.Logs
+		if logs == nil {
+			logs = []*types.Log{}
+		}
+		slim[i] = &slimReceipt{
+			Type:              r.Type,
+			PostStateOrStatus: status,
+			CumulativeGasUsed: r.CumulativeGasUsed,
+			Logs:              logs,
+		}
+	}
+	return rlp.EncodeToBytes(slim)
+}
+
+// DecodeReceipts decodes slim RLP encoded receipts, recomputing their bloom
+// filters. Only the consensus fields of the receipts are populated.
+func DecodeReceipts(input []byte) (types.Receipts, error) {
+	var slim []*slimReceipt
+	if err := rlp.DecodeBytes(input, &slim); err != nil {
+		return nil, err
+	}
+	receipts := make(types.Receipts, len(slim))
+	for i, s := range slim {
+		r := &types.Receipt{
+			Type:              s.Type,
+			CumulativeGasUsed: s.CumulativeGasUsed,
+			Logs:              s.Logs,
+		}
+		switch {
+		case bytes.Equal(s.PostStateOrStatus, []byte{0x01}):
+			r.Status = types.ReceiptStatusSuccessful
+		case len(s.PostStateOrStatus) == 0:
+			r.Status = types.ReceiptStatusFailed
+		case len(s.PostStateOrStatus) == common.HashLength:
+			r.PostState = s.PostStateOrStatus
+		default:
+			return nil, fmt.Errorf("invalid receipt %d status %x", i, s.PostStateOrStatus)
+		}
+		r.Bloom = types.CreateBloom(r)
+		receipts[i] = r
+	}
+	return receipts, nil
+}
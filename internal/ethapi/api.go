@@ -35,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -233,6 +234,15 @@ func (api *TxPoolAPI) ContentFrom(addr common.Address) map[string]map[string]*RP
 	return content
 }
 
+// RPCTxPoolStatus is the status of a single transaction as seen by the pool.
+type RPCTxPoolStatus struct {
+	Hash        common.Hash        `json:"hash"`
+	Status      string             `json:"status"`
+	Reason      *txpool.DropReason `json:"reason,omitempty"`
+	Replacement *common.Hash       `json:"replacement,omitempty"`
+	Time        *hexutil.Uint64    `json:"time,omitempty"`
+}
+
 // Status returns the number of pending and queued transaction in the pool.
 func (api *TxPoolAPI) Status() map[string]hexutil.Uint {
 	pending, queue := api.b.Stats()
@@ -242,6 +252,57 @@ func (api *TxPoolAPI) Status() map[string]hexutil.Uint {
 	}
 }
 
+// TxStatus returns the status of a single transaction in the pool, along with
+// the reason of its removal if it was recently dropped from the pool.
+func (api *TxPoolAPI) TxStatus(hash common.Hash) *RPCTxPoolStatus {
+	status := &RPCTxPoolStatus{Hash: hash, Status: "unknown"}
+	switch api.b.TxPoolStatus(hash) {
+	case txpool.TxStatusPending:
+		status.Status = "pending"
+	case txpool.TxStatusQueued:
+		status.Status = "queued"
+	default:
+		if record := api.b.TxPoolDropped(hash); record != nil {
+			dropped := hexutil.Uint64(record.Time.Unix())
+
+			status.Status = "dropped"
+			status.Reason = &record.Reason
+			status.Replacement = record.Replacement
+			status.Time = &dropped
+		}
+	}
+	return status
+}
+
+// Drops creates a subscription that is triggered each time a transaction is
+// removed from the pool, notifying its hash, the reason of the removal and the
+// replacing transaction, if any.
+func (api *TxPoolAPI) Drops(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		drops := make(chan txpool.DropEvent, 128)
+		dropSub := api.b.SubscribeTxDropEvent(drops)
+		defer dropSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-drops:
+				for _, drop := range ev.Drops {
+					notifier.Notify(rpcSub.ID, drop)
+				}
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
 // Inspect retrieves the content of the transaction pool and flattens it into an
 // easily inspectable list.
 func (api *TxPoolAPI) Inspect() map[string]map[string]map[string]string {
@@ -1544,6 +1605,26 @@ func (api *TransactionAPI) SendRawTransaction(ctx context.Context, input hexutil
 	return SubmitTransaction(ctx, api.b, tx)
 }
 
+// SendRawTransactionConditional will add the signed transaction to the transaction
+// pool along with inclusion conditions. The transaction is only included in a
+// block if the storage of the known accounts matches and the block number and
+// timestamp are within the given bounds. Conditional transactions are not
+// propagated to the network.
+func (api *TransactionAPI) SendRawTransactionConditional(ctx context.Context, input hexutil.Bytes, cond types.TransactionConditional) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if tx.Type() == types.BlobTxType {
+		return common.Hash{}, errors.New("conditional blob transactions are not supported")
+	}
+	if err := cond.Validate(); err != nil {
+		return common.Hash{}, err
+	}
+	tx.SetConditional(&cond)
+	return SubmitTransaction(ctx, api.b, tx)
+}
+
 // Sign calculates an ECDSA signature for:
 // keccak256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
@@ -0,0 +1,214 @@
+package metrics
+
+import (
+	"math"
+	"slices"
+	"sync"
+)
+
+// BucketHistogramSnapshot is a read-only copy of a BucketHistogram.
+type BucketHistogramSnapshot interface {
+	HistogramSnapshot
+
+	// Buckets returns the upper bounds of the buckets, and the cumulative counts
+	// of the values lower than or equal to each bound.
+	Buckets() (bounds []int64, counts []int64)
+}
+
+// GetOrRegisterBucketHistogram returns an existing BucketHistogram or constructs
+// and registers a new one with the given bucket bounds.
+func GetOrRegisterBucketHistogram(name string, r Registry, bounds []int64) *BucketHistogram {
+	if nil == r {
+		r = DefaultRegistry
+	}
+	return r.GetOrRegister(name, func() Histogram { return NewBucketHistogram(bounds) }).(*BucketHistogram)
+}
+
+// BucketHistogram is a Histogram counting the values into fixed buckets, like
+// the Prometheus histograms. Unlike the sample based histograms, it tracks all
+// the values since creation at a fixed memory cost, while the percentiles are
+// approximated from the buckets.
+type BucketHistogram struct {
+	bounds []int64 // Ascending upper bounds of the buckets
+
+	lock     sync.Mutex
+	counts   []int64 // Values in each bucket, the last one is unbounded
+	count    int64
+	sum      int64
+	min, max int64
+}
+
+// NewBucketHistogram constructs a new BucketHistogram with the given ascending
+// upper bounds of the buckets. Values above the last bound go into an extra
+// unbounded bucket.
+func NewBucketHistogram(bounds []int64) *BucketHistogram {
+	if !slices.IsSorted(bounds) {
+		panic("bucket bounds not ascending")
+	}
+	return &BucketHistogram{
+		bounds: slices.Clone(bounds),
+		counts: make([]int64, len(bounds)+1),
+	}
+}
+
+// Clear resets the histogram.
+func (h *BucketHistogram) Clear() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	clear(h.counts)
+	h.count, h.sum, h.min, h.max = 0, 0, 0, 0
+}
+
+// Update counts a new value.
+func (h *BucketHistogram) Update(v int64) {
+	if !metricsEnabled {
+		return
+	}
+	bucket, _ := slices.BinarySearch(h.bounds, v)
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.counts[bucket]++
+	if h.count == 0 || v < h.min {
+		h.min = v
+	}
+	if h.count == 0 || v > h.max {
+		h.max = v
+	}
+	h.count++
+	h.sum += v
+}
+
+// Snapshot returns a read-only copy of the histogram.
+func (h *BucketHistogram) Snapshot() HistogramSnapshot {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return &bucketHistogramSnapshot{
+		bounds: h.bounds,
+		counts: slices.Clone(h.counts),
+		count:  h.count,
+		sum:    h.sum,
+		min:    h.min,
+		max:    h.max,
+	}
+}
+
+// bucketHistogramSnapshot is a read-only copy of a BucketHistogram.
+type bucketHistogramSnapshot struct {
+	bounds   []int64
+	counts   []int64
+	count    int64
+	sum      int64
+	min, max int64
+}
+
+// Buckets returns the upper bounds of the buckets, and the cumulative counts of
+// the values lower than or equal to each bound.
+func (h *bucketHistogramSnapshot) Buckets() ([]int64, []int64) {
+	counts := make([]int64, len(h.bounds))
+	var total int64
+	for i := range h.bounds {
+		total += h.counts[i]
+		counts[i] = total
+	}
+	return h.bounds, counts
+}
+
+// Count returns the number of values.
+func (h *bucketHistogramSnapshot) Count() int64 { return h.count }
+
+// Max returns the highest value.
+func (h *bucketHistogramSnapshot) Max() int64 { return h.max }
+
+// Min returns the lowest value.
+func (h *bucketHistogramSnapshot) Min() int64 { return h.min }
+
+// Sum returns the sum of the values.
+func (h *bucketHistogramSnapshot) Sum() int64 { return h.sum }
+
+// Size returns the number of buckets.
+func (h *bucketHistogramSnapshot) Size() int { return len(h.counts) }
+
+// Mean returns the mean of the values.
+func (h *bucketHistogramSnapshot) Mean() float64 {
+	if h.count == 0 {
+		return 0
+	}
+	return float64(h.sum) / float64(h.count)
+}
+
+// bucketRange returns the range of the values in a bucket, narrowed to the
+// lowest and highest values.
+func (h *bucketHistogramSnapshot) bucketRange(bucket int) (float64, float64) {
+	lo, hi := h.min, h.max
+	if bucket > 0 && h.bounds[bucket-1] > lo {
+		lo = h.bounds[bucket-1]
+	}
+	if bucket < len(h.bounds) && h.bounds[bucket] < hi {
+		hi = h.bounds[bucket]
+	}
+	return float64(lo), float64(hi)
+}
+
+// Variance returns an approximation of the variance of the values, assuming
+// they are at the middle of their buckets.
+func (h *bucketHistogramSnapshot) Variance() float64 {
+	if h.count == 0 {
+		return 0
+	}
+	var (
+		mean = h.Mean()
+		sum  float64
+	)
+	for i, c := range h.counts {
+		if c == 0 {
+			continue
+		}
+		lo, hi := h.bucketRange(i)
+		d := (lo+hi)/2 - mean
+		sum += d * d * float64(c)
+	}
+	return sum / float64(h.count)
+}
+
+// StdDev returns an approximation of the standard deviation of the values.
+func (h *bucketHistogramSnapshot) StdDev() float64 {
+	return math.Sqrt(h.Variance())
+}
+
+// Percentile returns an approximation of the value at the given percentile,
+// interpolated within its bucket.
+func (h *bucketHistogramSnapshot) Percentile(p float64) float64 {
+	return h.Percentiles([]float64{p})[0]
+}
+
+// Percentiles returns approximations of the values at the given percentiles,
+// interpolated within their buckets.
+func (h *bucketHistogramSnapshot) Percentiles(ps []float64) []float64 {
+	scores := make([]float64, len(ps))
+	if h.count == 0 {
+		return scores
+	}
+	for i, p := range ps {
+		var (
+			rank  = p * float64(h.count)
+			total int64
+		)
+		for bucket, c := range h.counts {
+			if c == 0 || float64(total+c) < rank {
+				total += c
+				continue
+			}
+			lo, hi := h.bucketRange(bucket)
+			scores[i] = lo + (hi-lo)*(rank-float64(total))/float64(c)
+			break
+		}
+		if scores[i] < float64(h.min) {
+			scores[i] = float64(h.min)
+		}
+	}
+	return scores
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func BenchmarkBucketHistogram(b *testing.B) {
+	h := NewBucketHistogram([]int64{10, 100, 1000, 10000})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Update(int64(i))
+	}
+}
+
+func TestGetOrRegisterBucketHistogram(t *testing.T) {
+	r := NewRegistry()
+	GetOrRegisterBucketHistogram("foo", r, []int64{10}).Update(47)
+	if h := GetOrRegisterBucketHistogram("foo", r, []int64{10}).Snapshot(); h.Count() != 1 {
+		t.Fatal(h)
+	}
+}
+
+func TestBucketHistogram10000(t *testing.T) {
+	var bounds []int64
+	for b := int64(100); b <= 10000; b += 100 {
+		bounds = append(bounds, b)
+	}
+	h := NewBucketHistogram(bounds)
+	for i := 1; i <= 10000; i++ {
+		h.Update(int64(i))
+	}
+	snap := h.Snapshot()
+	if count, min, max, mean := snap.Count(), snap.Min(), snap.Max(), snap.Mean(); count != 10000 || min != 1 || max != 10000 || mean != 5000.5 {
+		t.Fatalf("totals mismatch: count %d, min %d, max %d, mean %v", count, min, max, mean)
+	}
+	// The percentiles and deviation are approximated within a bucket width
+	if stdDev := snap.StdDev(); math.Abs(stdDev-2886.751331514372) > 100 {
+		t.Errorf("stddev %v too far from 2886.75", stdDev)
+	}
+	for i, p := range snap.Percentiles([]float64{0.5, 0.75, 0.99}) {
+		want := []float64{5000.5, 7500.75, 9900.99}[i]
+		if math.Abs(p-want) > 100 {
+			t.Errorf("percentile %v too far from %v", p, want)
+		}
+	}
+}
+
+func TestBucketHistogramBuckets(t *testing.T) {
+	h := NewBucketHistogram([]int64{10, 100, 1000})
+	for _, v := range []int64{1, 10, 11, 50, 5000} {
+		h.Update(v)
+	}
+	snap := h.Snapshot().(BucketHistogramSnapshot)
+	bounds, counts := snap.Buckets()
+	if !slices.Equal(bounds, []int64{10, 100, 1000}) || !slices.Equal(counts, []int64{2, 4, 4}) {
+		t.Fatalf("buckets mismatch: bounds %v, counts %v", bounds, counts)
+	}
+	if count, sum, min, max := snap.Count(), snap.Sum(), snap.Min(), snap.Max(); count != 5 || sum != 5072 || min != 1 || max != 5000 {
+		t.Fatalf("totals mismatch: count %d, sum %d, min %d, max %d", count, sum, min, max)
+	}
+	// The percentiles are within the bucket of the value, the highest one in
+	// the unbounded bucket up to the maximum
+	ps := snap.Percentiles([]float64{0.2, 0.6, 1})
+	if ps[0] < 1 || ps[0] > 10 || ps[1] <= 10 || ps[1] > 100 || ps[2] != 5000 {
+		t.Fatalf("percentiles out of buckets: %v", ps)
+	}
+	h.Clear()
+	if snap := h.Snapshot(); snap.Count() != 0 || snap.Sum() != 0 {
+		t.Fatalf("histogram not cleared: count %d, sum %d", snap.Count(), snap.Sum())
+	}
+}
+
+func TestBucketHistogramEmpty(t *testing.T) {
+	h := NewBucketHistogram([]int64{10}).Snapshot()
+	if h.Count() != 0 || h.Min() != 0 || h.Max() != 0 || h.Mean() != 0 || h.StdDev() != 0 {
+		t.Fatalf("empty histogram has values: %+v", h)
+	}
+	for _, p := range h.Percentiles([]float64{0.5, 0.99}) {
+		if p != 0 || math.IsNaN(p) {
+			t.Fatalf("empty histogram has percentile %v", p)
+		}
+	}
+}
@@ -30,8 +30,10 @@ var (
 	typeGaugeTpl           = "# TYPE %s gauge\n"
 	typeCounterTpl         = "# TYPE %s counter\n"
 	typeSummaryTpl         = "# TYPE %s summary\n"
+	typeHistogramTpl       = "# TYPE %s histogram\n"
 	keyValueTpl            = "%s %v\n\n"
 	keyQuantileTagValueTpl = "%s {quantile=\"%s\"} %v\n"
+	keyBucketTagValueTpl   = "%s_bucket {le=\"%s\"} %v\n"
 )
 
 // collector is a collection of byte buffers that aggregate Prometheus reports
@@ -61,6 +63,8 @@ func (c *collector) Add(name string, i any) error {
 		c.addGaugeFloat64(name, m.Snapshot())
 	case *metrics.GaugeInfo:
 		c.addGaugeInfo(name, m.Snapshot())
+	case *metrics.BucketHistogram:
+		c.addBucketHistogram(name, m.Snapshot().(metrics.BucketHistogramSnapshot))
 	case metrics.Histogram:
 		c.addHistogram(name, m.Snapshot())
 	case *metrics.Meter:
@@ -106,6 +110,18 @@ func (c *collector) addHistogram(name string, m metrics.HistogramSnapshot) {
 	c.buff.WriteRune('\n')
 }
 
+func (c *collector) addBucketHistogram(name string, m metrics.BucketHistogramSnapshot) {
+	name = mutateKey(name)
+	c.buff.WriteString(fmt.Sprintf(typeHistogramTpl, name))
+	bounds, counts := m.Buckets()
+	for i := range bounds {
+		c.buff.WriteString(fmt.Sprintf(keyBucketTagValueTpl, name, strconv.FormatInt(bounds[i], 10), counts[i]))
+	}
+	c.buff.WriteString(fmt.Sprintf(keyBucketTagValueTpl, name, "+Inf", m.Count()))
+	c.buff.WriteString(fmt.Sprintf("%s_sum %v\n", name, m.Sum()))
+	c.buff.WriteString(fmt.Sprintf(keyValueTpl, name+"_count", m.Count()))
+}
+
 func (c *collector) addMeter(name string, m *metrics.MeterSnapshot) {
 	c.writeGaugeCounter(name, m.Count())
 }
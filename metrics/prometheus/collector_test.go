@@ -63,3 +63,25 @@ func findFirstDiffPos(a, b string) string {
 	}
 	return ""
 }
+
+func TestCollectorBucketHistogram(t *testing.T) {
+	h := metrics.NewBucketHistogram([]int64{10, 100})
+	for _, v := range []int64{5, 50, 500} {
+		h.Update(v)
+	}
+	c := newCollector()
+	if err := c.Add("test/latency", h); err != nil {
+		t.Fatal(err)
+	}
+	want := `# TYPE test_latency histogram
+test_latency_bucket {le="10"} 1
+test_latency_bucket {le="100"} 2
+test_latency_bucket {le="+Inf"} 3
+test_latency_sum 555
+test_latency_count 3
+
+`
+	if have := c.buff.String(); have != want {
+		t.Fatalf("unexpected collector output:\nhave\n%v\nwant\n%v", have, want)
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	maxBundleTxs       = 256  // Maximum number of transactions accepted in a bundle
+	maxBundlesPerBlock = 256  // Maximum number of bundles targeting the same block
+	maxBundles         = 4096 // Maximum number of bundles waiting for inclusion
+	maxBundleDistance  = 64   // Maximum distance of the target block from the head
+)
+
+var (
+	errEmptyBundle       = errors.New("bundle has no transactions")
+	errBundleTooLarge    = fmt.Errorf("bundle has more than %d transactions", maxBundleTxs)
+	errBundleBlobTx      = errors.New("blob transactions are not supported in bundles")
+	errBundleNoTarget    = errors.New("bundle has no target block number")
+	errBundleStale       = errors.New("bundle target block is already mined")
+	errBundleTooFar      = fmt.Errorf("bundle target block is more than %d blocks ahead", maxBundleDistance)
+	errBundleKnown       = errors.New("bundle already known")
+	errBlockBundlesFull  = fmt.Errorf("block has more than %d bundles", maxBundlesPerBlock)
+	errBundlesFull       = fmt.Errorf("more than %d bundles waiting for inclusion", maxBundles)
+	errBundleTxReverted  = errors.New("bundle transaction reverted")
+	errBundleOutOfWindow = errors.New("block timestamp outside of the bundle window")
+)
+
+// Bundle is an ordered list of transactions which are included atomically at
+// the top of a block: either all of them are included, or none of them.
+type Bundle struct {
+	Txs          types.Transactions // Transactions of the bundle, in inclusion order
+	BlockNumber  uint64             // Number of the block the bundle targets
+	MinTimestamp uint64             // Minimum block timestamp for inclusion, zero if unbounded
+	MaxTimestamp uint64             // Maximum block timestamp for inclusion, zero if unbounded
+
+	// RevertingTxHashes lists the transactions which are allowed to revert
+	// without invalidating the whole bundle.
+	RevertingTxHashes []common.Hash
+}
+
+// Hash returns the identifier of the bundle, the hash of its transaction hashes.
+func (b *Bundle) Hash() common.Hash {
+	hashes := make([]byte, 0, len(b.Txs)*common.HashLength)
+	for _, tx := range b.Txs {
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+	return crypto.Keccak256Hash(hashes)
+}
+
+// validate checks the bundle for inconsistencies not depending on the state.
+func (b *Bundle) validate() error {
+	switch {
+	case len(b.Txs) == 0:
+		return errEmptyBundle
+	case len(b.Txs) > maxBundleTxs:
+		return errBundleTooLarge
+	}
+	for _, tx := range b.Txs {
+		if tx.Type() == types.BlobTxType {
+			return errBundleBlobTx
+		}
+	}
+	return nil
+}
+
+// canRevert reports whether the given bundle transaction is allowed to revert.
+func (b *Bundle) canRevert(hash common.Hash) bool {
+	return slices.Contains(b.RevertingTxHashes, hash)
+}
+
+// inWindow reports whether a block with the given timestamp may include the
+// bundle.
+func (b *Bundle) inWindow(time uint64) bool {
+	if b.MinTimestamp != 0 && time < b.MinTimestamp {
+		return false
+	}
+	if b.MaxTimestamp != 0 && time > b.MaxTimestamp {
+		return false
+	}
+	return true
+}
+
+// BundleResult is the outcome of executing a bundle.
+type BundleResult struct {
+	Hash         common.Hash       // Hash of the bundle
+	GasUsed      uint64            // Total gas used by the bundle
+	CoinbaseDiff *big.Int          // Balance change of the fee recipient, fees and direct payments
+	Txs          []*BundleTxResult // Results of the individual transactions
+}
+
+// BundleTxResult is the outcome of executing a transaction of a bundle.
+type BundleTxResult struct {
+	Receipt      *types.Receipt
+	From         common.Address
+	CoinbaseDiff *big.Int // Balance change of the fee recipient caused by the transaction
+}
+
+// SendBundle adds the bundle to the set considered for inclusion in the block
+// with its target number. Bundles are dropped once their target block is mined,
+// or as soon as they fail to execute on top of a block being built. The target
+// block may be at most maxBundleDistance blocks ahead of the head, and the
+// number of bundles waiting for inclusion is capped.
+func (miner *Miner) SendBundle(bundle *Bundle) error {
+	if err := bundle.validate(); err != nil {
+		return err
+	}
+	if bundle.BlockNumber == 0 {
+		return errBundleNoTarget
+	}
+	head := miner.chain.CurrentHeader().Number.Uint64()
+	if bundle.BlockNumber <= head {
+		return errBundleStale
+	}
+	if bundle.BlockNumber > head+maxBundleDistance {
+		return errBundleTooFar
+	}
+	miner.bundleMu.Lock()
+	defer miner.bundleMu.Unlock()
+
+	// Take the chance to drop the bundles whose target block was mined
+	var total int
+	for number, bundles := range miner.bundles {
+		if number <= head {
+			delete(miner.bundles, number)
+			continue
+		}
+		total += len(bundles)
+	}
+	bundles := miner.bundles[bundle.BlockNumber]
+	hash := bundle.Hash()
+	for _, b := range bundles {
+		if b.Hash() == hash {
+			return errBundleKnown
+		}
+	}
+	switch {
+	case len(bundles) >= maxBundlesPerBlock:
+		return errBlockBundlesFull
+	case total >= maxBundles:
+		return errBundlesFull
+	}
+	miner.bundles[bundle.BlockNumber] = append(bundles, bundle)
+	return nil
+}
+
+// CallBundle simulates the bundle on top of the given parent block, without
+// adding it to the set considered for inclusion. A timestamp of zero means the
+// one following the parent. Reverting transactions are reported in the result
+// instead of failing the simulation.
+func (miner *Miner) CallBundle(bundle *Bundle, parent common.Hash, timestamp uint64) (*BundleResult, error) {
+	if err := bundle.validate(); err != nil {
+		return nil, err
+	}
+	sim := &Bundle{Txs: bundle.Txs}
+	for _, tx := range bundle.Txs {
+		sim.RevertingTxHashes = append(sim.RevertingTxHashes, tx.Hash())
+	}
+	miner.confMu.RLock()
+	coinbase := miner.config.PendingFeeRecipient
+	miner.confMu.RUnlock()
+
+	env, err := miner.prepareWork(&generateParams{
+		timestamp:  timestamp,
+		parentHash: parent,
+		coinbase:   coinbase,
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+	return miner.simulateBundle(env, sim)
+}
+
+// simulateBundle executes the bundle on top of a copy of the environment,
+// leaving the environment itself untouched. An error is returned if any of the
+// transactions is invalid, or reverts without being allowed to.
+func (miner *Miner) simulateBundle(env *environment, bundle *Bundle) (*BundleResult, error) {
+	var (
+		state   = env.state.Copy()
+		header  = types.CopyHeader(env.header)
+		evm     = vm.NewEVM(env.evm.Context, state, miner.chainConfig, vm.Config{})
+		gasPool = new(core.GasPool).AddGas(header.GasLimit)
+		tcount  = env.tcount
+	)
+	if env.gasPool != nil {
+		gasPool.SetGas(env.gasPool.Gas())
+	}
+	result := &BundleResult{
+		Hash:         bundle.Hash(),
+		CoinbaseDiff: new(big.Int),
+	}
+	for _, tx := range bundle.Txs {
+		from, err := types.Sender(env.signer, tx)
+		if err != nil {
+			return nil, fmt.Errorf("tx %#x: %w", tx.Hash(), err)
+		}
+		before := state.GetBalance(env.coinbase).ToBig()
+
+		state.SetTxContext(tx.Hash(), tcount)
+		receipt, err := core.ApplyTransaction(evm, gasPool, state, header, tx, &header.GasUsed)
+		if err != nil {
+			return nil, fmt.Errorf("tx %#x: %w", tx.Hash(), err)
+		}
+		if receipt.Status == types.ReceiptStatusFailed && !bundle.canRevert(tx.Hash()) {
+			return nil, fmt.Errorf("tx %#x: %w", tx.Hash(), errBundleTxReverted)
+		}
+		tcount++
+
+		diff := new(big.Int).Sub(state.GetBalance(env.coinbase).ToBig(), before)
+		result.GasUsed += receipt.GasUsed
+		result.CoinbaseDiff.Add(result.CoinbaseDiff, diff)
+		result.Txs = append(result.Txs, &BundleTxResult{
+			Receipt:      receipt,
+			From:         from,
+			CoinbaseDiff: diff,
+		})
+	}
+	return result, nil
+}
+
+// commitBundle includes all the transactions of the bundle in the block being
+// built, or none of them if the bundle fails to execute.
+func (miner *Miner) commitBundle(env *environment, bundle *Bundle) error {
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+	// The state journal doesn't span across transactions, so execute the bundle
+	// on a copy first and only include it if every transaction succeeds.
+	if _, err := miner.simulateBundle(env, bundle); err != nil {
+		return err
+	}
+	for _, tx := range bundle.Txs {
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+		if err := miner.commitTransaction(env, tx); err != nil {
+			// Execution is deterministic, the simulation should have caught this
+			log.Error("Bundle transaction failed after simulation", "bundle", bundle.Hash(), "hash", tx.Hash(), "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// commitBundles includes the bundles targeting the block being built, in the
+// order they were received. The bundles which fail to execute are dropped.
+func (miner *Miner) commitBundles(env *environment, interrupt *atomic.Int32) error {
+	number := env.header.Number.Uint64()
+
+	miner.bundleMu.Lock()
+	bundles := slices.Clone(miner.bundles[number])
+	miner.bundleMu.Unlock()
+
+	var failed []*Bundle
+	defer func() {
+		if len(failed) > 0 {
+			miner.dropBundles(number, failed)
+		}
+	}()
+	for _, bundle := range bundles {
+		if interrupt != nil {
+			if signal := interrupt.Load(); signal != commitInterruptNone {
+				return signalToErr(signal)
+			}
+		}
+		if !bundle.inWindow(env.header.Time) {
+			log.Trace("Skipping bundle outside of its window", "hash", bundle.Hash(), "time", env.header.Time, "err", errBundleOutOfWindow)
+			continue
+		}
+		err := miner.commitBundle(env, bundle)
+		switch {
+		case err == nil:
+			log.Trace("Included bundle", "hash", bundle.Hash(), "txs", len(bundle.Txs))
+
+		case errors.Is(err, core.ErrGasLimitReached):
+			// Not enough room left in this block, but the bundle may still fit
+			// in another one built for the same height.
+			log.Trace("Not enough gas left for bundle", "hash", bundle.Hash())
+
+		default:
+			log.Debug("Bundle failed, dropping", "hash", bundle.Hash(), "err", err)
+			failed = append(failed, bundle)
+		}
+	}
+	return nil
+}
+
+// dropBundles removes the given bundles targeting the given block.
+func (miner *Miner) dropBundles(number uint64, drop []*Bundle) {
+	miner.bundleMu.Lock()
+	defer miner.bundleMu.Unlock()
+
+	bundles := slices.DeleteFunc(miner.bundles[number], func(b *Bundle) bool {
+		return slices.Contains(drop, b)
+	})
+	if len(bundles) == 0 {
+		delete(miner.bundles, number)
+	} else {
+		miner.bundles[number] = bundles
+	}
+}
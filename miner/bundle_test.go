@@ -0,0 +1,197 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// newBundleTx creates a transaction of the test bank with the given nonce. If
+// revert is set, the transaction deploys a contract whose constructor reverts.
+func newBundleTx(nonce uint64, revert bool) *types.Transaction {
+	tx := &types.LegacyTx{
+		Nonce:    nonce,
+		To:       &testUserAddress,
+		Value:    big.NewInt(1),
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(params.InitialBaseFee),
+	}
+	if revert {
+		// PUSH1 0 PUSH1 0 REVERT
+		tx.To, tx.Gas, tx.Data = nil, 100000, common.FromHex("60006000fd")
+	}
+	return types.MustSignNewTx(testBankKey, types.LatestSigner(params.TestChainConfig), tx)
+}
+
+func buildBundlePayload(t *testing.T, w *Miner, b *testWorkerBackend) types.Transactions {
+	t.Helper()
+
+	payload, err := w.buildPayload(&BuildPayloadArgs{
+		Parent:    b.chain.CurrentBlock().Hash(),
+		Timestamp: uint64(time.Now().Unix()),
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to build payload %v", err)
+	}
+	full := payload.ResolveFull()
+	txs := make(types.Transactions, len(full.ExecutionPayload.Transactions))
+	for i, enc := range full.ExecutionPayload.Transactions {
+		txs[i] = new(types.Transaction)
+		if err := txs[i].UnmarshalBinary(enc); err != nil {
+			t.Fatalf("Failed to decode payload transaction: %v", err)
+		}
+	}
+	return txs
+}
+
+func TestBundleInclusion(t *testing.T) {
+	w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+	b.txPool.Add(newTxs, true)
+
+	// The bundle replaces the first pool transaction, the second one is
+	// included after it.
+	bundle := &Bundle{Txs: types.Transactions{newBundleTx(0, false)}, BlockNumber: 1}
+	if err := w.SendBundle(bundle); err != nil {
+		t.Fatalf("Failed to send bundle: %v", err)
+	}
+	txs := buildBundlePayload(t, w, b)
+	if len(txs) != 2 {
+		t.Fatalf("Unexpected transaction count, want 2, got %d", len(txs))
+	}
+	if txs[0].Hash() != bundle.Txs[0].Hash() || txs[1].Hash() != newTxs[0].Hash() {
+		t.Fatal("Bundle not included ahead of the pool transactions")
+	}
+	// Bundles targeting mined blocks are rejected
+	if err := w.SendBundle(&Bundle{Txs: bundle.Txs}); err != errBundleNoTarget {
+		t.Fatalf("Unexpected error, want %v, got %v", errBundleNoTarget, err)
+	}
+}
+
+func TestBundleRevert(t *testing.T) {
+	w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+
+	// A reverting bundle is dropped, without partially including it
+	reverting := &Bundle{
+		Txs:         types.Transactions{newBundleTx(0, false), newBundleTx(1, true)},
+		BlockNumber: 1,
+	}
+	if err := w.SendBundle(reverting); err != nil {
+		t.Fatalf("Failed to send bundle: %v", err)
+	}
+	txs := buildBundlePayload(t, w, b)
+	if len(txs) != 1 || txs[0].Hash() != pendingTxs[0].Hash() {
+		t.Fatalf("Unexpected payload transactions: %v", txs)
+	}
+	if len(w.bundles) != 0 {
+		t.Fatal("Reverting bundle not dropped")
+	}
+	// Reverts explicitly allowed don't invalidate the bundle
+	reverting.RevertingTxHashes = []common.Hash{reverting.Txs[1].Hash()}
+	if err := w.SendBundle(reverting); err != nil {
+		t.Fatalf("Failed to send bundle: %v", err)
+	}
+	txs = buildBundlePayload(t, w, b)
+	if len(txs) != 2 || txs[0].Hash() != reverting.Txs[0].Hash() || txs[1].Hash() != reverting.Txs[1].Hash() {
+		t.Fatalf("Unexpected payload transactions: %v", txs)
+	}
+}
+
+func TestSendBundleLimits(t *testing.T) {
+	w, _ := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+
+	send := func(nonce uint64, number uint64) error {
+		return w.SendBundle(&Bundle{Txs: types.Transactions{newBundleTx(nonce, false)}, BlockNumber: number})
+	}
+	if err := send(0, 0); err != errBundleNoTarget {
+		t.Fatalf("Unexpected error for missing target, want %v, got %v", errBundleNoTarget, err)
+	}
+	if err := send(0, maxBundleDistance+1); err != errBundleTooFar {
+		t.Fatalf("Unexpected error for distant target, want %v, got %v", errBundleTooFar, err)
+	}
+	if err := send(0, 1); err != nil {
+		t.Fatalf("Failed to send bundle: %v", err)
+	}
+	if err := send(0, 1); err != errBundleKnown {
+		t.Fatalf("Unexpected error for duplicate bundle, want %v, got %v", errBundleKnown, err)
+	}
+	for nonce := uint64(1); nonce < maxBundlesPerBlock; nonce++ {
+		if err := send(nonce, 1); err != nil {
+			t.Fatalf("Failed to send bundle %d: %v", nonce, err)
+		}
+	}
+	if err := send(maxBundlesPerBlock, 1); err != errBlockBundlesFull {
+		t.Fatalf("Unexpected error for full block, want %v, got %v", errBlockBundlesFull, err)
+	}
+	// Fill up the other target blocks until the total limit is reached
+	var nonce uint64
+	for number := uint64(2); ; number++ {
+		for i := 0; i < maxBundlesPerBlock; i++ {
+			err := send(nonce, number)
+			nonce++
+			if err == errBundlesFull {
+				if total := (number-1)*maxBundlesPerBlock + uint64(i); total != maxBundles {
+					t.Fatalf("Unexpected number of bundles accepted, want %d, got %d", maxBundles, total)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to send bundle: %v", err)
+			}
+		}
+	}
+}
+
+func TestCallBundle(t *testing.T) {
+	w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+
+	bundle := &Bundle{Txs: types.Transactions{newBundleTx(0, false), newBundleTx(1, true)}}
+	result, err := w.CallBundle(bundle, b.chain.CurrentBlock().Hash(), 0)
+	if err != nil {
+		t.Fatalf("Failed to call bundle: %v", err)
+	}
+	if result.Hash != bundle.Hash() || len(result.Txs) != 2 {
+		t.Fatalf("Unexpected bundle result: %+v", result)
+	}
+	if result.Txs[0].Receipt.Status != types.ReceiptStatusSuccessful || result.Txs[1].Receipt.Status != types.ReceiptStatusFailed {
+		t.Fatal("Unexpected transaction status")
+	}
+	if result.GasUsed != result.Txs[0].Receipt.GasUsed+result.Txs[1].Receipt.GasUsed {
+		t.Fatalf("Unexpected bundle gas used %d", result.GasUsed)
+	}
+	if result.Txs[0].From != testBankAddress {
+		t.Fatalf("Unexpected sender %x", result.Txs[0].From)
+	}
+	if diff := new(big.Int).Add(result.Txs[0].CoinbaseDiff, result.Txs[1].CoinbaseDiff); diff.Cmp(result.CoinbaseDiff) != 0 {
+		t.Fatalf("Unexpected bundle coinbase diff, want %v, got %v", diff, result.CoinbaseDiff)
+	}
+	// Invalid transactions fail the simulation
+	if _, err := w.CallBundle(&Bundle{Txs: types.Transactions{newBundleTx(1, false)}}, b.chain.CurrentBlock().Hash(), 0); err == nil {
+		t.Fatal("Expected nonce error")
+	}
+	// The simulated bundle is not submitted
+	if len(w.bundles) != 0 {
+		t.Fatal("Simulated bundle submitted for inclusion")
+	}
+}
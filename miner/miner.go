@@ -74,6 +74,9 @@ type Miner struct {
 	chain       *core.BlockChain
 	pending     *pending
 	pendingMu   sync.Mutex // Lock protects the pending block
+
+	bundles  map[uint64][]*Bundle // Bundles waiting for inclusion, keyed by target block number
+	bundleMu sync.Mutex           // Lock protects the bundle set
 }
 
 // New creates a new miner with provided config.
@@ -85,6 +88,7 @@ func New(eth Backend, config Config, engine consensus.Engine) *Miner {
 		txpool:      eth.TxPool(),
 		chain:       eth.BlockChain(),
 		pending:     &pending{},
+		bundles:     make(map[uint64][]*Bundle),
 	}
 }
 
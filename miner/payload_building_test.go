@@ -24,6 +24,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
@@ -194,6 +195,35 @@ func TestBuildPayload(t *testing.T) {
 	}
 }
 
+func TestBuildPayloadConditional(t *testing.T) {
+	w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+
+	// Add a transaction which may only be included from block 2 onwards
+	minBlock := hexutil.Uint64(2)
+	tx := types.MustSignNewTx(testBankKey, types.LatestSigner(params.TestChainConfig), &types.LegacyTx{
+		Nonce:    1,
+		To:       &testUserAddress,
+		Value:    big.NewInt(1000),
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(params.InitialBaseFee),
+	})
+	tx.SetConditional(&types.TransactionConditional{BlockNumberMin: &minBlock})
+	if err := b.txPool.Add([]*types.Transaction{tx}, true)[0]; err != nil {
+		t.Fatalf("Failed to add conditional transaction: %v", err)
+	}
+	payload, err := w.buildPayload(&BuildPayloadArgs{
+		Parent:    b.chain.CurrentBlock().Hash(),
+		Timestamp: uint64(time.Now().Unix()),
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to build payload %v", err)
+	}
+	full := payload.ResolveFull()
+	if len(full.ExecutionPayload.Transactions) != len(pendingTxs) {
+		t.Fatalf("Unexpected transaction count, want %d, got %d", len(pendingTxs), len(full.ExecutionPayload.Transactions))
+	}
+}
+
 func TestPayloadId(t *testing.T) {
 	t.Parallel()
 	ids := make(map[string]int)
@@ -289,6 +289,19 @@ func (miner *Miner) commitBlobTransaction(env *environment, tx *types.Transactio
 	if env.blobs+len(sc.Blobs) > maxBlobs {
 		return errors.New("max data blobs reached")
 	}
+	// From Osaka onwards the payload carries cell proofs. The pool converts the
+	// legacy sidecars, but some might still linger (e.g. reorged back in around
+	// the fork), convert those on the fly.
+	if miner.chainConfig.IsOsaka(env.header.Number, env.header.Time) {
+		if sc.Version == types.BlobSidecarVersion0 {
+			sc = sc.Copy()
+			if err := sc.ToV1(); err != nil {
+				return err
+			}
+		}
+	} else if sc.Version != types.BlobSidecarVersion0 {
+		return errors.New("blob cell proofs before osaka")
+	}
 	receipt, err := miner.applyTransaction(env, tx)
 	if err != nil {
 		return err
@@ -401,6 +414,14 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 			txs.Pop()
 			continue
 		}
+		// Skip the transaction if its inclusion conditions don't hold anymore
+		if cond := tx.Conditional(); cond != nil {
+			if err := miner.validateConditional(env, cond); err != nil {
+				log.Trace("Ignoring transaction with unmet conditional", "hash", ltx.Hash, "err", err)
+				txs.Pop()
+				continue
+			}
+		}
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
@@ -425,6 +446,20 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 	return nil
 }
 
+// validateConditional checks whether the inclusion conditions attached to a
+// transaction hold on top of the block being built.
+func (miner *Miner) validateConditional(env *environment, cond *types.TransactionConditional) error {
+	// Storage roots are only updated when the state is hashed, make sure they
+	// reflect the transactions already included.
+	for _, account := range cond.KnownAccounts {
+		if account.StorageRoot != nil {
+			env.state.IntermediateRoot(miner.chainConfig.IsEIP158(env.header.Number))
+			break
+		}
+	}
+	return txpool.ValidateConditional(cond, env.header, env.state)
+}
+
 // fillTransactions retrieves the pending transactions from the txpool and fills them
 // into the given sealing block. The transaction selection and ordering strategy can
 // be customized with the plugin in the future.
@@ -434,6 +469,10 @@ func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment)
 	prio := miner.prio
 	miner.confMu.RUnlock()
 
+	// Include the bundles targeting this block ahead of any pool transaction
+	if err := miner.commitBundles(env, interrupt); err != nil {
+		return err
+	}
 	// Retrieve the pending transactions pre-filtered by the 1559/4844 dynamic fees
 	filter := txpool.PendingFilter{
 		MinTip: uint256.MustFromBig(tip),
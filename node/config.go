@@ -204,6 +204,19 @@ type Config struct {
 	// BatchResponseMaxSize is the maximum number of bytes returned from a batched rpc call.
 	BatchResponseMaxSize int `toml:",omitempty"`
 
+	// RPCRateLimits are the per-client rate limits of the HTTP and WebSocket
+	// method calls.
+	RPCRateLimits *rpc.RateLimitConfig `toml:",omitempty"`
+
+	// RPCAccessFile is the path of the file configuring the methods callable by
+	// the HTTP and WebSocket clients, and the API keys and JWT secrets of the
+	// tenants, see RPCAccessConfig. The file is reloaded when changed.
+	RPCAccessFile string `toml:",omitempty"`
+
+	// RPCAccessLog configures the access log of the method calls served over
+	// HTTP and WebSocket.
+	RPCAccessLog *RPCAccessLogConfig `toml:",omitempty"`
+
 	// JWTSecret is the path to the hex-encoded jwt secret.
 	JWTSecret string `toml:",omitempty"`
 
@@ -213,6 +226,27 @@ type Config struct {
 	DBEngine string `toml:",omitempty"`
 }
 
+// RPCAccessLogConfig configures the access log of the HTTP and WebSocket
+// endpoints, a file with a JSON line for each method call served.
+type RPCAccessLogConfig struct {
+	// File is the path of the log file.
+	File string
+
+	// MaxSize is the size in megabytes at which the file is rotated, 100 if
+	// zero.
+	MaxSize int `toml:",omitempty"`
+
+	// MaxBackups is the number of rotated files retained, all if zero.
+	MaxBackups int `toml:",omitempty"`
+
+	// MaxAge is the number of days the rotated files are retained, forever if
+	// zero.
+	MaxAge int `toml:",omitempty"`
+
+	// Compress enables the compression of the rotated files.
+	Compress bool `toml:",omitempty"`
+}
+
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
 // account the set data folders as well as the designated platform we're currently
 // running on.
@@ -21,6 +21,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/bbolt"
 	"github.com/ethereum/go-ethereum/ethdb/leveldb"
 	"github.com/ethereum/go-ethereum/ethdb/pebble"
 	"github.com/ethereum/go-ethereum/log"
@@ -29,7 +30,7 @@ import (
 // openOptions contains the options to apply when opening a database.
 // OBS: If AncientsDirectory is empty, it indicates that no freezer is to be used.
 type openOptions struct {
-	Type              string // "leveldb" | "pebble"
+	Type              string // "leveldb" | "pebble" | "bbolt"
 	Directory         string // the datadir
 	AncientsDirectory string // the ancients-dir
 	Namespace         string // the namespace for database relevant metrics
@@ -72,7 +73,7 @@ func openDatabase(o openOptions) (ethdb.Database, error) {
 //	db is existent     |  from db         |  specified type (if compatible)
 func openKeyValueDatabase(o openOptions) (ethdb.Database, error) {
 	// Reject any unsupported database type
-	if len(o.Type) != 0 && o.Type != rawdb.DBLeveldb && o.Type != rawdb.DBPebble {
+	if len(o.Type) != 0 && o.Type != rawdb.DBLeveldb && o.Type != rawdb.DBPebble && o.Type != rawdb.DBBbolt {
 		return nil, fmt.Errorf("unknown db.engine %v", o.Type)
 	}
 	// Retrieve any pre-existing database's type and use that or the requested one
@@ -89,6 +90,10 @@ func openKeyValueDatabase(o openOptions) (ethdb.Database, error) {
 		log.Info("Using leveldb as the backing database")
 		return newLevelDBDatabase(o.Directory, o.Cache, o.Handles, o.Namespace, o.ReadOnly)
 	}
+	if o.Type == rawdb.DBBbolt || existingDb == rawdb.DBBbolt {
+		log.Info("Using bbolt as the backing database")
+		return newBboltDatabase(o.Directory, o.Namespace, o.ReadOnly, o.Ephemeral)
+	}
 	// No pre-existing database, no user-requested one either. Default to Pebble.
 	log.Info("Defaulting to pebble as the backing database")
 	return newPebbleDBDatabase(o.Directory, o.Cache, o.Handles, o.Namespace, o.ReadOnly, o.Ephemeral)
@@ -114,3 +119,14 @@ func newPebbleDBDatabase(file string, cache int, handles int, namespace string,
 	}
 	return rawdb.NewDatabase(db), nil
 }
+
+// newBboltDatabase creates a persistent key-value database without a freezer
+// moving immutable chain segments into cold storage. The bbolt engine relies on
+// the OS page cache, so no cache or file handle allowance is needed.
+func newBboltDatabase(file string, namespace string, readonly bool, ephemeral bool) (ethdb.Database, error) {
+	db, err := bbolt.New(file, namespace, readonly, ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	return rawdb.NewDatabase(db), nil
+}
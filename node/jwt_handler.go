@@ -17,36 +17,40 @@
 package node
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/golang-jwt/jwt/v4"
 )
 
 const jwtExpiryTimeout = 60 * time.Second
 
+// jwtClaims are the claims of the tokens, including the optional "id" claim
+// identifying the client.
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	ClientID string `json:"id,omitempty"`
+}
+
 type jwtHandler struct {
-	keyFunc func(token *jwt.Token) (interface{}, error)
-	next    http.Handler
+	secret []byte
+	next   http.Handler
 }
 
 // newJWTHandler creates a http.Handler with jwt authentication support.
 func newJWTHandler(secret []byte, next http.Handler) http.Handler {
 	return &jwtHandler{
-		keyFunc: func(token *jwt.Token) (interface{}, error) {
-			return secret, nil
-		},
-		next: next,
+		secret: secret,
+		next:   next,
 	}
 }
 
 // ServeHTTP implements http.Handler
 func (handler *jwtHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
-	var (
-		strToken string
-		claims   jwt.RegisteredClaims
-	)
+	var strToken string
 	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
 		strToken = strings.TrimPrefix(auth, "Bearer ")
 	}
@@ -54,27 +58,43 @@ func (handler *jwtHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
 		http.Error(out, "missing token", http.StatusUnauthorized)
 		return
 	}
+	claims, err := parseJWT(strToken, handler.secret)
+	if err != nil {
+		http.Error(out, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if claims.ClientID != "" {
+		r = r.WithContext(rpc.NewContextWithClientID(r.Context(), claims.ClientID))
+	}
+	handler.next.ServeHTTP(out, r)
+}
+
+// parseJWT validates a token signed with the secret, returning its claims.
+func parseJWT(strToken string, secret []byte) (*jwtClaims, error) {
 	// We explicitly set only HS256 allowed, and also disables the
 	// claim-check: the RegisteredClaims internally requires 'iat' to
 	// be no later than 'now', but we allow for a bit of drift.
-	token, err := jwt.ParseWithClaims(strToken, &claims, handler.keyFunc,
+	var (
+		claims  jwtClaims
+		keyFunc = func(token *jwt.Token) (interface{}, error) { return secret, nil }
+	)
+	token, err := jwt.ParseWithClaims(strToken, &claims, keyFunc,
 		jwt.WithValidMethods([]string{"HS256"}),
 		jwt.WithoutClaimsValidation())
 
 	switch {
 	case err != nil:
-		http.Error(out, err.Error(), http.StatusUnauthorized)
+		return nil, err
 	case !token.Valid:
-		http.Error(out, "invalid token", http.StatusUnauthorized)
+		return nil, errors.New("invalid token")
 	case !claims.VerifyExpiresAt(time.Now(), false): // optional
-		http.Error(out, "token is expired", http.StatusUnauthorized)
+		return nil, errors.New("token is expired")
 	case claims.IssuedAt == nil:
-		http.Error(out, "missing issued-at", http.StatusUnauthorized)
+		return nil, errors.New("missing issued-at")
 	case time.Since(claims.IssuedAt.Time) > jwtExpiryTimeout:
-		http.Error(out, "stale token", http.StatusUnauthorized)
+		return nil, errors.New("stale token")
 	case time.Until(claims.IssuedAt.Time) > jwtExpiryTimeout:
-		http.Error(out, "future token", http.StatusUnauthorized)
-	default:
-		handler.next.ServeHTTP(out, r)
+		return nil, errors.New("future token")
 	}
+	return &claims, nil
 }
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"hash/crc32"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -40,6 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/gofrs/flock"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // Node is a container on which services can be registered.
@@ -65,6 +67,8 @@ type Node struct {
 	wsAuth        *httpServer //
 	ipc           *ipcServer  // Stores information about the ipc http server
 	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
+	rpcAccess     *rpcAccess  // Access policy of the public endpoints, if configured
+	rpcAccessLog  io.Closer   // Access log file of the public endpoints, if configured
 
 	databases map[*closeTrackingDB]struct{} // All open databases
 }
@@ -394,6 +398,33 @@ func (n *Node) startRPC() error {
 		batchItemLimit:         n.config.BatchRequestLimit,
 		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
 	}
+	// The rate limits apply to the public endpoints, across HTTP and WebSocket
+	if n.config.RPCRateLimits != nil {
+		limiter, err := rpc.NewRateLimiter(*n.config.RPCRateLimits)
+		if err != nil {
+			return err
+		}
+		rpcConfig.rateLimiter = limiter
+	}
+	if n.config.RPCAccessFile != "" {
+		access, err := newRPCAccess(n.config.RPCAccessFile)
+		if err != nil {
+			return err
+		}
+		access.start()
+		n.rpcAccess, rpcConfig.access = access, access
+	}
+	if config := n.config.RPCAccessLog; config != nil {
+		file := &lumberjack.Logger{
+			Filename:   config.File,
+			MaxSize:    config.MaxSize,
+			MaxBackups: config.MaxBackups,
+			MaxAge:     config.MaxAge,
+			Compress:   config.Compress,
+		}
+		n.rpcAccessLog, rpcConfig.accessLog = file, rpc.NewAccessLog(file)
+		n.log.Info("Writing RPC access log", "file", config.File)
+	}
 
 	initHttp := func(server *httpServer, port int) error {
 		if err := server.setListenAddr(n.config.HTTPHost, port); err != nil {
@@ -429,6 +460,14 @@ func (n *Node) startRPC() error {
 		return nil
 	}
 
+	// Expose the authenticated-only APIs on the authenticated endpoints on top
+	// of the default modules, they are not reachable otherwise
+	authModules := slices.Clone(DefaultAuthModules)
+	for _, api := range n.rpcAPIs {
+		if api.Authenticated && !slices.Contains(authModules, api.Namespace) {
+			authModules = append(authModules, api.Namespace)
+		}
+	}
 	initAuth := func(port int, secret []byte) error {
 		// Enable auth via HTTP
 		server := n.httpAuth
@@ -444,7 +483,7 @@ func (n *Node) startRPC() error {
 		err := server.enableRPC(allAPIs, httpConfig{
 			CorsAllowedOrigins: DefaultAuthCors,
 			Vhosts:             n.config.AuthVirtualHosts,
-			Modules:            DefaultAuthModules,
+			Modules:            authModules,
 			prefix:             DefaultAuthPrefix,
 			rpcEndpointConfig:  sharedConfig,
 		})
@@ -459,7 +498,7 @@ func (n *Node) startRPC() error {
 			return err
 		}
 		if err := server.enableWS(allAPIs, wsConfig{
-			Modules:           DefaultAuthModules,
+			Modules:           authModules,
 			Origins:           DefaultAuthOrigins,
 			prefix:            DefaultAuthPrefix,
 			rpcEndpointConfig: sharedConfig,
@@ -521,6 +560,14 @@ func (n *Node) stopRPC() {
 	n.wsAuth.stop()
 	n.ipc.stop()
 	n.stopInProc()
+	if n.rpcAccess != nil {
+		n.rpcAccess.stop()
+		n.rpcAccess = nil
+	}
+	if n.rpcAccessLog != nil {
+		n.rpcAccessLog.Close()
+		n.rpcAccessLog = nil
+	}
 }
 
 // startInProc registers all RPC APIs on the inproc server.
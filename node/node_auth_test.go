@@ -135,6 +135,12 @@ func TestAuthEndpoints(t *testing.T) {
 			Public:        true,
 			Authenticated: true,
 		},
+		{
+			Namespace:     "extra",
+			Version:       "1.0",
+			Service:       helloRPC("hello extra"),
+			Authenticated: true,
+		},
 	})
 	if err := node.Start(); err != nil {
 		t.Fatalf("failed to start test node: %v", err)
@@ -195,6 +201,27 @@ func TestAuthEndpoints(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.name, testCase.Run)
 	}
+	// Authenticated-only modules are exposed on the authenticated endpoint even
+	// if not among the default modules, and nowhere else
+	cl, err := rpc.DialOptions(context.Background(), node.HTTPAuthEndpoint(), rpc.WithHTTPAuth(goodAuth))
+	if err != nil {
+		t.Fatalf("failed to dial auth endpoint: %v", err)
+	}
+	defer cl.Close()
+
+	var x string
+	if err := cl.Call(&x, "extra_helloWorld"); err != nil || x != "hello extra" {
+		t.Fatalf("authenticated-only module unavailable: %q, %v", x, err)
+	}
+	cl, err = rpc.Dial(node.HTTPEndpoint())
+	if err != nil {
+		t.Fatalf("failed to dial http endpoint: %v", err)
+	}
+	defer cl.Close()
+
+	if err := cl.Call(&x, "extra_helloWorld"); err == nil {
+		t.Fatal("authenticated-only module exposed on the public endpoint")
+	}
 }
 
 func noneAuth(secret [32]byte) rpc.HTTPAuth {
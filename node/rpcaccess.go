@@ -0,0 +1,285 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/naoina/toml"
+)
+
+// rpcAccessReloadInterval is the interval at which the access file is checked
+// for changes.
+const rpcAccessReloadInterval = 5 * time.Second
+
+// RPCTenant is a client of the HTTP and WebSocket endpoints, authenticated by
+// an API key or a JWT token sent as bearer token in the Authorization header.
+type RPCTenant struct {
+	// Keys are the API keys of the tenant.
+	Keys []string `toml:",omitempty"`
+
+	// JWTSecrets are the hex-encoded 32 byte secrets signing the JWT tokens of
+	// the tenant.
+	JWTSecrets []string `toml:",omitempty"`
+
+	// Allow and Deny list the selectors of the methods callable by the tenant,
+	// see rpc.ClientPolicy. The tenant may call all the exposed methods if both
+	// are empty.
+	Allow []string `toml:",omitempty"`
+	Deny  []string `toml:",omitempty"`
+
+	// Limits and Costs replace the rate limits of the endpoints for the tenant
+	// if set, see rpc.RateLimitConfig.
+	Limits map[string]rpc.RateLimit `toml:",omitempty"`
+	Costs  map[string]int           `toml:",omitempty"`
+}
+
+// RPCAccessConfig is the content of the access file of the HTTP and WebSocket
+// endpoints, a TOML file like:
+//
+//	Deny = ["*"]
+//
+//	[Tenants.internal]
+//	Keys = ["e9d1c9a1..."]
+//
+//	[Tenants.customer]
+//	JWTSecrets = ["0x7365..."]
+//	Allow = ["eth", "net_version"]
+//	Deny = ["eth_sendRawTransaction"]
+//	Limits = { "*" = { Rate = 10.0, Burst = 20 } }
+type RPCAccessConfig struct {
+	// Allow and Deny list the selectors of the methods callable by the anonymous
+	// clients, see rpc.ClientPolicy.
+	Allow []string `toml:",omitempty"`
+	Deny  []string `toml:",omitempty"`
+
+	// Tenants are the authenticated clients by name.
+	Tenants map[string]RPCTenant `toml:",omitempty"`
+}
+
+// rpcAccessTOML decodes the access files, rejecting unknown fields.
+var rpcAccessTOML = toml.Config{
+	NormFieldName: func(rt reflect.Type, key string) string {
+		return key
+	},
+	FieldToKey: func(rt reflect.Type, field string) string {
+		return field
+	},
+	MissingField: func(rt reflect.Type, field string) error {
+		return fmt.Errorf("field '%s' is not defined in %s", field, rt.String())
+	},
+}
+
+// tenantSecret is a JWT secret of a tenant.
+type tenantSecret struct {
+	tenant string
+	secret []byte
+}
+
+// rpcCredentials are the credentials of the tenants.
+type rpcCredentials struct {
+	keys    map[string]string // API keys to tenants
+	secrets []tenantSecret
+}
+
+// authenticate returns the tenant of a bearer token.
+func (c *rpcCredentials) authenticate(token string) (string, error) {
+	if tenant, ok := c.keys[token]; ok {
+		return tenant, nil
+	}
+	for _, s := range c.secrets {
+		_, err := parseJWT(token, s.secret)
+		if err == nil {
+			return s.tenant, nil
+		}
+		// Report the failed claim checks of the tokens signed by the secret
+		if !errors.Is(err, jwt.ErrSignatureInvalid) && !errors.Is(err, jwt.ErrTokenMalformed) {
+			return "", err
+		}
+	}
+	return "", errors.New("invalid credentials")
+}
+
+// rpcAccess authenticates the tenants of the HTTP and WebSocket endpoints, and
+// restricts the methods callable by the clients, as configured by the access
+// file. The file is reloaded when changed.
+type rpcAccess struct {
+	file   string
+	policy *rpc.AccessPolicy
+	creds  atomic.Pointer[rpcCredentials]
+
+	modTime time.Time // Modification time of the loaded file
+	size    int64     // Size of the loaded file
+	tenants int       // Number of tenants in the loaded file
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newRPCAccess loads the access file.
+func newRPCAccess(file string) (*rpcAccess, error) {
+	a := &rpcAccess{file: file, quit: make(chan struct{})}
+	if _, err := a.reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// reload loads the access file if it changed since the last load, reporting
+// whether it did.
+func (a *rpcAccess) reload() (bool, error) {
+	stat, err := os.Stat(a.file)
+	if err != nil {
+		return false, err
+	}
+	if stat.ModTime().Equal(a.modTime) && stat.Size() == a.size {
+		return false, nil
+	}
+	f, err := os.Open(a.file)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	var config RPCAccessConfig
+	if err := rpcAccessTOML.NewDecoder(bufio.NewReader(f)).Decode(&config); err != nil {
+		return false, fmt.Errorf("invalid access file %s: %w", a.file, err)
+	}
+	policy, creds, err := config.compile()
+	if err != nil {
+		return false, fmt.Errorf("invalid access file %s: %w", a.file, err)
+	}
+	if a.policy == nil {
+		if a.policy, err = rpc.NewAccessPolicy(policy); err != nil {
+			return false, fmt.Errorf("invalid access file %s: %w", a.file, err)
+		}
+	} else if err := a.policy.Update(policy); err != nil {
+		return false, fmt.Errorf("invalid access file %s: %w", a.file, err)
+	}
+	a.creds.Store(creds)
+	a.modTime, a.size, a.tenants = stat.ModTime(), stat.Size(), len(config.Tenants)
+	return true, nil
+}
+
+// compile converts the access file content into the access configuration of
+// the RPC servers and the credentials of the tenants.
+func (config *RPCAccessConfig) compile() (rpc.AccessConfig, *rpcCredentials, error) {
+	var (
+		policy = rpc.AccessConfig{
+			Default: rpc.ClientPolicy{Allow: config.Allow, Deny: config.Deny},
+			Clients: make(map[string]rpc.ClientPolicy),
+		}
+		creds = &rpcCredentials{keys: make(map[string]string)}
+	)
+	for name, tenant := range config.Tenants {
+		if name == "" {
+			return policy, nil, errors.New("tenant without name")
+		}
+		for _, key := range tenant.Keys {
+			if key == "" {
+				return policy, nil, fmt.Errorf("tenant %q: empty API key", name)
+			}
+			if other, ok := creds.keys[key]; ok {
+				return policy, nil, fmt.Errorf("tenants %q and %q: duplicate API key", other, name)
+			}
+			creds.keys[key] = name
+		}
+		for _, hex := range tenant.JWTSecrets {
+			secret := common.FromHex(strings.TrimSpace(hex))
+			if len(secret) != 32 {
+				return policy, nil, fmt.Errorf("tenant %q: invalid JWT secret length %d", name, len(secret))
+			}
+			creds.secrets = append(creds.secrets, tenantSecret{tenant: name, secret: secret})
+		}
+		client := rpc.ClientPolicy{Allow: tenant.Allow, Deny: tenant.Deny}
+		if len(tenant.Limits) > 0 || len(tenant.Costs) > 0 {
+			client.RateLimits = &rpc.RateLimitConfig{Limits: tenant.Limits, Costs: tenant.Costs}
+		}
+		policy.Clients[name] = client
+	}
+	return policy, creds, nil
+}
+
+// start launches the reload loop of the access file.
+func (a *rpcAccess) start() {
+	a.wg.Add(1)
+	go a.loop()
+}
+
+// stop terminates the reload loop.
+func (a *rpcAccess) stop() {
+	close(a.quit)
+	a.wg.Wait()
+}
+
+func (a *rpcAccess) loop() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(rpcAccessReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reloaded, err := a.reload()
+			switch {
+			case err != nil:
+				log.Warn("Failed to reload RPC access file, keeping the previous one", "file", a.file, "err", err)
+			case reloaded:
+				log.Info("Reloaded RPC access file", "file", a.file, "tenants", a.tenants)
+			}
+		case <-a.quit:
+			return
+		}
+	}
+}
+
+// handler wraps the handler of an RPC server, authenticating the tenants by
+// the bearer token of their requests. Requests without token are anonymous.
+func (a *rpcAccess) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(out http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if auth == "" {
+			next.ServeHTTP(out, r)
+			return
+		}
+		token, ok := strings.CutPrefix(auth, "Bearer ")
+		if !ok || token == "" {
+			http.Error(out, "invalid authorization header", http.StatusUnauthorized)
+			return
+		}
+		tenant, err := a.creds.Load().authenticate(token)
+		if err != nil {
+			http.Error(out, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(out, r.WithContext(rpc.NewContextWithClientID(r.Context(), tenant)))
+	})
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var testTenantSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// testAccessFile is an access file denying all methods to anonymous clients,
+// with a tenant authenticated by API key and one by JWT token.
+var testAccessFile = fmt.Sprintf(`
+Deny = ["*"]
+
+[Tenants.internal]
+Keys = ["internal-key"]
+
+[Tenants.customer]
+JWTSecrets = ["%s"]
+Allow = ["test"]
+Deny = ["test_sleep"]
+Limits = { "*" = { Rate = 0.000001, Burst = 2 } }
+`, hexutil.Encode(testTenantSecret))
+
+// writeAccessFile writes an access file, changing its modification time to be
+// reloaded even if written within the timestamp resolution of the filesystem.
+func writeAccessFile(t *testing.T, file string, content string) {
+	t.Helper()
+
+	stamp := time.Now()
+	if stat, err := os.Stat(file); err == nil && !stat.ModTime().Before(stamp) {
+		stamp = stat.ModTime().Add(time.Second)
+	}
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, stamp, stamp); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRPCAccess(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "access.toml")
+	writeAccessFile(t, file, testAccessFile)
+
+	access, err := newRPCAccess(file)
+	if err != nil {
+		t.Fatalf("failed to load access file: %v", err)
+	}
+	var (
+		cfg = rpcEndpointConfig{access: access}
+		srv = createAndStartServer(t, &httpConfig{rpcEndpointConfig: cfg}, false, nil, nil)
+		url = fmt.Sprintf("http://%v", srv.listenAddr())
+	)
+	defer srv.stop()
+
+	call := func(method string, auth string) (int, string) {
+		var headers []string
+		if auth != "" {
+			headers = []string{"Authorization", auth}
+		}
+		resp := rpcRequest(t, url, method, headers...)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(body)
+	}
+	token := func(secret []byte) string {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaim{"iat": time.Now().Unix()}).SignedString(secret)
+		return "Bearer " + token
+	}
+	const (
+		allowed = `"result"`
+		denied  = "does not exist/is not available"
+		limited = "rate limit exceeded"
+	)
+	check := func(method string, auth string, wantStatus int, want string) {
+		t.Helper()
+		status, body := call(method, auth)
+		if status != wantStatus || !strings.Contains(body, want) {
+			t.Fatalf("%s with %q: have status %d, body %s, want status %d with %q", method, auth, status, body, wantStatus, want)
+		}
+	}
+	check("test_greet", "", http.StatusOK, denied)
+	check("test_greet", "Bearer internal-key", http.StatusOK, allowed)
+	check(testMethod, "Bearer internal-key", http.StatusOK, allowed)
+	check("test_greet", "Bearer wrong-key", http.StatusUnauthorized, "invalid credentials")
+	check("test_greet", "Basic internal-key", http.StatusUnauthorized, "invalid authorization header")
+	check("test_greet", token([]byte("wrong-secret-wrong-secret-wrong!")), http.StatusUnauthorized, "invalid credentials")
+	check("test_greet", token(testTenantSecret), http.StatusOK, allowed)
+	check(testMethod, token(testTenantSecret), http.StatusOK, denied)
+	check("test_sleep", token(testTenantSecret), http.StatusOK, denied)
+	check("test_greet", token(testTenantSecret), http.StatusOK, allowed)
+	check("test_greet", token(testTenantSecret), http.StatusOK, limited)
+
+	// Broken files are rejected, keeping the loaded configuration
+	writeAccessFile(t, file, "Tenants = 1")
+	if _, err := access.reload(); err == nil {
+		t.Fatal("broken access file loaded")
+	}
+	check("test_greet", "Bearer internal-key", http.StatusOK, allowed)
+
+	// Changes apply to the next calls, keeping the unchanged limits
+	writeAccessFile(t, file, strings.Replace(testAccessFile, "internal-key", "rotated-key", 1))
+	if reloaded, err := access.reload(); !reloaded || err != nil {
+		t.Fatalf("access file not reloaded: %v", err)
+	}
+	check("test_greet", "Bearer internal-key", http.StatusUnauthorized, "invalid credentials")
+	check("test_greet", "Bearer rotated-key", http.StatusOK, allowed)
+	check("test_greet", token(testTenantSecret), http.StatusOK, limited)
+	if reloaded, err := access.reload(); reloaded || err != nil {
+		t.Fatalf("unchanged access file reloaded: %v", err)
+	}
+}
+
+func TestRPCAccessFileValidation(t *testing.T) {
+	dir := t.TempDir()
+	for i, content := range []string{
+		`Unknown = 1`,
+		`[Tenants.a]
+		Keys = [""]`,
+		`[Tenants.a]
+		Keys = ["key"]
+		[Tenants.b]
+		Keys = ["key"]`,
+		`[Tenants.a]
+		JWTSecrets = ["0x1234"]`,
+		`[Tenants.a]
+		Deny = [""]`,
+		`[Tenants.a]
+		Limits = { "*" = { Rate = 1.0, Burst = 0 } }`,
+	} {
+		file := filepath.Join(dir, fmt.Sprintf("access-%d.toml", i))
+		writeAccessFile(t, file, content)
+		if _, err := newRPCAccess(file); err == nil {
+			t.Errorf("invalid access file %d accepted", i)
+		}
+	}
+}
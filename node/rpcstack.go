@@ -58,6 +58,9 @@ type rpcEndpointConfig struct {
 	batchItemLimit         int
 	batchResponseSizeLimit int
 	httpBodyLimit          int
+	rateLimiter            *rpc.RateLimiter // optional, shared by the endpoints
+	access                 *rpcAccess       // optional, shared by the endpoints
+	accessLog              *rpc.AccessLog   // optional, shared by the endpoints
 }
 
 type rpcHandler struct {
@@ -199,10 +202,8 @@ func (h *httpServer) start() error {
 func (h *httpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// check if ws request and serve if ws enabled
 	ws := h.wsHandler.Load().(*rpcHandler)
-	if ws != nil && isWebsocket(r) {
-		if checkPath(r, h.wsConfig.prefix) {
-			ws.ServeHTTP(w, r)
-		}
+	if ws != nil && isWebsocket(r) && checkPath(r, h.wsConfig.prefix) {
+		ws.ServeHTTP(w, r)
 		return
 	}
 
@@ -309,12 +310,19 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
+	srv.SetRateLimiter(config.rateLimiter)
+	srv.SetAccessLog(config.accessLog)
+	handler := http.Handler(srv)
+	if config.access != nil {
+		srv.SetAccessPolicy(config.access.policy)
+		handler = config.access.handler(srv)
+	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
 	h.httpConfig = config
 	h.httpHandler.Store(&rpcHandler{
-		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
+		Handler: NewHTTPHandlerStack(handler, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
 		server:  srv,
 	})
 	return nil
@@ -344,12 +352,19 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
+	srv.SetRateLimiter(config.rateLimiter)
+	srv.SetAccessLog(config.accessLog)
+	handler := srv.WebsocketHandler(config.Origins)
+	if config.access != nil {
+		srv.SetAccessPolicy(config.access.policy)
+		handler = config.access.handler(handler)
+	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
 	h.wsConfig = config
 	h.wsHandler.Store(&rpcHandler{
-		Handler: NewWSHandlerStack(srv.WebsocketHandler(config.Origins), config.jwtSecret),
+		Handler: NewWSHandlerStack(handler, config.jwtSecret),
 		server:  srv,
 	})
 	return nil
@@ -572,7 +587,8 @@ func (w *gzipResponseWriter) close() {
 
 func newGzipHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		// Websocket upgrades need to hijack the connection, and are not compressed.
+		if isWebsocket(r) || !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -449,6 +449,46 @@ func TestJWT(t *testing.T) {
 	srv.stop()
 }
 
+// Tests that clients authenticated by JWT tokens carrying an "id" claim are rate
+// limited by identity rather than by address.
+func TestJWTClientRateLimit(t *testing.T) {
+	limiter, err := rpc.NewRateLimiter(rpc.RateLimitConfig{
+		Limits: map[string]rpc.RateLimit{"*": {Rate: 1e-6, Burst: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		secret = []byte("secret")
+		cfg    = rpcEndpointConfig{jwtSecret: secret, rateLimiter: limiter}
+		srv    = createAndStartServer(t, &httpConfig{rpcEndpointConfig: cfg}, false, nil, nil)
+		url    = fmt.Sprintf("http://%v", srv.listenAddr())
+	)
+	defer srv.stop()
+
+	call := func(id string) string {
+		claims := testClaim{"iat": time.Now().Unix()}
+		if id != "" {
+			claims["id"] = id
+		}
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+		resp := rpcRequest(t, url, testMethod, "Authorization", "Bearer "+token)
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+	for _, id := range []string{"alice", "bob", ""} {
+		if body := call(id); !strings.Contains(body, `"result"`) {
+			t.Fatalf("client %q: first call rejected: %s", id, body)
+		}
+		if body := call(id); !strings.Contains(body, "rate limit exceeded") {
+			t.Fatalf("client %q: second call not rejected: %s", id, body)
+		}
+	}
+}
+
 func TestGzipHandler(t *testing.T) {
 	type gzipTest struct {
 		name    string
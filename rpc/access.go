@@ -0,0 +1,169 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ClientPolicy restricts the methods callable by a client. Methods are matched
+// by selectors as in RateLimitConfig: a method name ("eth_getLogs"), a namespace
+// ("debug") or "*" for all methods.
+type ClientPolicy struct {
+	// Allow lists the selectors of the callable methods. All the methods exposed
+	// by the server are callable if empty.
+	Allow []string `toml:",omitempty"`
+
+	// Deny lists the selectors of the methods not callable, even if allowed.
+	Deny []string `toml:",omitempty"`
+
+	// RateLimits replace the rate limits of the server for the client, if set.
+	RateLimits *RateLimitConfig `toml:",omitempty"`
+}
+
+// permits reports whether the policy allows calling the method.
+func (p *ClientPolicy) permits(method string) bool {
+	selectors := rateSelectors(method)
+	for _, selector := range p.Deny {
+		if slices.Contains(selectors, selector) {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, selector := range p.Allow {
+		if slices.Contains(selectors, selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessConfig configures the policies applied to the method calls of the
+// clients of a server.
+type AccessConfig struct {
+	// Default is the policy of the anonymous clients, and of the clients without
+	// a policy of their own.
+	Default ClientPolicy
+
+	// Clients maps the identities of the authenticated clients to their policies,
+	// replacing the default one. See PeerInfo.ClientID.
+	Clients map[string]ClientPolicy `toml:",omitempty"`
+}
+
+// accessState is a validated access configuration with the rate limiters of the
+// clients having their own limits.
+type accessState struct {
+	config   AccessConfig
+	limiters map[string]*RateLimiter
+}
+
+// AccessPolicy decides which methods the clients of a server may call, and with
+// which rate limits. The configuration can be updated while serving requests.
+//
+// Calls over IPC and in-process connections are not restricted.
+type AccessPolicy struct {
+	lock  sync.Mutex // Serializes the updates
+	state atomic.Pointer[accessState]
+}
+
+// NewAccessPolicy creates an access policy with the given configuration.
+func NewAccessPolicy(config AccessConfig) (*AccessPolicy, error) {
+	p := new(AccessPolicy)
+	if err := p.Update(config); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Update replaces the configuration of the policy, it applies to the calls
+// handled from then on, including those of the connected clients. The rate
+// limits of the clients whose limits didn't change are kept.
+func (p *AccessPolicy) Update(config AccessConfig) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if err := validateClientPolicy(&config.Default); err != nil {
+		return fmt.Errorf("default policy: %w", err)
+	}
+	if config.Default.RateLimits != nil {
+		return errors.New("default policy: rate limits are set on the server")
+	}
+	var (
+		old   = p.state.Load()
+		state = &accessState{config: config, limiters: make(map[string]*RateLimiter)}
+	)
+	for id, policy := range config.Clients {
+		if err := validateClientPolicy(&policy); err != nil {
+			return fmt.Errorf("policy of client %q: %w", id, err)
+		}
+		if policy.RateLimits == nil {
+			continue
+		}
+		if old != nil && old.limiters[id] != nil && reflect.DeepEqual(old.config.Clients[id].RateLimits, policy.RateLimits) {
+			state.limiters[id] = old.limiters[id]
+			continue
+		}
+		limiter, err := NewRateLimiter(*policy.RateLimits)
+		if err != nil {
+			return fmt.Errorf("policy of client %q: %w", id, err)
+		}
+		state.limiters[id] = limiter
+	}
+	p.state.Store(state)
+	return nil
+}
+
+// validateClientPolicy checks that the selectors of a policy are not empty.
+func validateClientPolicy(policy *ClientPolicy) error {
+	for _, selector := range slices.Concat(policy.Allow, policy.Deny) {
+		if selector == "" {
+			return errors.New("empty method selector")
+		}
+	}
+	return nil
+}
+
+// check returns an error if the client issuing the request may not call the
+// method. Otherwise it returns the rate limiter of the client, or nil if the
+// limiter of the server applies.
+func (p *AccessPolicy) check(info PeerInfo, method string) (*RateLimiter, error) {
+	if info.Transport == "" || info.Transport == "ipc" {
+		return nil, nil
+	}
+	var (
+		state   = p.state.Load()
+		policy  = state.config.Default
+		limiter *RateLimiter
+	)
+	// Only the identities established by the authenticating handlers select
+	// a policy, the client header is set by the clients themselves.
+	if client, ok := state.config.Clients[info.ClientID]; ok && info.Authenticated {
+		policy, limiter = client, state.limiters[info.ClientID]
+	}
+	if !policy.permits(method) {
+		accessDenyMeter.Mark(1)
+		return nil, &methodNotFoundError{method: method}
+	}
+	return limiter, nil
+}
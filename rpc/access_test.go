@@ -0,0 +1,191 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientPolicyPermits(t *testing.T) {
+	policy := ClientPolicy{
+		Allow: []string{"eth", "debug_traceTransaction"},
+		Deny:  []string{"eth_sendRawTransaction"},
+	}
+	for method, want := range map[string]bool{
+		"eth_call":               true,
+		"debug_traceTransaction": true,
+		"eth_sendRawTransaction": false,
+		"debug_traceCall":        false,
+		"admin_peers":            false,
+	} {
+		if have := policy.permits(method); have != want {
+			t.Errorf("%s: permitted %v, want %v", method, have, want)
+		}
+	}
+	deny := ClientPolicy{Deny: []string{"*"}}
+	if deny.permits("eth_call") {
+		t.Error("method permitted by policy denying all")
+	}
+	if all := (ClientPolicy{}); !all.permits("admin_peers") {
+		t.Error("method denied by empty policy")
+	}
+}
+
+// checkMethodDenied checks whether the error is an access rejection.
+func checkMethodDenied(t *testing.T, call string, err error, want bool) {
+	t.Helper()
+
+	var rpcErr Error
+	switch {
+	case want && (!errors.As(err, &rpcErr) || rpcErr.ErrorCode() != -32601):
+		t.Fatalf("%s: expected method not found error, got %v", call, err)
+	case !want && err != nil:
+		t.Fatalf("%s: unexpected error: %v", call, err)
+	}
+}
+
+// Tests that the authenticated clients are subject to their own policies and
+// rate limits, while the others get the default policy.
+func TestAccessPolicy(t *testing.T) {
+	policy, err := NewAccessPolicy(AccessConfig{
+		Default: ClientPolicy{Allow: []string{"test_echo"}},
+		Clients: map[string]ClientPolicy{
+			"internal": {},
+			"customer": {
+				Deny: []string{"test_echo"},
+				RateLimits: &RateLimitConfig{
+					Limits: map[string]RateLimit{"*": {Rate: 1e-6, Burst: 1}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create access policy: %v", err)
+	}
+	limiter, err := NewRateLimiter(RateLimitConfig{
+		Limits:       map[string]RateLimit{"test_echo": {Rate: 1e-6, Burst: 2}},
+		ClientHeader: "X-Client",
+		Clients:      []string{"internal"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create rate limiter: %v", err)
+	}
+	server := newTestServer()
+	server.SetRateLimiter(limiter)
+	server.SetAccessPolicy(policy)
+	defer server.Stop()
+
+	// Authenticating handler identifying the clients by path
+	httpsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := strings.TrimPrefix(r.URL.Path, "/"); id != "" {
+			r = r.WithContext(NewContextWithClientID(r.Context(), id))
+		}
+		server.ServeHTTP(w, r)
+	}))
+	defer httpsrv.Close()
+
+	dial := func(url string, opts ...ClientOption) *Client {
+		client, err := DialOptions(context.Background(), url, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(client.Close)
+		return client
+	}
+	var (
+		anonymous = dial(httpsrv.URL)
+		spoofed   = dial(httpsrv.URL, WithHeader("X-Client", "internal"))
+		internal  = dial(httpsrv.URL + "/internal")
+		customer  = dial(httpsrv.URL + "/customer")
+		unknown   = dial(httpsrv.URL + "/unknown")
+	)
+	for i, tt := range []struct {
+		client  *Client
+		method  string
+		denied  bool
+		limited bool
+	}{
+		{anonymous, "test_echo", false, false},
+		{anonymous, "test_null", true, false},
+		{spoofed, "test_null", true, false}, // header identities don't select policies
+		{unknown, "test_null", true, false},
+		{internal, "test_null", false, false},
+		{internal, "test_echo", false, false},
+		{internal, "test_echo", false, false},
+		{internal, "test_echo", false, true}, // server limits apply
+		{customer, "test_echo", true, false},
+		{customer, "test_null", false, false},
+		{customer, "test_null", false, true}, // own limits apply
+	} {
+		var (
+			call   = fmt.Sprintf("call %d (%s)", i, tt.method)
+			result any
+			args   []any
+		)
+		if tt.method == "test_echo" {
+			args = []any{"x", 1, nil}
+		}
+		err := tt.client.Call(&result, tt.method, args...)
+		if tt.denied {
+			checkMethodDenied(t, call, err, true)
+		} else {
+			checkRateLimited(t, call, err, tt.limited)
+		}
+	}
+	// Updates apply to the subsequent calls, keeping the unchanged limits
+	err = policy.Update(AccessConfig{
+		Default: ClientPolicy{Deny: []string{"*"}},
+		Clients: map[string]ClientPolicy{
+			"customer": {
+				RateLimits: &RateLimitConfig{
+					Limits: map[string]RateLimit{"*": {Rate: 1e-6, Burst: 1}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to update access policy: %v", err)
+	}
+	var result any
+	checkMethodDenied(t, "anonymous after update", anonymous.Call(&result, "test_echo", "x", 1, nil), true)
+	checkMethodDenied(t, "internal after update", internal.Call(&result, "test_null"), true)
+	checkRateLimited(t, "customer after update", customer.Call(&result, "test_echo", "x", 1, nil), true)
+
+	// Local clients are not restricted
+	local := DialInProc(server)
+	defer local.Close()
+	checkMethodDenied(t, "local", local.Call(&result, "test_null"), false)
+}
+
+func TestAccessConfigValidation(t *testing.T) {
+	for _, config := range []AccessConfig{
+		{Default: ClientPolicy{Allow: []string{""}}},
+		{Default: ClientPolicy{RateLimits: &RateLimitConfig{}}},
+		{Clients: map[string]ClientPolicy{"a": {Deny: []string{""}}}},
+		{Clients: map[string]ClientPolicy{"a": {RateLimits: &RateLimitConfig{Costs: map[string]int{"eth": -1}}}}},
+	} {
+		if _, err := NewAccessPolicy(config); err == nil {
+			t.Errorf("invalid config accepted: %+v", config)
+		}
+	}
+}
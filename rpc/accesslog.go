@@ -0,0 +1,91 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// accessLogEntry is a line of the access log.
+type accessLogEntry struct {
+	Time         time.Time `json:"time"`
+	Method       string    `json:"method"`
+	ParamsSize   int       `json:"paramsSize"`
+	Transport    string    `json:"transport"`
+	RemoteAddr   string    `json:"remoteAddr,omitempty"`
+	ClientID     string    `json:"clientId,omitempty"`
+	UserAgent    string    `json:"userAgent,omitempty"`
+	Origin       string    `json:"origin,omitempty"`
+	Duration     float64   `json:"duration"`     // Serving time in seconds
+	ResponseSize int       `json:"responseSize"` // Size of the encoded result
+	ErrorCode    int       `json:"errorCode,omitempty"`
+}
+
+// AccessLog writes a JSON line for each method call served, recording the
+// method, the client and the cost of the call, to attribute the load of the
+// server to its clients.
+type AccessLog struct {
+	lock   sync.Mutex
+	w      io.Writer
+	failed bool // Whether a write failed, to only report the first failure
+}
+
+// NewAccessLog creates an access log writing to w.
+func NewAccessLog(w io.Writer) *AccessLog {
+	return &AccessLog{w: w}
+}
+
+// record writes the entry of a served call.
+func (l *AccessLog) record(ctx context.Context, msg *jsonrpcMessage, resp *jsonrpcMessage, elapsed time.Duration) {
+	info := PeerInfoFromContext(ctx)
+	entry := accessLogEntry{
+		Time:       time.Now(),
+		Method:     msg.Method,
+		ParamsSize: len(msg.Params),
+		Transport:  info.Transport,
+		RemoteAddr: info.RemoteAddr,
+		ClientID:   info.ClientID,
+		UserAgent:  info.HTTP.UserAgent,
+		Origin:     info.HTTP.Origin,
+		Duration:   elapsed.Seconds(),
+	}
+	if resp != nil {
+		entry.ResponseSize = len(resp.Result)
+		if resp.Error != nil {
+			entry.ErrorCode = resp.Error.Code
+		}
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if _, err := l.w.Write(line); err != nil && !l.failed {
+		log.Warn("Failed to write RPC access log", "err", err)
+		l.failed = true
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+func TestAccessLog(t *testing.T) {
+	// The rate limiter identifies the clients by header
+	limiter, err := NewRateLimiter(RateLimitConfig{ClientHeader: "X-Client", Clients: []string{"alice"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		out    bytes.Buffer
+		server = newTestServer()
+	)
+	server.SetRateLimiter(limiter)
+	server.SetAccessLog(NewAccessLog(&out))
+	defer server.Stop()
+
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	client, err := DialOptions(context.Background(), httpsrv.URL, WithHeader("X-Client", "alice"), WithHeader("User-Agent", "test-agent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var result echoResult
+	if err := client.Call(&result, "test_echo", "hello", 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(nil, "test_returnError"); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := client.Call(nil, "test_null"); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []accessLogEntry
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var entry accessLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid access log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("wrong number of entries: have %d, want 3", len(entries))
+	}
+	result0, _ := json.Marshal(result)
+	for i, want := range []struct {
+		method       string
+		paramsSize   int
+		responseSize int
+		errorCode    int
+	}{
+		{"test_echo", len(`["hello",1,null]`), len(result0), 0},
+		{"test_returnError", 0, 0, 444},
+		{"test_null", 0, len("null"), 0},
+	} {
+		entry := entries[i]
+		if entry.Method != want.method || entry.ParamsSize != want.paramsSize || entry.ResponseSize != want.responseSize || entry.ErrorCode != want.errorCode {
+			t.Errorf("entry %d mismatch: have %+v, want %+v", i, entry, want)
+		}
+		if entry.Transport != "http" || entry.ClientID != "alice" || entry.UserAgent != "test-agent" || entry.RemoteAddr == "" {
+			t.Errorf("entry %d: wrong peer info: %+v", i, entry)
+		}
+		if entry.Time.IsZero() || entry.Duration <= 0 {
+			t.Errorf("entry %d: missing timing: %+v", i, entry)
+		}
+	}
+	if _, ok := metrics.DefaultRegistry.Get(latencyHistName + "/test_echo").(*metrics.BucketHistogram); !ok {
+		t.Error("latency histogram of test_echo not registered")
+	}
+}
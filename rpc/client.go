@@ -89,6 +89,9 @@ type Client struct {
 	// config fields
 	batchItemLimit       int
 	batchResponseMaxSize int
+	rateLimiter          *RateLimiter
+	accessPolicy         *AccessPolicy
+	accessLog            *AccessLog
 
 	// writeConn is used for writing to the connection on the caller's goroutine. It should
 	// only be accessed outside of dispatch, with the write lock held. The write lock is
@@ -121,6 +124,9 @@ func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx = context.WithValue(ctx, clientContextKey{}, c)
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, conn.peerInfo())
 	handler := newHandler(ctx, conn, c.idgen, c.services, c.batchItemLimit, c.batchResponseMaxSize)
+	handler.rateLimiter = c.rateLimiter
+	handler.accessPolicy = c.accessPolicy
+	handler.accessLog = c.accessLog
 	return &clientConn{conn, handler}
 }
 
@@ -248,6 +254,9 @@ func initClient(conn ServerCodec, services *serviceRegistry, cfg *clientConfig)
 		idgen:                cfg.idgen,
 		batchItemLimit:       cfg.batchItemLimit,
 		batchResponseMaxSize: cfg.batchResponseLimit,
+		rateLimiter:          cfg.rateLimiter,
+		accessPolicy:         cfg.accessPolicy,
+		accessLog:            cfg.accessLog,
 		writeConn:            conn,
 		close:                make(chan struct{}),
 		closing:              make(chan struct{}),
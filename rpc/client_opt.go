@@ -41,6 +41,9 @@ type clientConfig struct {
 	idgen              func() ID
 	batchItemLimit     int
 	batchResponseLimit int
+	rateLimiter        *RateLimiter
+	accessPolicy       *AccessPolicy
+	accessLog          *AccessLog
 }
 
 func (cfg *clientConfig) initHeaders() {
@@ -476,6 +476,40 @@ func TestClientSubscribe(t *testing.T) {
 	}
 }
 
+// This test checks that a subscription terminated by the server delivers the
+// error to the client.
+func TestClientSubscribeServerError(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	nc := make(chan int)
+	count := 10
+	sub, err := client.Subscribe(context.Background(), "nftest", nc, "failingSubscription", count, 0)
+	if err != nil {
+		t.Fatal("can't subscribe:", err)
+	}
+	for i := 0; i < count; i++ {
+		if val := <-nc; val != i {
+			t.Fatalf("value mismatch: got %d, want %d", val, i)
+		}
+	}
+	select {
+	case v := <-nc:
+		t.Fatal("received value after termination:", v)
+	case err := <-sub.Err():
+		var rpcErr Error
+		if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != (testError{}).ErrorCode() || err.Error() != (testError{}).Error() {
+			t.Fatalf("wrong error: %v", err)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatalf("subscription not closed within 1s after termination")
+	}
+}
+
 // In this test, the connection drops while Subscribe is waiting for a response.
 func TestClientSubscribeClose(t *testing.T) {
 	t.Parallel()
@@ -65,6 +65,9 @@ type handler struct {
 	allowSubscribe       bool
 	batchRequestLimit    int
 	batchResponseMaxSize int
+	rateLimiter          *RateLimiter  // limits the calls of remote clients, if set
+	accessPolicy         *AccessPolicy // restricts the calls of remote clients, if set
+	accessLog            *AccessLog    // records the served calls, if set
 
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
@@ -374,12 +377,23 @@ func (h *handler) cancelServerSubscriptions(err error) {
 	defer h.subLock.Unlock()
 
 	for id, s := range h.serverSubs {
-		s.err <- err
-		close(s.err)
+		s.close(err)
 		delete(h.serverSubs, id)
 	}
 }
 
+// removeServerSubscription removes a subscription terminated by the server and
+// closes its error channel.
+func (h *handler) removeServerSubscription(s *Subscription, err error) {
+	h.subLock.Lock()
+	defer h.subLock.Unlock()
+
+	if h.serverSubs[s.ID] == s {
+		delete(h.serverSubs, s.ID)
+	}
+	s.close(err)
+}
+
 // startCallProc runs fn in a new goroutine and starts tracking it in the h.calls wait group.
 func (h *handler) startCallProc(fn func(*callProc)) {
 	h.callWG.Add(1)
@@ -455,9 +469,17 @@ func (h *handler) handleSubscriptionResult(msg *jsonrpcMessage) {
 		h.log.Debug("Dropping invalid subscription message")
 		return
 	}
-	if h.clientSubs[result.ID] != nil {
-		h.clientSubs[result.ID].deliver(result.Result)
+	sub := h.clientSubs[result.ID]
+	if sub == nil {
+		return
+	}
+	// The subscription was terminated by the server.
+	if result.Error != nil {
+		delete(h.clientSubs, result.ID)
+		sub.terminate(result.Error)
+		return
 	}
+	sub.deliver(result.Result)
 }
 
 // handleCallMsg executes a call message and returns the answer.
@@ -465,12 +487,18 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 	start := time.Now()
 	switch {
 	case msg.isNotification():
-		h.handleCall(ctx, msg)
+		resp := h.handleCall(ctx, msg)
 		h.log.Debug("Served "+msg.Method, "duration", time.Since(start))
+		if h.accessLog != nil {
+			h.accessLog.record(ctx.ctx, msg, resp, time.Since(start))
+		}
 		return nil
 
 	case msg.isCall():
 		resp := h.handleCall(ctx, msg)
+		if h.accessLog != nil {
+			h.accessLog.record(ctx.ctx, msg, resp, time.Since(start))
+		}
 		var logctx []any
 		logctx = append(logctx, "reqid", idForLog{msg.ID}, "duration", time.Since(start))
 		if resp.Error != nil {
@@ -506,6 +534,11 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
 	}
+	if callb != h.unsubscribeCb {
+		if err := h.checkAccess(cp.ctx, msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 
 	args, err := parsePositionalArguments(msg.Params, callb.argTypes)
 	if err != nil {
@@ -525,6 +558,7 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		}
 		rpcServingTimer.UpdateSince(start)
 		updateServeTimeHistogram(msg.Method, answer.Error == nil, time.Since(start))
+		updateLatencyHistogram(msg.Method, time.Since(start))
 	}
 
 	return answer
@@ -546,6 +580,9 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 	if callb == nil {
 		return msg.errorResponse(&subscriptionNotFoundError{namespace, name})
 	}
+	if err := h.checkAccess(cp.ctx, msg.Method); err != nil {
+		return msg.errorResponse(err)
+	}
 
 	// Parse subscription name arg too, but remove it before calling the callback.
 	argTypes := append([]reflect.Type{stringType}, callb.argTypes...)
@@ -563,6 +600,25 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 	return h.runMethod(ctx, msg, callb, args)
 }
 
+// checkAccess returns an error if the client issuing the request may not call
+// the method, or exceeded its rate limits.
+func (h *handler) checkAccess(ctx context.Context, method string) error {
+	limiter := h.rateLimiter
+	if h.accessPolicy != nil {
+		clientLimiter, err := h.accessPolicy.check(PeerInfoFromContext(ctx), method)
+		if err != nil {
+			return err
+		}
+		if clientLimiter != nil {
+			limiter = clientLimiter
+		}
+	}
+	if limiter != nil {
+		return limiter.allow(ctx, method)
+	}
+	return nil
+}
+
 // runMethod runs the Go callback for an RPC method.
 func (h *handler) runMethod(ctx context.Context, msg *jsonrpcMessage, callb *callback, args []reflect.Value) *jsonrpcMessage {
 	result, err := callb.call(ctx, msg.Method, args)
@@ -581,7 +637,7 @@ func (h *handler) unsubscribe(ctx context.Context, id ID) (bool, error) {
 	if s == nil {
 		return false, ErrSubscriptionNotFound
 	}
-	close(s.err)
+	s.close(nil)
 	delete(h.serverSubs, id)
 	return true, nil
 }
@@ -32,8 +32,41 @@ var (
 	serveTimeHistName = "rpc/duration"
 
 	rpcServingTimer = metrics.NewRegisteredTimer("rpc/duration/all", nil)
+
+	// rateLimitRejectName is the prefix of the per-method rate limit rejection meters.
+	rateLimitRejectName = "rpc/ratelimit/rejected"
+
+	rateLimitRejectMeter = metrics.NewRegisteredMeter(rateLimitRejectName+"/all", nil)
+
+	accessDenyMeter = metrics.NewRegisteredMeter("rpc/access/denied", nil)
+
+	// latencyHistName is the prefix of the per-method latency histograms.
+	latencyHistName = "rpc/latency"
+
+	// latencyBuckets are the upper bounds of the buckets of the latency histograms,
+	// from 100us to 30s.
+	latencyBuckets = []int64{
+		int64(100 * time.Microsecond), int64(250 * time.Microsecond), int64(500 * time.Microsecond),
+		int64(time.Millisecond), int64(2500 * time.Microsecond), int64(5 * time.Millisecond),
+		int64(10 * time.Millisecond), int64(25 * time.Millisecond), int64(50 * time.Millisecond),
+		int64(100 * time.Millisecond), int64(250 * time.Millisecond), int64(500 * time.Millisecond),
+		int64(time.Second), int64(2500 * time.Millisecond), int64(5 * time.Second),
+		int64(10 * time.Second), int64(30 * time.Second),
+	}
 )
 
+// rateLimitRejectMeterForMethod returns the meter tracking the rate limited
+// calls of a method.
+func rateLimitRejectMeterForMethod(method string) *metrics.Meter {
+	return metrics.GetOrRegisterMeter(rateLimitRejectName+"/"+method, nil)
+}
+
+// updateLatencyHistogram tracks the latency of a remote RPC call in the bucketed
+// histogram of the method, exposed as a Prometheus histogram.
+func updateLatencyHistogram(method string, elapsed time.Duration) {
+	metrics.GetOrRegisterBucketHistogram(latencyHistName+"/"+method, nil, latencyBuckets).Update(elapsed.Nanoseconds())
+}
+
 // updateServeTimeHistogram tracks the serving time of a remote RPC call.
 func updateServeTimeHistogram(method string, success bool, elapsed time.Duration) {
 	note := "success"
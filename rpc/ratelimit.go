@@ -0,0 +1,224 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimitSweepInterval is the interval at which the buckets of idle
+	// clients are dropped.
+	rateLimitSweepInterval = time.Minute
+
+	// maxRateBuckets is the maximum number of tracked token buckets. Beyond it,
+	// the least recently used buckets are dropped.
+	maxRateBuckets = 65536
+)
+
+// RateLimit is a token bucket. Calls take tokens from the bucket, which is
+// refilled at Rate tokens per second up to Burst tokens.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitConfig configures the limits applied to the method calls of each
+// client.
+type RateLimitConfig struct {
+	// Limits maps method selectors to the token buckets applied to each client.
+	// A selector is either a method name ("eth_getLogs"), a namespace ("debug")
+	// or "*" for all methods. Calls take tokens from the buckets of all the
+	// matching selectors.
+	Limits map[string]RateLimit
+
+	// Costs maps method selectors to the number of tokens taken by a call. The
+	// most specific matching selector applies, calls take one token otherwise.
+	Costs map[string]int `toml:",omitempty"`
+
+	// ClientHeader is the HTTP header identifying the clients, e.g. an API key
+	// header set by an authenticating proxy. Clients authenticated by the node
+	// are identified by their JWT token or access file tenant instead.
+	ClientHeader string `toml:",omitempty"`
+
+	// Clients lists the values of the client header accepted as identities.
+	// The header is set by the clients themselves, so requests with any other
+	// value are limited by their IP address, like the ones without the header.
+	Clients []string `toml:",omitempty"`
+}
+
+// rateBucketKey identifies the token bucket of a client for a selector.
+type rateBucketKey struct {
+	selector string
+	client   string
+}
+
+// RateLimiter limits the rate of method calls of each client. A limiter can be
+// shared by multiple servers, limiting the calls across all of them.
+type RateLimiter struct {
+	config  RateLimitConfig
+	clients map[string]struct{} // Accepted client header values
+
+	lock      sync.Mutex
+	buckets   lru.BasicLRU[rateBucketKey, *rate.Limiter]
+	lastSweep time.Time
+}
+
+// NewRateLimiter creates a rate limiter with the given configuration.
+func NewRateLimiter(config RateLimitConfig) (*RateLimiter, error) {
+	for selector, limit := range config.Limits {
+		if limit.Rate < 0 || limit.Burst <= 0 {
+			return nil, fmt.Errorf("invalid rate limit for %s: rate %v, burst %d", selector, limit.Rate, limit.Burst)
+		}
+	}
+	for selector, cost := range config.Costs {
+		if cost <= 0 {
+			return nil, fmt.Errorf("invalid cost for %s: %d", selector, cost)
+		}
+	}
+	if config.ClientHeader != "" && len(config.Clients) == 0 {
+		return nil, fmt.Errorf("client header %s without accepted clients", config.ClientHeader)
+	}
+	clients := make(map[string]struct{}, len(config.Clients))
+	for _, client := range config.Clients {
+		clients[client] = struct{}{}
+	}
+	return &RateLimiter{
+		config:    config,
+		clients:   clients,
+		buckets:   lru.NewBasicLRU[rateBucketKey, *rate.Limiter](maxRateBuckets),
+		lastSweep: time.Now(),
+	}, nil
+}
+
+// rateSelectors returns the selectors matching a method, most specific first.
+func rateSelectors(method string) []string {
+	if namespace, _, ok := strings.Cut(method, serviceMethodSeparator); ok {
+		return []string{method, namespace, "*"}
+	}
+	return []string{method, "*"}
+}
+
+// cost returns the number of tokens taken by a call to the method.
+func (l *RateLimiter) cost(method string) int {
+	for _, selector := range rateSelectors(method) {
+		if cost, ok := l.config.Costs[selector]; ok {
+			return cost
+		}
+	}
+	return 1
+}
+
+// client returns the identity of the client the buckets are tracked for, or
+// an empty string if the client is local and not limited.
+func (l *RateLimiter) client(info PeerInfo) string {
+	if info.Transport == "" || info.Transport == "ipc" {
+		return ""
+	}
+	if info.ClientID != "" {
+		if _, ok := l.clients[info.ClientID]; ok || info.Authenticated {
+			return "id:" + info.ClientID
+		}
+	}
+	host, _, err := net.SplitHostPort(info.RemoteAddr)
+	if err != nil {
+		host = info.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// allow takes the tokens for a method call of the client issuing the request,
+// returning an error if any of the matching buckets doesn't have enough.
+func (l *RateLimiter) allow(ctx context.Context, method string) error {
+	client := l.client(PeerInfoFromContext(ctx))
+	if client == "" {
+		return nil
+	}
+	var (
+		now      = time.Now()
+		cost     = l.cost(method)
+		reserved []*rate.Reservation
+	)
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if now.Sub(l.lastSweep) > rateLimitSweepInterval {
+		l.sweep(now)
+	}
+	for _, selector := range rateSelectors(method) {
+		limit, ok := l.config.Limits[selector]
+		if !ok {
+			continue
+		}
+		key := rateBucketKey{selector, client}
+		bucket, ok := l.buckets.Get(key)
+		if !ok {
+			bucket = rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)
+			l.buckets.Add(key, bucket)
+		}
+		r := bucket.ReserveN(now, cost)
+		if !r.OK() || r.DelayFrom(now) > 0 {
+			// Return the tokens taken from the other buckets, the call is
+			// rejected as a whole
+			if r.OK() {
+				r.CancelAt(now)
+			}
+			for _, r := range reserved {
+				r.CancelAt(now)
+			}
+			rateLimitRejectMeter.Mark(1)
+			rateLimitRejectMeterForMethod(method).Mark(1)
+			return &rateLimitError{method: method}
+		}
+		reserved = append(reserved, r)
+	}
+	return nil
+}
+
+// sweep drops the buckets refilled to their burst, they are equivalent to new
+// ones. The caller must hold the lock.
+func (l *RateLimiter) sweep(now time.Time) {
+	for _, key := range l.buckets.Keys() {
+		if bucket, _ := l.buckets.Peek(key); bucket.TokensAt(now) >= float64(bucket.Burst()) {
+			l.buckets.Remove(key)
+		}
+	}
+	l.lastSweep = now
+}
+
+type clientIDContextKey struct{}
+
+// NewContextWithClientID returns a copy of the context identifying the client of
+// the request. HTTP handlers authenticating the clients in front of the server
+// use it to let the server attribute the requests, see PeerInfo.ClientID.
+func NewContextWithClientID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, clientIDContextKey{}, id)
+}
+
+// clientIDFromContext returns the client identity set by NewContextWithClientID.
+func clientIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(clientIDContextKey{}).(string)
+	return id
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newRateLimitedServer creates a test server with the given rate limits.
+func newRateLimitedServer(t *testing.T, config RateLimitConfig) *Server {
+	t.Helper()
+
+	limiter, err := NewRateLimiter(config)
+	if err != nil {
+		t.Fatalf("failed to create rate limiter: %v", err)
+	}
+	server := newTestServer()
+	server.SetRateLimiter(limiter)
+	t.Cleanup(server.Stop)
+	return server
+}
+
+// checkRateLimited checks whether the error is a rate limit rejection.
+func checkRateLimited(t *testing.T, call string, err error, want bool) {
+	t.Helper()
+
+	var rpcErr Error
+	switch {
+	case want && (!errors.As(err, &rpcErr) || rpcErr.ErrorCode() != errcodeLimitExceeded):
+		t.Fatalf("%s: expected rate limit error, got %v", call, err)
+	case !want && err != nil:
+		t.Fatalf("%s: unexpected error: %v", call, err)
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	server := newRateLimitedServer(t, RateLimitConfig{
+		Limits: map[string]RateLimit{
+			"test":      {Rate: 1e-6, Burst: 4},
+			"test_echo": {Rate: 1e-6, Burst: 1},
+		},
+		Costs: map[string]int{"test_repeat": 2},
+	})
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	client, err := DialHTTP(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	for _, tt := range []struct {
+		method  string
+		args    []any
+		limited bool
+	}{
+		{"test_echo", []any{"x", 1, nil}, false}, // method bucket 0, namespace bucket 3
+		{"test_echo", []any{"x", 1, nil}, true},  // method bucket empty
+		{"test_repeat", []any{"x", 1}, false},    // namespace bucket 1
+		{"test_repeat", []any{"x", 1}, true},     // namespace bucket short of the cost
+		{"test_null", nil, false},                // namespace bucket 0
+		{"test_null", nil, true},
+		{"rpc_modules", nil, false}, // other namespaces not limited
+	} {
+		var result any
+		err := client.Call(&result, tt.method, tt.args...)
+		checkRateLimited(t, tt.method, err, tt.limited)
+	}
+	// Local clients are not limited
+	local := DialInProc(server)
+	defer local.Close()
+
+	var result any
+	checkRateLimited(t, "local test_null", local.Call(&result, "test_null"), false)
+}
+
+// Tests that rate limited batch items are rejected individually.
+func TestRateLimitsBatch(t *testing.T) {
+	server := newRateLimitedServer(t, RateLimitConfig{
+		Limits: map[string]RateLimit{"*": {Rate: 1e-6, Burst: 2}},
+	})
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	client, err := DialHTTP(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	batch := make([]BatchElem, 3)
+	for i := range batch {
+		batch[i] = BatchElem{Method: "test_null", Result: new(any)}
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal(err)
+	}
+	for i, elem := range batch {
+		checkRateLimited(t, "batch item", elem.Error, i == 2)
+	}
+}
+
+// Tests that clients identified by an accepted header or by the authenticating
+// handlers have their own buckets, across HTTP and WebSocket, while clients with
+// unknown header values share the bucket of their address.
+func TestRateLimitsClientID(t *testing.T) {
+	server := newRateLimitedServer(t, RateLimitConfig{
+		Limits:       map[string]RateLimit{"test_peerInfo": {Rate: 1e-6, Burst: 1}},
+		ClientHeader: "X-Api-Key",
+		Clients:      []string{"alice", "bob"},
+	})
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	// Authenticating handler identifying the WebSocket clients by path
+	wssrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := NewContextWithClientID(r.Context(), strings.TrimPrefix(r.URL.Path, "/"))
+		server.WebsocketHandler([]string{"*"}).ServeHTTP(w, r.WithContext(ctx))
+	}))
+	defer wssrv.Close()
+
+	dial := func(url string, opts ...ClientOption) *Client {
+		client, err := DialOptions(context.Background(), url, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(client.Close)
+		return client
+	}
+	wsURL := "ws" + strings.TrimPrefix(wssrv.URL, "http")
+	for i, tt := range []struct {
+		client    *Client
+		id        string
+		exhausted bool // Whether the bucket of the client was used by a previous one
+	}{
+		{dial(httpsrv.URL, WithHeader("X-Api-Key", "alice")), "alice", false},
+		{dial(httpsrv.URL, WithHeader("X-Api-Key", "bob")), "bob", false},
+		{dial(wsURL + "/carol"), "carol", false},
+		{dial(wsURL+"/alice", WithHeader("X-Api-Key", "bob")), "alice", true}, // authenticated identity first
+		{dial(httpsrv.URL), "", false},
+		{dial(httpsrv.URL, WithHeader("X-Api-Key", "mallory")), "mallory", true}, // unknown header, limited by address
+	} {
+		var info PeerInfo
+		err := tt.client.Call(&info, "test_peerInfo")
+		checkRateLimited(t, fmt.Sprintf("client %d first call", i), err, tt.exhausted)
+		if err == nil && info.ClientID != tt.id {
+			t.Fatalf("client %d: id mismatch: have %q, want %q", i, info.ClientID, tt.id)
+		}
+		err = tt.client.Call(&info, "test_peerInfo")
+		checkRateLimited(t, fmt.Sprintf("client %d second call", i), err, true)
+	}
+}
+
+func TestRateLimitConfigValidation(t *testing.T) {
+	for _, config := range []RateLimitConfig{
+		{Limits: map[string]RateLimit{"eth": {Rate: 1, Burst: 0}}},
+		{Limits: map[string]RateLimit{"eth": {Rate: -1, Burst: 1}}},
+		{Costs: map[string]int{"eth_getLogs": 0}},
+		{ClientHeader: "X-Api-Key"},
+	} {
+		if _, err := NewRateLimiter(config); err == nil {
+			t.Errorf("invalid config accepted: %+v", config)
+		}
+	}
+}
+
+// Tests that the number of tracked buckets is capped, dropping the least
+// recently used ones.
+func TestRateLimitBucketCap(t *testing.T) {
+	limiter, err := NewRateLimiter(RateLimitConfig{
+		Limits: map[string]RateLimit{"*": {Rate: 1e-6, Burst: 2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := func(addr string) error {
+		ctx := context.WithValue(context.Background(), peerInfoContextKey{}, PeerInfo{Transport: "http", RemoteAddr: addr})
+		return limiter.allow(ctx, "test_echo")
+	}
+	// Drain a token of the first client, then flood the limiter with others
+	if err := call("10.0.0.1:1"); err != nil {
+		t.Fatalf("first call rejected: %v", err)
+	}
+	for i := 0; i < maxRateBuckets; i++ {
+		call(fmt.Sprintf("10.%d.%d.%d:1", 1+i>>16, byte(i>>8), byte(i)))
+	}
+	if n := limiter.buckets.Len(); n != maxRateBuckets {
+		t.Fatalf("bucket count mismatch: have %d, want %d", n, maxRateBuckets)
+	}
+	// The bucket of the first client was evicted, so it has its burst again
+	for i := 0; i < 2; i++ {
+		if err := call("10.0.0.1:1"); err != nil {
+			t.Fatalf("call %d after eviction rejected: %v", i, err)
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"io"
 	"net"
+	"net/http"
 	"sync"
 	"sync/atomic"
 
@@ -54,6 +55,9 @@ type Server struct {
 	batchItemLimit     int
 	batchResponseLimit int
 	httpBodyLimit      int
+	rateLimiter        *RateLimiter
+	accessPolicy       *AccessPolicy
+	accessLog          *AccessLog
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -89,6 +93,43 @@ func (s *Server) SetHTTPBodyLimit(limit int) {
 	s.httpBodyLimit = limit
 }
 
+// SetRateLimiter sets the limiter applied to the method calls of remote clients.
+// Calls over IPC and in-process connections are not limited.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetRateLimiter(limiter *RateLimiter) {
+	s.rateLimiter = limiter
+}
+
+// SetAccessPolicy sets the policy restricting the methods callable by remote
+// clients. Calls over IPC and in-process connections are not restricted.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetAccessPolicy(policy *AccessPolicy) {
+	s.accessPolicy = policy
+}
+
+// SetAccessLog sets the log recording the method calls served.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetAccessLog(accessLog *AccessLog) {
+	s.accessLog = accessLog
+}
+
+// setClientID sets the identity of the client sending the request, if known.
+func (s *Server) setClientID(info *PeerInfo, r *http.Request) {
+	if id := clientIDFromContext(r.Context()); id != "" {
+		info.ClientID, info.Authenticated = id, true
+		return
+	}
+	if s.rateLimiter != nil && s.rateLimiter.config.ClientHeader != "" {
+		info.ClientID = r.Header.Get(s.rateLimiter.config.ClientHeader)
+	}
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either an RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -114,6 +155,9 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 		idgen:              s.idgen,
 		batchItemLimit:     s.batchItemLimit,
 		batchResponseLimit: s.batchResponseLimit,
+		rateLimiter:        s.rateLimiter,
+		accessPolicy:       s.accessPolicy,
+		accessLog:          s.accessLog,
 	}
 	c := initClient(codec, &s.services, cfg)
 	<-codec.closed()
@@ -149,6 +193,9 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.batchItemLimit, s.batchResponseLimit)
 	h.allowSubscribe = false
+	h.rateLimiter = s.rateLimiter
+	h.accessPolicy = s.accessPolicy
+	h.accessLog = s.accessLog
 	defer h.close(io.EOF, nil)
 
 	reqs, batch, err := codec.readBatch()
@@ -235,6 +282,15 @@ type PeerInfo struct {
 		Origin    string
 		Host      string
 	}
+
+	// ClientID identifies the client, as established by the HTTP handlers in
+	// front of the server (e.g. the "id" claim of a JWT token) or by the client
+	// header of the rate limiter. It is empty for anonymous clients.
+	ClientID string
+
+	// Authenticated is set if the ClientID was established by the HTTP handlers,
+	// rather than taken from the header set by the client.
+	Authenticated bool
 }
 
 type peerInfoContextKey struct{}
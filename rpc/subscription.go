@@ -45,6 +45,8 @@ var (
 
 	// ErrSubscriptionNotFound is returned when the notification for the given id is not found
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+
+	errSubscriptionTerminated = errors.New("subscription terminated")
 )
 
 var globalGen = randomIDGenerator()
@@ -108,6 +110,7 @@ type Notifier struct {
 	buffer       []any
 	callReturned bool
 	activated    bool
+	unsubErr     error // Error the subscription was terminated with, if any
 }
 
 // CreateSubscription returns a new subscription that is coupled to the
@@ -138,6 +141,9 @@ func (n *Notifier) Notify(id ID, data any) error {
 	} else if n.sub.ID != id {
 		panic("Notify with wrong ID")
 	}
+	if n.unsubErr != nil {
+		return ErrSubscriptionNotFound
+	}
 	if n.activated {
 		return n.send(n.sub, data)
 	}
@@ -145,12 +151,47 @@ func (n *Notifier) Notify(id ID, data any) error {
 	return nil
 }
 
-// takeSubscription returns the subscription (if one has been created). No subscription can
-// be created after this call.
+// Unsubscribe terminates the subscription with the given error, which is sent to
+// the client in a last notification. The error is also delivered on the Err channel
+// of the subscription, which is then closed. Notifications can't be sent anymore.
+func (n *Notifier) Unsubscribe(id ID, err error) error {
+	n.mu.Lock()
+	if n.sub == nil {
+		panic("can't Unsubscribe before subscription is created")
+	} else if n.sub.ID != id {
+		panic("Unsubscribe with wrong ID")
+	}
+	if n.unsubErr != nil {
+		n.mu.Unlock()
+		return nil
+	}
+	if err == nil {
+		err = errSubscriptionTerminated
+	}
+	n.unsubErr = err
+
+	// Notifications are buffered until the subscription is activated, the error
+	// is sent along with them in that case.
+	var sendErr error
+	if n.activated {
+		sendErr = n.sendError(n.sub, err)
+	}
+	sub := n.sub
+	n.mu.Unlock()
+
+	n.h.removeServerSubscription(sub, err)
+	return sendErr
+}
+
+// takeSubscription returns the subscription (if one has been created and is not yet
+// terminated). No subscription can be created after this call.
 func (n *Notifier) takeSubscription() *Subscription {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 	n.callReturned = true
+	if n.unsubErr != nil {
+		return nil
+	}
 	return n.sub
 }
 
@@ -167,6 +208,9 @@ func (n *Notifier) activate() error {
 		}
 	}
 	n.activated = true
+	if n.unsubErr != nil {
+		return n.sendError(n.sub, n.unsubErr)
+	}
 	return nil
 }
 
@@ -182,19 +226,43 @@ func (n *Notifier) send(sub *Subscription, data any) error {
 	return n.h.conn.writeJSON(context.Background(), &msg, false)
 }
 
+func (n *Notifier) sendError(sub *Subscription, err error) error {
+	msg := jsonrpcSubscriptionNotification{
+		Version: vsn,
+		Method:  n.namespace + notificationMethodSuffix,
+		Params: subscriptionErrorEnc{
+			ID:    string(sub.ID),
+			Error: newJSONError(err),
+		},
+	}
+	return n.h.conn.writeJSON(context.Background(), &msg, false)
+}
+
 // A Subscription is created by a notifier and tied to that notifier. The client can use
 // this subscription to wait for an unsubscribe request for the client, see Err().
 type Subscription struct {
 	ID        ID
 	namespace string
 	err       chan error // closed on unsubscribe
+	closeOnce sync.Once
 }
 
-// Err returns a channel that is closed when the client send an unsubscribe request.
+// Err returns a channel that is closed when the client send an unsubscribe request,
+// or the subscription is terminated by the server.
 func (s *Subscription) Err() <-chan error {
 	return s.err
 }
 
+// close delivers the error, if any, and closes the error channel.
+func (s *Subscription) close(err error) {
+	s.closeOnce.Do(func() {
+		if err != nil {
+			s.err <- err
+		}
+		close(s.err)
+	})
+}
+
 // MarshalJSON marshals a subscription as its ID.
 func (s *Subscription) MarshalJSON() ([]byte, error) {
 	return json.Marshal(s.ID)
@@ -286,6 +354,18 @@ func (sub *ClientSubscription) close(err error) {
 	}
 }
 
+// terminate is called by the client's message dispatcher when the server ends the
+// subscription. Notifications received before are still delivered.
+func (sub *ClientSubscription) terminate(err error) {
+	sub.close(&subscriptionTerminatedError{err})
+}
+
+// subscriptionTerminatedError wraps the error a subscription was ended with by
+// the server, telling the forwarding loop to deliver the queued notifications.
+type subscriptionTerminatedError struct{ err error }
+
+func (e *subscriptionTerminatedError) Error() string { return e.err.Error() }
+
 // run is the forwarding loop of the subscription. It runs in its own goroutine and
 // is launched by the client's handler after the subscription has been created.
 func (sub *ClientSubscription) run() {
@@ -321,8 +401,10 @@ func (sub *ClientSubscription) forward() (unsubscribeServer bool, err error) {
 		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(sub.in)},
 		{Dir: reflect.SelectSend, Chan: sub.channel},
 	}
-	buffer := list.New()
-
+	var (
+		buffer     = list.New()
+		terminated error // Set if the server ended the subscription
+	)
 	for {
 		var chosen int
 		var recv reflect.Value
@@ -341,8 +423,17 @@ func (sub *ClientSubscription) forward() (unsubscribeServer bool, err error) {
 				err = recv.Interface().(error)
 			}
 			if err == errUnsubscribed {
-				// Exiting because Unsubscribe was called, unsubscribe on server.
-				return true, nil
+				// Exiting because Unsubscribe was called, unsubscribe on server
+				// unless it already ended the subscription.
+				return terminated == nil, nil
+			}
+			if term, ok := err.(*subscriptionTerminatedError); ok {
+				// Ended by the server, deliver the queued notifications first.
+				if buffer.Len() == 0 {
+					return false, term.err
+				}
+				terminated = term.err
+				continue
 			}
 			return false, err
 
@@ -359,6 +450,9 @@ func (sub *ClientSubscription) forward() (unsubscribeServer bool, err error) {
 		case 2: // sub.channel<-
 			cases[2].Send = reflect.Value{} // Don't hold onto the value.
 			buffer.Remove(buffer.Front())
+			if terminated != nil && buffer.Len() == 0 {
+				return false, terminated
+			}
 		}
 	}
 }
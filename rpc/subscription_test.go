@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -282,3 +283,34 @@ func TestNotify(t *testing.T) {
 		t.Errorf("have:\n%v\nwant:\n%v\n", have, want)
 	}
 }
+
+func TestNotifierUnsubscribe(t *testing.T) {
+	t.Parallel()
+
+	out := new(bytes.Buffer)
+	id := ID("test")
+	sub := &Subscription{ID: id, err: make(chan error, 1)}
+	notifier := &Notifier{
+		h:         &handler{conn: &mockConn{json.NewEncoder(out)}, serverSubs: map[ID]*Subscription{id: sub}},
+		sub:       sub,
+		activated: true,
+	}
+	notifier.Unsubscribe(id, errors.New("failed"))
+	have := strings.TrimSpace(out.String())
+	want := `{"jsonrpc":"2.0","method":"_subscription","params":{"subscription":"test","error":{"code":-32000,"message":"failed"}}}`
+	if have != want {
+		t.Errorf("have:\n%v\nwant:\n%v\n", have, want)
+	}
+	if err := <-sub.Err(); err == nil || err.Error() != "failed" {
+		t.Errorf("wrong subscription error: %v", err)
+	}
+	if _, ok := <-sub.Err(); ok {
+		t.Error("subscription error channel not closed")
+	}
+	if len(notifier.h.serverSubs) != 0 {
+		t.Error("terminated subscription not removed")
+	}
+	if err := notifier.Notify(id, "hello"); err != ErrSubscriptionNotFound {
+		t.Errorf("notification after termination: %v", err)
+	}
+}
@@ -61,6 +61,7 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			return
 		}
 		codec := newWebsocketCodec(conn, r.Host, r.Header, wsDefaultReadLimit)
+		s.setClientID(&codec.info, r)
 		s.ServeCodec(codec, 0)
 	})
 }
@@ -291,7 +292,7 @@ type websocketCodec struct {
 	pongReceived chan struct{}
 }
 
-func newWebsocketCodec(conn *websocket.Conn, host string, req http.Header, readLimit int64) ServerCodec {
+func newWebsocketCodec(conn *websocket.Conn, host string, req http.Header, readLimit int64) *websocketCodec {
 	conn.SetReadLimit(readLimit)
 	encode := func(v interface{}, isErrorResponse bool) error {
 		return conn.WriteJSON(v)
@@ -20,6 +20,7 @@ import (
 	"errors"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/triedb/database"
 	"github.com/ethereum/go-ethereum/triedb/pathdb"
 )
 
@@ -70,3 +71,20 @@ func (db *Database) HistoryRange() (uint64, uint64, error) {
 	}
 	return pdb.HistoryRange()
 }
+
+// HistoricReader constructs a reader for accessing the requested historical
+// state, which is resolved from the indexed state histories.
+//
+// This function is only supported by path mode database with state indexing
+// enabled.
+func (db *Database) HistoricReader(root common.Hash) (database.StateReader, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return nil, errors.New("not supported")
+	}
+	reader, err := pdb.HistoricReader(root)
+	if err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
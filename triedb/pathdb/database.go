@@ -110,10 +110,11 @@ type layer interface {
 
 // Config contains the settings for database.
 type Config struct {
-	StateHistory    uint64 // Number of recent blocks to maintain state history for
-	CleanCacheSize  int    // Maximum memory allowance (in bytes) for caching clean nodes
-	WriteBufferSize int    // Maximum memory allowance (in bytes) for write buffer
-	ReadOnly        bool   // Flag whether the database is opened in read only mode.
+	StateHistory        uint64 // Number of recent blocks to maintain state history for
+	EnableStateIndexing bool   // Whether to index state histories for serving historical state
+	CleanCacheSize      int    // Maximum memory allowance (in bytes) for caching clean nodes
+	WriteBufferSize     int    // Maximum memory allowance (in bytes) for write buffer
+	ReadOnly            bool   // Flag whether the database is opened in read only mode.
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -136,6 +137,9 @@ func (c *Config) fields() []interface{} {
 	list = append(list, "cache", common.StorageSize(c.CleanCacheSize))
 	list = append(list, "buffer", common.StorageSize(c.WriteBufferSize))
 	list = append(list, "history", c.StateHistory)
+	if c.EnableStateIndexing {
+		list = append(list, "index", true)
+	}
 	return list
 }
 
@@ -197,6 +201,7 @@ type Database struct {
 	diskdb  ethdb.Database               // Persistent storage for matured trie nodes
 	tree    *layerTree                   // The group for all known layers
 	freezer ethdb.ResettableAncientStore // Freezer for storing trie histories, nil possible in tests
+	indexer *historyIndexer              // History indexer for serving historical state, nil if disabled
 	lock    sync.RWMutex                 // Lock to prevent mutations from happening at the same time
 }
 
@@ -234,6 +239,9 @@ func New(diskdb ethdb.Database, config *Config, isVerkle bool) *Database {
 	if err := db.repairHistory(); err != nil {
 		log.Crit("Failed to repair state history", "err", err)
 	}
+	if db.indexer != nil {
+		db.indexer.start()
+	}
 	// Disable database in case node is still in the initial state sync stage.
 	if rawdb.ReadSnapSyncStatusFlag(diskdb) == rawdb.StateSyncRunning && !db.readOnly {
 		if err := db.Disable(); err != nil {
@@ -267,6 +275,21 @@ func (db *Database) repairHistory() error {
 	}
 	db.freezer = freezer
 
+	// Set up the state history indexer if state indexing is enabled, otherwise
+	// drop the leftover index as it can't be kept aligned with the histories.
+	if !db.readOnly {
+		if db.config.EnableStateIndexing && !db.isVerkle {
+			db.indexer, err = newHistoryIndexer(db.diskdb, db.freezer)
+			if err != nil {
+				log.Crit("Failed to initialize state history indexer", "err", err)
+			}
+		} else if rawdb.ReadStateHistoryIndexHead(db.diskdb) != nil {
+			log.Info("Deleting state history index")
+			if err := rawdb.DeleteStateHistoryIndex(db.diskdb); err != nil {
+				log.Crit("Failed to delete state history index", "err", err)
+			}
+		}
+	}
 	// Reset the entire state histories if the trie database is not initialized
 	// yet. This action is necessary because these state histories are not
 	// expected to exist without an initialized trie database.
@@ -281,13 +304,18 @@ func (db *Database) repairHistory() error {
 			if err != nil {
 				log.Crit("Failed to reset state histories", "err", err)
 			}
+			if db.indexer != nil {
+				if err := db.indexer.reset(); err != nil {
+					log.Crit("Failed to reset state history index", "err", err)
+				}
+			}
 			log.Info("Truncated extraneous state history")
 		}
 		return nil
 	}
 	// Truncate the extra state histories above in freezer in case it's not
 	// aligned with the disk layer. It might happen after a unclean shutdown.
-	pruned, err := truncateFromHead(db.diskdb, db.freezer, id)
+	pruned, err := db.truncateHistoryHead(id)
 	if err != nil {
 		log.Crit("Failed to truncate extra state histories", "err", err)
 	}
@@ -297,6 +325,37 @@ func (db *Database) repairHistory() error {
 	return nil
 }
 
+// truncateHistoryHead removes the state histories above the given id, along
+// with the associated index entries if state indexing is enabled.
+func (db *Database) truncateHistoryHead(nhead uint64) (int, error) {
+	if db.indexer == nil {
+		return truncateFromHead(db.diskdb, db.freezer, nhead)
+	}
+	var pruned int
+	err := db.indexer.shorten(nhead, func() error {
+		var err error
+		pruned, err = truncateFromHead(db.diskdb, db.freezer, nhead)
+		return err
+	})
+	return pruned, err
+}
+
+// truncateHistoryTail removes the state histories up to and including the
+// given id, along with the associated index entries if state indexing is
+// enabled.
+func (db *Database) truncateHistoryTail(ntail uint64) (int, error) {
+	if db.indexer == nil {
+		return truncateFromTail(db.diskdb, db.freezer, ntail)
+	}
+	var pruned int
+	err := db.indexer.prune(ntail, func() error {
+		var err error
+		pruned, err = truncateFromTail(db.diskdb, db.freezer, ntail)
+		return err
+	})
+	return pruned, err
+}
+
 // Update adds a new layer into the tree, if that can be linked to an existing
 // old parent. It is disallowed to insert a disk layer (the origin of all). Apart
 // from that this function will flatten the extra diff layers at bottom into disk
@@ -403,6 +462,11 @@ func (db *Database) Enable(root common.Hash) error {
 			return err
 		}
 	}
+	if db.indexer != nil {
+		if err := db.indexer.reset(); err != nil {
+			return err
+		}
+	}
 	// Re-construct a new disk layer backed by persistent state
 	// with **empty clean cache and node buffer**.
 	db.tree.reset(newDiskLayer(root, 0, db, nil, newBuffer(db.config.WriteBufferSize, nil, nil, 0)))
@@ -454,7 +518,7 @@ func (db *Database) Recover(root common.Hash) error {
 		db.tree.reset(dl)
 	}
 	rawdb.DeleteTrieJournal(db.diskdb)
-	_, err := truncateFromHead(db.diskdb, db.freezer, dl.stateID())
+	_, err := db.truncateHistoryHead(dl.stateID())
 	if err != nil {
 		return err
 	}
@@ -508,6 +572,11 @@ func (db *Database) Close() error {
 	// Release the memory held by clean cache.
 	db.tree.bottom().resetCache()
 
+	// Terminate the background state history indexing.
+	if db.indexer != nil {
+		db.indexer.close()
+	}
+
 	// Close the attached state history freezer.
 	if db.freezer == nil {
 		return nil
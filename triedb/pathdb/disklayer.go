@@ -235,6 +235,9 @@ func (dl *diskLayer) commit(bottom *diffLayer, force bool) (*diskLayer, error) {
 		if err != nil {
 			return nil, err
 		}
+		if dl.db.indexer != nil {
+			dl.db.indexer.notify()
+		}
 		// Determine if the persisted history object has exceeded the configured
 		// limitation, set the overflow as true if so.
 		tail, err := dl.db.freezer.Tail()
@@ -278,7 +281,7 @@ func (dl *diskLayer) commit(bottom *diffLayer, force bool) (*diskLayer, error) {
 	// To remove outdated history objects from the end, we set the 'tail' parameter
 	// to 'oldest-1' due to the offset between the freezer index and the history ID.
 	if overflow {
-		pruned, err := truncateFromTail(ndl.db.diskdb, ndl.db.freezer, oldest-1)
+		pruned, err := ndl.db.truncateHistoryTail(oldest - 1)
 		if err != nil {
 			return nil, err
 		}
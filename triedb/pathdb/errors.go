@@ -39,4 +39,12 @@ var (
 	// errStateUnrecoverable is returned if state is required to be reverted to
 	// a destination without associated state history available.
 	errStateUnrecoverable = errors.New("state is unrecoverable")
+
+	// errStateHistoryPruned is returned if the requested historical state is
+	// no longer available as the associated state histories have been pruned.
+	errStateHistoryPruned = errors.New("state history pruned")
+
+	// errStateHistoryNotIndexed is returned if the state histories required to
+	// serve a historical state are not yet indexed.
+	errStateHistoryNotIndexed = errors.New("state history not indexed")
 )
@@ -0,0 +1,403 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// The state history index maps each account and storage slot to the list of
+// state histories in which it was modified. Given a historical state with id
+// N, the value of an entry can be resolved by locating the first history after
+// N in which the entry was modified and taking the original value recorded
+// there. If no such history exists, the entry has remained unchanged since and
+// the value can be read from the disk layer.
+//
+// The index is keyed by the hash of the account address and the hash of the
+// storage slot key, and is stored in the key-value store with one entry per
+// modification:
+//
+//   - account: prefix + account hash + history id
+//   - storage: prefix + account hash + slot hash + history id
+//
+// The ids are encoded in big-endian, so that an iterator can be used to find
+// the first modification not earlier than the given id.
+//
+// The index is maintained by a background indexer. It guarantees that all the
+// state histories within the range (tail, head] are indexed, in which tail is
+// the tail of the state history freezer and head is the id of the last indexed
+// state history.
+
+// indexHistory writes the index entries of the given state history into the
+// provided batch.
+func indexHistory(batch ethdb.KeyValueWriter, h *history, id uint64) {
+	accounts, storages := h.stateSet()
+	for addrHash := range accounts {
+		rawdb.WriteAccountHistoryIndex(batch, addrHash, id)
+	}
+	for addrHash, slots := range storages {
+		for slotHash := range slots {
+			rawdb.WriteStorageHistoryIndex(batch, addrHash, slotHash, id)
+		}
+	}
+}
+
+// unindexHistory removes the index entries of the given state history via the
+// provided batch.
+func unindexHistory(batch ethdb.KeyValueWriter, h *history, id uint64) {
+	accounts, storages := h.stateSet()
+	for addrHash := range accounts {
+		rawdb.DeleteAccountHistoryIndex(batch, addrHash, id)
+	}
+	for addrHash, slots := range storages {
+		for slotHash := range slots {
+			rawdb.DeleteStorageHistoryIndex(batch, addrHash, slotHash, id)
+		}
+	}
+}
+
+// historyStateCacheSize is the number of decoded state histories cached for
+// serving historical state reads.
+const historyStateCacheSize = 64
+
+// historyStateSet is the decoded content of a state history, keyed by the hash
+// of the account address and the hash of the storage slot key.
+type historyStateSet struct {
+	accounts map[common.Hash][]byte
+	storages map[common.Hash]map[common.Hash][]byte
+}
+
+// historyIndexer indexes the state histories in the background and keeps the
+// index aligned with the state history freezer.
+type historyIndexer struct {
+	disk    ethdb.KeyValueStore
+	freezer ethdb.AncientReader
+	head    atomic.Uint64                        // The id of the last indexed state history
+	states  *lru.Cache[uint64, *historyStateSet] // Cache of decoded state histories
+
+	// lock protects the index from being mutated while historical state is
+	// being resolved. Indexing and unindexing hold the write lock, readers
+	// hold the read lock.
+	lock sync.RWMutex
+
+	trigger chan struct{}
+	closed  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newHistoryIndexer constructs the history indexer. The background indexing is
+// not started until start is called.
+func newHistoryIndexer(disk ethdb.KeyValueStore, freezer ethdb.AncientReader) (*historyIndexer, error) {
+	tail, err := freezer.Tail()
+	if err != nil {
+		return nil, err
+	}
+	last, err := freezer.Ancients()
+	if err != nil {
+		return nil, err
+	}
+	indexer := &historyIndexer{
+		disk:    disk,
+		freezer: freezer,
+		states:  lru.NewCache[uint64, *historyStateSet](historyStateCacheSize),
+		trigger: make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+	}
+	head := rawdb.ReadStateHistoryIndexHead(disk)
+	switch {
+	case head == nil:
+		// The index is not initialized yet, start indexing from the
+		// first available state history.
+		rawdb.WriteStateHistoryIndexHead(disk, tail)
+		indexer.head.Store(tail)
+
+	case *head > last:
+		// The index is ahead of the state histories, which can happen if
+		// the histories were lost after an unclean shutdown. The entries
+		// of the missing histories can't be removed, rebuild the index.
+		log.Warn("State history index is ahead of histories, rebuilding", "indexed", *head, "histories", last)
+		if err := rawdb.DeleteStateHistoryIndex(disk); err != nil {
+			return nil, err
+		}
+		rawdb.WriteStateHistoryIndexHead(disk, tail)
+		indexer.head.Store(tail)
+
+	default:
+		indexer.head.Store(max(*head, tail))
+	}
+	return indexer, nil
+}
+
+// start launches the background indexing.
+func (i *historyIndexer) start() {
+	i.wg.Add(1)
+	go i.loop()
+	i.notify()
+}
+
+// close terminates the background indexing and waits for it to exit.
+func (i *historyIndexer) close() {
+	select {
+	case <-i.closed:
+		return
+	default:
+		close(i.closed)
+	}
+	i.wg.Wait()
+}
+
+// notify signals the indexer that new state histories are available.
+func (i *historyIndexer) notify() {
+	select {
+	case i.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// loop is the main loop of the indexer, indexing the newly available state
+// histories whenever it is notified.
+func (i *historyIndexer) loop() {
+	defer i.wg.Done()
+
+	for {
+		select {
+		case <-i.trigger:
+			if err := i.run(); err != nil {
+				log.Error("Failed to index state histories", "err", err)
+			}
+		case <-i.closed:
+			return
+		}
+	}
+}
+
+// run indexes all the available state histories which are not yet indexed.
+func (i *historyIndexer) run() error {
+	var (
+		start  = time.Now()
+		logged = time.Now()
+		first  = i.head.Load() + 1
+	)
+	for {
+		done, err := i.indexBatch()
+		if err != nil {
+			return err
+		}
+		head := i.head.Load()
+		if done {
+			if head >= first {
+				indexHistoryTimer.UpdateSince(start)
+				log.Debug("Indexed state histories", "from", first, "to", head, "elapsed", common.PrettyDuration(time.Since(start)))
+			}
+			return nil
+		}
+		select {
+		case <-i.closed:
+			return nil
+		default:
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Indexing state histories", "indexed", head, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+}
+
+// indexBatch indexes a batch of state histories after the current head. The
+// returned flag indicates whether all the available histories are indexed.
+func (i *historyIndexer) indexBatch() (bool, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	tail, err := i.freezer.Tail()
+	if err != nil {
+		return false, err
+	}
+	last, err := i.freezer.Ancients()
+	if err != nil {
+		return false, err
+	}
+	from := max(i.head.Load(), tail) + 1
+	if from > last {
+		return true, nil
+	}
+	var (
+		batch = i.disk.NewBatch()
+		to    = from
+	)
+	for ; ; to++ {
+		h, err := readHistory(i.freezer, to)
+		if err != nil {
+			return false, err
+		}
+		indexHistory(batch, h, to)
+
+		if to == last || batch.ValueSize() >= ethdb.IdealBatchSize {
+			break
+		}
+	}
+	rawdb.WriteStateHistoryIndexHead(batch, to)
+	if err := batch.Write(); err != nil {
+		return false, err
+	}
+	i.head.Store(to)
+	return to == last, nil
+}
+
+// shorten removes the index entries of all state histories above the given id,
+// and then invokes the supplied callback to truncate the histories themselves.
+// The indexer lock is held throughout, preventing the histories from being
+// indexed again before they are truncated.
+func (i *historyIndexer) shorten(nhead uint64, truncate func() error) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	// Drop the cached histories, they may be replaced by the new ones.
+	i.states.Purge()
+
+	var (
+		start = time.Now()
+		head  = i.head.Load()
+	)
+	if head > nhead {
+		tail, err := i.freezer.Tail()
+		if err != nil {
+			return err
+		}
+		batch := i.disk.NewBatch()
+		for id := head; id > nhead && id > tail; id-- {
+			h, err := readHistory(i.freezer, id)
+			if err != nil {
+				// The history is not available anymore, the associated
+				// entries can't be located. Rebuild the index instead.
+				log.Warn("Failed to unindex state history, rebuilding index", "id", id, "err", err)
+				if err := rawdb.DeleteStateHistoryIndex(i.disk); err != nil {
+					return err
+				}
+				rawdb.WriteStateHistoryIndexHead(i.disk, tail)
+				i.head.Store(tail)
+				return truncate()
+			}
+			unindexHistory(batch, h, id)
+
+			if batch.ValueSize() >= ethdb.IdealBatchSize {
+				rawdb.WriteStateHistoryIndexHead(batch, id-1)
+				if err := batch.Write(); err != nil {
+					return err
+				}
+				batch.Reset()
+				i.head.Store(id - 1)
+			}
+		}
+		rawdb.WriteStateHistoryIndexHead(batch, max(nhead, tail))
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		i.head.Store(max(nhead, tail))
+		unindexHistoryTimer.UpdateSince(start)
+		log.Debug("Unindexed state histories", "from", head, "to", nhead, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+	return truncate()
+}
+
+// prune removes the index entries of all state histories up to and including
+// the given id, and invokes the supplied callback to truncate the histories
+// themselves. The indexer lock is held throughout, preventing the histories
+// from being accessed by historical state readers while they are removed.
+//
+// The entries are collected before the truncation but only deleted afterwards.
+// If a crash happens in between, the leftover entries refer to histories below
+// the tail, which are never looked up again; deleting them first instead could
+// leave existing histories unindexed and yield wrong historical state.
+func (i *historyIndexer) prune(ntail uint64, truncate func() error) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	tail, err := i.freezer.Tail()
+	if err != nil {
+		return err
+	}
+	var (
+		start = time.Now()
+		head  = i.head.Load()
+		batch = i.disk.NewBatch()
+	)
+	for id := tail + 1; id <= ntail; id++ {
+		i.states.Remove(id)
+		if id > head {
+			continue
+		}
+		h, err := readHistory(i.freezer, id)
+		if err != nil {
+			return err
+		}
+		unindexHistory(batch, h, id)
+	}
+	if err := truncate(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if min(head, ntail) > tail {
+		unindexHistoryTimer.UpdateSince(start)
+		log.Debug("Unindexed pruned state histories", "from", tail+1, "to", min(head, ntail), "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+	return nil
+}
+
+// reset drops the entire index. It's meant to be used when all the state
+// histories are removed.
+func (i *historyIndexer) reset() error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.states.Purge()
+	if err := rawdb.DeleteStateHistoryIndex(i.disk); err != nil {
+		return err
+	}
+	tail, err := i.freezer.Tail()
+	if err != nil {
+		return err
+	}
+	rawdb.WriteStateHistoryIndexHead(i.disk, tail)
+	i.head.Store(tail)
+	return nil
+}
+
+// readStates retrieves the decoded state set of the specified state history.
+func (i *historyIndexer) readStates(id uint64) (*historyStateSet, error) {
+	if set, ok := i.states.Get(id); ok {
+		return set, nil
+	}
+	h, err := readHistory(i.freezer, id)
+	if err != nil {
+		return nil, err
+	}
+	accounts, storages := h.stateSet()
+	set := &historyStateSet{accounts: accounts, storages: storages}
+	i.states.Add(id, set)
+	return set, nil
+}
@@ -0,0 +1,310 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb/database"
+)
+
+const (
+	// maxUnindexedHistories is the maximum number of state histories which
+	// are allowed to be scanned sequentially if they are not yet indexed.
+	maxUnindexedHistories = 128
+
+	// maxHistoricalReadRetries is the maximum number of attempts for resolving
+	// a historical state entry if the disk layer keeps progressing.
+	maxHistoricalReadRetries = 16
+)
+
+// layerNodeDatabase wraps a single layer as the trie node database, allowing
+// the trie to be opened on top of it.
+type layerNodeDatabase struct {
+	layer layer
+}
+
+// NodeReader implements database.NodeDatabase, returning a node reader of the
+// wrapped layer.
+func (db *layerNodeDatabase) NodeReader(root common.Hash) (database.NodeReader, error) {
+	if root != db.layer.rootHash() {
+		return nil, fmt.Errorf("state %#x is not available", root)
+	}
+	return &reader{layer: db.layer}, nil
+}
+
+// HistoricalStateReader is a state reader that provides access to a historical
+// state below the disk layer. The state is resolved by combining the reverse
+// state diffs recorded in the state histories with the state of the disk layer.
+type HistoricalStateReader struct {
+	db   *Database
+	root common.Hash // The root of the historical state
+	id   uint64      // The state id of the historical state
+}
+
+// HistoricReader constructs a reader for accessing the requested historical
+// state. An error is returned if state indexing is not enabled, or the state
+// is not available, either because it's not a canonical state known locally or
+// because the associated histories have been pruned.
+func (db *Database) HistoricReader(root common.Hash) (*HistoricalStateReader, error) {
+	if db.indexer == nil {
+		return nil, errors.New("historical state is not supported, state indexing is disabled")
+	}
+	id := rawdb.ReadStateID(db.diskdb, root)
+	if id == nil {
+		return nil, fmt.Errorf("state %#x is not available", root)
+	}
+	if *id >= db.tree.bottom().stateID() {
+		return nil, fmt.Errorf("state %#x is not historical", root)
+	}
+	tail, err := db.freezer.Tail()
+	if err != nil {
+		return nil, err
+	}
+	if *id < tail {
+		return nil, fmt.Errorf("%w: state %#x", errStateHistoryPruned, root)
+	}
+	// Ensure the state is connected with the subsequent state history. The
+	// state lookups are not cleaned up when the database is reset, so they
+	// might refer to stale ids.
+	blob := rawdb.ReadStateHistoryMeta(db.freezer, *id+1)
+	if len(blob) == 0 {
+		return nil, fmt.Errorf("%w: state %#x", errStateHistoryPruned, root)
+	}
+	var m meta
+	if err := m.decode(blob); err != nil {
+		return nil, err
+	}
+	if m.parent != root {
+		return nil, fmt.Errorf("state %#x is not available", root)
+	}
+	return &HistoricalStateReader{db: db, root: root, id: *id}, nil
+}
+
+// Account directly retrieves the account associated with a particular hash in
+// the slim data format.
+//
+// Note:
+// - the returned account object is safe to modify
+// - no error will be returned if the requested account is not found in database
+func (r *HistoricalStateReader) Account(hash common.Hash) (*types.SlimAccount, error) {
+	blob, err := r.AccountRLP(hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) == 0 {
+		return nil, nil
+	}
+	account := new(types.SlimAccount)
+	if err := rlp.DecodeBytes(blob, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// AccountRLP directly retrieves the account RLP associated with a particular
+// hash in the slim data format.
+//
+// Note:
+// - the returned account data is not a copy, please don't modify it
+// - no error will be returned if the requested account is not found in database
+func (r *HistoricalStateReader) AccountRLP(hash common.Hash) ([]byte, error) {
+	defer func(start time.Time) { historicalAccountReadTimer.UpdateSince(start) }(time.Now())
+
+	return r.resolve(
+		func(start, limit uint64) (uint64, bool) {
+			return rawdb.ReadAccountHistoryIndex(r.db.diskdb, hash, start, limit)
+		},
+		func(set *historyStateSet) ([]byte, bool) {
+			blob, ok := set.accounts[hash]
+			return blob, ok
+		},
+		func(dl layer) ([]byte, error) {
+			return readAccountFromLayer(dl, hash)
+		},
+	)
+}
+
+// Storage directly retrieves the storage data associated with a particular hash,
+// within a particular account.
+//
+// Note:
+// - the returned storage data is not a copy, please don't modify it
+// - no error will be returned if the requested slot is not found in database
+func (r *HistoricalStateReader) Storage(accountHash, storageHash common.Hash) ([]byte, error) {
+	defer func(start time.Time) { historicalStorageReadTimer.UpdateSince(start) }(time.Now())
+
+	return r.resolve(
+		func(start, limit uint64) (uint64, bool) {
+			return rawdb.ReadStorageHistoryIndex(r.db.diskdb, accountHash, storageHash, start, limit)
+		},
+		func(set *historyStateSet) ([]byte, bool) {
+			slots, ok := set.storages[accountHash]
+			if !ok {
+				return nil, false
+			}
+			blob, ok := slots[storageHash]
+			return blob, ok
+		},
+		func(dl layer) ([]byte, error) {
+			return readStorageFromLayer(dl, accountHash, storageHash)
+		},
+	)
+}
+
+// resolve retrieves the value of a state entry in the historical state. The
+// entry is located in the first state history after the target state in which
+// it was modified, or in the disk layer if it has been left untouched since.
+//
+// The resolution is retried if the disk layer becomes stale in the meantime.
+func (r *HistoricalStateReader) resolve(find func(start, limit uint64) (uint64, bool), match func(*historyStateSet) ([]byte, bool), disk func(layer) ([]byte, error)) ([]byte, error) {
+	for i := 0; i < maxHistoricalReadRetries; i++ {
+		blob, err := r.resolveOnce(find, match, disk)
+		if errors.Is(err, errSnapshotStale) {
+			continue
+		}
+		return blob, err
+	}
+	return nil, errSnapshotStale
+}
+
+// resolveOnce is the single-attempt version of resolve.
+func (r *HistoricalStateReader) resolveOnce(find func(start, limit uint64) (uint64, bool), match func(*historyStateSet) ([]byte, bool), disk func(layer) ([]byte, error)) ([]byte, error) {
+	// Resolve the disk layer before acquiring the indexer lock, as the state
+	// histories are pruned with the layer tree locked. The layer is only read
+	// after the indexer lock is released, it might become stale in between,
+	// in which case the resolution is retried.
+	dl := r.db.tree.bottom()
+	blob, found, err := r.resolveHistory(dl.stateID(), find, match)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return blob, nil
+	}
+	// The entry has not been modified since the target state, resolve it from
+	// the disk layer instead.
+	return disk(dl)
+}
+
+// resolveHistory locates the first state history within (r.id, limit] in which
+// the entry was modified and returns the original value recorded there. The
+// returned flag indicates whether such a history was found.
+func (r *HistoricalStateReader) resolveHistory(limit uint64, find func(start, limit uint64) (uint64, bool), match func(*historyStateSet) ([]byte, bool)) ([]byte, bool, error) {
+	indexer := r.db.indexer
+
+	// Hold the read lock to prevent the state histories from being unindexed
+	// and truncated while they are being accessed.
+	indexer.lock.RLock()
+	defer indexer.lock.RUnlock()
+
+	if r.id >= limit {
+		return nil, false, fmt.Errorf("state %#x is not available", r.root)
+	}
+	// The histories might have been pruned since the reader was constructed.
+	tail, err := r.db.freezer.Tail()
+	if err != nil {
+		return nil, false, err
+	}
+	if r.id < tail {
+		return nil, false, fmt.Errorf("%w: state %#x", errStateHistoryPruned, r.root)
+	}
+	head := indexer.head.Load()
+	if head < r.id {
+		head = r.id
+	}
+	if limit > head && limit-head > maxUnindexedHistories {
+		return nil, false, fmt.Errorf("%w: indexed %d, required %d", errStateHistoryNotIndexed, head, limit)
+	}
+	// Look up the first modification after the target state in the index,
+	// and then scan through the histories not yet indexed.
+	if id, found := find(r.id+1, min(head, limit)); found {
+		set, err := indexer.readStates(id)
+		if err != nil {
+			return nil, false, err
+		}
+		blob, _ := match(set)
+		return blob, true, nil
+	}
+	for id := head + 1; id <= limit; id++ {
+		set, err := indexer.readStates(id)
+		if err != nil {
+			return nil, false, err
+		}
+		if blob, found := match(set); found {
+			return blob, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// readAccountFromLayer retrieves the account associated with a particular hash
+// from the account trie of the given layer, in the slim data format.
+func readAccountFromLayer(l layer, hash common.Hash) ([]byte, error) {
+	tr, err := trie.New(trie.StateTrieID(l.rootHash()), &layerNodeDatabase{layer: l})
+	if err != nil {
+		return nil, err
+	}
+	blob, err := tr.Get(hash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) == 0 {
+		return nil, nil
+	}
+	account, err := types.FullAccount(blob)
+	if err != nil {
+		return nil, err
+	}
+	return types.SlimAccountRLP(*account), nil
+}
+
+// readStorageFromLayer retrieves the storage slot associated with a particular
+// hash from the storage trie of the given layer, in the RLP-encoded format.
+func readStorageFromLayer(l layer, accountHash, storageHash common.Hash) ([]byte, error) {
+	db := &layerNodeDatabase{layer: l}
+	tr, err := trie.New(trie.StateTrieID(l.rootHash()), db)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := tr.Get(accountHash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) == 0 {
+		return nil, nil
+	}
+	account, err := types.FullAccount(blob)
+	if err != nil {
+		return nil, err
+	}
+	if account.Root == types.EmptyRootHash {
+		return nil, nil
+	}
+	st, err := trie.New(trie.StorageTrieID(l.rootHash(), accountHash, account.Root), db)
+	if err != nil {
+		return nil, err
+	}
+	return st.Get(storageHash.Bytes())
+}
@@ -0,0 +1,281 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// waitIndexing waits until all the available state histories are indexed.
+func waitIndexing(db *Database) error {
+	timeout := time.After(10 * time.Second)
+	for {
+		last, err := db.freezer.Ancients()
+		if err != nil {
+			return err
+		}
+		if db.indexer.head.Load() == last {
+			return nil
+		}
+		select {
+		case <-timeout:
+			return fmt.Errorf("indexing timeout, indexed: %d, histories: %d", db.indexer.head.Load(), last)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// checkHistoricalState verifies that the historical state of the given root can
+// be fully resolved with the historical state reader.
+func checkHistoricalState(env *tester, root common.Hash) error {
+	reader, err := env.db.HistoricReader(root)
+	if err != nil {
+		return err
+	}
+	for addrHash, account := range env.snapAccounts[root] {
+		blob, err := reader.AccountRLP(addrHash)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(blob, account) {
+			return fmt.Errorf("account %x mismatch, want %x, got %x", addrHash, account, blob)
+		}
+	}
+	// The accounts created afterwards should not be existent.
+	for addrHash := range env.accounts {
+		if _, ok := env.snapAccounts[root][addrHash]; ok {
+			continue
+		}
+		blob, err := reader.AccountRLP(addrHash)
+		if err != nil {
+			return err
+		}
+		if len(blob) != 0 {
+			return fmt.Errorf("unexpected account %x", addrHash)
+		}
+	}
+	for addrHash, slots := range env.snapStorages[root] {
+		for slotHash, slot := range slots {
+			blob, err := reader.Storage(addrHash, slotHash)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(blob, slot) {
+				return fmt.Errorf("storage %x-%x mismatch, want %x, got %x", addrHash, slotHash, slot, blob)
+			}
+		}
+	}
+	return nil
+}
+
+func TestHistoricalStateReader(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	env := newTester(t, 0, false, 32, true)
+	defer env.release()
+
+	if err := waitIndexing(env.db); err != nil {
+		t.Fatal(err)
+	}
+	bottom := env.bottomIndex()
+
+	// Both the initial state and all the states below the disk layer should
+	// be accessible.
+	if err := checkHistoricalState(env, types.EmptyRootHash); err != nil {
+		t.Fatalf("Failed to read initial state, %v", err)
+	}
+	for i := 0; i < bottom; i++ {
+		if err := checkHistoricalState(env, env.roots[i]); err != nil {
+			t.Fatalf("Failed to read historical state %d, %v", i, err)
+		}
+	}
+	// The states at or above the disk layer are not historical.
+	for i := bottom; i < len(env.roots); i++ {
+		if _, err := env.db.HistoricReader(env.roots[i]); err == nil {
+			t.Fatalf("Unexpected historical reader for state %d", i)
+		}
+	}
+	if _, err := env.db.HistoricReader(common.Hash{0x1}); err == nil {
+		t.Fatal("Unexpected historical reader for unknown state")
+	}
+}
+
+func TestHistoricalStateReaderUnindexed(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	env := newTester(t, 0, false, 32, true)
+	defer env.release()
+
+	if err := waitIndexing(env.db); err != nil {
+		t.Fatal(err)
+	}
+	// Drop the last few indexed histories, they should be scanned sequentially
+	// instead.
+	bottom := env.bottomIndex()
+	env.db.indexer.close()
+	if err := env.db.indexer.shorten(uint64(bottom-8), func() error { return nil }); err != nil {
+		t.Fatalf("Failed to unindex histories, %v", err)
+	}
+	for i := 0; i < bottom; i++ {
+		if err := checkHistoricalState(env, env.roots[i]); err != nil {
+			t.Fatalf("Failed to read historical state %d, %v", i, err)
+		}
+	}
+}
+
+func TestHistoryUnindexOnRecover(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	env := newTester(t, 0, false, 32, true)
+	defer env.release()
+
+	if err := waitIndexing(env.db); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		bottom = env.bottomIndex()
+		target = bottom - 10
+	)
+	// Collect the entries touched by the histories to be reverted
+	var histories []*history
+	for id := uint64(target + 2); id <= uint64(bottom+1); id++ {
+		h, err := readHistory(env.db.freezer, id)
+		if err != nil {
+			t.Fatalf("Failed to read history %d, %v", id, err)
+		}
+		histories = append(histories, h)
+	}
+	if err := env.db.Recover(env.roots[target]); err != nil {
+		t.Fatalf("Failed to recover state, %v", err)
+	}
+	if head := env.db.indexer.head.Load(); head != uint64(target+1) {
+		t.Fatalf("Unexpected index head, want %d, got %d", target+1, head)
+	}
+	if head := rawdb.ReadStateHistoryIndexHead(env.db.diskdb); head == nil || *head != uint64(target+1) {
+		t.Fatalf("Unexpected persisted index head, want %d, got %v", target+1, head)
+	}
+	for _, h := range histories {
+		accounts, storages := h.stateSet()
+		for addrHash := range accounts {
+			if id, ok := rawdb.ReadAccountHistoryIndex(env.db.diskdb, addrHash, uint64(target+2), ^uint64(0)); ok {
+				t.Fatalf("Unexpected account index %x at %d", addrHash, id)
+			}
+		}
+		for addrHash, slots := range storages {
+			for slotHash := range slots {
+				if id, ok := rawdb.ReadStorageHistoryIndex(env.db.diskdb, addrHash, slotHash, uint64(target+2), ^uint64(0)); ok {
+					t.Fatalf("Unexpected storage index %x-%x at %d", addrHash, slotHash, id)
+				}
+			}
+		}
+	}
+	// The states below the recovered disk layer are still accessible.
+	for i := 0; i < target; i++ {
+		if err := checkHistoricalState(env, env.roots[i]); err != nil {
+			t.Fatalf("Failed to read historical state %d, %v", i, err)
+		}
+	}
+}
+
+func TestHistoricalStateReaderPruned(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	env := newTester(t, 0, false, 32, true)
+	defer env.release()
+
+	if err := waitIndexing(env.db); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		bottom = env.bottomIndex()
+		cutoff = bottom - 10
+	)
+	// Collect the entries touched by the histories to be pruned
+	var histories []*history
+	for id := uint64(1); id <= uint64(cutoff); id++ {
+		h, err := readHistory(env.db.freezer, id)
+		if err != nil {
+			t.Fatalf("Failed to read history %d, %v", id, err)
+		}
+		histories = append(histories, h)
+	}
+	// Construct a reader across the cutoff before pruning, it must not access
+	// the pruned histories afterwards.
+	stale, err := env.db.HistoricReader(env.roots[cutoff-2])
+	if err != nil {
+		t.Fatalf("Failed to construct historical reader, %v", err)
+	}
+	if _, err := env.db.truncateHistoryTail(uint64(cutoff)); err != nil {
+		t.Fatalf("Failed to prune state histories, %v", err)
+	}
+	for id, h := range histories {
+		accounts, storages := h.stateSet()
+		for addrHash := range accounts {
+			if n, ok := rawdb.ReadAccountHistoryIndex(env.db.diskdb, addrHash, 0, uint64(cutoff)); ok {
+				t.Fatalf("Unexpected account index %x at %d, history %d", addrHash, n, id+1)
+			}
+		}
+		for addrHash, slots := range storages {
+			for slotHash := range slots {
+				if n, ok := rawdb.ReadStorageHistoryIndex(env.db.diskdb, addrHash, slotHash, 0, uint64(cutoff)); ok {
+					t.Fatalf("Unexpected storage index %x-%x at %d, history %d", addrHash, slotHash, n, id+1)
+				}
+			}
+		}
+	}
+	for addrHash := range env.snapAccounts[env.roots[cutoff-2]] {
+		if _, err := stale.AccountRLP(addrHash); !errors.Is(err, errStateHistoryPruned) {
+			t.Fatalf("Unexpected error reading pruned state, want %v, got %v", errStateHistoryPruned, err)
+		}
+	}
+	// The states below the cutoff are not available anymore, the ones above
+	// are still accessible.
+	for i := 0; i < cutoff; i++ {
+		if _, err := env.db.HistoricReader(env.roots[i]); err == nil {
+			t.Fatalf("Unexpected historical reader for pruned state %d", i)
+		}
+	}
+	for i := cutoff; i < bottom; i++ {
+		if err := checkHistoricalState(env, env.roots[i]); err != nil {
+			t.Fatalf("Failed to read historical state %d, %v", i, err)
+		}
+	}
+}
@@ -60,4 +60,10 @@ var (
 	historyBuildTimeMeter  = metrics.NewRegisteredTimer("pathdb/history/time", nil)
 	historyDataBytesMeter  = metrics.NewRegisteredMeter("pathdb/history/bytes/data", nil)
 	historyIndexBytesMeter = metrics.NewRegisteredMeter("pathdb/history/bytes/index", nil)
+
+	indexHistoryTimer   = metrics.NewRegisteredTimer("pathdb/history/index/time", nil)
+	unindexHistoryTimer = metrics.NewRegisteredTimer("pathdb/history/unindex/time", nil)
+
+	historicalAccountReadTimer = metrics.NewRegisteredTimer("pathdb/history/read/account", nil)
+	historicalStorageReadTimer = metrics.NewRegisteredTimer("pathdb/history/read/storage", nil)
 )